@@ -0,0 +1,674 @@
+package emojix
+
+import (
+	"context"
+	"database/sql"
+	"emojix/model"
+	"emojix/usecase"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// The JSON API mirrors the HTMX routes for bots and non-browser clients. It
+// never redirects: a missing session or a refused action is a JSON error.
+
+const apiPrefix = "/api/v1"
+
+// maxAPIBody caps request bodies; the largest legit one is a chat line.
+const maxAPIBody = 64 << 10
+
+func (e *webServer) registerAPI(mux *http.ServeMux) {
+	mux.HandleFunc("POST "+apiPrefix+"/users", e.APIInitUser)
+	mux.HandleFunc("GET "+apiPrefix+"/me", e.APIMe)
+	mux.HandleFunc("PUT "+apiPrefix+"/me", e.APIUpdateProfile)
+	mux.HandleFunc("POST "+apiPrefix+"/games", e.APIInitGame)
+	mux.HandleFunc("GET "+apiPrefix+"/games/{id}", e.APIGameState)
+	mux.HandleFunc("POST "+apiPrefix+"/games/{id}/join", e.APIJoinGame)
+	mux.HandleFunc("PUT "+apiPrefix+"/games/{id}/team", e.APIPickTeam)
+	mux.HandleFunc("POST "+apiPrefix+"/games/{id}/spectate", e.APISpectate)
+	mux.HandleFunc("POST "+apiPrefix+"/games/{id}/play", e.APIPlay)
+	mux.HandleFunc("POST "+apiPrefix+"/games/{id}/pick", e.APIPickWord)
+	mux.HandleFunc("POST "+apiPrefix+"/games/{id}/reroll", e.APIRerollWords)
+	mux.HandleFunc("POST "+apiPrefix+"/games/{id}/giveup", e.APIGiveUpTurn)
+	mux.HandleFunc("POST "+apiPrefix+"/games/{id}/voteskip", e.APIVoteSkip)
+	mux.HandleFunc("POST "+apiPrefix+"/games/{id}/guess", e.APIGuess)
+	mux.HandleFunc("POST "+apiPrefix+"/games/{id}/messages", e.APIMessage)
+	mux.HandleFunc("GET "+apiPrefix+"/games/{id}/leaderboard", e.APILeaderboard)
+	mux.HandleFunc("GET "+apiPrefix+"/games/{id}/events", e.APIGameEvents)
+	// Without these, unknown API paths would fall through to the HTML index.
+	notFound := func(w http.ResponseWriter, r *http.Request) {
+		writeAPIError(w, http.StatusNotFound, "not_found", "no such API route")
+	}
+	mux.HandleFunc("GET "+apiPrefix+"/", notFound)
+	mux.HandleFunc("POST "+apiPrefix+"/", notFound)
+}
+
+// apiErrorBody is every non-2xx API response:
+// {"error": {"code": "room_full", "message": "room is full"}}.
+type apiErrorBody struct {
+	Error apiErrorDetail `json:"error"`
+}
+
+type apiErrorDetail struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// apiErrorCodes gives well-known sentinels a stable code clients can switch
+// on; other errors use their usecase.ErrorKind. The status always comes from
+// the kind, as for the HTML routes, and the message may change.
+var apiErrorCodes = []struct {
+	err  error
+	code string
+}{
+	{usecase.ErrUserNotInGame, "not_in_game"},
+	{usecase.ErrNotTeller, "not_teller"},
+	{usecase.ErrTellerCannotGuess, "teller_cannot_guess"},
+	{usecase.ErrInviteRequired, "invite_required"},
+	{usecase.ErrRoomLocked, "room_locked"},
+	{usecase.ErrPlayerKicked, "kicked"},
+	{usecase.ErrInvalidOption, "invalid_option"},
+	{usecase.ErrTellerEmojiOnly, "teller_emoji_only"},
+	{usecase.ErrEmptyMessage, "empty_message"},
+	{usecase.ErrInvalidGameSettings, "invalid_settings"},
+	{usecase.ErrAlreadyPicked, "already_picked"},
+	{usecase.ErrPickFirst, "pick_first"},
+	{usecase.ErrTurnNotStarted, "turn_not_started"},
+	{usecase.ErrJoinGameRoomFull, "room_full"},
+	{usecase.ErrJoinGameUserAlreadyJoined, "already_joined"},
+	{usecase.ErrNoWords, "no_words"},
+	{usecase.ErrGameFinished, "game_finished"},
+	{usecase.ErrShuttingDown, "shutting_down"},
+	{usecase.ErrSlowDown, "slow_down"},
+	{usecase.ErrNicknameTaken, "nickname_taken"},
+	{usecase.ErrNicknameProfane, "nickname_rejected"},
+	{usecase.ErrInvalidAvatar, "invalid_avatar"},
+	{usecase.ErrStealNotOpen, "steal_not_open"},
+	{usecase.ErrTeamsLocked, "teams_locked"},
+	{usecase.ErrInvalidTeam, "invalid_team"},
+	{usecase.ErrNotTeamGame, "not_team_game"},
+	{usecase.ErrSpectatorsWatchOnly, "spectating"},
+	{usecase.ErrTellerCannotSpectate, "teller_cannot_spectate"},
+	{usecase.ErrOnlyTellerGivesUp, "not_teller"},
+	{usecase.ErrTurnOver, "turn_over"},
+	{usecase.ErrAlreadyRerolled, "already_rerolled"},
+	{usecase.ErrNoRerollWords, "no_words"},
+	{usecase.ErrTellerCannotVote, "teller_cannot_vote"},
+}
+
+func writeAPIError(w http.ResponseWriter, status int, code, msg string) {
+	writeJSON(w, status, apiErrorBody{Error: apiErrorDetail{Code: code, Message: msg}})
+}
+
+// apiError answers with the status for err's kind and logs unclassified
+// errors as a 500.
+func apiError(w http.ResponseWriter, err error, msg string) {
+	kind := usecase.KindOf(err)
+	if kind == usecase.KindInternal {
+		log.Printf("%s: %v\n", msg, err)
+		writeAPIError(w, http.StatusInternalServerError, kind.String(), msg)
+		return
+	}
+	setRetryAfter(w, err)
+	code := kind.String()
+	for _, c := range apiErrorCodes {
+		if errors.Is(err, c.err) {
+			code = c.code
+			break
+		}
+	}
+	writeAPIError(w, errorStatus(err), code, err.Error())
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("failed to write json: %v\n", err)
+	}
+}
+
+// readJSON decodes an optional body into v; an empty body leaves v as is.
+func readJSON(w http.ResponseWriter, r *http.Request, v any) bool {
+	err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxAPIBody)).Decode(v)
+	if err != nil && !errors.Is(err, io.EOF) {
+		writeAPIError(w, http.StatusBadRequest, "bad_request", "invalid JSON body: "+err.Error())
+		return false
+	}
+	return true
+}
+
+// apiSession authenticates with "Authorization: Bearer <token>", the token
+// POST /users returned, falling back to the browser's session cookie.
+func (e *webServer) apiSession(w http.ResponseWriter, r *http.Request) (Session, bool) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		if c, err := r.Cookie(sessionCookieKey); err == nil {
+			token = c.Value
+		}
+	}
+	token = strings.TrimSpace(token)
+	if token == "" {
+		writeAPIError(w, http.StatusUnauthorized, "unauthorized", "missing bearer token")
+		return Session{}, false
+	}
+	userID, _, err := e.sessions.verify(token)
+	if err != nil {
+		writeAPIError(w, http.StatusUnauthorized, "unauthorized", "invalid token")
+		return Session{}, false
+	}
+
+	user, err := e.emojixUsecase.GetUser(r.Context(), userID)
+	if err != nil {
+		if errors.Is(err, usecase.ErrUserNotFound) || errors.Is(err, sql.ErrNoRows) {
+			writeAPIError(w, http.StatusUnauthorized, "unauthorized", "unknown token")
+			return Session{}, false
+		}
+		apiError(w, err, "failed to load user")
+		return Session{}, false
+	}
+	return Session{UserID: user.ID, Nickname: user.Nickname, Avatar: user.Avatar}, true
+}
+
+type apiUser struct {
+	ID       string `json:"id"`
+	Nickname string `json:"nickname"`
+	Avatar   string `json:"avatar,omitempty"`
+	Token    string `json:"token,omitempty"`
+}
+
+type apiSettings struct {
+	TurnSeconds  int    `json:"turn_seconds,omitempty"`
+	PickSeconds  int    `json:"pick_seconds,omitempty"`
+	MinPlayers   int    `json:"min_players,omitempty"`
+	MaxPlayers   int    `json:"max_players,omitempty"`
+	Rounds       int    `json:"rounds,omitempty"`
+	Scoring      string `json:"scoring,omitempty"`
+	Private      bool   `json:"private,omitempty"`
+	Teams        bool   `json:"teams,omitempty"`
+	StealSeconds int    `json:"steal_seconds,omitempty"` // team games: wait before the other team may guess
+	Reveals      string `json:"reveals,omitempty"`       // percents of the turn, e.g. "50,80", or "off"
+}
+
+func newAPISettings(s model.GameSettings) apiSettings {
+	return apiSettings{
+		TurnSeconds:  int(s.TurnDuration / time.Second),
+		PickSeconds:  int(s.PickDuration / time.Second),
+		MinPlayers:   s.MinPlayers,
+		MaxPlayers:   s.MaxPlayers,
+		Rounds:       s.Rounds,
+		Scoring:      s.Scoring,
+		Private:      s.Private,
+		Teams:        s.Teams,
+		StealSeconds: int(s.StealDelay / time.Second),
+		Reveals:      s.Reveals,
+	}
+}
+
+func (s apiSettings) model() model.GameSettings {
+	return model.GameSettings{
+		TurnDuration: time.Duration(s.TurnSeconds) * time.Second,
+		PickDuration: time.Duration(s.PickSeconds) * time.Second,
+		MinPlayers:   s.MinPlayers,
+		MaxPlayers:   s.MaxPlayers,
+		Rounds:       s.Rounds,
+		Scoring:      s.Scoring,
+		Private:      s.Private,
+		Teams:        s.Teams,
+		StealDelay:   time.Duration(s.StealSeconds) * time.Second,
+		Reveals:      s.Reveals,
+	}
+}
+
+type apiGame struct {
+	ID         string      `json:"id"`
+	ListID     string      `json:"list_id"`
+	Status     string      `json:"status"`
+	HostID     string      `json:"host_id,omitempty"`
+	InviteCode string      `json:"invite_code,omitempty"`
+	Settings   apiSettings `json:"settings"`
+}
+
+type apiWordOption struct {
+	ID   string `json:"id"`
+	Word string `json:"word"`
+}
+
+type apiMessage struct {
+	PlayerID string `json:"player_id,omitempty"`
+	Me       bool   `json:"me"`
+	Content  string `json:"content"`
+	Nickname string `json:"nickname"`
+	IsSystem bool   `json:"is_system,omitempty"`
+	IsGuess  bool   `json:"is_guess,omitempty"`
+}
+
+type apiLeaderboardEntry struct {
+	PlayerID    string `json:"player_id"`
+	Nickname    string `json:"nickname"`
+	Avatar      string `json:"avatar,omitempty"`
+	Team        string `json:"team,omitempty"`
+	Me          bool   `json:"me"`
+	GuessedWord bool   `json:"guessed_word"`
+	IsTeller    bool   `json:"is_teller"`
+	Score       int    `json:"score"`
+	Away        bool   `json:"away"`
+}
+
+func newAPILeaderboard(entries []model.LeaderboardEntry) []apiLeaderboardEntry {
+	out := make([]apiLeaderboardEntry, 0, len(entries))
+	for _, e := range entries {
+		out = append(out, apiLeaderboardEntry(e))
+	}
+	return out
+}
+
+type apiTeamScore struct {
+	Team  string `json:"team"`
+	Score int    `json:"score"`
+	Me    bool   `json:"me"`
+}
+
+type apiTurnResult struct {
+	TellerNickname string          `json:"teller_nickname"`
+	Word           string          `json:"word,omitempty"`
+	Scores         []apiTurnScores `json:"scores"`
+}
+
+type apiTurnScores struct {
+	Nickname string `json:"nickname"`
+	Score    int    `json:"score"`
+}
+
+// apiGameState is model.GameState for one player: Word is masked unless they
+// are the teller or already solved it, and WordOptions is teller-only.
+type apiGameState struct {
+	GameID            string                `json:"game_id"`
+	Status            string                `json:"status"`
+	Settings          apiSettings           `json:"settings"`
+	HostID            string                `json:"host_id,omitempty"`
+	IsHost            bool                  `json:"is_host"`
+	InviteCode        string                `json:"invite_code,omitempty"`
+	Locked            bool                  `json:"locked"`
+	WaitingForPlayers bool                  `json:"waiting_for_players"`
+	TurnID            string                `json:"turn_id,omitempty"`
+	TurnStartedAt     *time.Time            `json:"turn_started_at,omitempty"`
+	TurnEnded         bool                  `json:"turn_ended"`
+	AwaitingPick      bool                  `json:"awaiting_pick"`
+	IsTeller          bool                  `json:"is_teller"`
+	TellerNickname    string                `json:"teller_nickname,omitempty"`
+	WordOptions       []apiWordOption       `json:"word_options,omitempty"`
+	Word              string                `json:"word"`
+	Hint              string                `json:"hint"`
+	LetterCount       int                   `json:"letter_count"`
+	WordCount         int                   `json:"word_count"`
+	Messages          []apiMessage          `json:"messages"`
+	Leaderboard       []apiLeaderboardEntry `json:"leaderboard"`
+	Podium            []apiLeaderboardEntry `json:"podium,omitempty"`
+	TurnResults       []apiTurnResult       `json:"turn_results,omitempty"`
+	TeamLeaderboard   []apiTeamScore        `json:"team_leaderboard,omitempty"`
+	MyTeam            string                `json:"my_team,omitempty"`
+	TellerTeam        string                `json:"teller_team,omitempty"`
+	StealOpensAt      *time.Time            `json:"steal_opens_at,omitempty"`
+	IsSpectator       bool                  `json:"is_spectator"`
+	QueuePosition     int                   `json:"queue_position,omitempty"`
+	SpectatorCount    int                   `json:"spectator_count"`
+	CanReroll         bool                  `json:"can_reroll"`
+	SkipVotes         int                   `json:"skip_votes"`
+	SkipVotesNeeded   int                   `json:"skip_votes_needed,omitempty"`
+	VotedSkip         bool                  `json:"voted_skip"`
+}
+
+func newAPIGameState(gs model.GameState) apiGameState {
+	out := apiGameState{
+		GameID:            gs.GameID,
+		Status:            gs.Status,
+		Settings:          newAPISettings(gs.Settings),
+		HostID:            gs.HostID,
+		IsHost:            gs.IsHost,
+		InviteCode:        gs.InviteCode,
+		Locked:            gs.Locked,
+		WaitingForPlayers: gs.WaitingForPlayers,
+		TurnID:            gs.TurnID,
+		TurnEnded:         gs.TurnEnded,
+		AwaitingPick:      gs.AwaitingPick,
+		IsTeller:          gs.IsTeller,
+		TellerNickname:    gs.TellerNickname,
+		Word:              gs.Word,
+		Hint:              gs.Hint,
+		LetterCount:       gs.LetterCount,
+		WordCount:         gs.WordCount,
+		Messages:          make([]apiMessage, 0, len(gs.Messages)),
+		Leaderboard:       newAPILeaderboard(gs.Leaderboard),
+		MyTeam:            gs.MyTeam,
+		TellerTeam:        gs.TellerTeam,
+		IsSpectator:       gs.IsSpectator,
+		QueuePosition:     gs.QueuePosition,
+		SpectatorCount:    gs.SpectatorCount,
+		CanReroll:         gs.CanReroll,
+		SkipVotes:         gs.SkipVotes,
+		SkipVotesNeeded:   gs.SkipVotesNeeded,
+		VotedSkip:         gs.VotedSkip,
+	}
+	if !gs.TurnStartedAt.IsZero() {
+		started := gs.TurnStartedAt
+		out.TurnStartedAt = &started
+	}
+	if !gs.StealOpensAt.IsZero() {
+		opens := gs.StealOpensAt
+		out.StealOpensAt = &opens
+	}
+	for _, t := range gs.TeamLeaderboard {
+		out.TeamLeaderboard = append(out.TeamLeaderboard, apiTeamScore(t))
+	}
+	for _, w := range gs.WordOptions {
+		out.WordOptions = append(out.WordOptions, apiWordOption{ID: w.ID, Word: w.Word})
+	}
+	for _, m := range gs.Messages {
+		out.Messages = append(out.Messages, apiMessage(m))
+	}
+	if len(gs.Podium) > 0 {
+		out.Podium = newAPILeaderboard(gs.Podium)
+	}
+	for _, t := range gs.TurnResults {
+		result := apiTurnResult{TellerNickname: t.TellerNickname, Word: t.Word, Scores: []apiTurnScores{}}
+		for _, s := range t.Scores {
+			result.Scores = append(result.Scores, apiTurnScores(s))
+		}
+		out.TurnResults = append(out.TurnResults, result)
+	}
+	return out
+}
+
+// APIInitUser mints a user; the returned token authenticates later calls.
+func (e *webServer) APIInitUser(w http.ResponseWriter, r *http.Request) {
+	user, err := e.emojixUsecase.InitUser(r.Context())
+	if err != nil {
+		apiError(w, err, "failed to init user")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, apiUser{ID: user.ID, Nickname: user.Nickname, Token: e.sessions.sign(user.ID)})
+}
+
+func (e *webServer) APIMe(w http.ResponseWriter, r *http.Request) {
+	session, ok := e.apiSession(w, r)
+	if !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, apiUser{ID: session.UserID, Nickname: session.Nickname, Avatar: session.Avatar})
+}
+
+// APIUpdateProfile sets the caller's nickname and avatar; an empty avatar
+// clears it.
+func (e *webServer) APIUpdateProfile(w http.ResponseWriter, r *http.Request) {
+	session, ok := e.apiSession(w, r)
+	if !ok {
+		return
+	}
+	var body struct {
+		Nickname string `json:"nickname"`
+		Avatar   string `json:"avatar"`
+	}
+	if !readJSON(w, r, &body) {
+		return
+	}
+
+	user, err := e.emojixUsecase.UpdateProfile(r.Context(), session.UserID, body.Nickname, body.Avatar)
+	if err != nil {
+		apiError(w, err, "failed to update profile")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, apiUser{ID: user.ID, Nickname: user.Nickname, Avatar: user.Avatar})
+}
+
+func (e *webServer) APIInitGame(w http.ResponseWriter, r *http.Request) {
+	session, ok := e.apiSession(w, r)
+	if !ok {
+		return
+	}
+	var body struct {
+		ListID   string      `json:"list_id"`
+		Settings apiSettings `json:"settings"`
+	}
+	if !readJSON(w, r, &body) {
+		return
+	}
+	if body.ListID == "" {
+		writeAPIError(w, http.StatusBadRequest, "bad_request", "list_id required")
+		return
+	}
+
+	game, err := e.emojixUsecase.InitGame(r.Context(), session.UserID, body.ListID, body.Settings.model())
+	if err != nil {
+		apiError(w, err, "failed to create game")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, apiGame{
+		ID:         game.ID,
+		ListID:     game.ListID,
+		Status:     game.Status,
+		HostID:     game.HostID,
+		InviteCode: game.InviteCode,
+		Settings:   newAPISettings(game.Settings),
+	})
+}
+
+func (e *webServer) APIJoinGame(w http.ResponseWriter, r *http.Request) {
+	session, ok := e.apiSession(w, r)
+	if !ok {
+		return
+	}
+
+	var body struct {
+		Spectate bool `json:"spectate"`
+	}
+	if !readJSON(w, r, &body) {
+		return
+	}
+
+	if err := e.emojixUsecase.JoinGame(r.Context(), r.PathValue("id"), session.UserID, body.Spectate); err != nil {
+		apiError(w, err, "failed to join")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// APIPickTeam moves the caller to {"team": "red"|"blue"} while the game is
+// in the lobby.
+func (e *webServer) APIPickTeam(w http.ResponseWriter, r *http.Request) {
+	session, ok := e.apiSession(w, r)
+	if !ok {
+		return
+	}
+	var body struct {
+		Team string `json:"team"`
+	}
+	if !readJSON(w, r, &body) {
+		return
+	}
+
+	if err := e.emojixUsecase.PickTeam(r.Context(), r.PathValue("id"), session.UserID, body.Team); err != nil {
+		apiError(w, err, "failed to pick team")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// APISpectate moves the caller to the stands; APIPlay queues a spectator for
+// the next free seat.
+func (e *webServer) APISpectate(w http.ResponseWriter, r *http.Request) {
+	e.apiPlayerAction(w, r, e.emojixUsecase.Spectate, "failed to spectate")
+}
+
+func (e *webServer) APIPlay(w http.ResponseWriter, r *http.Request) {
+	e.apiPlayerAction(w, r, e.emojixUsecase.Play, "failed to queue for a seat")
+}
+
+// APIRerollWords swaps the teller's options once per turn; APIGiveUpTurn
+// ends the caller's turn as teller; APIVoteSkip votes to skip the turn.
+func (e *webServer) APIRerollWords(w http.ResponseWriter, r *http.Request) {
+	e.apiPlayerAction(w, r, e.emojixUsecase.RerollWords, "failed to reroll words")
+}
+
+func (e *webServer) APIGiveUpTurn(w http.ResponseWriter, r *http.Request) {
+	e.apiPlayerAction(w, r, e.emojixUsecase.GiveUpTurn, "failed to give up the turn")
+}
+
+func (e *webServer) APIVoteSkip(w http.ResponseWriter, r *http.Request) {
+	e.apiPlayerAction(w, r, e.emojixUsecase.VoteSkip, "failed to vote to skip")
+}
+
+func (e *webServer) apiPlayerAction(w http.ResponseWriter, r *http.Request, action func(ctx context.Context, gameID, userID string) error, msg string) {
+	session, ok := e.apiSession(w, r)
+	if !ok {
+		return
+	}
+
+	if err := action(r.Context(), r.PathValue("id"), session.UserID); err != nil {
+		apiError(w, err, msg)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (e *webServer) APIGameState(w http.ResponseWriter, r *http.Request) {
+	session, ok := e.apiSession(w, r)
+	if !ok {
+		return
+	}
+
+	gameState, err := e.emojixUsecase.GameState(r.Context(), r.PathValue("id"), session.UserID)
+	if err != nil {
+		apiError(w, err, "failed to load game")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newAPIGameState(gameState))
+}
+
+func (e *webServer) APIPickWord(w http.ResponseWriter, r *http.Request) {
+	session, ok := e.apiSession(w, r)
+	if !ok {
+		return
+	}
+	var body struct {
+		WordID string `json:"word_id"`
+	}
+	if !readJSON(w, r, &body) {
+		return
+	}
+
+	if err := e.emojixUsecase.PickWord(r.Context(), r.PathValue("id"), session.UserID, body.WordID); err != nil {
+		apiError(w, err, "failed to pick word")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (e *webServer) APIGuess(w http.ResponseWriter, r *http.Request) {
+	session, ok := e.apiSession(w, r)
+	if !ok {
+		return
+	}
+	var body struct {
+		Content string `json:"content"`
+	}
+	if !readJSON(w, r, &body) {
+		return
+	}
+
+	correct, err := e.emojixUsecase.Guess(r.Context(), r.PathValue("id"), session.UserID, body.Content)
+	if err != nil {
+		apiError(w, err, "failed to process guess")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, struct {
+		Correct bool `json:"correct"`
+	}{correct})
+}
+
+func (e *webServer) APIMessage(w http.ResponseWriter, r *http.Request) {
+	session, ok := e.apiSession(w, r)
+	if !ok {
+		return
+	}
+	var body struct {
+		Content string `json:"content"`
+	}
+	if !readJSON(w, r, &body) {
+		return
+	}
+
+	if err := e.emojixUsecase.Message(r.Context(), r.PathValue("id"), session.UserID, body.Content); err != nil {
+		apiError(w, err, "failed to send message")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (e *webServer) APILeaderboard(w http.ResponseWriter, r *http.Request) {
+	session, ok := e.apiSession(w, r)
+	if !ok {
+		return
+	}
+
+	entries, err := e.emojixUsecase.Leaderboard(r.Context(), r.PathValue("id"), session.UserID)
+	if err != nil {
+		apiError(w, err, "failed to fetch leaderboard")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newAPILeaderboard(entries))
+}
+
+// APIGameEvents streams the game's notifications as server-sent events, the
+// same ones the page's /game/{id}/sse gets, with chat messages as JSON
+// instead of rendered HTML.
+func (e *webServer) APIGameEvents(w http.ResponseWriter, r *http.Request) {
+	session, ok := e.apiSession(w, r)
+	if !ok {
+		return
+	}
+
+	gameID := r.PathValue("id")
+	if err := e.emojixUsecase.CheckMember(r.Context(), gameID, session.UserID); err != nil {
+		apiError(w, err, "failed to check membership")
+		return
+	}
+
+	e.streamGame(w, r, gameID, session.UserID, func(notifType, data string) (string, error) {
+		if notifType != "msg" {
+			return data, nil
+		}
+		msgNotif := usecase.GameMsgNotification{}
+		if err := msgNotif.ParseData(data); err != nil {
+			return "", err
+		}
+		msg := apiMessage{
+			Me:       session.UserID == msgNotif.UserID,
+			Nickname: msgNotif.Nickname,
+			Content:  msgNotif.Content,
+			IsSystem: msgNotif.IsSystem,
+		}
+		if !msgNotif.IsSystem {
+			msg.PlayerID = msgNotif.UserID
+		}
+		b, err := json.Marshal(msg)
+		return string(b), err
+	})
+}
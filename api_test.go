@@ -0,0 +1,391 @@
+package emojix
+
+import (
+	"context"
+	"emojix/model"
+	"emojix/usecase"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// apiDo runs one request through the real route table as userID (empty:
+// no bearer token) and decodes a JSON body into out when non-nil.
+func apiDo(t *testing.T, srv *webServer, method, path, userID, body string, out any) *http.Response {
+	t.Helper()
+	var rd io.Reader
+	if body != "" {
+		rd = strings.NewReader(body)
+	}
+	r := newReq(method, path, rd)
+	if userID != "" {
+		r.Header.Set("Authorization", "Bearer "+testSessions.sign(userID))
+	}
+	w := httptest.NewRecorder()
+
+	srv.mux().ServeHTTP(w, r)
+
+	resp := w.Result()
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			t.Fatalf("%s %s: decode body: %v", method, path, err)
+		}
+	}
+	return resp
+}
+
+func TestAPI_InitUser_ReturnsToken(t *testing.T) {
+	uc := newMockUsecase()
+	uc.InitUserFn = func(ctx context.Context) (model.User, error) {
+		return model.User{ID: "u1", Nickname: "nick"}, nil
+	}
+	srv := newServer(uc, &MockView{})
+
+	var user apiUser
+	resp := apiDo(t, srv, "POST", "/api/v1/users", "", "", &user)
+
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("status = %d, want 201", resp.StatusCode)
+	}
+	if user.ID != "u1" || user.Nickname != "nick" || user.Token != testSessions.sign("u1") {
+		t.Errorf("user = %+v, want u1/nick with a signed token", user)
+	}
+}
+
+func TestAPI_MissingToken_401(t *testing.T) {
+	srv := newServer(newMockUsecase(), &MockView{})
+
+	var body apiErrorBody
+	resp := apiDo(t, srv, "GET", "/api/v1/games/g1", "", "", &body)
+
+	if resp.StatusCode != http.StatusUnauthorized || body.Error.Code != "unauthorized" {
+		t.Fatalf("status = %d code = %q, want 401 unauthorized", resp.StatusCode, body.Error.Code)
+	}
+}
+
+func TestAPI_UnknownToken_401(t *testing.T) {
+	uc := newMockUsecase()
+	uc.GetUserFn = func(ctx context.Context, userID string) (model.User, error) {
+		return model.User{}, usecase.ErrUserNotFound
+	}
+	srv := newServer(uc, &MockView{})
+
+	resp := apiDo(t, srv, "GET", "/api/v1/games/g1", "stale", "", nil)
+
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want 401", resp.StatusCode)
+	}
+	if uc.GameStateCalls != 0 {
+		t.Error("expected GameState not to be called")
+	}
+}
+
+func TestAPI_UnsignedToken_401(t *testing.T) {
+	uc := newMockUsecase()
+	srv := newServer(uc, &MockView{})
+
+	r := newReq("GET", "/api/v1/games/g1", nil)
+	r.Header.Set("Authorization", "Bearer u1")
+	w := httptest.NewRecorder()
+
+	srv.mux().ServeHTTP(w, r)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want 401", w.Code)
+	}
+	if uc.GetUserCalls != 0 || uc.GameStateCalls != 0 {
+		t.Error("a raw user id must not reach the usecase")
+	}
+}
+
+func TestAPI_InitGame_PassesSettings(t *testing.T) {
+	uc := newMockUsecase()
+	uc.InitGameFn = func(ctx context.Context, userID, listID string, settings model.GameSettings) (model.Game, error) {
+		return model.Game{ID: "g1", ListID: listID, Status: model.LobbyGameStatus, HostID: userID, Settings: settings}, nil
+	}
+	srv := newServer(uc, &MockView{})
+
+	var game apiGame
+	resp := apiDo(t, srv, "POST", "/api/v1/games", "u1", `{"list_id": "list-1", "settings": {"turn_seconds": 90, "private": true}}`, &game)
+
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("status = %d, want 201", resp.StatusCode)
+	}
+	if uc.InitGameLastUserID != "u1" || uc.InitGameLastListID != "list-1" {
+		t.Errorf("InitGame args = (%q, %q), want (u1, list-1)", uc.InitGameLastUserID, uc.InitGameLastListID)
+	}
+	want := model.GameSettings{TurnDuration: 90 * time.Second, Private: true}
+	if uc.InitGameLastSettings != want {
+		t.Errorf("settings = %+v, want %+v", uc.InitGameLastSettings, want)
+	}
+	if game.ID != "g1" || game.Settings.TurnSeconds != 90 || !game.Settings.Private {
+		t.Errorf("game = %+v", game)
+	}
+}
+
+func TestAPI_InitGame_BadJSON_400(t *testing.T) {
+	uc := newMockUsecase()
+	srv := newServer(uc, &MockView{})
+
+	var body apiErrorBody
+	resp := apiDo(t, srv, "POST", "/api/v1/games", "u1", `{"list_id":`, &body)
+
+	if resp.StatusCode != http.StatusBadRequest || body.Error.Code != "bad_request" {
+		t.Fatalf("status = %d code = %q, want 400 bad_request", resp.StatusCode, body.Error.Code)
+	}
+	if uc.InitGameCalls != 0 {
+		t.Error("expected InitGame not to be called")
+	}
+}
+
+func TestAPI_GameState_JSON(t *testing.T) {
+	uc := newMockUsecase()
+	uc.GameStateFn = func(ctx context.Context, gameID, userID string) (model.GameState, error) {
+		return model.GameState{
+			GameID:       gameID,
+			Status:       model.PlayingGameStatus,
+			AwaitingPick: true,
+			IsTeller:     true,
+			WordOptions:  []model.Word{{ID: "w1", Word: "apple", Hint: "🍎"}},
+			Leaderboard:  []model.LeaderboardEntry{{PlayerID: "u1", Nickname: "nick", Me: true, Score: 3}},
+			Messages:     []model.GameStateMessage{{Content: "hi", Nickname: "other"}},
+		}, nil
+	}
+	srv := newServer(uc, &MockView{})
+
+	var state apiGameState
+	resp := apiDo(t, srv, "GET", "/api/v1/games/g1", "u1", "", &state)
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+	if uc.GameStateLastGameID != "g1" || uc.GameStateLastUserID != "u1" {
+		t.Errorf("GameState args = (%q, %q), want (g1, u1)", uc.GameStateLastGameID, uc.GameStateLastUserID)
+	}
+	if len(state.WordOptions) != 1 || state.WordOptions[0].ID != "w1" {
+		t.Errorf("word options = %+v", state.WordOptions)
+	}
+	if len(state.Leaderboard) != 1 || state.Leaderboard[0].Score != 3 || !state.Leaderboard[0].Me {
+		t.Errorf("leaderboard = %+v", state.Leaderboard)
+	}
+	if len(state.Messages) != 1 || state.Messages[0].Content != "hi" || state.Messages[0].Nickname != "other" {
+		t.Errorf("messages = %+v", state.Messages)
+	}
+	if state.TurnStartedAt != nil {
+		t.Errorf("turn_started_at = %v, want omitted before the pick", state.TurnStartedAt)
+	}
+}
+
+func TestAPI_Guess_ReturnsCorrect(t *testing.T) {
+	uc := newMockUsecase()
+	uc.GuessFn = func(ctx context.Context, gameID, userID, word string) (bool, error) {
+		return word == "apple", nil
+	}
+	srv := newServer(uc, &MockView{})
+
+	var got struct {
+		Correct bool `json:"correct"`
+	}
+	resp := apiDo(t, srv, "POST", "/api/v1/games/g1/guess", "u1", `{"content": "apple"}`, &got)
+
+	if resp.StatusCode != http.StatusOK || !got.Correct {
+		t.Fatalf("status = %d correct = %v, want 200 true", resp.StatusCode, got.Correct)
+	}
+	if uc.GuessLastGameID != "g1" || uc.GuessLastUserID != "u1" {
+		t.Errorf("Guess args = (%q, %q), want (g1, u1)", uc.GuessLastGameID, uc.GuessLastUserID)
+	}
+}
+
+func TestAPI_Actions_204(t *testing.T) {
+	uc := newMockUsecase()
+	srv := newServer(uc, &MockView{})
+
+	for _, tc := range []struct {
+		path string
+		body string
+	}{
+		{"/api/v1/games/g1/join", ""},
+		{"/api/v1/games/g1/pick", `{"word_id": "w1"}`},
+		{"/api/v1/games/g1/messages", `{"content": "hello"}`},
+		{"/api/v1/games/g1/spectate", ""},
+		{"/api/v1/games/g1/play", ""},
+		{"/api/v1/games/g1/reroll", ""},
+		{"/api/v1/games/g1/giveup", ""},
+		{"/api/v1/games/g1/voteskip", ""},
+	} {
+		resp := apiDo(t, srv, "POST", tc.path, "u1", tc.body, nil)
+		if resp.StatusCode != http.StatusNoContent {
+			t.Errorf("POST %s status = %d, want 204", tc.path, resp.StatusCode)
+		}
+	}
+	if resp := apiDo(t, srv, "PUT", "/api/v1/games/g1/team", "u1", `{"team": "blue"}`, nil); resp.StatusCode != http.StatusNoContent {
+		t.Errorf("PUT team status = %d, want 204", resp.StatusCode)
+	}
+	if uc.SeatCalls != 2 {
+		t.Errorf("seat calls = %d, want spectate and play", uc.SeatCalls)
+	}
+	if uc.TurnActionCalls != 3 || uc.TurnActionLastAction != "voteskip" {
+		t.Errorf("turn action calls = %d (last %q), want reroll, giveup and voteskip", uc.TurnActionCalls, uc.TurnActionLastAction)
+	}
+	if uc.JoinGameLastGameID != "g1" || uc.PickWordLastWordID != "w1" || uc.MessageLastWord != "hello" || uc.PickTeamLastTeam != model.BlueTeam {
+		t.Errorf("calls = join %q, pick %q, message %q, team %q", uc.JoinGameLastGameID, uc.PickWordLastWordID, uc.MessageLastWord, uc.PickTeamLastTeam)
+	}
+}
+
+func TestAPI_ErrorMapping(t *testing.T) {
+	for _, tc := range []struct {
+		err    error
+		status int
+		code   string
+	}{
+		{usecase.ErrNotTeller, http.StatusForbidden, "not_teller"},
+		{usecase.ErrInvalidOption, http.StatusBadRequest, "invalid_option"},
+		{usecase.ErrAlreadyPicked, http.StatusConflict, "already_picked"},
+		{fmt.Errorf("pick: %w", usecase.ErrGameFinished), http.StatusGone, "game_finished"},
+		{usecase.ErrShuttingDown, http.StatusServiceUnavailable, "shutting_down"},
+		{usecase.ErrStealNotOpen, http.StatusConflict, "steal_not_open"},
+		{usecase.ErrSpectatorsWatchOnly, http.StatusForbidden, "spectating"},
+		{usecase.ErrAlreadyRerolled, http.StatusConflict, "already_rerolled"},
+		{usecase.ErrTellerCannotVote, http.StatusForbidden, "teller_cannot_vote"},
+		{errSentinel, http.StatusInternalServerError, "internal"},
+	} {
+		t.Run(tc.code, func(t *testing.T) {
+			uc := newMockUsecase()
+			uc.PickWordFn = func(ctx context.Context, gameID, userID, wordID string) error {
+				return tc.err
+			}
+			srv := newServer(uc, &MockView{})
+
+			var body apiErrorBody
+			resp := apiDo(t, srv, "POST", "/api/v1/games/g1/pick", "u1", `{"word_id": "w1"}`, &body)
+
+			if resp.StatusCode != tc.status || body.Error.Code != tc.code {
+				t.Fatalf("status = %d code = %q, want %d %q", resp.StatusCode, body.Error.Code, tc.status, tc.code)
+			}
+			if ct := resp.Header.Get("Content-Type"); ct != "application/json" {
+				t.Errorf("Content-Type = %q, want application/json", ct)
+			}
+		})
+	}
+}
+
+func TestAPI_JoinRoomFull_409(t *testing.T) {
+	uc := newMockUsecase()
+	uc.JoinGameFn = func(ctx context.Context, gameID, userID string) error {
+		return usecase.ErrJoinGameRoomFull
+	}
+	srv := newServer(uc, &MockView{})
+
+	var body apiErrorBody
+	resp := apiDo(t, srv, "POST", "/api/v1/games/g1/join", "u1", "", &body)
+
+	if resp.StatusCode != http.StatusConflict || body.Error.Code != "room_full" {
+		t.Fatalf("status = %d code = %q, want 409 room_full", resp.StatusCode, body.Error.Code)
+	}
+}
+
+func TestAPI_JoinGame_Spectate(t *testing.T) {
+	uc := newMockUsecase()
+	srv := newServer(uc, &MockView{})
+
+	resp := apiDo(t, srv, "POST", "/api/v1/games/g1/join", "u1", `{"spectate": true}`, nil)
+
+	if resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("status = %d, want 204", resp.StatusCode)
+	}
+	if !uc.JoinGameLastSpectate {
+		t.Error("expected the join to ask for the stands")
+	}
+}
+
+func TestAPI_GameEvents_BearerStream(t *testing.T) {
+	uc := newMockUsecase()
+	uc.GameUpdatesFn = func(ctx context.Context, gameID, userID string, lastEventID uint64, h usecase.GameUpdateHandler) error {
+		if err := h(3, "hintupdated", "🍎<"); err != nil {
+			return err
+		}
+		return h(4, "msg", "u2,bob,hello")
+	}
+	srv := newServer(uc, &MockView{})
+
+	resp := apiDo(t, srv, "GET", "/api/v1/games/g1/events", "u1", "", nil)
+
+	if got := resp.Header.Get("Content-Type"); got != "text/event-stream" {
+		t.Fatalf("Content-Type = %q, want text/event-stream", got)
+	}
+	b, _ := io.ReadAll(resp.Body)
+	body := string(b)
+	for _, want := range []string{
+		"event: init\n",
+		"id: 3\nevent: hintupdated\ndata: 🍎<\n\n",
+		`id: 4` + "\n" + `event: msg` + "\n" + `data: {"player_id":"u2","me":false,"content":"hello","nickname":"bob"}`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("body %q, want it to contain %q", body, want)
+		}
+	}
+}
+
+func TestAPI_GameEvents_NotMember_JSONError(t *testing.T) {
+	uc := newMockUsecase()
+	uc.CheckMemberFn = func(ctx context.Context, gameID, userID string) error {
+		return usecase.ErrUserNotInGame
+	}
+	srv := newServer(uc, &MockView{})
+
+	var body apiErrorBody
+	resp := apiDo(t, srv, "GET", "/api/v1/games/g1/events", "u1", "", &body)
+
+	if resp.StatusCode < 400 || body.Error.Code != "not_in_game" {
+		t.Fatalf("status = %d code = %q, want not_in_game", resp.StatusCode, body.Error.Code)
+	}
+	if uc.GameUpdatesCalls != 0 {
+		t.Error("expected no stream for a non-member")
+	}
+}
+
+func TestAPI_UnknownRoute_JSON404(t *testing.T) {
+	srv := newServer(newMockUsecase(), &MockView{})
+
+	var body apiErrorBody
+	resp := apiDo(t, srv, "GET", "/api/v1/nope", "u1", "", &body)
+
+	if resp.StatusCode != http.StatusNotFound || body.Error.Code != "not_found" {
+		t.Fatalf("status = %d code = %q, want 404 not_found", resp.StatusCode, body.Error.Code)
+	}
+}
+
+func TestAPI_UpdateProfile(t *testing.T) {
+	uc := newMockUsecase()
+	srv := newServer(uc, &MockView{})
+
+	var user apiUser
+	resp := apiDo(t, srv, "PUT", "/api/v1/me", "u1", `{"nickname":"Sly Otter","avatar":"🦦"}`, &user)
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+	if user.ID != "u1" || user.Nickname != "Sly Otter" || user.Avatar != "🦦" || user.Token != "" {
+		t.Errorf("user = %+v, want u1/Sly Otter/🦦 without a token", user)
+	}
+}
+
+func TestAPI_UpdateProfile_NicknameTaken_409(t *testing.T) {
+	uc := newMockUsecase()
+	uc.UpdateProfileFn = func(ctx context.Context, userID, nickname, avatar string) (model.User, error) {
+		return model.User{}, usecase.ErrNicknameTaken
+	}
+	srv := newServer(uc, &MockView{})
+
+	var body apiErrorBody
+	resp := apiDo(t, srv, "PUT", "/api/v1/me", "u1", `{"nickname":"AngryDog"}`, &body)
+
+	if resp.StatusCode != http.StatusConflict || body.Error.Code != "nickname_taken" {
+		t.Fatalf("status = %d code = %q, want 409 nickname_taken", resp.StatusCode, body.Error.Code)
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"emojix/config"
 	"flag"
 	"fmt"
 	"os"
@@ -12,11 +13,12 @@ import (
 	"time"
 )
 
+// dev takes every serve flag and hands args on to each serve it starts, so
+// the child loads the same config; loading it here just fails early.
 // ponytail: 300ms poll is fine for local dev; fsnotify if it ever feels laggy.
 func dev(args []string) error {
 	fs := flag.NewFlagSet("dev", flag.ContinueOnError)
-	dbName := fs.String("db", "emojix.db", "sqlite file")
-	if err := fs.Parse(args); err != nil {
+	if _, err := config.Load(fs, args, os.Getenv); err != nil {
 		return err
 	}
 
@@ -25,7 +27,7 @@ func dev(args []string) error {
 
 	var cmd *exec.Cmd
 	start := func() error {
-		cmd = exec.Command("go", "run", "./cmd/emojix", "serve", "-db", *dbName)
+		cmd = exec.Command("go", append([]string{"run", "./cmd/emojix", "serve"}, args...)...)
 		cmd.Stdout = os.Stdout
 		cmd.Stderr = os.Stderr
 		return cmd.Start()
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"emojix/model"
+	"emojix/repository"
+	"emojix/service"
+	"emojix/usecase"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func lists(args []string) error {
+	if len(args) < 1 || args[0] != "import" {
+		return fmt.Errorf("lists needs an action: import <file.csv|file.json>")
+	}
+	// the file comes before flags: lists import words.csv -db x.db
+	if len(args) < 2 {
+		return fmt.Errorf("lists import needs a file")
+	}
+	file := args[1]
+
+	fs := flag.NewFlagSet("lists import", flag.ContinueOnError)
+	dbName := fs.String("db", "emojix.db", "sqlite file")
+	listID := fs.String("list", "", "add to this existing list id")
+	title := fs.String("title", "", "create a new list with this title (default: file name)")
+	if err := fs.Parse(args[2:]); err != nil {
+		return err
+	}
+	if *listID != "" && *title != "" {
+		return fmt.Errorf("use either -list or -title, not both")
+	}
+	if *listID == "" && *title == "" {
+		*title = strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+	}
+
+	words, err := readWordsFile(file)
+	if err != nil {
+		return err
+	}
+
+	db, err := repository.InitSqliteDB(*dbName)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	notifier, err := service.NewGameNotifier()
+	if err != nil {
+		return err
+	}
+	uc := usecase.NewEmojixUsecase(
+		repository.NewUserRepository(db),
+		repository.NewGameRepository(db),
+		repository.NewWordRepository(db),
+		repository.NewUnitOfWorkFactory(db),
+		notifier,
+		service.NewGameLoop(service.NewRealClock()),
+		service.NewRealClock(),
+	)
+	report, err := uc.ImportWords(context.Background(), usecase.ImportParams{
+		ListID: *listID,
+		Title:  *title,
+		Words:  words,
+	})
+	printImportReport(os.Stdout, report)
+	return err
+}
+
+// readWordsFile reads word/hint pairs from CSV (two columns, optional
+// "word,hint" header) or JSON ([{"word": "...", "hint": "..."}]), picked by
+// extension.
+func readWordsFile(path string) ([]model.Word, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return readWordsCSV(f)
+	case ".json":
+		return readWordsJSON(f)
+	default:
+		return nil, fmt.Errorf("unsupported file type %q: want .csv or .json", filepath.Ext(path))
+	}
+}
+
+func readWordsCSV(r io.Reader) ([]model.Word, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = 2
+	cr.TrimLeadingSpace = true
+
+	words := []model.Word{}
+	for i := 0; ; i++ {
+		rec, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return words, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if i == 0 && strings.EqualFold(rec[0], "word") && strings.EqualFold(rec[1], "hint") {
+			continue
+		}
+		words = append(words, model.Word{Word: rec[0], Hint: rec[1]})
+	}
+}
+
+func readWordsJSON(r io.Reader) ([]model.Word, error) {
+	var rows []struct {
+		Word string `json:"word"`
+		Hint string `json:"hint"`
+	}
+	if err := json.NewDecoder(r).Decode(&rows); err != nil {
+		return nil, err
+	}
+	words := make([]model.Word, 0, len(rows))
+	for _, row := range rows {
+		words = append(words, model.Word{Word: row.Word, Hint: row.Hint})
+	}
+	return words, nil
+}
+
+func printImportReport(w io.Writer, report usecase.ImportReport) {
+	for _, p := range report.Invalid {
+		fmt.Fprintf(w, "row %d %q: %v\n", p.Row, p.Word, p.Err)
+	}
+	for _, p := range report.Duplicates {
+		fmt.Fprintf(w, "row %d %q: duplicate, skipped\n", p.Row, p.Word)
+	}
+	if len(report.Invalid) > 0 {
+		fmt.Fprintf(w, "%d invalid rows, nothing imported\n", len(report.Invalid))
+		return
+	}
+	if report.List.ID != "" {
+		fmt.Fprintf(w, "imported %d words into %q (%s), %d duplicates skipped\n",
+			len(report.Added), report.List.Title, report.List.ID, len(report.Duplicates))
+	}
+}
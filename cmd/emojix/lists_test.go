@@ -0,0 +1,77 @@
+package main
+
+import (
+	"database/sql"
+	"emojix/usecase"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	_ "modernc.org/sqlite"
+)
+
+func TestReadWordsCSVSkipsHeader(t *testing.T) {
+	words, err := readWordsCSV(strings.NewReader("word,hint\nApple, 🍎\n\"Ice Cream\",🍦\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(words) != 2 || words[0].Word != "Apple" || words[0].Hint != "🍎" || words[1].Word != "Ice Cream" {
+		t.Errorf("unexpected words: %+v", words)
+	}
+}
+
+func TestListsImport(t *testing.T) {
+	root := findGoMod(t)
+	old, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(root); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(old) })
+
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, "lists.db")
+	if err := migrate([]string{"up", "-db", dbPath}); err != nil {
+		t.Fatalf("up: %v", err)
+	}
+
+	good := filepath.Join(dir, "fruit.json")
+	err = os.WriteFile(good, []byte(`[{"word":"Apple","hint":"🍎"},{"word":"apple","hint":"🍏"},{"word":"Pear","hint":"🍐"}]`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := lists([]string{"import", good, "-db", dbPath}); err != nil {
+		t.Fatalf("import: %v", err)
+	}
+
+	bad := filepath.Join(dir, "bad.csv")
+	if err := os.WriteFile(bad, []byte("Kiwi,green\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := lists([]string{"import", bad, "-db", dbPath}); !errors.Is(err, usecase.ErrInvalidWord) {
+		t.Fatalf("expected ErrInvalidWord but got %v", err)
+	}
+
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	var title string
+	var n int
+	err = db.QueryRow(`SELECT l.title, COUNT(w.id) FROM word_lists l JOIN words w ON w.list_id = l.id GROUP BY l.id`).Scan(&title, &n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if title != "fruit" || n != 2 {
+		t.Errorf("expected list fruit with 2 words but got %q with %d", title, n)
+	}
+	if err := db.QueryRow(`SELECT COUNT(*) FROM word_lists`).Scan(&n); err != nil || n != 1 {
+		t.Errorf("expected the failed import to leave 1 list, got %d (err %v)", n, err)
+	}
+}
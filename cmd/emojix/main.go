@@ -18,6 +18,8 @@ func main() {
 		err = migrate(os.Args[2:])
 	case "dev":
 		err = dev(os.Args[2:])
+	case "lists":
+		err = lists(os.Args[2:])
 	case "help", "-h", "--help":
 		usage()
 	default:
@@ -38,8 +40,14 @@ commands:
   serve              start the game server
   migrate <action>   db: up | reset | seed | fresh | create <name>
   dev                serve with auto-reload on .go/.gohtml changes
+  lists import <file>
+                     add words from a .csv (word,hint) or .json file
 
-flags (serve, migrate, dev):
+flags (serve, migrate, dev, lists):
   -db string   sqlite file (default emojix.db)
+
+flags (lists import):
+  -list string    add to an existing list id
+  -title string   create a new list (default: file name)
 `)
 }
@@ -1,55 +1,132 @@
 package main
 
 import (
+	"context"
 	"emojix"
+	"emojix/config"
 	"emojix/repository"
 	"emojix/service"
 	"emojix/usecase"
+	"expvar"
 	"flag"
 	"fmt"
 	"log"
+	"log/slog"
 	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 )
 
 func serve(args []string) error {
 	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
-	dbName := fs.String("db", "emojix.db", "sqlite file")
-	if err := fs.Parse(args); err != nil {
+	cfg, err := config.Load(fs, args, os.Getenv)
+	if err != nil {
+		return err
+	}
+	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: cfg.LogLevel})))
+
+	if cfg.BaseURL != "" {
+		fmt.Printf("server running on %s...\n", cfg.BaseURL)
+	} else {
+		_, port, _ := net.SplitHostPort(cfg.Addr)
+		fmt.Printf("server running on http://localhost:%s...\n", port)
+		if localIP := getLocalIP(); localIP != "" {
+			fmt.Printf("server running on http://%s:%s...\n", localIP, port)
+		}
+	}
+
+	db, err := repository.InitSqliteDB(cfg.DBPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	clock := service.NewRealClock()
+	presence := service.NewPresence(clock)
+	go presence.Run(ctx)
+
+	notifierOpts := []service.NotifierOption{service.WithQueueSize(cfg.SSEQueue), service.WithOverflowPolicy(cfg.SSEOverflow)}
+	gameLoop := service.NewGameLoop(clock)
+	if cfg.Broker == "sqlite" {
+		broker := repository.NewSqliteBroker(db, repository.DefaultBrokerPoll)
+		notifierOpts = append(notifierOpts, service.WithBroker(broker))
+		gameLoop, err = service.NewLeasedGameLoop(gameLoop, repository.NewSqliteLeaseStore(db), broker, clock,
+			service.WithLeaseOwner(instanceID(cfg)))
+		if err != nil {
+			return err
+		}
+	}
+
+	notifier, err := service.NewGameNotifier(notifierOpts...)
+	if err != nil {
 		return err
 	}
+	expvar.Publish("notifier", expvar.Func(func() any { return notifier.Stats() }))
+	if cfg.DebugAddr != "" {
+		// expvar registers itself on the default mux, which the game
+		// server doesn't use, so this stays off the public port.
+		go func() {
+			log.Println(http.ListenAndServe(cfg.DebugAddr, nil))
+		}()
+	}
 
-	localIP := getLocalIP()
-	fmt.Println("server running on http://localhost:9000...")
-	if localIP != "" {
-		fmt.Printf("server running on http://%s:9000...\n", localIP)
+	uc := usecase.NewEmojixUsecase(
+		repository.NewUserRepository(db),
+		repository.NewGameRepository(db),
+		repository.NewWordRepository(db),
+		repository.NewUnitOfWorkFactory(db),
+		notifier,
+		gameLoop,
+		clock,
+		usecase.WithPresence(presence),
+		usecase.WithGameDefaults(cfg.GameDefaults),
+		usecase.WithRateLimits(cfg.RateLimits),
+	)
+	if err := uc.RecoverGames(ctx); err != nil {
+		log.Printf("failed to recover running games: %v", err)
+	}
+	if cfg.Broker == "sqlite" {
+		// pick up games whose instance died once their lease lapses
+		go uc.WatchGames(ctx, service.DefaultLeaseTTL)
 	}
 
-	db, err := repository.InitSqliteDB(*dbName)
+	srv, err := emojix.NewWebServer(uc, emojix.NewHTMLView(), cfg)
 	if err != nil {
 		return err
 	}
+	return srv.Start(ctx, cfg.Addr, cfg.ShutdownTimeout)
+}
 
-	emojix.NewWebServer(
-		usecase.NewEmojixUsecase(
-			repository.NewUserRepository(db),
-			repository.NewGameRepository(db),
-			repository.NewWordRepository(db),
-			repository.NewUnitOfWorkFactory(db),
-			service.NewGameNotifier(),
-			service.NewGameLoop(service.NewRealClock()),
-			service.NewRealClock(),
-		),
-		emojix.NewHTMLView(),
-	).Start()
-	return nil
+// instanceID is cfg.InstanceID, or else the host name and listen address,
+// which stay put when the same instance restarts.
+func instanceID(cfg config.Config) string {
+	if cfg.InstanceID != "" {
+		return cfg.InstanceID
+	}
+	host, _ := os.Hostname()
+	return host + "/" + cfg.Addr
 }
 
+// getLocalIP returns the first non-loopback IPv4 address, so the banner
+// can show a URL other devices on the LAN can open.
 func getLocalIP() string {
-	conn, err := net.Dial("udp", "8.8.8.8:80")
+	addrs, err := net.InterfaceAddrs()
 	if err != nil {
-		log.Println(err)
 		return ""
 	}
-	defer conn.Close()
-	return conn.LocalAddr().(*net.UDPAddr).IP.String()
+	for _, a := range addrs {
+		ipNet, ok := a.(*net.IPNet)
+		if !ok || ipNet.IP.IsLoopback() {
+			continue
+		}
+		if ip := ipNet.IP.To4(); ip != nil {
+			return ip.String()
+		}
+	}
+	return ""
 }
@@ -0,0 +1,353 @@
+// Package config holds the server settings. Load fills them from defaults,
+// an optional env file, EMOJIX_* environment variables and command-line
+// flags, each overriding the one before.
+package config
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"emojix/model"
+	"emojix/service"
+	"emojix/usecase"
+)
+
+// FileEnv names the env file to read when -config is not given.
+const FileEnv = "EMOJIX_CONFIG"
+
+// MinSessionKeyLen is the shortest session key accepted, in bytes.
+const MinSessionKeyLen = 32
+
+type Config struct {
+	Addr string // listen address
+	// BaseURL is the public origin share links point at, e.g.
+	// https://emojix.example; empty means whatever the browser is on.
+	BaseURL string
+	// SecureCookies marks the session cookies Secure; turn it on behind HTTPS.
+	SecureCookies bool
+	// SessionKeys sign session tokens. The first signs new ones; the rest
+	// still verify, so a key can be rotated out without logging everyone
+	// off. Empty means a random key per process.
+	SessionKeys []string
+	DBPath      string
+	StaticDir   string // served under /static/
+	LogLevel    slog.Level
+	// GameDefaults fill whatever a room's creator leaves unset.
+	GameDefaults model.GameSettings
+	// RateLimits cap how fast each player may guess and chat in a game.
+	RateLimits usecase.RateLimits
+	// InstanceID names this server in the shared game leases. It must differ
+	// between instances running side by side and stay the same across a
+	// restart; empty means the host name and Addr.
+	InstanceID string
+	// Broker is "local" when one process serves everything, or "sqlite" for
+	// instances sharing DBPath.
+	Broker string
+	// SSEQueue is how many events a stream may fall behind before
+	// SSEOverflow decides what to do with it.
+	SSEQueue    int
+	SSEOverflow service.OverflowPolicy
+	// DebugAddr serves /debug/vars when set; keep it off the public port.
+	DebugAddr string
+	// ShutdownTimeout bounds the wait for turn ends and requests on
+	// SIGINT/SIGTERM.
+	ShutdownTimeout time.Duration
+}
+
+// Default is the configuration used when nothing is set.
+func Default() Config {
+	return Config{
+		Addr:            "0.0.0.0:9000",
+		DBPath:          "emojix.db",
+		StaticDir:       "static",
+		LogLevel:        slog.LevelInfo,
+		GameDefaults:    usecase.DefaultGameSettings(),
+		RateLimits:      usecase.DefaultRateLimits(),
+		Broker:          "local",
+		SSEQueue:        service.DefaultQueueSize,
+		SSEOverflow:     service.Disconnect,
+		ShutdownTimeout: 10 * time.Second,
+	}
+}
+
+// setting is one configurable field, reachable as flag and as env variable.
+type setting struct {
+	flag, env, usage string
+	set              func(c *Config, v string) error
+	boolean          bool // flag may be given without a value
+}
+
+var settings = []setting{
+	{"addr", "EMOJIX_ADDR", "listen address", func(c *Config, v string) error {
+		c.Addr = v
+		return nil
+	}, false},
+	{"base-url", "EMOJIX_BASE_URL", "public origin for share links, e.g. https://emojix.example", func(c *Config, v string) error {
+		c.BaseURL = strings.TrimSuffix(v, "/")
+		return nil
+	}, false},
+	{"secure-cookies", "EMOJIX_SECURE_COOKIES", "mark session cookies Secure (serving over HTTPS)", func(c *Config, v string) error {
+		return parseInto(&c.SecureCookies, v, strconv.ParseBool)
+	}, true},
+	{"session-keys", "EMOJIX_SESSION_KEYS", "comma-separated session signing keys, newest first", func(c *Config, v string) error {
+		c.SessionKeys = nil
+		for _, k := range strings.Split(v, ",") {
+			if k = strings.TrimSpace(k); k != "" {
+				c.SessionKeys = append(c.SessionKeys, k)
+			}
+		}
+		return nil
+	}, false},
+	{"db", "EMOJIX_DB", "sqlite file", func(c *Config, v string) error {
+		c.DBPath = v
+		return nil
+	}, false},
+	{"static-dir", "EMOJIX_STATIC_DIR", "directory served under /static/", func(c *Config, v string) error {
+		c.StaticDir = v
+		return nil
+	}, false},
+	{"log-level", "EMOJIX_LOG_LEVEL", "debug, info, warn or error", func(c *Config, v string) error {
+		return c.LogLevel.UnmarshalText([]byte(v))
+	}, false},
+	{"turn-duration", "EMOJIX_TURN_DURATION", "default turn length", func(c *Config, v string) error {
+		return parseInto(&c.GameDefaults.TurnDuration, v, time.ParseDuration)
+	}, false},
+	{"pick-duration", "EMOJIX_PICK_DURATION", "default time for the teller to pick", func(c *Config, v string) error {
+		return parseInto(&c.GameDefaults.PickDuration, v, time.ParseDuration)
+	}, false},
+	{"min-players", "EMOJIX_MIN_PLAYERS", "default players needed to start", func(c *Config, v string) error {
+		return parseInto(&c.GameDefaults.MinPlayers, v, strconv.Atoi)
+	}, false},
+	{"max-players", "EMOJIX_MAX_PLAYERS", "default room capacity", func(c *Config, v string) error {
+		return parseInto(&c.GameDefaults.MaxPlayers, v, strconv.Atoi)
+	}, false},
+	{"rounds", "EMOJIX_ROUNDS", "default times each player tells", func(c *Config, v string) error {
+		return parseInto(&c.GameDefaults.Rounds, v, strconv.Atoi)
+	}, false},
+	{"scoring", "EMOJIX_SCORING", "default scoring: classic or timed", func(c *Config, v string) error {
+		c.GameDefaults.Scoring = v
+		return nil
+	}, false},
+	{"steal-delay", "EMOJIX_STEAL_DELAY", "default wait before the other team may steal in team games", func(c *Config, v string) error {
+		return parseInto(&c.GameDefaults.StealDelay, v, time.ParseDuration)
+	}, false},
+	{"reveals", "EMOJIX_REVEALS", "default letter reveals as percents of the turn, e.g. 50,80, or off", func(c *Config, v string) error {
+		c.GameDefaults.Reveals = v
+		return nil
+	}, false},
+	{"guess-limit", "EMOJIX_GUESS_LIMIT", "guesses per player as burst/interval, e.g. 5/1s, or off", func(c *Config, v string) error {
+		return parseInto(&c.RateLimits.Guess, v, usecase.ParseRateLimit)
+	}, false},
+	{"message-limit", "EMOJIX_MESSAGE_LIMIT", "chat messages per player as burst/interval, e.g. 5/2s, or off", func(c *Config, v string) error {
+		return parseInto(&c.RateLimits.Message, v, usecase.ParseRateLimit)
+	}, false},
+	{"instance-id", "EMOJIX_INSTANCE_ID", "this server's name in shared game leases (default host name and addr)", func(c *Config, v string) error {
+		c.InstanceID = v
+		return nil
+	}, false},
+	{"wrong-guess-cooldown", "EMOJIX_WRONG_GUESS_COOLDOWN", "wait after each wrong guess, e.g. 2s (0 for none)", func(c *Config, v string) error {
+		return parseInto(&c.RateLimits.WrongGuessCooldown, v, time.ParseDuration)
+	}, false},
+	{"broker", "EMOJIX_BROKER", "event delivery: local (one process) or sqlite (instances sharing -db)", func(c *Config, v string) error {
+		c.Broker = v
+		return nil
+	}, false},
+	{"sse-queue", "EMOJIX_SSE_QUEUE", "events queued per SSE subscriber before the overflow policy applies", func(c *Config, v string) error {
+		return parseInto(&c.SSEQueue, v, strconv.Atoi)
+	}, false},
+	{"sse-overflow", "EMOJIX_SSE_OVERFLOW", "slow SSE subscriber policy: disconnect, drop-oldest or coalesce", func(c *Config, v string) error {
+		return parseInto(&c.SSEOverflow, v, service.ParseOverflowPolicy)
+	}, false},
+	{"debug-addr", "EMOJIX_DEBUG_ADDR", "serve /debug/vars (notifier stats) on this address, e.g. localhost:6060", func(c *Config, v string) error {
+		c.DebugAddr = v
+		return nil
+	}, false},
+	{"shutdown-timeout", "EMOJIX_SHUTDOWN_TIMEOUT", "on SIGINT/SIGTERM, how long to wait for turn ends and requests to finish", func(c *Config, v string) error {
+		return parseInto(&c.ShutdownTimeout, v, time.ParseDuration)
+	}, false},
+}
+
+func parseInto[T any](dst *T, v string, parse func(string) (T, error)) error {
+	parsed, err := parse(v)
+	if err != nil {
+		return err
+	}
+	*dst = parsed
+	return nil
+}
+
+// Load adds the config flags (and -config) to fs, parses args with it and
+// returns the validated result. getenv is os.Getenv outside tests.
+func Load(fs *flag.FlagSet, args []string, getenv func(string) string) (Config, error) {
+	def := Default()
+	fromFlags := map[string]string{}
+	for _, s := range settings {
+		register := fs.Func
+		if s.boolean {
+			register = fs.BoolFunc
+		}
+		register(s.flag, s.usage+" (env "+s.env+")", func(v string) error {
+			fromFlags[s.flag] = v
+			return nil
+		})
+	}
+	file := fs.String("config", "", "env file of EMOJIX_* settings (env "+FileEnv+")")
+	if err := fs.Parse(args); err != nil {
+		return def, err
+	}
+
+	cfg := def
+	if *file == "" {
+		*file = getenv(FileEnv)
+	}
+	if *file != "" {
+		values, err := readEnvFile(*file)
+		if err != nil {
+			return def, err
+		}
+		if err := cfg.apply(func(s setting) (string, bool) {
+			v, ok := values[s.env]
+			return v, ok
+		}, *file); err != nil {
+			return def, err
+		}
+	}
+
+	// ENV=prod predates this package and still means HTTPS.
+	if getenv("ENV") == "prod" {
+		cfg.SecureCookies = true
+	}
+	if err := cfg.apply(func(s setting) (string, bool) {
+		v := getenv(s.env)
+		return v, v != ""
+	}, "environment"); err != nil {
+		return def, err
+	}
+	if err := cfg.apply(func(s setting) (string, bool) {
+		v, ok := fromFlags[s.flag]
+		return v, ok
+	}, "flags"); err != nil {
+		return def, err
+	}
+
+	return cfg, cfg.Validate()
+}
+
+// apply sets every setting lookup has a value for; source names it in errors.
+func (c *Config) apply(lookup func(setting) (string, bool), source string) error {
+	for _, s := range settings {
+		v, ok := lookup(s)
+		if !ok {
+			continue
+		}
+		if err := s.set(c, v); err != nil {
+			return fmt.Errorf("%s: %s (%s=%q): %w", source, s.flag, s.env, v, err)
+		}
+	}
+	return nil
+}
+
+// readEnvFile reads KEY=VALUE lines; blank lines, # comments, an "export "
+// prefix and quotes around the value are allowed. Keys outside EMOJIX_* are
+// left alone so the file can be shared, but a misspelt EMOJIX_ key is an
+// error.
+func readEnvFile(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	values := map[string]string{}
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+		if !ok {
+			return nil, fmt.Errorf("%s:%d: expected KEY=VALUE", path, n)
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			value = unquoted
+		} else if len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'' {
+			value = value[1 : len(value)-1]
+		}
+		if strings.HasPrefix(key, "EMOJIX_") && !slices.ContainsFunc(settings, func(s setting) bool { return s.env == key }) {
+			return nil, fmt.Errorf("%s:%d: unknown setting %s", path, n, key)
+		}
+		values[key] = value
+	}
+	return values, scanner.Err()
+}
+
+// Validate reports the first setting that cannot work.
+func (c Config) Validate() error {
+	if _, _, err := net.SplitHostPort(c.Addr); err != nil {
+		return fmt.Errorf("addr: %w", err)
+	}
+	if c.BaseURL != "" {
+		u, err := url.Parse(c.BaseURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("base-url: %q is not an http(s) origin", c.BaseURL)
+		}
+		if c.SecureCookies && u.Scheme != "https" {
+			return errors.New("secure-cookies: browsers drop Secure cookies on the http base-url")
+		}
+	}
+	for i, k := range c.SessionKeys {
+		if len(k) < MinSessionKeyLen {
+			return fmt.Errorf("session-keys: key %d is shorter than %d bytes", i+1, MinSessionKeyLen)
+		}
+	}
+	if c.DBPath == "" {
+		return errors.New("db: empty path")
+	}
+	if info, err := os.Stat(c.StaticDir); err != nil || !info.IsDir() {
+		return fmt.Errorf("static-dir: %q is not a directory", c.StaticDir)
+	}
+	if err := usecase.ValidateGameSettings(c.GameDefaults); err != nil {
+		return fmt.Errorf("game defaults: %w", err)
+	}
+	if c.GameDefaults.Scoring != usecase.ClassicScoring && c.GameDefaults.Scoring != usecase.TimedScoring {
+		return fmt.Errorf("scoring: unknown policy %q", c.GameDefaults.Scoring)
+	}
+	if c.RateLimits.WrongGuessCooldown < 0 {
+		return errors.New("wrong-guess-cooldown: negative duration")
+	}
+	switch c.Broker {
+	case "local":
+	case "sqlite":
+		if len(c.SessionKeys) == 0 {
+			// each instance would otherwise sign with its own random key
+			return errors.New("broker: sqlite needs session-keys shared by every instance")
+		}
+	default:
+		return fmt.Errorf("broker: unknown broker %q", c.Broker)
+	}
+	if c.SSEQueue < 1 {
+		return fmt.Errorf("sse-queue: %d is not a queue size", c.SSEQueue)
+	}
+	if c.DebugAddr != "" {
+		if _, _, err := net.SplitHostPort(c.DebugAddr); err != nil {
+			return fmt.Errorf("debug-addr: %w", err)
+		}
+	}
+	if c.ShutdownTimeout < 0 {
+		return errors.New("shutdown-timeout: negative duration")
+	}
+	return nil
+}
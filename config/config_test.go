@@ -0,0 +1,205 @@
+package config
+
+import (
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"emojix/service"
+	"emojix/usecase"
+)
+
+func load(t *testing.T, env map[string]string, args ...string) (Config, error) {
+	t.Helper()
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	if _, ok := env["EMOJIX_STATIC_DIR"]; !ok {
+		args = append([]string{"-static-dir", t.TempDir()}, args...)
+	}
+	return Load(fs, args, func(k string) string { return env[k] })
+}
+
+func writeFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "emojix.env")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	cfg, err := load(t, nil)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	def := Default()
+	if cfg.Addr != def.Addr || cfg.DBPath != def.DBPath || cfg.SecureCookies || cfg.GameDefaults != def.GameDefaults || cfg.RateLimits != def.RateLimits {
+		t.Errorf("cfg = %+v, want defaults %+v", cfg, def)
+	}
+}
+
+func TestLoad_Precedence(t *testing.T) {
+	file := writeFile(t, `# shared with other tools
+export EMOJIX_DB="file.db"
+EMOJIX_ADDR=127.0.0.1:1000
+EMOJIX_ROUNDS='3'
+OTHER_TOOL=ignored
+`)
+	env := map[string]string{
+		FileEnv:       file,
+		"EMOJIX_ADDR": "127.0.0.1:2000",
+		"EMOJIX_DB":   "env.db",
+	}
+
+	cfg, err := load(t, env, "-db", "flag.db", "-turn-duration", "45s")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.DBPath != "flag.db" {
+		t.Errorf("DBPath = %q, want flag.db (flag beats env)", cfg.DBPath)
+	}
+	if cfg.Addr != "127.0.0.1:2000" {
+		t.Errorf("Addr = %q, want 127.0.0.1:2000 (env beats file)", cfg.Addr)
+	}
+	if cfg.GameDefaults.Rounds != 3 {
+		t.Errorf("Rounds = %d, want 3 from file", cfg.GameDefaults.Rounds)
+	}
+	if cfg.GameDefaults.TurnDuration != 45*time.Second {
+		t.Errorf("TurnDuration = %v, want 45s", cfg.GameDefaults.TurnDuration)
+	}
+}
+
+func TestLoad_ConfigFlagBeatsEnvFile(t *testing.T) {
+	envFile := writeFile(t, "EMOJIX_DB=env-file.db\n")
+	flagFile := writeFile(t, "EMOJIX_DB=flag-file.db\n")
+
+	cfg, err := load(t, map[string]string{FileEnv: envFile}, "-config", flagFile)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.DBPath != "flag-file.db" {
+		t.Errorf("DBPath = %q, want flag-file.db", cfg.DBPath)
+	}
+}
+
+func TestLoad_LegacyProdEnv(t *testing.T) {
+	cfg, err := load(t, map[string]string{"ENV": "prod"})
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if !cfg.SecureCookies {
+		t.Error("ENV=prod should turn on SecureCookies")
+	}
+
+	cfg, err = load(t, map[string]string{"ENV": "prod"}, "-secure-cookies=false")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.SecureCookies {
+		t.Error("-secure-cookies=false should override ENV=prod")
+	}
+}
+
+func TestLoad_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		args []string
+		want string
+	}{
+		{"unknown key in file", map[string]string{FileEnv: "FILE:EMOJIX_ROUND=3\n"}, nil, "unknown setting EMOJIX_ROUND"},
+		{"malformed line", map[string]string{FileEnv: "FILE:EMOJIX_DB\n"}, nil, "expected KEY=VALUE"},
+		{"missing file", nil, []string{"-config", "/nonexistent/emojix.env"}, "no such file"},
+		{"bad duration", map[string]string{"EMOJIX_TURN_DURATION": "soon"}, nil, "turn-duration"},
+		{"bad log level", nil, []string{"-log-level", "loud"}, "log-level"},
+		{"short session key", nil, []string{"-session-keys", strings.Repeat("k", 32) + ",short"}, "key 2 is shorter"},
+		{"bad addr", nil, []string{"-addr", "9000"}, "addr"},
+		{"relative base url", nil, []string{"-base-url", "emojix.example"}, "base-url"},
+		{"secure cookies over http", nil, []string{"-base-url", "http://emojix.example", "-secure-cookies"}, "secure-cookies"},
+		{"missing static dir", map[string]string{"EMOJIX_STATIC_DIR": "/nonexistent"}, nil, "static-dir"},
+		{"min above max", nil, []string{"-min-players", "5", "-max-players", "3"}, "game defaults"},
+		{"steal delay past the turn", nil, []string{"-turn-duration", "30s", "-steal-delay", "45s"}, "game defaults"},
+		{"unknown scoring", nil, []string{"-scoring", "golf"}, "scoring"},
+		{"falling reveals", nil, []string{"-reveals", "80,50"}, "game defaults"},
+		{"bad guess limit", nil, []string{"-guess-limit", "5"}, "guess-limit"},
+		{"negative cooldown", map[string]string{"EMOJIX_WRONG_GUESS_COOLDOWN": "-1s"}, nil, "wrong-guess-cooldown"},
+		{"unknown broker", nil, []string{"-broker", "redis"}, "broker"},
+		{"shared broker without session keys", map[string]string{"EMOJIX_BROKER": "sqlite"}, nil, "session-keys"},
+		{"empty sse queue", nil, []string{"-sse-queue", "0"}, "sse-queue"},
+		{"unknown sse overflow", nil, []string{"-sse-overflow", "block"}, "sse-overflow"},
+		{"bad debug addr", nil, []string{"-debug-addr", "6060"}, "debug-addr"},
+		{"negative shutdown timeout", nil, []string{"-shutdown-timeout", "-1s"}, "shutdown-timeout"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := map[string]string{}
+			for k, v := range tt.env {
+				if content, ok := strings.CutPrefix(v, "FILE:"); ok {
+					v = writeFile(t, content)
+				}
+				env[k] = v
+			}
+			_, err := load(t, env, tt.args...)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoad_TrimsBaseURLSlash(t *testing.T) {
+	cfg, err := load(t, nil, "-base-url", "https://emojix.example/", "-secure-cookies")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.BaseURL != "https://emojix.example" {
+		t.Errorf("BaseURL = %q", cfg.BaseURL)
+	}
+}
+
+func TestLoad_SessionKeys(t *testing.T) {
+	newKey, oldKey := strings.Repeat("n", 32), strings.Repeat("o", 40)
+	cfg, err := load(t, map[string]string{"EMOJIX_SESSION_KEYS": " " + newKey + ", " + oldKey + ","})
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(cfg.SessionKeys) != 2 || cfg.SessionKeys[0] != newKey || cfg.SessionKeys[1] != oldKey {
+		t.Errorf("SessionKeys = %q, want [new old]", cfg.SessionKeys)
+	}
+}
+
+func TestLoad_RateLimits(t *testing.T) {
+	cfg, err := load(t, map[string]string{"EMOJIX_MESSAGE_LIMIT": "off"}, "-guess-limit", "3/2s", "-wrong-guess-cooldown", "1500ms")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	want := usecase.RateLimits{
+		Guess:              usecase.RateLimit{Burst: 3, Every: 2 * time.Second},
+		WrongGuessCooldown: 1500 * time.Millisecond,
+	}
+	if cfg.RateLimits != want {
+		t.Errorf("RateLimits = %+v, want %+v", cfg.RateLimits, want)
+	}
+}
+
+func TestLoad_ServeSettings(t *testing.T) {
+	file := writeFile(t, "EMOJIX_SSE_OVERFLOW=coalesce\nEMOJIX_SHUTDOWN_TIMEOUT=5s\n")
+	env := map[string]string{
+		FileEnv:               file,
+		"EMOJIX_BROKER":       "sqlite",
+		"EMOJIX_SESSION_KEYS": strings.Repeat("k", 32),
+		"EMOJIX_SSE_QUEUE":    "16",
+	}
+	cfg, err := load(t, env, "-debug-addr", "localhost:6060", "-shutdown-timeout", "20s")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Broker != "sqlite" || cfg.SSEQueue != 16 || cfg.SSEOverflow != service.Coalesce || cfg.DebugAddr != "localhost:6060" || cfg.ShutdownTimeout != 20*time.Second {
+		t.Errorf("cfg = %+v, want the serve settings from file, env and flags", cfg)
+	}
+}
@@ -0,0 +1,82 @@
+package emojix
+
+import (
+	"crypto/hmac"
+	"encoding/base64"
+	"net/http"
+	"strings"
+
+	"emojix/usecase"
+)
+
+// CSRF tokens are a MAC of the session's user id, so they need no storage and
+// stay valid for as long as the session does. Pages carry the token in the
+// base template: HTMX sends it as csrfHeader, plain forms as csrfFormField.
+const (
+	csrfHeader    = "X-CSRF-Token"
+	csrfFormField = "csrf-token"
+)
+
+var errCSRF = usecase.NewError(usecase.KindForbidden, "this page is out of date, reload it and try again")
+
+func (s *sessionSigner) csrfToken(userID string) string {
+	return base64.RawURLEncoding.EncodeToString(s.mac(s.keys[0], "csrf", userID))
+}
+
+// verifyCSRF accepts a token minted by any key, like verify does for
+// sessions.
+func (s *sessionSigner) verifyCSRF(userID, token string) bool {
+	sig, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(sig) == 0 {
+		return false
+	}
+	for _, key := range s.keys {
+		if hmac.Equal(sig, s.mac(key, "csrf", userID)) {
+			return true
+		}
+	}
+	return false
+}
+
+// csrf refuses unsafe requests that ride on the session cookie without the
+// session's token. Requests without a valid session have no one to act as
+// and are left to the handlers; API calls with a bearer token cannot be
+// forged cross-site, since browsers only attach cookies.
+func (e *webServer) csrf(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			next.ServeHTTP(w, r)
+			return
+		}
+		isAPI := strings.HasPrefix(r.URL.Path, apiPrefix+"/")
+		if isAPI && strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ") {
+			next.ServeHTTP(w, r)
+			return
+		}
+		c, err := r.Cookie(sessionCookieKey)
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		userID, _, err := e.sessions.verify(c.Value)
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		token := r.Header.Get(csrfHeader)
+		if token == "" && !isAPI {
+			token = r.PostFormValue(csrfFormField)
+		}
+		if !e.sessions.verifyCSRF(userID, token) {
+			if isAPI {
+				writeAPIError(w, http.StatusForbidden, "csrf", errCSRF.Error())
+				return
+			}
+			e.handleError(w, r, errCSRF, "csrf check failed")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,107 @@
+package emojix
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCSRF(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	form := func(method, path, body string) *http.Request {
+		r := newReq(method, path, strings.NewReader(body))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		return r
+	}
+
+	for _, tc := range []struct {
+		name string
+		req  func() *http.Request
+		want int
+	}{
+		{"GET needs no token", func() *http.Request {
+			return withSession(newReq("GET", "/game/g1", nil), "u1")
+		}, http.StatusNoContent},
+		{"POST without a token", func() *http.Request {
+			return withSession(form("POST", "/game/g1/guess", "content=apple"), "u1")
+		}, http.StatusForbidden},
+		{"POST with the header", func() *http.Request {
+			r := withSession(form("POST", "/game/g1/guess", "content=apple"), "u1")
+			r.Header.Set(csrfHeader, testSessions.csrfToken("u1"))
+			return r
+		}, http.StatusNoContent},
+		{"POST with the form field", func() *http.Request {
+			return withSession(form("POST", "/game/new", "list-id=l1&csrf-token="+testSessions.csrfToken("u1")), "u1")
+		}, http.StatusNoContent},
+		{"POST with another user's token", func() *http.Request {
+			r := withSession(form("POST", "/game/g1/guess", "content=apple"), "u1")
+			r.Header.Set(csrfHeader, testSessions.csrfToken("u2"))
+			return r
+		}, http.StatusForbidden},
+		{"session cookie is not a token", func() *http.Request {
+			r := withSession(form("POST", "/game/g1/guess", "content=apple"), "u1")
+			r.Header.Set(csrfHeader, testSessions.sign("u1"))
+			return r
+		}, http.StatusForbidden},
+		{"no session is left to the handler", func() *http.Request {
+			return form("POST", "/game/new", "list-id=l1")
+		}, http.StatusNoContent},
+		{"API with a bearer token", func() *http.Request {
+			r := newReq("POST", "/api/v1/games/g1/guess", strings.NewReader(`{"content":"apple"}`))
+			r.Header.Set("Authorization", "Bearer "+testSessions.sign("u1"))
+			return withSession(r, "u1")
+		}, http.StatusNoContent},
+		{"API riding on the cookie", func() *http.Request {
+			return withSession(newReq("POST", "/api/v1/games/g1/guess", strings.NewReader(`{"content":"apple"}`)), "u1")
+		}, http.StatusForbidden},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := newServer(newMockUsecase(), &MockView{})
+			w := httptest.NewRecorder()
+
+			srv.csrf(ok).ServeHTTP(w, tc.req())
+
+			if w.Code != tc.want {
+				t.Errorf("status = %d, want %d", w.Code, tc.want)
+			}
+		})
+	}
+}
+
+func TestCSRF_HTMXMismatchRetargetsFragment(t *testing.T) {
+	view := &MockView{}
+	srv := newServer(newMockUsecase(), view)
+
+	r := withSession(newReq("POST", "/game/g1/message", strings.NewReader("content=hi")), "u1")
+	r.Header.Set("HX-Request", "true")
+	r.Header.Set(csrfHeader, "stale")
+	w := httptest.NewRecorder()
+
+	srv.mux().ServeHTTP(w, r)
+
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("status = %d, want 403", w.Code)
+	}
+	if w.Header().Get("HX-Retarget") != errorTarget || view.renderErrorFragmentCalls != 1 {
+		t.Errorf("want the error fragment retargeted at %s", errorTarget)
+	}
+	if p := view.renderErrorFragmentLastParam; p.Message != errCSRF.Error() {
+		t.Errorf("fragment message = %q, want %q", p.Message, errCSRF.Error())
+	}
+}
+
+func TestCSRF_TokenSurvivesKeyRotation(t *testing.T) {
+	oldKey := "old-session-key-0123456789abcdefgh"
+	before, _ := newSessionSigner([]string{oldKey})
+	after, _ := newSessionSigner([]string{"new-session-key-0123456789abcdefgh", oldKey})
+
+	if !after.verifyCSRF("u1", before.csrfToken("u1")) {
+		t.Error("token minted by a still-listed key was refused")
+	}
+	if after.verifyCSRF("u1", "") {
+		t.Error("empty token accepted")
+	}
+}
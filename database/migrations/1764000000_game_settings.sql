@@ -0,0 +1,5 @@
+-- Per-room rules chosen on POST /game/new. Defaults match the old constants.
+ALTER TABLE games ADD COLUMN turn_duration_ms INT NOT NULL DEFAULT 60000;
+ALTER TABLE games ADD COLUMN pick_duration_ms INT NOT NULL DEFAULT 10000;
+ALTER TABLE games ADD COLUMN min_players INT NOT NULL DEFAULT 2;
+ALTER TABLE games ADD COLUMN max_players INT NOT NULL DEFAULT 10;
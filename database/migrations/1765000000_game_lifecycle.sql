@@ -0,0 +1,6 @@
+-- Game lifecycle: lobby -> playing -> finished. rounds = times each player tells
+-- (0 on pre-existing rows means "use the default").
+ALTER TABLE games ADD COLUMN status TEXT NOT NULL DEFAULT 'lobby';
+ALTER TABLE games ADD COLUMN rounds INT NOT NULL DEFAULT 0;
+-- Set once on the first "play again" so every player lands in the same rematch.
+ALTER TABLE games ADD COLUMN next_game_id TEXT REFERENCES games(id);
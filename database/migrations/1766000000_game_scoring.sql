@@ -0,0 +1,2 @@
+-- ScoringPolicy name per game; existing games keep the original formula.
+ALTER TABLE games ADD COLUMN scoring TEXT NOT NULL DEFAULT 'classic';
@@ -0,0 +1,11 @@
+-- Undo stack for the teller's hint board: each Append/Replace pushes the board
+-- as it was before the edit; UndoTurnHint pops the newest row.
+CREATE TABLE IF NOT EXISTS turn_hint_history (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	turn_id TEXT NOT NULL,
+	previous_hint TEXT NOT NULL,
+	created_at INT NOT NULL,
+	FOREIGN KEY (turn_id) REFERENCES game_turns(id)
+);
+
+CREATE INDEX IF NOT EXISTS turn_hint_history_turn_id ON turn_hint_history (turn_id);
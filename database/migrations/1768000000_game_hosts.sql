@@ -0,0 +1,11 @@
+-- Host-run rooms: host_id is the creator until TransferHost, invite_code is a
+-- short shareable code, private rooms only admit new players through it.
+ALTER TABLE games ADD COLUMN host_id TEXT REFERENCES users(id);
+
+ALTER TABLE games ADD COLUMN invite_code TEXT;
+
+ALTER TABLE games ADD COLUMN private INT NOT NULL DEFAULT 0;
+
+ALTER TABLE games ADD COLUMN locked INT NOT NULL DEFAULT 0;
+
+CREATE UNIQUE INDEX IF NOT EXISTS games_invite_code ON games (invite_code);
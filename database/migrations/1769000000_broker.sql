@@ -0,0 +1,21 @@
+-- Cross-instance messaging for `serve -broker sqlite`: every instance polls
+-- broker_messages for rows past the last id it saw; rows are pruned after a
+-- while. game_leases records which instance drives each game's loop.
+CREATE TABLE IF NOT EXISTS broker_messages (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	topic TEXT NOT NULL,
+	game_id TEXT NOT NULL,
+	except_user TEXT NOT NULL DEFAULT '',
+	only_user TEXT NOT NULL DEFAULT '',
+	type TEXT NOT NULL,
+	data TEXT NOT NULL,
+	created_at INT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS broker_messages_created_at ON broker_messages (created_at);
+
+CREATE TABLE IF NOT EXISTS game_leases (
+	game_id TEXT PRIMARY KEY,
+	owner TEXT NOT NULL,
+	expires_at INT NOT NULL
+);
@@ -0,0 +1,6 @@
+-- Turn timers, so a restarted server can resume them: the teller must pick by
+-- pick_deadline, play ends by end_deadline (set on pick), ended_at is set
+-- once the turn's end has been handled.
+ALTER TABLE game_turns ADD COLUMN pick_deadline INT;
+ALTER TABLE game_turns ADD COLUMN end_deadline INT;
+ALTER TABLE game_turns ADD COLUMN ended_at INT;
@@ -0,0 +1,2 @@
+-- A single emoji shown next to the nickname; empty until the player picks one.
+ALTER TABLE users ADD COLUMN avatar TEXT NOT NULL DEFAULT '';
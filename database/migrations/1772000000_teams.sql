@@ -0,0 +1,6 @@
+-- Team mode: red and blue teams alternate tellers; the other team may steal
+-- after steal_delay_ms. players.team is kept when a player drops, so they
+-- rejoin on the same side.
+ALTER TABLE games ADD COLUMN teams INT NOT NULL DEFAULT 0;
+ALTER TABLE games ADD COLUMN steal_delay_ms INT NOT NULL DEFAULT 0;
+ALTER TABLE players ADD COLUMN team TEXT NOT NULL DEFAULT '';
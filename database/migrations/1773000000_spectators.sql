@@ -0,0 +1,3 @@
+-- Spectators watch without playing. queued_at orders the ones waiting for a
+-- seat (unix micros); 0 means not queued.
+ALTER TABLE players ADD COLUMN queued_at INT NOT NULL DEFAULT 0;
@@ -0,0 +1,5 @@
+-- Letter reveals: games.reveals is the schedule as comma-separated percents
+-- of the turn ('' takes the server default, 'off' none); game_turns.revealed
+-- the rune positions unmasked so far, in reveal order.
+ALTER TABLE games ADD COLUMN reveals TEXT NOT NULL DEFAULT '';
+ALTER TABLE game_turns ADD COLUMN revealed TEXT NOT NULL DEFAULT '';
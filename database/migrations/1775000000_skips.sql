@@ -0,0 +1,12 @@
+-- Turn skips: game_turns.rerolled marks the teller's one reroll of the word
+-- options; turn_skip_votes holds each guesser's vote to skip the turn.
+ALTER TABLE game_turns ADD COLUMN rerolled INT NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS turn_skip_votes (
+	turn_id TEXT NOT NULL,
+	player_id TEXT NOT NULL,
+	created_at INT NOT NULL,
+	PRIMARY KEY (turn_id, player_id),
+	FOREIGN KEY (turn_id) REFERENCES game_turns(id),
+	FOREIGN KEY (player_id) REFERENCES users(id)
+);
@@ -0,0 +1,3 @@
+-- word_lists.owner_id is the user who created the list and alone may edit
+-- it. Lists from before, seeded or imported, have none and are read-only.
+ALTER TABLE word_lists ADD COLUMN owner_id TEXT REFERENCES users(id);
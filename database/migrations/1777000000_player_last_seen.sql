@@ -0,0 +1,3 @@
+-- last_seen_at is the latest beat (unix micros) of any live connection a
+-- player has to the game, on whichever instance holds it; 0 means never.
+ALTER TABLE players ADD COLUMN last_seen_at INT NOT NULL DEFAULT 0;
@@ -0,0 +1,9 @@
+-- game_seq numbers each game's events, which clients resume from with
+-- Last-Event-ID. broker_game_seqs keeps the counters, as the messages
+-- themselves are pruned.
+ALTER TABLE broker_messages ADD COLUMN game_seq INT NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS broker_game_seqs (
+	game_id TEXT PRIMARY KEY,
+	seq INT NOT NULL
+);
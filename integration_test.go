@@ -6,9 +6,11 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"testing"
 
+	"emojix/config"
 	"emojix/repository"
 	"emojix/service"
 	"emojix/usecase"
@@ -54,7 +56,10 @@ func newE2EServer(t *testing.T) (*httptest.Server, *http.Client) {
 	gameRepo := repository.NewGameRepository(db)
 	wordRepo := repository.NewWordRepository(db)
 	unitOfWorkFactory := repository.NewUnitOfWorkFactory(db)
-	gameNotifier := service.NewGameNotifier()
+	gameNotifier, err := service.NewGameNotifier()
+	if err != nil {
+		t.Fatal(err)
+	}
 	gameLoop := service.NewGameLoop(service.NewRealClock())
 	t.Cleanup(gameLoop.Stop)
 
@@ -68,7 +73,10 @@ func newE2EServer(t *testing.T) (*httptest.Server, *http.Client) {
 		service.NewRealClock(),
 	)
 
-	srv := &webServer{view: NewHTMLView(), emojixUsecase: uc, kickDelay: defaultKickDelay}
+	srv, err := NewWebServer(uc, NewHTMLView(), config.Default())
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	ts := httptest.NewServer(srv.mux())
 	t.Cleanup(ts.Close)
@@ -82,7 +90,9 @@ func newE2EServer(t *testing.T) (*httptest.Server, *http.Client) {
 	return ts, client
 }
 
-func doWithCookies(t *testing.T, client *http.Client, method, urlStr string, body io.Reader, cookies []*http.Cookie) *http.Response {
+// doWithCookies sends csrf (the token initSession scraped) as a browser
+// would; GETs pass "".
+func doWithCookies(t *testing.T, client *http.Client, method, urlStr string, body io.Reader, cookies []*http.Cookie, csrf string) *http.Response {
 	t.Helper()
 	req, err := http.NewRequest(method, urlStr, body)
 	if err != nil {
@@ -94,6 +104,9 @@ func doWithCookies(t *testing.T, client *http.Client, method, urlStr string, bod
 	if body != nil {
 		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 	}
+	if csrf != "" {
+		req.Header.Set(csrfHeader, csrf)
+	}
 	resp, err := client.Do(req)
 	if err != nil {
 		t.Fatalf("%s %s: %v", method, urlStr, err)
@@ -101,7 +114,7 @@ func doWithCookies(t *testing.T, client *http.Client, method, urlStr string, bod
 	return resp
 }
 
-func initSession(t *testing.T, ts *httptest.Server, client *http.Client) (cookies []*http.Cookie, nickname string) {
+func initSession(t *testing.T, ts *httptest.Server, client *http.Client) (cookies []*http.Cookie, nickname, csrf string) {
 	t.Helper()
 	resp, err := client.Get(ts.URL + "/init")
 	if err != nil {
@@ -112,15 +125,23 @@ func initSession(t *testing.T, ts *httptest.Server, client *http.Client) (cookie
 		t.Fatalf("GET /init status = %d, want 302", resp.StatusCode)
 	}
 	cookies = resp.Cookies()
-	for _, c := range cookies {
-		if c.Name == nicknameCookieKey {
-			nickname = c.Value
-		}
+
+	// The nickname lives server-side now; read it off the home page.
+	resp = doWithCookies(t, client, "GET", ts.URL+"/", nil, cookies, "")
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := regexp.MustCompile(`Welcome, <em>([^<]+)</em>`).FindSubmatch(body)
+	if m == nil {
+		t.Fatalf("GET / status = %d, no nickname on the page", resp.StatusCode)
 	}
-	if nickname == "" {
-		t.Fatal("nickname cookie not set")
+	tok := regexp.MustCompile(`<meta name="csrf-token" content="([^"]+)"`).FindSubmatch(body)
+	if tok == nil {
+		t.Fatal("GET / has no csrf token")
 	}
-	return cookies, nickname
+	return cookies, string(m[1]), string(tok[1])
 }
 
 // TestE2EInitNewGameGuessFlow drives a full session through the real stack:
@@ -129,11 +150,11 @@ func TestE2EInitNewGameGuessFlow(t *testing.T) {
 	ts, client := newE2EServer(t)
 
 	// 1. Host session.
-	hostCookies, hostNick := initSession(t, ts, client)
+	hostCookies, hostNick, hostCSRF := initSession(t, ts, client)
 
 	// 2. Create a game with list l1.
 	form := url.Values{"list-id": {"l1"}}
-	resp := doWithCookies(t, client, "POST", ts.URL+"/game/new", strings.NewReader(form.Encode()), hostCookies)
+	resp := doWithCookies(t, client, "POST", ts.URL+"/game/new", strings.NewReader(form.Encode()), hostCookies, hostCSRF)
 	resp.Body.Close()
 	if resp.StatusCode != http.StatusSeeOther {
 		t.Fatalf("POST /game/new status = %d, want 303", resp.StatusCode)
@@ -144,7 +165,7 @@ func TestE2EInitNewGameGuessFlow(t *testing.T) {
 	}
 
 	// 3. Solo host waits for a second player — no pick UI yet.
-	resp = doWithCookies(t, client, "GET", ts.URL+gamePath, nil, hostCookies)
+	resp = doWithCookies(t, client, "GET", ts.URL+gamePath, nil, hostCookies, "")
 	body, err := io.ReadAll(resp.Body)
 	resp.Body.Close()
 	if err != nil {
@@ -162,15 +183,15 @@ func TestE2EInitNewGameGuessFlow(t *testing.T) {
 	}
 
 	// 4. Guesser joins — this starts the game.
-	guesserCookies, guesserNick := initSession(t, ts, client)
-	resp = doWithCookies(t, client, "GET", ts.URL+gamePath+"/join", nil, guesserCookies)
+	guesserCookies, guesserNick, guesserCSRF := initSession(t, ts, client)
+	resp = doWithCookies(t, client, "GET", ts.URL+gamePath+"/join", nil, guesserCookies, "")
 	resp.Body.Close()
 	if resp.StatusCode != http.StatusFound {
 		t.Fatalf("join status = %d, want 302", resp.StatusCode)
 	}
 
 	// 5. Host (teller by join order) sees pick UI with 3 options.
-	resp = doWithCookies(t, client, "GET", ts.URL+gamePath, nil, hostCookies)
+	resp = doWithCookies(t, client, "GET", ts.URL+gamePath, nil, hostCookies, "")
 	body, err = io.ReadAll(resp.Body)
 	resp.Body.Close()
 	if err != nil {
@@ -189,14 +210,14 @@ func TestE2EInitNewGameGuessFlow(t *testing.T) {
 
 	// 6. Host picks Apple.
 	form = url.Values{"word-id": {"w1"}}
-	resp = doWithCookies(t, client, "POST", ts.URL+gamePath+"/pick", strings.NewReader(form.Encode()), hostCookies)
+	resp = doWithCookies(t, client, "POST", ts.URL+gamePath+"/pick", strings.NewReader(form.Encode()), hostCookies, hostCSRF)
 	resp.Body.Close()
 	if resp.StatusCode != http.StatusSeeOther {
 		t.Fatalf("POST pick status = %d, want 303", resp.StatusCode)
 	}
 
 	// 7. Guesser sees masked word + hint, not the plain word.
-	resp = doWithCookies(t, client, "GET", ts.URL+gamePath, nil, guesserCookies)
+	resp = doWithCookies(t, client, "GET", ts.URL+gamePath, nil, guesserCookies, "")
 	body, err = io.ReadAll(resp.Body)
 	resp.Body.Close()
 	if err != nil {
@@ -221,7 +242,7 @@ func TestE2EInitNewGameGuessFlow(t *testing.T) {
 
 	// 7. Guesser guesses correctly.
 	form = url.Values{"content": {"apple"}}
-	resp = doWithCookies(t, client, "POST", ts.URL+gamePath+"/guess", strings.NewReader(form.Encode()), guesserCookies)
+	resp = doWithCookies(t, client, "POST", ts.URL+gamePath+"/guess", strings.NewReader(form.Encode()), guesserCookies, guesserCSRF)
 	resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
 		t.Fatalf("POST guess status = %d, want 200", resp.StatusCode)
@@ -231,7 +252,7 @@ func TestE2EInitNewGameGuessFlow(t *testing.T) {
 	}
 
 	// 8. Only guesser needed to finish (teller excluded) → inline waiting, no loading exile.
-	resp = doWithCookies(t, client, "GET", ts.URL+gamePath, nil, guesserCookies)
+	resp = doWithCookies(t, client, "GET", ts.URL+gamePath, nil, guesserCookies, "")
 	body, err = io.ReadAll(resp.Body)
 	resp.Body.Close()
 	if err != nil {
@@ -248,7 +269,7 @@ func TestE2EInitNewGameGuessFlow(t *testing.T) {
 	}
 
 	// 9. Leaderboard reflects the score.
-	resp = doWithCookies(t, client, "GET", ts.URL+gamePath+"/leaderboard", nil, guesserCookies)
+	resp = doWithCookies(t, client, "GET", ts.URL+gamePath+"/leaderboard", nil, guesserCookies, "")
 	body, err = io.ReadAll(resp.Body)
 	resp.Body.Close()
 	if err != nil {
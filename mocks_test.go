@@ -5,7 +5,9 @@ import (
 	"emojix/model"
 	"emojix/usecase"
 	"io"
+	"strconv"
 	"sync"
+	"time"
 )
 
 // MockEmojixUsecase is a per-method func-field mock of usecase.EmojixUsecase.
@@ -28,24 +30,57 @@ type MockEmojixUsecase struct {
 	GetUserCalls      int
 	GetUserLastUserID string
 
-	InitGameFn         func(ctx context.Context, userID string, listID string) (model.Game, error)
-	InitGameCalls      int
-	InitGameLastUserID string
-	InitGameLastListID string
+	UpdateProfileFn           func(ctx context.Context, userID, nickname, avatar string) (model.User, error)
+	UpdateProfileCalls        int
+	UpdateProfileLastUserID   string
+	UpdateProfileLastNickname string
+	UpdateProfileLastAvatar   string
+
+	InitGameFn           func(ctx context.Context, userID string, listID string, settings model.GameSettings) (model.Game, error)
+	InitGameCalls        int
+	InitGameLastUserID   string
+	InitGameLastListID   string
+	InitGameLastSettings model.GameSettings
 
 	ListWordListsFn    func(ctx context.Context) ([]model.WordList, error)
 	ListWordListsCalls int
 
+	GetWordListFn    func(ctx context.Context, listID string) (model.WordList, []model.Word, error)
+	GetWordListCalls int
+
+	CreateWordListFn        func(ctx context.Context, userID, title string) (model.WordList, error)
+	CreateWordListCalls     int
+	CreateWordListLastTitle string
+
+	AddWordFn         func(ctx context.Context, userID, listID, word, hint string) (model.Word, error)
+	AddWordCalls      int
+	AddWordLastUserID string
+	AddWordLastListID string
+	AddWordLastWord   string
+	AddWordLastHint   string
+
+	UpdateWordFn         func(ctx context.Context, userID, wordID, word, hint string) error
+	UpdateWordCalls      int
+	UpdateWordLastWordID string
+
+	DeleteWordFn         func(ctx context.Context, userID, wordID string) error
+	DeleteWordCalls      int
+	DeleteWordLastWordID string
+
+	ImportWordsFn    func(ctx context.Context, params usecase.ImportParams) (usecase.ImportReport, error)
+	ImportWordsCalls int
+
 	PickWordFn         func(ctx context.Context, gameID, userID, wordID string) error
 	PickWordCalls      int
 	PickWordLastGameID string
 	PickWordLastUserID string
 	PickWordLastWordID string
 
-	JoinGameFn         func(ctx context.Context, gameID string, userID string) error
-	JoinGameCalls      int
-	JoinGameLastGameID string
-	JoinGameLastUserID string
+	JoinGameFn           func(ctx context.Context, gameID string, userID string) error
+	JoinGameCalls        int
+	JoinGameLastGameID   string
+	JoinGameLastUserID   string
+	JoinGameLastSpectate bool
 
 	GuessFn         func(ctx context.Context, gameID string, userID string, word string) (bool, error)
 	GuessCalls      int
@@ -64,15 +99,18 @@ type MockEmojixUsecase struct {
 	GameStateLastGameID string
 	GameStateLastUserID string
 
-	GameUpdatesFn         func(ctx context.Context, gameID string, userID string, handler usecase.GameUpdateHandler) error
-	GameUpdatesCalls      int
-	GameUpdatesLastGameID string
-	GameUpdatesLastUserID string
+	GameUpdatesFn          func(ctx context.Context, gameID string, userID string, lastEventID uint64, handler usecase.GameUpdateHandler) error
+	GameUpdatesCalls       int
+	GameUpdatesLastGameID  string
+	GameUpdatesLastUserID  string
+	GameUpdatesLastEventID uint64
+
+	CheckMemberFn    func(ctx context.Context, gameID, userID string) error
+	CheckMemberCalls int
 
-	KickInactiveUserFn         func(ctx context.Context, gameID, userID string) error
-	KickInactiveUserCalls      int
-	KickInactiveUserLastGameID string
-	KickInactiveUserLastUserID string
+	SetAwayFn       func(ctx context.Context, gameID, userID string, away bool) error
+	SetAwayCalls    int
+	SetAwayLastAway bool
 
 	LeaderboardFn         func(ctx context.Context, gameID, userID string) ([]model.LeaderboardEntry, error)
 	LeaderboardCalls      int
@@ -83,6 +121,58 @@ type MockEmojixUsecase struct {
 	GameWordCalls      int
 	GameWordLastGameID string
 	GameWordLastUserID string
+
+	HintFn         func(ctx context.Context, action, gameID, userID, content string) (string, error)
+	HintCalls      int
+	HintLastAction string
+	HintLastGameID string
+	HintLastUserID string
+	HintLastValue  string
+
+	JoinGameByCodeFn       func(ctx context.Context, code, userID string) (model.Game, error)
+	JoinGameByCodeCalls    int
+	JoinGameByCodeLastCode string
+
+	// HostActionFn backs KickPlayer, LockRoom, TransferHost and SkipTurn, told
+	// apart by action; value is the target player id or "true"/"false".
+	HostActionFn         func(ctx context.Context, action, gameID, hostID, value string) error
+	HostActionCalls      int
+	HostActionLastAction string
+	HostActionLastGameID string
+	HostActionLastHostID string
+	HostActionLastValue  string
+
+	RematchFn         func(ctx context.Context, gameID, userID string) (model.Game, error)
+	RematchCalls      int
+	RematchLastGameID string
+	RematchLastUserID string
+
+	GameHistoryFn         func(ctx context.Context, gameID, userID string) (model.GameHistory, error)
+	GameHistoryCalls      int
+	GameHistoryLastGameID string
+
+	PickTeamFn         func(ctx context.Context, gameID, userID string, team model.Team) error
+	PickTeamCalls      int
+	PickTeamLastGameID string
+	PickTeamLastUserID string
+	PickTeamLastTeam   model.Team
+
+	TeamLeaderboardFn    func(ctx context.Context, gameID, userID string) ([]model.TeamScore, error)
+	TeamLeaderboardCalls int
+
+	SeatFn         func(ctx context.Context, action, gameID, userID string) error
+	SeatCalls      int
+	SeatLastAction string
+	SeatLastGameID string
+	SeatLastUserID string
+
+	// TurnActionFn backs RerollWords, GiveUpTurn and VoteSkip, told apart by
+	// action ("reroll", "giveup" or "voteskip").
+	TurnActionFn         func(ctx context.Context, action, gameID, userID string) error
+	TurnActionCalls      int
+	TurnActionLastAction string
+	TurnActionLastGameID string
+	TurnActionLastUserID string
 }
 
 func newMockUsecase() *MockEmojixUsecase {
@@ -95,12 +185,36 @@ func newMockUsecase() *MockEmojixUsecase {
 		// Default: session user exists so existing handler tests keep working.
 		return model.User{ID: userID}, nil
 	}
-	m.InitGameFn = func(ctx context.Context, userID string, listID string) (model.Game, error) {
+	m.InitGameFn = func(ctx context.Context, userID string, listID string, settings model.GameSettings) (model.Game, error) {
 		return model.Game{}, nil
 	}
 	m.ListWordListsFn = func(ctx context.Context) ([]model.WordList, error) {
 		return nil, nil
 	}
+	m.GetWordListFn = func(ctx context.Context, listID string) (model.WordList, []model.Word, error) {
+		return model.WordList{ID: listID}, nil, nil
+	}
+	m.CreateWordListFn = func(ctx context.Context, userID, title string) (model.WordList, error) {
+		return model.WordList{Title: title}, nil
+	}
+	m.AddWordFn = func(ctx context.Context, userID, listID, word, hint string) (model.Word, error) {
+		return model.Word{ListID: listID, Word: word, Hint: hint}, nil
+	}
+	m.UpdateWordFn = func(ctx context.Context, userID, wordID, word, hint string) error {
+		return nil
+	}
+	m.DeleteWordFn = func(ctx context.Context, userID, wordID string) error {
+		return nil
+	}
+	m.ImportWordsFn = func(ctx context.Context, params usecase.ImportParams) (usecase.ImportReport, error) {
+		return usecase.ImportReport{}, nil
+	}
+	m.JoinGameByCodeFn = func(ctx context.Context, code, userID string) (model.Game, error) {
+		return model.Game{}, nil
+	}
+	m.HostActionFn = func(ctx context.Context, action, gameID, hostID, value string) error {
+		return nil
+	}
 	m.PickWordFn = func(ctx context.Context, gameID, userID, wordID string) error {
 		return nil
 	}
@@ -116,18 +230,45 @@ func newMockUsecase() *MockEmojixUsecase {
 	m.GameStateFn = func(ctx context.Context, gameID, userID string) (model.GameState, error) {
 		return model.GameState{}, nil
 	}
-	m.GameUpdatesFn = func(ctx context.Context, gameID, userID string, handler usecase.GameUpdateHandler) error {
+	m.GameUpdatesFn = func(ctx context.Context, gameID, userID string, lastEventID uint64, handler usecase.GameUpdateHandler) error {
 		return nil
 	}
-	m.KickInactiveUserFn = func(ctx context.Context, gameID, userID string) error {
+	m.CheckMemberFn = func(ctx context.Context, gameID, userID string) error {
 		return nil
 	}
+	m.SetAwayFn = func(ctx context.Context, gameID, userID string, away bool) error {
+		return nil
+	}
+	m.UpdateProfileFn = func(ctx context.Context, userID, nickname, avatar string) (model.User, error) {
+		return model.User{ID: userID, Nickname: nickname, Avatar: avatar}, nil
+	}
 	m.LeaderboardFn = func(ctx context.Context, gameID, userID string) ([]model.LeaderboardEntry, error) {
 		return nil, nil
 	}
 	m.GameWordFn = func(ctx context.Context, gameID, userID string) (string, error) {
 		return "", nil
 	}
+	m.PickTeamFn = func(ctx context.Context, gameID, userID string, team model.Team) error {
+		return nil
+	}
+	m.TeamLeaderboardFn = func(ctx context.Context, gameID, userID string) ([]model.TeamScore, error) {
+		return nil, nil
+	}
+	m.SeatFn = func(ctx context.Context, action, gameID, userID string) error {
+		return nil
+	}
+	m.TurnActionFn = func(ctx context.Context, action, gameID, userID string) error {
+		return nil
+	}
+	m.RematchFn = func(ctx context.Context, gameID, userID string) (model.Game, error) {
+		return model.Game{}, nil
+	}
+	m.GameHistoryFn = func(ctx context.Context, gameID, userID string) (model.GameHistory, error) {
+		return model.GameHistory{GameID: gameID}, nil
+	}
+	m.HintFn = func(ctx context.Context, action, gameID, userID, content string) (string, error) {
+		return "", nil
+	}
 	return m
 }
 
@@ -147,13 +288,14 @@ func (m *MockEmojixUsecase) GetUser(ctx context.Context, userID string) (model.U
 	return m.GetUserFn(ctx, userID)
 }
 
-func (m *MockEmojixUsecase) InitGame(ctx context.Context, userID string, listID string) (model.Game, error) {
+func (m *MockEmojixUsecase) InitGame(ctx context.Context, userID string, listID string, settings model.GameSettings) (model.Game, error) {
 	m.mu.Lock()
 	m.InitGameCalls++
 	m.InitGameLastUserID = userID
 	m.InitGameLastListID = listID
+	m.InitGameLastSettings = settings
 	m.mu.Unlock()
-	return m.InitGameFn(ctx, userID, listID)
+	return m.InitGameFn(ctx, userID, listID, settings)
 }
 
 func (m *MockEmojixUsecase) ListWordLists(ctx context.Context) ([]model.WordList, error) {
@@ -163,6 +305,55 @@ func (m *MockEmojixUsecase) ListWordLists(ctx context.Context) ([]model.WordList
 	return m.ListWordListsFn(ctx)
 }
 
+func (m *MockEmojixUsecase) GetWordList(ctx context.Context, listID string) (model.WordList, []model.Word, error) {
+	m.mu.Lock()
+	m.GetWordListCalls++
+	m.mu.Unlock()
+	return m.GetWordListFn(ctx, listID)
+}
+
+func (m *MockEmojixUsecase) CreateWordList(ctx context.Context, userID, title string) (model.WordList, error) {
+	m.mu.Lock()
+	m.CreateWordListCalls++
+	m.CreateWordListLastTitle = title
+	m.mu.Unlock()
+	return m.CreateWordListFn(ctx, userID, title)
+}
+
+func (m *MockEmojixUsecase) AddWord(ctx context.Context, userID, listID, word, hint string) (model.Word, error) {
+	m.mu.Lock()
+	m.AddWordCalls++
+	m.AddWordLastUserID = userID
+	m.AddWordLastListID = listID
+	m.AddWordLastWord = word
+	m.AddWordLastHint = hint
+	m.mu.Unlock()
+	return m.AddWordFn(ctx, userID, listID, word, hint)
+}
+
+func (m *MockEmojixUsecase) UpdateWord(ctx context.Context, userID, wordID, word, hint string) error {
+	m.mu.Lock()
+	m.UpdateWordCalls++
+	m.UpdateWordLastWordID = wordID
+	m.mu.Unlock()
+	return m.UpdateWordFn(ctx, userID, wordID, word, hint)
+}
+
+func (m *MockEmojixUsecase) DeleteWord(ctx context.Context, userID, wordID string) error {
+	m.mu.Lock()
+	m.DeleteWordCalls++
+	m.DeleteWordLastWordID = wordID
+	m.mu.Unlock()
+	return m.DeleteWordFn(ctx, userID, wordID)
+}
+
+func (m *MockEmojixUsecase) ImportWords(ctx context.Context, params usecase.ImportParams) (usecase.ImportReport, error) {
+	m.mu.Lock()
+	m.ImportWordsCalls++
+	m.mu.Unlock()
+	return m.ImportWordsFn(ctx, params)
+}
+
 func (m *MockEmojixUsecase) PickWord(ctx context.Context, gameID, userID, wordID string) error {
 	m.mu.Lock()
 	m.PickWordCalls++
@@ -173,11 +364,12 @@ func (m *MockEmojixUsecase) PickWord(ctx context.Context, gameID, userID, wordID
 	return m.PickWordFn(ctx, gameID, userID, wordID)
 }
 
-func (m *MockEmojixUsecase) JoinGame(ctx context.Context, gameID string, userID string) error {
+func (m *MockEmojixUsecase) JoinGame(ctx context.Context, gameID string, userID string, spectate bool) error {
 	m.mu.Lock()
 	m.JoinGameCalls++
 	m.JoinGameLastGameID = gameID
 	m.JoinGameLastUserID = userID
+	m.JoinGameLastSpectate = spectate
 	m.mu.Unlock()
 	return m.JoinGameFn(ctx, gameID, userID)
 }
@@ -211,22 +403,39 @@ func (m *MockEmojixUsecase) GameState(ctx context.Context, gameID string, userID
 	return m.GameStateFn(ctx, gameID, userID)
 }
 
-func (m *MockEmojixUsecase) GameUpdates(ctx context.Context, gameID string, userID string, handler usecase.GameUpdateHandler) error {
+func (m *MockEmojixUsecase) GameUpdates(ctx context.Context, gameID string, userID string, lastEventID uint64, handler usecase.GameUpdateHandler) error {
 	m.mu.Lock()
 	m.GameUpdatesCalls++
 	m.GameUpdatesLastGameID = gameID
 	m.GameUpdatesLastUserID = userID
+	m.GameUpdatesLastEventID = lastEventID
 	m.mu.Unlock()
-	return m.GameUpdatesFn(ctx, gameID, userID, handler)
+	return m.GameUpdatesFn(ctx, gameID, userID, lastEventID, handler)
 }
 
-func (m *MockEmojixUsecase) KickInactiveUser(ctx context.Context, gameID, userID string) error {
+func (m *MockEmojixUsecase) CheckMember(ctx context.Context, gameID, userID string) error {
 	m.mu.Lock()
-	m.KickInactiveUserCalls++
-	m.KickInactiveUserLastGameID = gameID
-	m.KickInactiveUserLastUserID = userID
+	m.CheckMemberCalls++
 	m.mu.Unlock()
-	return m.KickInactiveUserFn(ctx, gameID, userID)
+	return m.CheckMemberFn(ctx, gameID, userID)
+}
+
+func (m *MockEmojixUsecase) UpdateProfile(ctx context.Context, userID, nickname, avatar string) (model.User, error) {
+	m.mu.Lock()
+	m.UpdateProfileCalls++
+	m.UpdateProfileLastUserID = userID
+	m.UpdateProfileLastNickname = nickname
+	m.UpdateProfileLastAvatar = avatar
+	m.mu.Unlock()
+	return m.UpdateProfileFn(ctx, userID, nickname, avatar)
+}
+
+func (m *MockEmojixUsecase) SetAway(ctx context.Context, gameID, userID string, away bool) error {
+	m.mu.Lock()
+	m.SetAwayCalls++
+	m.SetAwayLastAway = away
+	m.mu.Unlock()
+	return m.SetAwayFn(ctx, gameID, userID, away)
 }
 
 func (m *MockEmojixUsecase) Leaderboard(ctx context.Context, gameID, userID string) ([]model.LeaderboardEntry, error) {
@@ -238,6 +447,65 @@ func (m *MockEmojixUsecase) Leaderboard(ctx context.Context, gameID, userID stri
 	return m.LeaderboardFn(ctx, gameID, userID)
 }
 
+func (m *MockEmojixUsecase) PickTeam(ctx context.Context, gameID, userID string, team model.Team) error {
+	m.mu.Lock()
+	m.PickTeamCalls++
+	m.PickTeamLastGameID = gameID
+	m.PickTeamLastUserID = userID
+	m.PickTeamLastTeam = team
+	m.mu.Unlock()
+	return m.PickTeamFn(ctx, gameID, userID, team)
+}
+
+func (m *MockEmojixUsecase) TeamLeaderboard(ctx context.Context, gameID, userID string) ([]model.TeamScore, error) {
+	m.mu.Lock()
+	m.TeamLeaderboardCalls++
+	m.mu.Unlock()
+	return m.TeamLeaderboardFn(ctx, gameID, userID)
+}
+
+// seat records calls for Spectate and Play, which share SeatFn and are told
+// apart by action ("spectate" or "play").
+func (m *MockEmojixUsecase) seat(ctx context.Context, action, gameID, userID string) error {
+	m.mu.Lock()
+	m.SeatCalls++
+	m.SeatLastAction = action
+	m.SeatLastGameID = gameID
+	m.SeatLastUserID = userID
+	m.mu.Unlock()
+	return m.SeatFn(ctx, action, gameID, userID)
+}
+
+func (m *MockEmojixUsecase) Spectate(ctx context.Context, gameID, userID string) error {
+	return m.seat(ctx, "spectate", gameID, userID)
+}
+
+func (m *MockEmojixUsecase) Play(ctx context.Context, gameID, userID string) error {
+	return m.seat(ctx, "play", gameID, userID)
+}
+
+func (m *MockEmojixUsecase) turnAction(ctx context.Context, action, gameID, userID string) error {
+	m.mu.Lock()
+	m.TurnActionCalls++
+	m.TurnActionLastAction = action
+	m.TurnActionLastGameID = gameID
+	m.TurnActionLastUserID = userID
+	m.mu.Unlock()
+	return m.TurnActionFn(ctx, action, gameID, userID)
+}
+
+func (m *MockEmojixUsecase) RerollWords(ctx context.Context, gameID, userID string) error {
+	return m.turnAction(ctx, "reroll", gameID, userID)
+}
+
+func (m *MockEmojixUsecase) GiveUpTurn(ctx context.Context, gameID, userID string) error {
+	return m.turnAction(ctx, "giveup", gameID, userID)
+}
+
+func (m *MockEmojixUsecase) VoteSkip(ctx context.Context, gameID, userID string) error {
+	return m.turnAction(ctx, "voteskip", gameID, userID)
+}
+
 func (m *MockEmojixUsecase) GameWord(ctx context.Context, gameID, userID string) (string, error) {
 	m.mu.Lock()
 	m.GameWordCalls++
@@ -247,6 +515,97 @@ func (m *MockEmojixUsecase) GameWord(ctx context.Context, gameID, userID string)
 	return m.GameWordFn(ctx, gameID, userID)
 }
 
+func (m *MockEmojixUsecase) Rematch(ctx context.Context, gameID, userID string) (model.Game, error) {
+	m.mu.Lock()
+	m.RematchCalls++
+	m.RematchLastGameID = gameID
+	m.RematchLastUserID = userID
+	m.mu.Unlock()
+	return m.RematchFn(ctx, gameID, userID)
+}
+
+func (m *MockEmojixUsecase) GameHistory(ctx context.Context, gameID, userID string) (model.GameHistory, error) {
+	m.mu.Lock()
+	m.GameHistoryCalls++
+	m.GameHistoryLastGameID = gameID
+	m.mu.Unlock()
+	return m.GameHistoryFn(ctx, gameID, userID)
+}
+
+// RecoverGames, WatchGames and Shutdown are only called around serving, never
+// by a handler.
+func (m *MockEmojixUsecase) RecoverGames(ctx context.Context) error {
+	return nil
+}
+
+func (m *MockEmojixUsecase) WatchGames(ctx context.Context, every time.Duration) {}
+
+func (m *MockEmojixUsecase) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+// hint records calls for the three hint-board methods, which share HintFn
+// and are told apart by action ("append", "replace" or "undo").
+func (m *MockEmojixUsecase) hint(ctx context.Context, action, gameID, userID, content string) (string, error) {
+	m.mu.Lock()
+	m.HintCalls++
+	m.HintLastAction = action
+	m.HintLastGameID = gameID
+	m.HintLastUserID = userID
+	m.HintLastValue = content
+	m.mu.Unlock()
+	return m.HintFn(ctx, action, gameID, userID, content)
+}
+
+func (m *MockEmojixUsecase) AppendHint(ctx context.Context, gameID, userID, emoji string) (string, error) {
+	return m.hint(ctx, "append", gameID, userID, emoji)
+}
+
+func (m *MockEmojixUsecase) ReplaceHint(ctx context.Context, gameID, userID, hint string) (string, error) {
+	return m.hint(ctx, "replace", gameID, userID, hint)
+}
+
+func (m *MockEmojixUsecase) UndoHint(ctx context.Context, gameID, userID string) (string, error) {
+	return m.hint(ctx, "undo", gameID, userID, "")
+}
+
+func (m *MockEmojixUsecase) JoinGameByCode(ctx context.Context, code, userID string) (model.Game, error) {
+	m.mu.Lock()
+	m.JoinGameByCodeCalls++
+	m.JoinGameByCodeLastCode = code
+	m.mu.Unlock()
+	return m.JoinGameByCodeFn(ctx, code, userID)
+}
+
+// hostAction records calls for the host-only methods, which share
+// HostActionFn.
+func (m *MockEmojixUsecase) hostAction(ctx context.Context, action, gameID, hostID, value string) error {
+	m.mu.Lock()
+	m.HostActionCalls++
+	m.HostActionLastAction = action
+	m.HostActionLastGameID = gameID
+	m.HostActionLastHostID = hostID
+	m.HostActionLastValue = value
+	m.mu.Unlock()
+	return m.HostActionFn(ctx, action, gameID, hostID, value)
+}
+
+func (m *MockEmojixUsecase) KickPlayer(ctx context.Context, gameID, hostID, playerID string) error {
+	return m.hostAction(ctx, "kick", gameID, hostID, playerID)
+}
+
+func (m *MockEmojixUsecase) LockRoom(ctx context.Context, gameID, hostID string, locked bool) error {
+	return m.hostAction(ctx, "lock", gameID, hostID, strconv.FormatBool(locked))
+}
+
+func (m *MockEmojixUsecase) TransferHost(ctx context.Context, gameID, hostID, newHostID string) error {
+	return m.hostAction(ctx, "host", gameID, hostID, newHostID)
+}
+
+func (m *MockEmojixUsecase) SkipTurn(ctx context.Context, gameID, hostID string) error {
+	return m.hostAction(ctx, "skip", gameID, hostID, "")
+}
+
 // Compile-time guard.
 var _ usecase.EmojixUsecase = (*MockEmojixUsecase)(nil)
 
@@ -258,15 +617,28 @@ var _ usecase.EmojixUsecase = (*MockEmojixUsecase)(nil)
 type MockView struct {
 	mu sync.Mutex
 
-	renderErrorPageFn     func(wr io.Writer) error
-	renderErrorPageCalls  int
-	renderErrorPageWriter io.Writer
+	renderErrorPageFn        func(wr io.Writer, params ErrorViewParam) error
+	renderErrorPageCalls     int
+	renderErrorPageLastParam ErrorViewParam
+	renderErrorPageWriter    io.Writer
+
+	renderErrorFragmentFn        func(wr io.Writer, params ErrorViewParam) error
+	renderErrorFragmentCalls     int
+	renderErrorFragmentLastParam ErrorViewParam
 
 	renderIndexPageFn        func(wr io.Writer, params IndexPageViewParam) error
 	renderIndexPageCalls     int
 	renderIndexPageLastParam IndexPageViewParam
 	renderIndexPageWriter    io.Writer
 
+	renderListsPageFn        func(wr io.Writer, params ListsPageViewParam) error
+	renderListsPageCalls     int
+	renderListsPageLastParam ListsPageViewParam
+
+	renderListPageFn        func(wr io.Writer, params ListPageViewParam) error
+	renderListPageCalls     int
+	renderListPageLastParam ListPageViewParam
+
 	renderGamePageFn        func(wr io.Writer, params GamePageViewParam) error
 	renderGamePageCalls     int
 	renderGamePageLastParam GamePageViewParam
@@ -286,15 +658,31 @@ type MockView struct {
 	renderGameLeaderboardCalls     int
 	renderGameLeaderboardLastParam GameLeaderboardViewParam
 	renderGameLeaderboardWriter    io.Writer
+
+	renderHistoryPageFn        func(wr io.Writer, params HistoryPageViewParam) error
+	renderHistoryPageCalls     int
+	renderHistoryPageLastParam HistoryPageViewParam
 }
 
-func (m *MockView) renderErrorPage(wr io.Writer) error {
+func (m *MockView) renderErrorPage(wr io.Writer, params ErrorViewParam) error {
 	m.mu.Lock()
 	m.renderErrorPageCalls++
+	m.renderErrorPageLastParam = params
 	m.renderErrorPageWriter = wr
 	m.mu.Unlock()
 	if m.renderErrorPageFn != nil {
-		return m.renderErrorPageFn(wr)
+		return m.renderErrorPageFn(wr, params)
+	}
+	return nil
+}
+
+func (m *MockView) renderErrorFragment(wr io.Writer, params ErrorViewParam) error {
+	m.mu.Lock()
+	m.renderErrorFragmentCalls++
+	m.renderErrorFragmentLastParam = params
+	m.mu.Unlock()
+	if m.renderErrorFragmentFn != nil {
+		return m.renderErrorFragmentFn(wr, params)
 	}
 	return nil
 }
@@ -311,6 +699,39 @@ func (m *MockView) renderIndexPage(wr io.Writer, params IndexPageViewParam) erro
 	return nil
 }
 
+func (m *MockView) renderListsPage(wr io.Writer, params ListsPageViewParam) error {
+	m.mu.Lock()
+	m.renderListsPageCalls++
+	m.renderListsPageLastParam = params
+	m.mu.Unlock()
+	if m.renderListsPageFn != nil {
+		return m.renderListsPageFn(wr, params)
+	}
+	return nil
+}
+
+func (m *MockView) renderListPage(wr io.Writer, params ListPageViewParam) error {
+	m.mu.Lock()
+	m.renderListPageCalls++
+	m.renderListPageLastParam = params
+	m.mu.Unlock()
+	if m.renderListPageFn != nil {
+		return m.renderListPageFn(wr, params)
+	}
+	return nil
+}
+
+func (m *MockView) renderHistoryPage(wr io.Writer, params HistoryPageViewParam) error {
+	m.mu.Lock()
+	m.renderHistoryPageCalls++
+	m.renderHistoryPageLastParam = params
+	m.mu.Unlock()
+	if m.renderHistoryPageFn != nil {
+		return m.renderHistoryPageFn(wr, params)
+	}
+	return nil
+}
+
 func (m *MockView) renderGamePage(wr io.Writer, params GamePageViewParam) error {
 	m.mu.Lock()
 	m.renderGamePageCalls++
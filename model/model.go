@@ -2,17 +2,52 @@ package model
 
 import "time"
 
+type GameStatus = string
+
+var LobbyGameStatus GameStatus = "lobby"
+var PlayingGameStatus GameStatus = "playing"
+var FinishedGameStatus GameStatus = "finished"
+
 type Game struct {
-	ID     string
-	ListID string
+	ID         string
+	ListID     string
+	Settings   GameSettings
+	Status     GameStatus
+	NextGameID string // rematch created from the results page, if any
+	HostID     string // may run host actions; empty for games from before hosts
+	InviteCode string // short code accepted by JoinGameByCode
+	Locked     bool   // host closed the room to new players
 
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
 
+// GameSettings are the per-room rules chosen when the game is created.
+type GameSettings struct {
+	TurnDuration time.Duration
+	PickDuration time.Duration // teller is skipped if they don't pick in time
+	MinPlayers   int           // loop waits (or pauses) below this many active players
+	MaxPlayers   int           // JoinGame rejects beyond this many active players
+	Rounds       int           // game is over once every active player told this many turns
+	Scoring      string        // ScoringPolicy name, see usecase.ClassicScoring
+	Private      bool          // new players need the invite code
+	Teams        bool          // red and blue teams; see Team
+	StealDelay   time.Duration // in team games, how long the other team waits to guess
+	// Reveals is when letters of the word are unmasked, as comma-separated
+	// percents of the turn ("50,80"); "off" for never.
+	Reveals string
+}
+
+// Invite codes skip look-alike characters (0/O, 1/I) so they can be read out.
+const (
+	InviteCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	InviteCodeLength   = 6
+)
+
 type WordList struct {
-	ID    string
-	Title string
+	ID      string
+	Title   string
+	OwnerID string // empty for seeded and imported lists, which nobody edits
 }
 
 type Word struct {
@@ -33,6 +68,13 @@ type GameTurn struct {
 	EmojiHint string // live emoji board; seeded from word.hint on pick
 	CreatedAt time.Time
 	StartedAt time.Time // zero until teller picks
+	// Timer deadlines, kept so a restart can resume the turn. EndDeadline is
+	// zero until the teller picks, EndedAt until the turn is over.
+	PickDeadline time.Time
+	EndDeadline  time.Time
+	EndedAt      time.Time
+	Revealed     []int // rune positions of the word unmasked so far, in order
+	Rerolled     bool  // the teller has swapped the options once
 }
 
 type Score struct {
@@ -48,14 +90,37 @@ type PlayerState = string
 
 var ActivePlayerState PlayerState = "active"
 var InactivePlayerState PlayerState = "inactive"
+var KickedPlayerState PlayerState = "kicked" // removed by the host; may not rejoin
+// SpectatorPlayerState watches the game (stream, board and chat) but neither
+// guesses nor tells.
+var SpectatorPlayerState PlayerState = "spectator"
+
+// Team is a side in a team game. In a team game only the teller's team may
+// guess at first; the other team may steal once GameSettings.StealDelay has
+// passed.
+type Team = string
+
+const (
+	RedTeam  Team = "red"
+	BlueTeam Team = "blue"
+)
+
+// Teams lists the sides in the order they take turns.
+var Teams = []Team{RedTeam, BlueTeam}
 
 type Player struct {
 	ID       string
 	Nickname string
+	Avatar   string
+	Team     Team // empty outside team games
 
 	State string
 
 	JoinedAt time.Time
+	QueuedAt time.Time // spectators waiting for a seat; zero otherwise
+	// LastSeenAt is the latest beat of any connection the player has to the
+	// game, across instances; zero if they never connected.
+	LastSeenAt time.Time
 }
 
 type Message struct {
@@ -69,6 +134,7 @@ type Message struct {
 type User struct {
 	ID       string
 	Nickname string
+	Avatar   string // a single emoji, or empty
 
 	CreatedAt time.Time
 	UpdatedAt time.Time
@@ -77,13 +143,25 @@ type User struct {
 type LeaderboardEntry struct {
 	PlayerID    string
 	Nickname    string
+	Avatar      string
+	Team        Team
 	Me          bool
 	GuessedWord bool
 	IsTeller    bool
 	Score       int
+	Away        bool // connected but the tab is in the background
+}
+
+// TeamScore is one row of the team leaderboard: the sum of its players'
+// points, including players who have since left.
+type TeamScore struct {
+	Team  Team
+	Score int
+	Me    bool // the current player is on this team
 }
 
 type GameStateMessage struct {
+	PlayerID string // empty for system lines
 	Me       bool
 	Content  string
 	Nickname string
@@ -91,6 +169,26 @@ type GameStateMessage struct {
 	IsGuess  bool // wrong-guess line (live response; optional style)
 }
 
+// TurnResult is one row of the final per-turn breakdown.
+type TurnResult struct {
+	TurnID         string
+	TellerNickname string
+	Word           string // empty when the teller never picked
+	Scores         []TurnScore
+}
+
+// HintEdit is one kept edit of a turn's hint board (undone ones are gone).
+type HintEdit struct {
+	TurnID   string
+	Previous string // the board before the edit
+	At       time.Time
+}
+
+type TurnScore struct {
+	Nickname string
+	Score    int
+}
+
 type GameState struct {
 	GameID            string
 	CurrentUserID     string
@@ -99,6 +197,14 @@ type GameState struct {
 	TurnEnded         bool
 	AwaitingPick      bool
 	WaitingForPlayers bool // true until min players join and first turn starts
+	Settings          GameSettings
+	Status            GameStatus
+	HostID            string
+	IsHost            bool
+	InviteCode        string
+	Locked            bool
+	Podium            []LeaderboardEntry // top three, once Status is finished
+	TurnResults       []TurnResult       // oldest first, once Status is finished
 	IsTeller          bool
 	TellerNickname    string
 	WordOptions       []Word // teller-only, while AwaitingPick
@@ -108,4 +214,60 @@ type GameState struct {
 	WordCount         int // whitespace-separated words in the secret
 	Messages          []GameStateMessage
 	Leaderboard       []LeaderboardEntry
+	TeamLeaderboard   []TeamScore // highest first; empty outside team games
+	MyTeam            Team
+	TellerTeam        Team
+	StealOpensAt      time.Time // when the other team may start guessing
+	IsSpectator       bool
+	QueuePosition     int // 1-based place in line for a seat; 0 when not queued
+	SpectatorCount    int
+	CanReroll         bool // teller, while AwaitingPick and not yet rerolled
+	SkipVotes         int  // guessers voting to skip the current turn
+	SkipVotesNeeded   int  // majority of active guessers
+	VotedSkip         bool
+}
+
+// GameHistory is a finished game read back turn by turn, for the history
+// page and its replay.
+type GameHistory struct {
+	GameID  string
+	Podium  []LeaderboardEntry
+	Turns   []TurnHistory // oldest first
+	StartAt time.Time     // when the first turn was dealt
+}
+
+// TurnHistory is one turn as it was played.
+type TurnHistory struct {
+	TurnID         string
+	TellerID       string
+	TellerNickname string
+	Word           string   // empty when the teller never picked
+	Passed         []string // options the teller did not pick
+	Hint           string   // the hint board as the turn ended
+	CreatedAt      time.Time
+	StartedAt      time.Time // zero when the teller never picked
+	EndedAt        time.Time
+	Events         []HistoryEvent // oldest first
+	Scores         []TurnScore    // points per player over the turn
+}
+
+type HistoryEventKind = string
+
+const (
+	DealtHistoryEvent   HistoryEventKind = "dealt"   // options dealt to the teller
+	PickedHistoryEvent  HistoryEventKind = "picked"  // the teller picked Content
+	HintHistoryEvent    HistoryEventKind = "hint"    // a teller chat message, or the board after an edit
+	GuessHistoryEvent   HistoryEventKind = "guess"   // a guesser's message
+	TurnEndHistoryEvent HistoryEventKind = "turnend" // time ran out or all guessed
+)
+
+// HistoryEvent is one moment of a turn, in the order the replay shows it.
+type HistoryEvent struct {
+	Kind     HistoryEventKind
+	At       time.Time
+	PlayerID string
+	Nickname string
+	Content  string
+	Correct  bool        // a guess that scored
+	Scores   []TurnScore // points the event moved; negative for penalties
 }
@@ -3,10 +3,17 @@ package repository
 import (
 	"context"
 	"emojix/model"
+	"errors"
+	"time"
 )
 
+// ErrWordInUse is returned by UpdateWord and DeleteWord for a word already
+// dealt in a game.
+var ErrWordInUse = errors.New("word is used by a game")
+
 type UserCreateOrUpdateParams struct {
 	Nickname string
+	Avatar   string
 }
 
 type UserRepository interface {
@@ -20,22 +27,78 @@ type AddTurnParams struct {
 	OptionA  string
 	OptionB  string
 	OptionC  string
+	// PickDeadline is when the teller's pick phase times out.
+	PickDeadline time.Time
 }
 
 type GameRepository interface {
 	FindByID(ctx context.Context, id string) (model.Game, error)
-	Create(ctx context.Context, listID string) (model.Game, error)
+	Create(ctx context.Context, listID string, settings model.GameSettings) (model.Game, error)
+	// FindByInviteCode returns sql.ErrNoRows for an unknown code.
+	FindByInviteCode(ctx context.Context, code string) (model.Game, error)
+	SetStatus(ctx context.Context, gameID string, status model.GameStatus) error
+	SetHost(ctx context.Context, gameID string, userID string) error
+	SetLocked(ctx context.Context, gameID string, locked bool) error
+	// ClaimNextGame records nextGameID as the rematch of gameID unless one is
+	// already set, and returns whichever id won.
+	ClaimNextGame(ctx context.Context, gameID string, nextGameID string) (string, error)
+	// ListPlayingIDs returns the ids of every game still being played.
+	ListPlayingIDs(ctx context.Context) ([]string, error)
+	// ListOpenGameIDs returns the unfinished games userID has a seat in
+	// (any state but kicked).
+	ListOpenGameIDs(ctx context.Context, userID string) ([]string, error)
 
 	// Players/Users
 	AddPlayer(ctx context.Context, gameID string, userID string) error
 	SetPlayerState(ctx context.Context, gameID string, userID string, state model.PlayerState) error
+	SetPlayerTeam(ctx context.Context, gameID string, userID string, team model.Team) error
+	// AddSpectator seats a new userID as a spectator, queued for a seat or
+	// not; SetSpectator moves an existing one to the stands, queued or not.
+	// SetPlayerState takes a player out of the queue.
+	AddSpectator(ctx context.Context, gameID string, userID string, queued bool) error
+	SetSpectator(ctx context.Context, gameID string, userID string, queued bool) error
+	// SeatPlayer seats userID, new to gameID or back from inactive, unless
+	// gameID already has maxPlayers active; like PromoteSpectator it counts
+	// and seats in one statement. It reports whether it seated them.
+	SeatPlayer(ctx context.Context, gameID string, userID string, maxPlayers int) (bool, error)
+	// PromoteSpectator seats a queued spectator unless gameID already has
+	// maxPlayers active, counting and seating in one statement so concurrent
+	// promotions cannot overfill the room. It reports whether it seated them.
+	PromoteSpectator(ctx context.Context, gameID string, userID string, maxPlayers int) (bool, error)
 	GetPlayers(ctx context.Context, gameID string) ([]model.Player, error)
+	// SeenPlayer records that userID had a live connection to gameID at at.
+	SeenPlayer(ctx context.Context, gameID string, userID string, at time.Time) error
 
 	GetLatestTurn(ctx context.Context, gameID string) (model.GameTurn, error)
+	// GetTurns returns every turn of gameID, oldest first.
+	GetTurns(ctx context.Context, gameID string) ([]model.GameTurn, error)
 	AddTurn(ctx context.Context, params AddTurnParams) (model.GameTurn, error)
-	// SetTurnWord assigns the picked word and seeds emoji_hint (typically word.Hint).
-	SetTurnWord(ctx context.Context, turnID string, wordID string, emojiHint string) error
+	// SetTurnWord assigns the picked word, seeds emoji_hint (typically
+	// word.Hint) and records when play ends.
+	SetTurnWord(ctx context.Context, turnID string, wordID string, emojiHint string, endDeadline time.Time) error
+	// EndTurn stamps gameID's running turn, if any, as ended at endedAt.
+	EndTurn(ctx context.Context, gameID string, endedAt time.Time) error
 	CountTurns(ctx context.Context, gameID string) (int, error)
+	// SetTurnRevealed stores the positions of the turn's word unmasked so far.
+	SetTurnRevealed(ctx context.Context, turnID string, revealed []int) error
+	// RerollTurnOptions swaps the options of a turn still awaiting its pick,
+	// once. It returns sql.ErrNoRows if the turn was picked or rerolled.
+	RerollTurnOptions(ctx context.Context, turnID, optionA, optionB, optionC string) error
+	// AddSkipVote records playerID's vote to skip the turn; voting twice is
+	// a no-op.
+	AddSkipVote(ctx context.Context, turnID, playerID string) error
+	// GetSkipVotes returns the ids of players who voted to skip the turn.
+	GetSkipVotes(ctx context.Context, turnID string) ([]string, error)
+	// AppendTurnHint adds emoji to the end of the turn's board and returns the
+	// new board. Append and Replace push the previous board for UndoTurnHint.
+	AppendTurnHint(ctx context.Context, turnID string, emoji string) (string, error)
+	ReplaceTurnHint(ctx context.Context, turnID string, hint string) (string, error)
+	// UndoTurnHint restores the board from before the latest edit. With
+	// nothing to undo it returns the current board unchanged.
+	UndoTurnHint(ctx context.Context, turnID string) (string, error)
+	// GetHintEdits returns the board edits still on the undo stacks of
+	// gameID's turns, oldest first.
+	GetHintEdits(ctx context.Context, gameID string) ([]model.HintEdit, error)
 
 	// Message/Content
 	GetMessages(ctx context.Context, gameID string) ([]model.Message, error)
@@ -50,6 +113,17 @@ type WordRepository interface {
 	// GetUnusedByList returns words in listID not yet played (word_id set) in gameID.
 	GetUnusedByList(ctx context.Context, listID, gameID string) ([]model.Word, error)
 	FindByID(ctx context.Context, id string) (model.Word, error)
+
+	FindListByID(ctx context.Context, id string) (model.WordList, error)
+	// GetByList returns every word of listID ordered by word.
+	GetByList(ctx context.Context, listID string) ([]model.Word, error)
+	// CreateList makes a list editable by ownerID; empty means by nobody.
+	CreateList(ctx context.Context, title, ownerID string) (model.WordList, error)
+	AddWord(ctx context.Context, listID, word, hint string) (model.Word, error)
+	// UpdateWord and DeleteWord change a word unless a game turn dealt it
+	// (ErrWordInUse): turns and game history resolve words by id.
+	UpdateWord(ctx context.Context, id, word, hint string) error
+	DeleteWord(ctx context.Context, id string) error
 }
 
 type UnitOfWorkFactory interface {
@@ -57,7 +131,9 @@ type UnitOfWorkFactory interface {
 }
 
 type UnitOfWork interface {
+	UserRepository() UserRepository
 	GameRepository() GameRepository
+	WordRepository() WordRepository
 
 	Rollback() error
 	Commit() error
@@ -12,44 +12,128 @@ import (
 	"context"
 	"emojix/model"
 	"emojix/repository"
+	"time"
 )
 
 type MockGameRepository struct {
 	repository.GameRepository
 	FindByIDMock         func(ctx context.Context, id string) (model.Game, error)
-	CreateMock           func(ctx context.Context, listID string) (model.Game, error)
+	CreateMock           func(ctx context.Context, listID string, settings model.GameSettings) (model.Game, error)
 	CreateCalled         bool
+	SetStatusMock        func(ctx context.Context, gameID string, status model.GameStatus) error
+	SetStatusCalled      bool
+	SetStatusLastStatus  model.GameStatus
+	FindByInviteCodeMock func(ctx context.Context, code string) (model.Game, error)
+	SetHostMock          func(ctx context.Context, gameID, userID string) error
+	SetHostLastUserID    string
+	SetLockedMock        func(ctx context.Context, gameID string, locked bool) error
+	SetLockedCalled      bool
+	ClaimNextGameMock    func(ctx context.Context, gameID, nextGameID string) (string, error)
 	GetPlayersMock       func(ctx context.Context, id string) ([]model.Player, error)
 	GetMessagesMock      func(ctx context.Context, id string) ([]model.Message, error)
 	GetScoresMock        func(ctx context.Context, id string) ([]model.Score, error)
 	GetLatestTurnMock    func(ctx context.Context, id string) (model.GameTurn, error)
+	GetTurnsMock         func(ctx context.Context, gameID string) ([]model.GameTurn, error)
 	AddTurnMock          func(ctx context.Context, params repository.AddTurnParams) (model.GameTurn, error)
 	AddTurnCalled        bool
-	SetTurnWordMock      func(ctx context.Context, turnID, wordID, emojiHint string) error
+	SetTurnWordMock      func(ctx context.Context, turnID, wordID, emojiHint string, endDeadline time.Time) error
 	SetTurnWordCalled    bool
 	SetTurnWordLastHint  string
+	EndTurnMock          func(ctx context.Context, gameID string, endedAt time.Time) error
+	EndTurnCalled        bool
+	ListPlayingIDsMock   func(ctx context.Context) ([]string, error)
+	ListOpenGameIDsMock  func(ctx context.Context, userID string) ([]string, error)
 	CountTurnsMock       func(ctx context.Context, gameID string) (int, error)
+	AppendTurnHintMock   func(ctx context.Context, turnID, emoji string) (string, error)
+	ReplaceTurnHintMock  func(ctx context.Context, turnID, hint string) (string, error)
+	UndoTurnHintMock     func(ctx context.Context, turnID string) (string, error)
 	SendMessageMock      func(ctx context.Context, gameID string, turnID string, userID string, content string) (model.Message, error)
 	SendMessageCalled    bool
 	AddPlayerMock        func(ctx context.Context, id string, playerID string) error
 	AddPlayerCalled      bool
 	SetPlayerStateMock   func(ctx context.Context, gameID, userID string, state model.PlayerState) error
 	SetPlayerStateCalled bool
+	SetPlayerTeamMock    func(ctx context.Context, gameID, userID string, team model.Team) error
+	SetPlayerTeamCalled  bool
+	SeenPlayerMock       func(ctx context.Context, gameID, userID string, at time.Time) error
+	SeenPlayerCalled     bool
+	SetTurnRevealedMock  func(ctx context.Context, turnID string, revealed []int) error
+	RerollOptionsMock    func(ctx context.Context, turnID, optionA, optionB, optionC string) error
+	AddSkipVoteMock      func(ctx context.Context, turnID, playerID string) error
+	AddSkipVoteCalled    bool
+	GetSkipVotesMock     func(ctx context.Context, turnID string) ([]string, error)
+	GetHintEditsMock     func(ctx context.Context, gameID string) ([]model.HintEdit, error)
+	AddSpectatorMock     func(ctx context.Context, gameID, userID string, queued bool) error
+	AddSpectatorCalled   bool
+	SetSpectatorMock     func(ctx context.Context, gameID, userID string, queued bool) error
+	SetSpectatorCalled   bool
+	PromoteSpectatorMock func(ctx context.Context, gameID, userID string, maxPlayers int) (bool, error)
+	SeatPlayerMock       func(ctx context.Context, gameID, userID string, maxPlayers int) (bool, error)
+	SeatPlayerCalled     bool
 	AddScoreMock         func(ctx context.Context, gameID string, userID string, messageID string, turnID string, score int) error
 	AddScoreCalled       bool
 }
 
+// FindByID defaults to a bare game (zero settings, i.e. usecase defaults) so
+// tests that don't care about room rules need not wire it.
 func (m *MockGameRepository) FindByID(ctx context.Context, id string) (model.Game, error) {
-	return m.FindByIDMock(ctx, id)
+	if m.FindByIDMock != nil {
+		return m.FindByIDMock(ctx, id)
+	}
+	return model.Game{ID: id}, nil
 }
 
-func (m *MockGameRepository) Create(ctx context.Context, listID string) (model.Game, error) {
+func (m *MockGameRepository) Create(ctx context.Context, listID string, settings model.GameSettings) (model.Game, error) {
 	m.CreateCalled = true
-	return m.CreateMock(ctx, listID)
+	return m.CreateMock(ctx, listID, settings)
 }
 
+func (m *MockGameRepository) FindByInviteCode(ctx context.Context, code string) (model.Game, error) {
+	return m.FindByInviteCodeMock(ctx, code)
+}
+
+// SetHost records the new host and defaults to success, so InitGame tests
+// need not wire it.
+func (m *MockGameRepository) SetHost(ctx context.Context, gameID, userID string) error {
+	m.SetHostLastUserID = userID
+	if m.SetHostMock != nil {
+		return m.SetHostMock(ctx, gameID, userID)
+	}
+	return nil
+}
+
+func (m *MockGameRepository) SetLocked(ctx context.Context, gameID string, locked bool) error {
+	m.SetLockedCalled = true
+	return m.SetLockedMock(ctx, gameID, locked)
+}
+
+func (m *MockGameRepository) SetStatus(ctx context.Context, gameID string, status model.GameStatus) error {
+	m.SetStatusCalled = true
+	m.SetStatusLastStatus = status
+	if m.SetStatusMock != nil {
+		return m.SetStatusMock(ctx, gameID, status)
+	}
+	return nil
+}
+
+func (m *MockGameRepository) ClaimNextGame(ctx context.Context, gameID, nextGameID string) (string, error) {
+	return m.ClaimNextGameMock(ctx, gameID, nextGameID)
+}
+
+// GetPlayers defaults to an empty room so tests that don't look at the
+// roster need not wire it.
 func (m *MockGameRepository) GetPlayers(ctx context.Context, id string) ([]model.Player, error) {
-	return m.GetPlayersMock(ctx, id)
+	if m.GetPlayersMock != nil {
+		return m.GetPlayersMock(ctx, id)
+	}
+	return []model.Player{}, nil
+}
+func (m *MockGameRepository) SeenPlayer(ctx context.Context, gameID, userID string, at time.Time) error {
+	m.SeenPlayerCalled = true
+	if m.SeenPlayerMock != nil {
+		return m.SeenPlayerMock(ctx, gameID, userID, at)
+	}
+	return nil
 }
 func (m *MockGameRepository) GetMessages(ctx context.Context, id string) ([]model.Message, error) {
 	return m.GetMessagesMock(ctx, id)
@@ -60,24 +144,58 @@ func (m *MockGameRepository) GetScores(ctx context.Context, id string) ([]model.
 func (m *MockGameRepository) GetLatestTurn(ctx context.Context, id string) (model.GameTurn, error) {
 	return m.GetLatestTurnMock(ctx, id)
 }
+func (m *MockGameRepository) GetTurns(ctx context.Context, gameID string) ([]model.GameTurn, error) {
+	if m.GetTurnsMock != nil {
+		return m.GetTurnsMock(ctx, gameID)
+	}
+	return nil, nil
+}
 func (m *MockGameRepository) AddTurn(ctx context.Context, params repository.AddTurnParams) (model.GameTurn, error) {
 	m.AddTurnCalled = true
 	return m.AddTurnMock(ctx, params)
 }
-func (m *MockGameRepository) SetTurnWord(ctx context.Context, turnID, wordID, emojiHint string) error {
+func (m *MockGameRepository) SetTurnWord(ctx context.Context, turnID, wordID, emojiHint string, endDeadline time.Time) error {
 	m.SetTurnWordCalled = true
 	m.SetTurnWordLastHint = emojiHint
 	if m.SetTurnWordMock != nil {
-		return m.SetTurnWordMock(ctx, turnID, wordID, emojiHint)
+		return m.SetTurnWordMock(ctx, turnID, wordID, emojiHint, endDeadline)
 	}
 	return nil
 }
+
+// EndTurn defaults to success so onTurnEnd tests need not wire it.
+func (m *MockGameRepository) EndTurn(ctx context.Context, gameID string, endedAt time.Time) error {
+	m.EndTurnCalled = true
+	if m.EndTurnMock != nil {
+		return m.EndTurnMock(ctx, gameID, endedAt)
+	}
+	return nil
+}
+
+func (m *MockGameRepository) ListPlayingIDs(ctx context.Context) ([]string, error) {
+	return m.ListPlayingIDsMock(ctx)
+}
+func (m *MockGameRepository) ListOpenGameIDs(ctx context.Context, userID string) ([]string, error) {
+	if m.ListOpenGameIDsMock != nil {
+		return m.ListOpenGameIDsMock(ctx, userID)
+	}
+	return []string{}, nil
+}
 func (m *MockGameRepository) CountTurns(ctx context.Context, gameID string) (int, error) {
 	if m.CountTurnsMock != nil {
 		return m.CountTurnsMock(ctx, gameID)
 	}
 	return 0, nil
 }
+func (m *MockGameRepository) AppendTurnHint(ctx context.Context, turnID, emoji string) (string, error) {
+	return m.AppendTurnHintMock(ctx, turnID, emoji)
+}
+func (m *MockGameRepository) ReplaceTurnHint(ctx context.Context, turnID, hint string) (string, error) {
+	return m.ReplaceTurnHintMock(ctx, turnID, hint)
+}
+func (m *MockGameRepository) UndoTurnHint(ctx context.Context, turnID string) (string, error) {
+	return m.UndoTurnHintMock(ctx, turnID)
+}
 func (m *MockGameRepository) SendMessage(ctx context.Context, gameID string, turnID string, userID string, content string) (model.Message, error) {
 	m.SendMessageCalled = true
 	return m.SendMessageMock(ctx, gameID, turnID, userID, content)
@@ -90,6 +208,52 @@ func (m *MockGameRepository) SetPlayerState(ctx context.Context, gameID, userID
 	m.SetPlayerStateCalled = true
 	return m.SetPlayerStateMock(ctx, gameID, userID, state)
 }
+func (m *MockGameRepository) SetPlayerTeam(ctx context.Context, gameID, userID string, team model.Team) error {
+	m.SetPlayerTeamCalled = true
+	return m.SetPlayerTeamMock(ctx, gameID, userID, team)
+}
+func (m *MockGameRepository) SetTurnRevealed(ctx context.Context, turnID string, revealed []int) error {
+	return m.SetTurnRevealedMock(ctx, turnID, revealed)
+}
+func (m *MockGameRepository) RerollTurnOptions(ctx context.Context, turnID, optionA, optionB, optionC string) error {
+	return m.RerollOptionsMock(ctx, turnID, optionA, optionB, optionC)
+}
+func (m *MockGameRepository) AddSkipVote(ctx context.Context, turnID, playerID string) error {
+	m.AddSkipVoteCalled = true
+	return m.AddSkipVoteMock(ctx, turnID, playerID)
+}
+
+// GetHintEdits defaults to no edits, leaving the board as dealt.
+func (m *MockGameRepository) GetHintEdits(ctx context.Context, gameID string) ([]model.HintEdit, error) {
+	if m.GetHintEditsMock != nil {
+		return m.GetHintEditsMock(ctx, gameID)
+	}
+	return nil, nil
+}
+
+// GetSkipVotes defaults to no votes so tests that don't look at skips need
+// not wire it.
+func (m *MockGameRepository) GetSkipVotes(ctx context.Context, turnID string) ([]string, error) {
+	if m.GetSkipVotesMock != nil {
+		return m.GetSkipVotesMock(ctx, turnID)
+	}
+	return []string{}, nil
+}
+func (m *MockGameRepository) SeatPlayer(ctx context.Context, gameID, userID string, maxPlayers int) (bool, error) {
+	m.SeatPlayerCalled = true
+	return m.SeatPlayerMock(ctx, gameID, userID, maxPlayers)
+}
+func (m *MockGameRepository) PromoteSpectator(ctx context.Context, gameID, userID string, maxPlayers int) (bool, error) {
+	return m.PromoteSpectatorMock(ctx, gameID, userID, maxPlayers)
+}
+func (m *MockGameRepository) AddSpectator(ctx context.Context, gameID, userID string, queued bool) error {
+	m.AddSpectatorCalled = true
+	return m.AddSpectatorMock(ctx, gameID, userID, queued)
+}
+func (m *MockGameRepository) SetSpectator(ctx context.Context, gameID, userID string, queued bool) error {
+	m.SetSpectatorCalled = true
+	return m.SetSpectatorMock(ctx, gameID, userID, queued)
+}
 func (m *MockGameRepository) AddScore(ctx context.Context, gameID string, userID string, messageID string, turnID string, score int) error {
 	m.AddScoreCalled = true
 	return m.AddScoreMock(ctx, gameID, userID, messageID, turnID, score)
@@ -101,6 +265,13 @@ type MockWordRepository struct {
 	GetListsMock         func(ctx context.Context) ([]model.WordList, error)
 	GetUnusedByListMock  func(ctx context.Context, listID, gameID string) ([]model.Word, error)
 	GetUnusedByListCount int
+	FindListByIDMock     func(ctx context.Context, id string) (model.WordList, error)
+	GetByListMock        func(ctx context.Context, listID string) ([]model.Word, error)
+	CreateListMock       func(ctx context.Context, title, ownerID string) (model.WordList, error)
+	AddWordMock          func(ctx context.Context, listID, word, hint string) (model.Word, error)
+	AddWordCount         int
+	UpdateWordMock       func(ctx context.Context, id, word, hint string) error
+	DeleteWordMock       func(ctx context.Context, id string) error
 }
 
 func (m *MockWordRepository) FindByID(ctx context.Context, id string) (model.Word, error) {
@@ -119,6 +290,34 @@ func (m *MockWordRepository) GetUnusedByList(ctx context.Context, listID, gameID
 	return m.GetUnusedByListMock(ctx, listID, gameID)
 }
 
+func (m *MockWordRepository) FindListByID(ctx context.Context, id string) (model.WordList, error) {
+	return m.FindListByIDMock(ctx, id)
+}
+
+func (m *MockWordRepository) GetByList(ctx context.Context, listID string) ([]model.Word, error) {
+	if m.GetByListMock != nil {
+		return m.GetByListMock(ctx, listID)
+	}
+	return nil, nil
+}
+
+func (m *MockWordRepository) CreateList(ctx context.Context, title, ownerID string) (model.WordList, error) {
+	return m.CreateListMock(ctx, title, ownerID)
+}
+
+func (m *MockWordRepository) AddWord(ctx context.Context, listID, word, hint string) (model.Word, error) {
+	m.AddWordCount++
+	return m.AddWordMock(ctx, listID, word, hint)
+}
+
+func (m *MockWordRepository) UpdateWord(ctx context.Context, id, word, hint string) error {
+	return m.UpdateWordMock(ctx, id, word, hint)
+}
+
+func (m *MockWordRepository) DeleteWord(ctx context.Context, id string) error {
+	return m.DeleteWordMock(ctx, id)
+}
+
 type MockUserRepository struct {
 	repository.UserRepository
 	FindByIDMock         func(ctx context.Context, id string) (model.User, error)
@@ -137,18 +336,30 @@ func (m *MockUserRepository) CreateOrUpdate(ctx context.Context, id string, para
 
 type MockUnitOfWork struct {
 	repository.UnitOfWork
+	UserRepositoryMock *MockUserRepository
 	GameRepositoryMock *MockGameRepository
+	WordRepositoryMock *MockWordRepository
 	RollbackMock       func() error
 	CommitMock         func() error
 	RollbackCalled     bool
 	CommitCalled       bool
 }
 
+// UserRepository implements repository.UnitOfWork.
+func (uow *MockUnitOfWork) UserRepository() repository.UserRepository {
+	return uow.UserRepositoryMock
+}
+
 // GameRepository implements repository.UnitOfWork.
 func (uow *MockUnitOfWork) GameRepository() repository.GameRepository {
 	return uow.GameRepositoryMock
 }
 
+// WordRepository implements repository.UnitOfWork.
+func (uow *MockUnitOfWork) WordRepository() repository.WordRepository {
+	return uow.WordRepositoryMock
+}
+
 // Commit implements repository.UnitOfWork.
 func (uow *MockUnitOfWork) Commit() error {
 	uow.CommitCalled = true
@@ -6,7 +6,10 @@ import (
 	"database/sql"
 	"emojix/model"
 	"encoding/hex"
-	"log"
+	"errors"
+	"log/slog"
+	"strconv"
+	"strings"
 	"time"
 
 	_ "modernc.org/sqlite"
@@ -50,20 +53,33 @@ func (uow *sqliteUnitOfWork) Rollback() error {
 	return uow.tx.Rollback()
 }
 
+// UserRepository implements UnitOfWork.
+func (uow *sqliteUnitOfWork) UserRepository() UserRepository {
+	return NewUserRepository(uow.tx)
+}
+
 // GameRepository implements UnitOfWork.
 func (uow *sqliteUnitOfWork) GameRepository() GameRepository {
 	return NewGameRepository(uow.tx)
 }
 
+// WordRepository implements UnitOfWork.
+func (uow *sqliteUnitOfWork) WordRepository() WordRepository {
+	return NewWordRepository(uow.tx)
+}
+
 func InitSqliteDB(fileName string) (*sql.DB, error) {
-	db, err := sql.Open("sqlite", fileName)
-	if err != nil {
-		return db, err
+	// Pragmas are per connection, so they go in the DSN for the driver to
+	// apply to every connection in the pool. busy_timeout makes a connection
+	// wait out another's write (this process's or, with several serve
+	// processes sharing the file, theirs) instead of failing with SQLITE_BUSY.
+	sep := "?"
+	if strings.Contains(fileName, "?") {
+		sep = "&"
 	}
-
-	_, err = db.Exec("PRAGMA foreign_keys = ON;")
+	db, err := sql.Open("sqlite", fileName+sep+"_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)")
 	if err != nil {
-		return nil, err
+		return db, err
 	}
 
 	return db, nil
@@ -78,7 +94,7 @@ func NewUserRepository(db DBTX) UserRepository {
 }
 
 func (r *sqliteUserRepository) FindByID(ctx context.Context, id string) (model.User, error) {
-	row := r.db.QueryRowContext(ctx, "SELECT id, nickname, created_at, updated_at FROM users WHERE id = ?", id)
+	row := r.db.QueryRowContext(ctx, "SELECT id, nickname, avatar, created_at, updated_at FROM users WHERE id = ?", id)
 
 	err := row.Err()
 
@@ -90,7 +106,7 @@ func (r *sqliteUserRepository) FindByID(ctx context.Context, id string) (model.U
 
 	var createdAt, updatedAt int64
 
-	err = row.Scan(&user.ID, &user.Nickname, &createdAt, &updatedAt)
+	err = row.Scan(&user.ID, &user.Nickname, &user.Avatar, &createdAt, &updatedAt)
 
 	if err != nil {
 		return user, err
@@ -103,7 +119,7 @@ func (r *sqliteUserRepository) FindByID(ctx context.Context, id string) (model.U
 }
 
 func (r *sqliteUserRepository) CreateOrUpdate(ctx context.Context, id string, params UserCreateOrUpdateParams) error {
-	log.Println("CREATE OR UPDATE", id)
+	slog.Debug("create or update user", "id", id)
 	row := r.db.QueryRowContext(ctx, "SELECT id FROM users WHERE id = ?", id)
 
 	err := row.Err()
@@ -117,7 +133,7 @@ func (r *sqliteUserRepository) CreateOrUpdate(ctx context.Context, id string, pa
 	nowMs := time.Now().UnixMicro()
 
 	if err == sql.ErrNoRows {
-		_, err = r.db.ExecContext(ctx, "INSERT INTO users (id, nickname, created_at, updated_at) VALUES (?, ?, ?, ?)", id, params.Nickname, nowMs, nowMs)
+		_, err = r.db.ExecContext(ctx, "INSERT INTO users (id, nickname, avatar, created_at, updated_at) VALUES (?, ?, ?, ?, ?)", id, params.Nickname, params.Avatar, nowMs, nowMs)
 		if err != nil {
 			return err
 		}
@@ -129,7 +145,7 @@ func (r *sqliteUserRepository) CreateOrUpdate(ctx context.Context, id string, pa
 		return err
 	}
 
-	_, err = r.db.ExecContext(ctx, "UPDATE users SET nickname = ?, updated_at = ? WHERE id = ?", params.Nickname, nowMs, id)
+	_, err = r.db.ExecContext(ctx, "UPDATE users SET nickname = ?, avatar = ?, updated_at = ? WHERE id = ?", params.Nickname, params.Avatar, nowMs, id)
 	if err != nil {
 		return err
 	}
@@ -147,8 +163,24 @@ func NewGameRepository(db DBTX) GameRepository {
 
 func (r *sqliteGameRepository) FindByID(ctx context.Context, id string) (model.Game, error) {
 
-	row := r.db.QueryRowContext(ctx, "SELECT id, list_id, created_at, updated_at FROM games WHERE id = ?", id)
+	row := r.db.QueryRowContext(ctx, `
+		SELECT `+gameColumns+`
+		FROM games WHERE id = ?`, id)
+	return scanGame(row)
+}
+
+// FindByInviteCode looks a game up by its invite code (already normalized).
+func (r *sqliteGameRepository) FindByInviteCode(ctx context.Context, code string) (model.Game, error) {
+	row := r.db.QueryRowContext(ctx, `
+		SELECT `+gameColumns+`
+		FROM games WHERE invite_code = ?`, code)
+	return scanGame(row)
+}
+
+const gameColumns = `id, list_id, turn_duration_ms, pick_duration_ms, min_players, max_players, rounds, scoring, private,
+		       teams, steal_delay_ms, reveals, status, next_game_id, host_id, invite_code, locked, created_at, updated_at`
 
+func scanGame(row *sql.Row) (model.Game, error) {
 	err := row.Err()
 
 	game := model.Game{}
@@ -158,21 +190,44 @@ func (r *sqliteGameRepository) FindByID(ctx context.Context, id string) (model.G
 	}
 
 	var createdAt, updatedAt int64
-	var listID sql.NullString
+	var turnMs, pickMs, stealMs int64
+	var listID, nextGameID, hostID, inviteCode sql.NullString
 
-	err = row.Scan(&game.ID, &listID, &createdAt, &updatedAt)
+	err = row.Scan(
+		&game.ID, &listID, &turnMs, &pickMs, &game.Settings.MinPlayers, &game.Settings.MaxPlayers, &game.Settings.Rounds, &game.Settings.Scoring, &game.Settings.Private,
+		&game.Settings.Teams, &stealMs, &game.Settings.Reveals, &game.Status, &nextGameID, &hostID, &inviteCode, &game.Locked, &createdAt, &updatedAt,
+	)
 
 	if err != nil {
 		return game, err
 	}
 
 	game.ListID = listID.String
+	game.NextGameID = nextGameID.String
+	game.HostID = hostID.String
+	game.InviteCode = inviteCode.String
+	game.Settings.TurnDuration = time.Duration(turnMs) * time.Millisecond
+	game.Settings.PickDuration = time.Duration(pickMs) * time.Millisecond
+	game.Settings.StealDelay = time.Duration(stealMs) * time.Millisecond
 	game.CreatedAt = time.UnixMicro(createdAt)
 	game.UpdatedAt = time.UnixMicro(updatedAt)
 
 	return game, nil
 }
 
+// generateInviteCode draws model.InviteCodeLength characters from
+// model.InviteCodeAlphabet; the unique index rejects the rare collision.
+func generateInviteCode() (string, error) {
+	bytes := make([]byte, model.InviteCodeLength)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	for i, b := range bytes {
+		bytes[i] = model.InviteCodeAlphabet[int(b)%len(model.InviteCodeAlphabet)]
+	}
+	return string(bytes), nil
+}
+
 func generateRandomID() (string, error) {
 	// Create a byte slice of size 16 (128 bits)
 	bytes := make([]byte, 16)
@@ -187,20 +242,35 @@ func generateRandomID() (string, error) {
 	return hex.EncodeToString(bytes), nil
 }
 
-func (r *sqliteGameRepository) Create(ctx context.Context, listID string) (model.Game, error) {
+func (r *sqliteGameRepository) Create(ctx context.Context, listID string, settings model.GameSettings) (model.Game, error) {
 	id, err := generateRandomID()
 	if err != nil {
 		return model.Game{}, err
 	}
 
+	inviteCode, err := generateInviteCode()
+	if err != nil {
+		return model.Game{}, err
+	}
+
 	game := model.Game{
-		ID:        id,
-		ListID:    listID,
-		UpdatedAt: time.Now(),
-		CreatedAt: time.Now(),
+		ID:         id,
+		ListID:     listID,
+		Settings:   settings,
+		Status:     model.LobbyGameStatus,
+		InviteCode: inviteCode,
+		UpdatedAt:  time.Now(),
+		CreatedAt:  time.Now(),
 	}
 
-	_, err = r.db.ExecContext(ctx, "INSERT INTO games (id, list_id, updated_at, created_at) VALUES (?, ?, ?, ?)", game.ID, game.ListID, game.UpdatedAt.Unix(), game.CreatedAt.Unix())
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO games (id, list_id, turn_duration_ms, pick_duration_ms, min_players, max_players, rounds, scoring, private, teams, steal_delay_ms, reveals, status, invite_code, updated_at, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		game.ID, game.ListID,
+		settings.TurnDuration.Milliseconds(), settings.PickDuration.Milliseconds(), settings.MinPlayers, settings.MaxPlayers, settings.Rounds, settings.Scoring, settings.Private,
+		settings.Teams, settings.StealDelay.Milliseconds(), settings.Reveals,
+		game.Status, game.InviteCode, game.UpdatedAt.Unix(), game.CreatedAt.Unix(),
+	)
 
 	if err != nil {
 		return model.Game{}, err
@@ -209,11 +279,62 @@ func (r *sqliteGameRepository) Create(ctx context.Context, listID string) (model
 	return game, nil
 }
 
+func (r *sqliteGameRepository) SetHost(ctx context.Context, gameID string, userID string) error {
+	_, err := r.db.ExecContext(ctx,
+		"UPDATE games SET host_id = ?, updated_at = ? WHERE id = ?",
+		userID, time.Now().Unix(), gameID,
+	)
+	return err
+}
+
+func (r *sqliteGameRepository) SetLocked(ctx context.Context, gameID string, locked bool) error {
+	_, err := r.db.ExecContext(ctx,
+		"UPDATE games SET locked = ?, updated_at = ? WHERE id = ?",
+		locked, time.Now().Unix(), gameID,
+	)
+	return err
+}
+
+func (r *sqliteGameRepository) SetStatus(ctx context.Context, gameID string, status model.GameStatus) error {
+	_, err := r.db.ExecContext(ctx,
+		"UPDATE games SET status = ?, updated_at = ? WHERE id = ?",
+		status, time.Now().Unix(), gameID,
+	)
+	return err
+}
+
+func (r *sqliteGameRepository) ClaimNextGame(ctx context.Context, gameID string, nextGameID string) (string, error) {
+	_, err := r.db.ExecContext(ctx,
+		"UPDATE games SET next_game_id = ?, updated_at = ? WHERE id = ? AND next_game_id IS NULL",
+		nextGameID, time.Now().Unix(), gameID,
+	)
+	if err != nil {
+		return "", err
+	}
+
+	var winner sql.NullString
+	err = r.db.QueryRowContext(ctx, "SELECT next_game_id FROM games WHERE id = ?", gameID).Scan(&winner)
+	if err != nil {
+		return "", err
+	}
+	return winner.String, nil
+}
+
+func (r *sqliteGameRepository) SetPlayerTeam(ctx context.Context, gameID string, userID string, team model.Team) error {
+	_, err := r.db.ExecContext(ctx,
+		"UPDATE players SET team = ? WHERE game_id = ? AND player_id = ?",
+		team, gameID, userID,
+	)
+	return err
+}
+
+// SetPlayerState never changes a kicked player: the kick is final even when
+// their SSE disconnect later marks them inactive.
 func (r *sqliteGameRepository) SetPlayerState(ctx context.Context, gameID string, userID string, state model.PlayerState) error {
 	_, err := r.db.ExecContext(
 		ctx,
-		"UPDATE players SET state = ? WHERE game_id = ? AND player_id = ?",
-		state, gameID, userID,
+		"UPDATE players SET state = ?, queued_at = 0 WHERE game_id = ? AND player_id = ? AND state != ?",
+		state, gameID, userID, model.KickedPlayerState,
 	)
 
 	if err != nil {
@@ -223,6 +344,47 @@ func (r *sqliteGameRepository) SetPlayerState(ctx context.Context, gameID string
 	return nil
 }
 
+func (r *sqliteGameRepository) ListPlayingIDs(ctx context.Context) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT id FROM games WHERE status = ?`, model.PlayingGameStatus)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+func (r *sqliteGameRepository) ListOpenGameIDs(ctx context.Context, userID string) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT g.id
+		FROM games g
+		JOIN players p ON p.game_id = g.id
+		WHERE p.player_id = ? AND p.state != ? AND g.status != ?`,
+		userID, model.KickedPlayerState, model.FinishedGameStatus)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
 func (r *sqliteGameRepository) AddPlayer(ctx context.Context, gameID string, userID string) error {
 	_, err := r.db.ExecContext(ctx, "INSERT INTO players (game_id,  player_id, state, joined_at) VALUES (?, ?, ?, ?)", gameID, userID, model.ActivePlayerState, time.Now().UnixMicro())
 
@@ -233,12 +395,100 @@ func (r *sqliteGameRepository) AddPlayer(ctx context.Context, gameID string, use
 	return nil
 }
 
+func (r *sqliteGameRepository) SeatPlayer(ctx context.Context, gameID string, userID string, maxPlayers int) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE players SET state = ?, queued_at = 0
+		WHERE game_id = ? AND player_id = ? AND state = ?
+		AND (SELECT COUNT(*) FROM players WHERE game_id = ? AND state = ?) < ?`,
+		model.ActivePlayerState, gameID, userID, model.InactivePlayerState,
+		gameID, model.ActivePlayerState, maxPlayers,
+	)
+	if err != nil {
+		return false, err
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 1 {
+		return n == 1, err
+	}
+
+	// Not a returning player, or the room is full: only a newcomer gets a
+	// row, and only while there is room.
+	res, err = r.db.ExecContext(ctx, `
+		INSERT INTO players (game_id, player_id, state, joined_at)
+		SELECT ?, ?, ?, ?
+		WHERE NOT EXISTS (SELECT 1 FROM players WHERE game_id = ? AND player_id = ?)
+		AND (SELECT COUNT(*) FROM players WHERE game_id = ? AND state = ?) < ?`,
+		gameID, userID, model.ActivePlayerState, time.Now().UnixMicro(),
+		gameID, userID,
+		gameID, model.ActivePlayerState, maxPlayers,
+	)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
+
+func (r *sqliteGameRepository) AddSpectator(ctx context.Context, gameID string, userID string, queued bool) error {
+	now := time.Now().UnixMicro()
+	var queuedAt int64
+	if queued {
+		queuedAt = now
+	}
+	_, err := r.db.ExecContext(ctx,
+		"INSERT INTO players (game_id, player_id, state, joined_at, queued_at) VALUES (?, ?, ?, ?, ?)",
+		gameID, userID, model.SpectatorPlayerState, now, queuedAt,
+	)
+	return err
+}
+
+// SetSpectator keeps a queued spectator's place in line when asked to queue
+// again.
+func (r *sqliteGameRepository) SetSpectator(ctx context.Context, gameID string, userID string, queued bool) error {
+	var queuedAt any = 0
+	if queued {
+		queuedAt = time.Now().UnixMicro()
+	}
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE players
+		SET state = ?, queued_at = CASE WHEN ? != 0 AND queued_at != 0 THEN queued_at ELSE ? END
+		WHERE game_id = ? AND player_id = ? AND state != ?`,
+		model.SpectatorPlayerState, queuedAt, queuedAt, gameID, userID, model.KickedPlayerState,
+	)
+	return err
+}
+
+func (r *sqliteGameRepository) SeenPlayer(ctx context.Context, gameID string, userID string, at time.Time) error {
+	_, err := r.db.ExecContext(
+		ctx,
+		"UPDATE players SET last_seen_at = MAX(last_seen_at, ?) WHERE game_id = ? AND player_id = ?",
+		at.UnixMicro(), gameID, userID,
+	)
+	return err
+}
+
+func (r *sqliteGameRepository) PromoteSpectator(ctx context.Context, gameID string, userID string, maxPlayers int) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE players
+		SET state = ?, queued_at = 0
+		WHERE game_id = ? AND player_id = ? AND state = ? AND queued_at != 0
+		AND (SELECT COUNT(*) FROM players WHERE game_id = ? AND state = ?) < ?`,
+		model.ActivePlayerState, gameID, userID, model.SpectatorPlayerState,
+		gameID, model.ActivePlayerState, maxPlayers,
+	)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
+
 func (r *sqliteGameRepository) GetPlayers(ctx context.Context, gameID string) ([]model.Player, error) {
 	rows, err := r.db.QueryContext(ctx, `
-		SELECT u.id, u.nickname, p.state, p.joined_at
+		SELECT u.id, u.nickname, u.avatar, p.team, p.state, p.joined_at, p.queued_at, p.last_seen_at
 		FROM players p
 		JOIN users u ON p.player_id = u.id
-		WHERE p.game_id = ?`, gameID)
+		WHERE p.game_id = ?
+		ORDER BY p.joined_at`, gameID)
 	if err != nil {
 		return nil, err
 	}
@@ -247,12 +497,18 @@ func (r *sqliteGameRepository) GetPlayers(ctx context.Context, gameID string) ([
 	players := []model.Player{}
 	for rows.Next() {
 		var player model.Player
-		var joinedAt int64
-		err = rows.Scan(&player.ID, &player.Nickname, &player.State, &joinedAt)
+		var joinedAt, queuedAt, lastSeenAt int64
+		err = rows.Scan(&player.ID, &player.Nickname, &player.Avatar, &player.Team, &player.State, &joinedAt, &queuedAt, &lastSeenAt)
 		if err != nil {
 			return nil, err
 		}
 		player.JoinedAt = time.UnixMicro(joinedAt)
+		if queuedAt != 0 {
+			player.QueuedAt = time.UnixMicro(queuedAt)
+		}
+		if lastSeenAt != 0 {
+			player.LastSeenAt = time.UnixMicro(lastSeenAt)
+		}
 		players = append(players, player)
 	}
 
@@ -321,35 +577,89 @@ func (r *sqliteGameRepository) SendMessage(ctx context.Context, gameID string, t
 
 func (r *sqliteGameRepository) GetLatestTurn(ctx context.Context, gameID string) (model.GameTurn, error) {
 	row := r.db.QueryRowContext(ctx, `
-		SELECT id, word_id, teller_id, option_a, option_b, option_c, emoji_hint, created_at, started_at
+		SELECT `+turnColumns+`
 		FROM game_turns WHERE game_id = ? ORDER BY created_at DESC LIMIT 1`, gameID)
 
-	err := row.Err()
-
-	turn := model.GameTurn{GameID: gameID}
+	if err := row.Err(); err != nil {
+		return model.GameTurn{GameID: gameID}, err
+	}
+	return scanTurn(row.Scan, gameID)
+}
 
+func (r *sqliteGameRepository) GetTurns(ctx context.Context, gameID string) ([]model.GameTurn, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+turnColumns+`
+		FROM game_turns WHERE game_id = ? ORDER BY created_at ASC`, gameID)
 	if err != nil {
-		return turn, err
+		return nil, err
+	}
+	defer rows.Close()
+
+	turns := []model.GameTurn{}
+	for rows.Next() {
+		turn, err := scanTurn(rows.Scan, gameID)
+		if err != nil {
+			return nil, err
+		}
+		turns = append(turns, turn)
 	}
 
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return turns, nil
+}
+
+const turnColumns = `id, word_id, teller_id, option_a, option_b, option_c, emoji_hint, created_at, started_at,
+		       pick_deadline, end_deadline, ended_at, revealed, rerolled`
+
+func scanTurn(scan func(dest ...any) error, gameID string) (model.GameTurn, error) {
+	turn := model.GameTurn{GameID: gameID}
+
 	var createdAt int64
 	var wordID sql.NullString
-	var startedAt sql.NullInt64
-	err = row.Scan(&turn.ID, &wordID, &turn.TellerID, &turn.OptionA, &turn.OptionB, &turn.OptionC, &turn.EmojiHint, &createdAt, &startedAt)
-
+	var startedAt, pickDeadline, endDeadline, endedAt sql.NullInt64
+	var revealed string
+	err := scan(
+		&turn.ID, &wordID, &turn.TellerID, &turn.OptionA, &turn.OptionB, &turn.OptionC, &turn.EmojiHint, &createdAt, &startedAt,
+		&pickDeadline, &endDeadline, &endedAt, &revealed, &turn.Rerolled,
+	)
 	if err != nil {
 		return turn, err
 	}
+	for _, pos := range strings.Split(revealed, ",") {
+		if n, err := strconv.Atoi(pos); err == nil {
+			turn.Revealed = append(turn.Revealed, n)
+		}
+	}
 
 	turn.WordID = wordID.String
 	turn.CreatedAt = time.UnixMicro(createdAt)
-	if startedAt.Valid {
-		turn.StartedAt = time.UnixMicro(startedAt.Int64)
-	}
+	turn.StartedAt = nullMicro(startedAt)
+	turn.PickDeadline = nullMicro(pickDeadline)
+	turn.EndDeadline = nullMicro(endDeadline)
+	turn.EndedAt = nullMicro(endedAt)
 
 	return turn, nil
 }
 
+// nullMicro converts a nullable UnixMicro column, NULL being the zero time.
+func nullMicro(v sql.NullInt64) time.Time {
+	if !v.Valid {
+		return time.Time{}
+	}
+	return time.UnixMicro(v.Int64)
+}
+
+// microOrNull is nullMicro's inverse.
+func microOrNull(t time.Time) any {
+	if t.IsZero() {
+		return nil
+	}
+	return t.UnixMicro()
+}
+
 func (r *sqliteGameRepository) AddTurn(ctx context.Context, params AddTurnParams) (model.GameTurn, error) {
 	id, err := generateRandomID()
 	if err != nil {
@@ -357,19 +667,21 @@ func (r *sqliteGameRepository) AddTurn(ctx context.Context, params AddTurnParams
 	}
 
 	turn := model.GameTurn{
-		ID:        id,
-		GameID:    params.GameID,
-		TellerID:  params.TellerID,
-		OptionA:   params.OptionA,
-		OptionB:   params.OptionB,
-		OptionC:   params.OptionC,
-		CreatedAt: time.Now(),
+		ID:           id,
+		GameID:       params.GameID,
+		TellerID:     params.TellerID,
+		OptionA:      params.OptionA,
+		OptionB:      params.OptionB,
+		OptionC:      params.OptionC,
+		CreatedAt:    time.Now(),
+		PickDeadline: params.PickDeadline,
 	}
 
 	_, err = r.db.ExecContext(ctx,
-		`INSERT INTO game_turns (id, game_id, word_id, teller_id, option_a, option_b, option_c, created_at, started_at)
-		 VALUES (?, ?, NULL, ?, ?, ?, ?, ?, NULL)`,
+		`INSERT INTO game_turns (id, game_id, word_id, teller_id, option_a, option_b, option_c, created_at, started_at, pick_deadline)
+		 VALUES (?, ?, NULL, ?, ?, ?, ?, ?, NULL, ?)`,
 		id, params.GameID, params.TellerID, params.OptionA, params.OptionB, params.OptionC, turn.CreatedAt.UnixMicro(),
+		microOrNull(params.PickDeadline),
 	)
 	if err != nil {
 		return model.GameTurn{}, err
@@ -378,15 +690,165 @@ func (r *sqliteGameRepository) AddTurn(ctx context.Context, params AddTurnParams
 	return turn, nil
 }
 
-func (r *sqliteGameRepository) SetTurnWord(ctx context.Context, turnID string, wordID string, emojiHint string) error {
+func (r *sqliteGameRepository) SetTurnWord(ctx context.Context, turnID string, wordID string, emojiHint string, endDeadline time.Time) error {
 	now := time.Now().UnixMicro()
 	_, err := r.db.ExecContext(ctx,
-		`UPDATE game_turns SET word_id = ?, emoji_hint = ?, started_at = ? WHERE id = ? AND word_id IS NULL`,
-		wordID, emojiHint, now, turnID,
+		`UPDATE game_turns SET word_id = ?, emoji_hint = ?, started_at = ?, end_deadline = ? WHERE id = ? AND word_id IS NULL`,
+		wordID, emojiHint, now, microOrNull(endDeadline), turnID,
 	)
 	return err
 }
 
+func (r *sqliteGameRepository) EndTurn(ctx context.Context, gameID string, endedAt time.Time) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE game_turns SET ended_at = ? WHERE game_id = ? AND ended_at IS NULL`,
+		endedAt.UnixMicro(), gameID,
+	)
+	return err
+}
+
+func (r *sqliteGameRepository) SetTurnRevealed(ctx context.Context, turnID string, revealed []int) error {
+	positions := make([]string, len(revealed))
+	for i, n := range revealed {
+		positions[i] = strconv.Itoa(n)
+	}
+	_, err := r.db.ExecContext(ctx, `UPDATE game_turns SET revealed = ? WHERE id = ?`, strings.Join(positions, ","), turnID)
+	return err
+}
+
+func (r *sqliteGameRepository) RerollTurnOptions(ctx context.Context, turnID, optionA, optionB, optionC string) error {
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE game_turns SET option_a = ?, option_b = ?, option_c = ?, rerolled = 1
+		 WHERE id = ? AND word_id IS NULL AND rerolled = 0`,
+		optionA, optionB, optionC, turnID,
+	)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *sqliteGameRepository) AddSkipVote(ctx context.Context, turnID, playerID string) error {
+	_, err := r.db.ExecContext(ctx,
+		`INSERT OR IGNORE INTO turn_skip_votes (turn_id, player_id, created_at) VALUES (?, ?, ?)`,
+		turnID, playerID, time.Now().UnixMicro(),
+	)
+	return err
+}
+
+func (r *sqliteGameRepository) GetSkipVotes(ctx context.Context, turnID string) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT player_id FROM turn_skip_votes WHERE turn_id = ? ORDER BY created_at ASC`, turnID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	votes := []string{}
+	for rows.Next() {
+		var playerID string
+		if err := rows.Scan(&playerID); err != nil {
+			return nil, err
+		}
+		votes = append(votes, playerID)
+	}
+	return votes, rows.Err()
+}
+
+func (r *sqliteGameRepository) AppendTurnHint(ctx context.Context, turnID string, emoji string) (string, error) {
+	if err := r.pushTurnHint(ctx, turnID); err != nil {
+		return "", err
+	}
+	_, err := r.db.ExecContext(ctx, `UPDATE game_turns SET emoji_hint = emoji_hint || ? WHERE id = ?`, emoji, turnID)
+	if err != nil {
+		return "", err
+	}
+	return r.turnHint(ctx, turnID)
+}
+
+func (r *sqliteGameRepository) ReplaceTurnHint(ctx context.Context, turnID string, hint string) (string, error) {
+	if err := r.pushTurnHint(ctx, turnID); err != nil {
+		return "", err
+	}
+	_, err := r.db.ExecContext(ctx, `UPDATE game_turns SET emoji_hint = ? WHERE id = ?`, hint, turnID)
+	if err != nil {
+		return "", err
+	}
+	return hint, nil
+}
+
+func (r *sqliteGameRepository) UndoTurnHint(ctx context.Context, turnID string) (string, error) {
+	var id int64
+	var previous string
+	err := r.db.QueryRowContext(ctx,
+		`SELECT id, previous_hint FROM turn_hint_history WHERE turn_id = ? ORDER BY id DESC LIMIT 1`, turnID,
+	).Scan(&id, &previous)
+	if errors.Is(err, sql.ErrNoRows) {
+		return r.turnHint(ctx, turnID)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if _, err = r.db.ExecContext(ctx, `UPDATE game_turns SET emoji_hint = ? WHERE id = ?`, previous, turnID); err != nil {
+		return "", err
+	}
+	if _, err = r.db.ExecContext(ctx, `DELETE FROM turn_hint_history WHERE id = ?`, id); err != nil {
+		return "", err
+	}
+	return previous, nil
+}
+
+func (r *sqliteGameRepository) GetHintEdits(ctx context.Context, gameID string) ([]model.HintEdit, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT h.turn_id, h.previous_hint, h.created_at
+		FROM turn_hint_history h
+		JOIN game_turns t ON t.id = h.turn_id
+		WHERE t.game_id = ?
+		ORDER BY h.id`, gameID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	edits := []model.HintEdit{}
+	for rows.Next() {
+		var edit model.HintEdit
+		var at int64
+		if err := rows.Scan(&edit.TurnID, &edit.Previous, &at); err != nil {
+			return nil, err
+		}
+		edit.At = time.UnixMicro(at)
+		edits = append(edits, edit)
+	}
+	return edits, rows.Err()
+}
+
+// pushTurnHint saves the current board so the next edit can be undone.
+func (r *sqliteGameRepository) pushTurnHint(ctx context.Context, turnID string) error {
+	res, err := r.db.ExecContext(ctx, `
+		INSERT INTO turn_hint_history (turn_id, previous_hint, created_at)
+		SELECT id, emoji_hint, ? FROM game_turns WHERE id = ?`,
+		time.Now().UnixMicro(), turnID,
+	)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *sqliteGameRepository) turnHint(ctx context.Context, turnID string) (string, error) {
+	var hint string
+	err := r.db.QueryRowContext(ctx, `SELECT emoji_hint FROM game_turns WHERE id = ?`, turnID).Scan(&hint)
+	return hint, err
+}
+
 func (r *sqliteGameRepository) CountTurns(ctx context.Context, gameID string) (int, error) {
 	row := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM game_turns WHERE game_id = ?`, gameID)
 	var n int
@@ -444,7 +906,7 @@ func NewWordRepository(db DBTX) WordRepository {
 }
 
 func (r *sqliteWordRepository) GetLists(ctx context.Context) ([]model.WordList, error) {
-	rows, err := r.db.QueryContext(ctx, `SELECT id, title FROM word_lists ORDER BY title`)
+	rows, err := r.db.QueryContext(ctx, `SELECT id, title, owner_id FROM word_lists ORDER BY title`)
 	if err != nil {
 		return nil, err
 	}
@@ -453,9 +915,11 @@ func (r *sqliteWordRepository) GetLists(ctx context.Context) ([]model.WordList,
 	lists := []model.WordList{}
 	for rows.Next() {
 		var list model.WordList
-		if err = rows.Scan(&list.ID, &list.Title); err != nil {
+		var ownerID sql.NullString
+		if err = rows.Scan(&list.ID, &list.Title, &ownerID); err != nil {
 			return nil, err
 		}
+		list.OwnerID = ownerID.String
 		lists = append(lists, list)
 	}
 	return lists, rows.Err()
@@ -514,3 +978,106 @@ func (r *sqliteWordRepository) FindByID(ctx context.Context, id string) (model.W
 
 	return word, nil
 }
+
+func (r *sqliteWordRepository) FindListByID(ctx context.Context, id string) (model.WordList, error) {
+	list := model.WordList{}
+	var ownerID sql.NullString
+	err := r.db.QueryRowContext(ctx, "SELECT id, title, owner_id FROM word_lists WHERE id = ?", id).Scan(&list.ID, &list.Title, &ownerID)
+	list.OwnerID = ownerID.String
+	return list, err
+}
+
+func (r *sqliteWordRepository) GetByList(ctx context.Context, listID string) ([]model.Word, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, list_id, word, hint FROM words
+		WHERE list_id = ?
+		ORDER BY word COLLATE NOCASE, id`, listID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	words := []model.Word{}
+	for rows.Next() {
+		var word model.Word
+		var lid sql.NullString
+		if err = rows.Scan(&word.ID, &lid, &word.Word, &word.Hint); err != nil {
+			return nil, err
+		}
+		word.ListID = lid.String
+		words = append(words, word)
+	}
+	return words, rows.Err()
+}
+
+func (r *sqliteWordRepository) CreateList(ctx context.Context, title, ownerID string) (model.WordList, error) {
+	id, err := generateRandomID()
+	if err != nil {
+		return model.WordList{}, err
+	}
+	_, err = r.db.ExecContext(ctx, "INSERT INTO word_lists (id, title, owner_id) VALUES (?, ?, ?)", id, title, sql.NullString{String: ownerID, Valid: ownerID != ""})
+	if err != nil {
+		return model.WordList{}, err
+	}
+	return model.WordList{ID: id, Title: title, OwnerID: ownerID}, nil
+}
+
+func (r *sqliteWordRepository) AddWord(ctx context.Context, listID, word, hint string) (model.Word, error) {
+	id, err := generateRandomID()
+	if err != nil {
+		return model.Word{}, err
+	}
+	_, err = r.db.ExecContext(ctx, "INSERT INTO words (id, list_id, word, hint) VALUES (?, ?, ?, ?)", id, listID, word, hint)
+	if err != nil {
+		return model.Word{}, err
+	}
+	return model.Word{ID: id, ListID: listID, Word: word, Hint: hint}, nil
+}
+
+func (r *sqliteWordRepository) UpdateWord(ctx context.Context, id, word, hint string) error {
+	if err := r.checkUnused(ctx, id); err != nil {
+		return err
+	}
+	res, err := r.db.ExecContext(ctx, "UPDATE words SET word = ?, hint = ? WHERE id = ?", word, hint, id)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// checkUnused returns ErrWordInUse once a game turn dealt the word. Options
+// are plain ids without a foreign key, so they are checked alongside word_id:
+// a dealt option must stay resolvable, and unchanged, for the turn's picker
+// and the game's history.
+func (r *sqliteWordRepository) checkUnused(ctx context.Context, id string) error {
+	var used bool
+	err := r.db.QueryRowContext(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM game_turns
+			WHERE word_id = ?1 OR option_a = ?1 OR option_b = ?1 OR option_c = ?1
+		)`, id).Scan(&used)
+	if err != nil {
+		return err
+	}
+	if used {
+		return ErrWordInUse
+	}
+	return nil
+}
+
+func (r *sqliteWordRepository) DeleteWord(ctx context.Context, id string) error {
+	if err := r.checkUnused(ctx, id); err != nil {
+		return err
+	}
+	res, err := r.db.ExecContext(ctx, "DELETE FROM words WHERE id = ?", id)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
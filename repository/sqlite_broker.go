@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"emojix/service"
+	"errors"
+	"log"
+	"time"
+)
+
+const (
+	// DefaultBrokerPoll is how often each instance checks for new messages.
+	DefaultBrokerPoll = 100 * time.Millisecond
+	// brokerRetention is how long messages are kept; far longer than any
+	// instance takes between polls.
+	brokerRetention = 10 * time.Minute
+	brokerBatch     = 500
+)
+
+type sqliteBroker struct {
+	db        *sql.DB
+	pollEvery time.Duration
+}
+
+// NewSqliteBroker returns a service.Broker that passes messages through the
+// broker_messages table, so instances sharing the database file see each
+// other's events.
+func NewSqliteBroker(db *sql.DB, pollEvery time.Duration) service.Broker {
+	return &sqliteBroker{db: db, pollEvery: pollEvery}
+}
+
+func (b *sqliteBroker) Publish(ctx context.Context, msg service.BrokerMessage) error {
+	tx, err := b.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Numbered in the same transaction as the insert, so a game's events
+	// reach subscribers in game_seq order.
+	if msg.Topic == service.EventsTopic {
+		err = tx.QueryRowContext(ctx, `
+			INSERT INTO broker_game_seqs (game_id, seq) VALUES (?, 1)
+			ON CONFLICT (game_id) DO UPDATE SET seq = seq + 1
+			RETURNING seq`, msg.GameID).Scan(&msg.GameSeq)
+		if err != nil {
+			return err
+		}
+	}
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO broker_messages (topic, game_id, game_seq, except_user, only_user, type, data, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		msg.Topic, msg.GameID, msg.GameSeq, msg.Except, msg.Only, msg.Type, msg.Data, time.Now().UnixMicro())
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (b *sqliteBroker) Subscribe(ctx context.Context) (<-chan service.BrokerMessage, error) {
+	var last uint64
+	err := b.db.QueryRowContext(ctx, "SELECT COALESCE(MAX(id), 0) FROM broker_messages").Scan(&last)
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan service.BrokerMessage, brokerBatch)
+	go func() {
+		defer close(ch)
+		polls := 0
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(b.pollEvery):
+			}
+			msgs, err := b.since(ctx, last)
+			if err != nil {
+				if ctx.Err() == nil {
+					log.Printf("broker poll: %v", err)
+				}
+				continue
+			}
+			for _, msg := range msgs {
+				select {
+				case ch <- msg:
+					last = msg.Seq
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			polls++
+			if polls%600 == 0 {
+				b.prune(ctx)
+			}
+		}
+	}()
+	return ch, nil
+}
+
+func (b *sqliteBroker) since(ctx context.Context, last uint64) ([]service.BrokerMessage, error) {
+	rows, err := b.db.QueryContext(ctx, `
+		SELECT id, topic, game_id, game_seq, except_user, only_user, type, data
+		FROM broker_messages WHERE id > ? ORDER BY id LIMIT ?`, last, brokerBatch)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	msgs := []service.BrokerMessage{}
+	for rows.Next() {
+		msg := service.BrokerMessage{}
+		err := rows.Scan(&msg.Seq, &msg.Topic, &msg.GameID, &msg.GameSeq, &msg.Except, &msg.Only, &msg.Type, &msg.Data)
+		if err != nil {
+			return nil, err
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs, rows.Err()
+}
+
+func (b *sqliteBroker) prune(ctx context.Context) {
+	cutoff := time.Now().Add(-brokerRetention).UnixMicro()
+	if _, err := b.db.ExecContext(ctx, "DELETE FROM broker_messages WHERE created_at < ?", cutoff); err != nil {
+		log.Printf("broker prune: %v", err)
+	}
+}
+
+type sqliteLeaseStore struct {
+	db *sql.DB
+}
+
+// NewSqliteLeaseStore returns a service.LeaseStore backed by game_leases.
+func NewSqliteLeaseStore(db *sql.DB) service.LeaseStore {
+	return &sqliteLeaseStore{db: db}
+}
+
+func (s *sqliteLeaseStore) Acquire(ctx context.Context, gameID, owner string, ttl time.Duration) (bool, error) {
+	now := time.Now()
+	// One statement, so two instances racing for a free lease can't both win.
+	res, err := s.db.ExecContext(ctx, `
+		INSERT INTO game_leases (game_id, owner, expires_at) VALUES (?, ?, ?)
+		ON CONFLICT (game_id) DO UPDATE SET owner = excluded.owner, expires_at = excluded.expires_at
+		WHERE game_leases.owner = excluded.owner OR game_leases.expires_at <= ?`,
+		gameID, owner, now.Add(ttl).UnixMicro(), now.UnixMicro())
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
+
+func (s *sqliteLeaseStore) Release(ctx context.Context, gameID, owner string) error {
+	_, err := s.db.ExecContext(ctx, "DELETE FROM game_leases WHERE game_id = ? AND owner = ?", gameID, owner)
+	return err
+}
+
+func (s *sqliteLeaseStore) Holder(ctx context.Context, gameID string) (string, error) {
+	var owner string
+	err := s.db.QueryRowContext(ctx,
+		"SELECT owner FROM game_leases WHERE game_id = ? AND expires_at > ?",
+		gameID, time.Now().UnixMicro()).Scan(&owner)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	return owner, err
+}
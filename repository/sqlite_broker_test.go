@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"emojix/service"
+	"testing"
+	"time"
+)
+
+func TestSqliteBroker(t *testing.T) {
+	db := newTestDB(t)
+	broker := NewSqliteBroker(db, time.Millisecond)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Published before anyone subscribed: not replayed.
+	if err := broker.Publish(ctx, service.BrokerMessage{Topic: service.EventsTopic, GameID: "g1", Type: "old"}); err != nil {
+		t.Fatal(err)
+	}
+
+	subA, err := broker.Subscribe(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	subB, err := broker.Subscribe(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sent := []service.BrokerMessage{
+		{Topic: service.EventsTopic, GameID: "g1", Except: "u1", Type: "msg", Data: "u1,nick,hi"},
+		{Topic: service.LoopTopic, GameID: "g1", Type: "skip"},
+		{Topic: service.EventsTopic, GameID: "g2", Type: "join", Data: "u2,nick"},
+		{Topic: service.EventsTopic, GameID: "g1", Type: "msg", Data: "u1,nick,again"},
+	}
+	// Each game's events count up on their own, after the "old" one.
+	gameSeqs := []uint64{2, 0, 1, 3}
+	for _, msg := range sent {
+		if err := broker.Publish(ctx, msg); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for name, sub := range map[string]<-chan service.BrokerMessage{"A": subA, "B": subB} {
+		var prev uint64
+		for i, want := range sent {
+			select {
+			case got := <-sub:
+				want.Seq = got.Seq
+				want.GameSeq = gameSeqs[i]
+				if got != want {
+					t.Errorf("%s[%d] = %+v, want %+v", name, i, got, want)
+				}
+				if got.Seq <= prev {
+					t.Errorf("%s[%d] seq %d not after %d", name, i, got.Seq, prev)
+				}
+				prev = got.Seq
+			case <-time.After(time.Second):
+				t.Fatalf("%s: expected message %d", name, i)
+			}
+		}
+	}
+
+	cancel()
+	if _, open := <-subA; open {
+		t.Error("expected the subscription to close with its context")
+	}
+}
+
+func TestSqliteLeaseStore(t *testing.T) {
+	db := newTestDB(t)
+	leases := NewSqliteLeaseStore(db)
+	ctx := context.Background()
+
+	acquire := func(owner string, ttl time.Duration) bool {
+		t.Helper()
+		ok, err := leases.Acquire(ctx, "g1", owner, ttl)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ok
+	}
+
+	if !acquire("a", time.Minute) {
+		t.Fatal("expected a free lease to be acquired")
+	}
+	if acquire("b", time.Minute) {
+		t.Error("expected a held lease to be refused")
+	}
+	if !acquire("a", time.Minute) {
+		t.Error("expected the owner to renew")
+	}
+	if holder, _ := leases.Holder(ctx, "g1"); holder != "a" {
+		t.Errorf("expected a to hold the lease, got %q", holder)
+	}
+
+	// b can't release a's lease; a can.
+	_ = leases.Release(ctx, "g1", "b")
+	if acquire("b", time.Minute) {
+		t.Error("expected a release by a non-owner to do nothing")
+	}
+	_ = leases.Release(ctx, "g1", "a")
+	if holder, _ := leases.Holder(ctx, "g1"); holder != "" {
+		t.Errorf("expected the released lease to be free, held by %q", holder)
+	}
+
+	// An expired lease goes to whoever asks next.
+	if !acquire("a", -time.Second) || !acquire("b", time.Minute) {
+		t.Error("expected an expired lease to be taken over")
+	}
+}
@@ -4,6 +4,9 @@ import (
 	"context"
 	"database/sql"
 	"emojix/model"
+	"errors"
+	"maps"
+	"slices"
 	"testing"
 	"time"
 
@@ -78,6 +81,7 @@ func TestUserRepository(t *testing.T) {
 			userID := "some-id"
 			params := UserCreateOrUpdateParams{
 				Nickname: "new-nickname",
+				Avatar:   "🦊",
 			}
 			err = repo.CreateOrUpdate(context.Background(), userID, params)
 			if err != nil {
@@ -97,6 +101,10 @@ func TestUserRepository(t *testing.T) {
 				t.Errorf("expected nickname %s but got %s", "new-nickname", user.Nickname)
 			}
 
+			if user.Avatar != "🦊" {
+				t.Errorf("expected avatar %s but got %s", "🦊", user.Avatar)
+			}
+
 			if user.CreatedAt.Compare(now) != 0 {
 				t.Errorf("expected created_at %v but got %v", now, user.CreatedAt)
 			}
@@ -257,6 +265,118 @@ func TestWordRepository(t *testing.T) {
 			t.Errorf("expected hint %s but got %s", "hint-1", word.Hint)
 		}
 	})
+
+	t.Run("list and word authoring", func(t *testing.T) {
+		db := newTestDB(t)
+		repo := NewWordRepository(db)
+		ctx := context.Background()
+
+		now := time.Now()
+		_, err := db.Exec("INSERT INTO users (id, nickname, created_at, updated_at) VALUES ('owner-id', 'owner', ?, ?)", now.UnixMicro(), now.UnixMicro())
+		if err != nil {
+			t.Fatal(err)
+		}
+		list, err := repo.CreateList(ctx, "Fruit", "owner-id")
+		if err != nil {
+			t.Fatal(err)
+		}
+		found, err := repo.FindListByID(ctx, list.ID)
+		if err != nil || found != list || found.OwnerID != "owner-id" {
+			t.Fatalf("expected %+v but got %+v (err %v)", list, found, err)
+		}
+		seeded, err := repo.CreateList(ctx, "Seeded", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		lists, err := repo.GetLists(ctx)
+		if err != nil || len(lists) != 2 || lists[0] != list || lists[1] != seeded {
+			t.Fatalf("expected lists %+v, %+v but got %+v (err %v)", list, seeded, lists, err)
+		}
+
+		pear, err := repo.AddWord(ctx, list.ID, "pear", "🍐")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = repo.AddWord(ctx, list.ID, "Apple", "🍎"); err != nil {
+			t.Fatal(err)
+		}
+		if err = repo.UpdateWord(ctx, pear.ID, "Banana", "🍌"); err != nil {
+			t.Fatal(err)
+		}
+
+		words, err := repo.GetByList(ctx, list.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(words) != 2 || words[0].Word != "Apple" || words[1].Word != "Banana" || words[1].Hint != "🍌" {
+			t.Errorf("unexpected words: %+v", words)
+		}
+
+		if err = repo.DeleteWord(ctx, pear.ID); err != nil {
+			t.Fatal(err)
+		}
+		if err = repo.DeleteWord(ctx, pear.ID); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("expected sql.ErrNoRows deleting twice but got %v", err)
+		}
+		if err = repo.UpdateWord(ctx, "missing", "x", "🍎"); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("expected sql.ErrNoRows updating a missing word but got %v", err)
+		}
+		if _, err = repo.FindListByID(ctx, "missing"); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("expected sql.ErrNoRows for a missing list but got %v", err)
+		}
+	})
+
+	t.Run("UpdateWord and DeleteWord keep dealt words", func(t *testing.T) {
+		db := newTestDB(t)
+		repo := NewWordRepository(db)
+		seedList(t, db, "l1", "Action")
+
+		_, err := db.Exec(`INSERT INTO words (id, list_id, word, hint) VALUES
+			('1', 'l1', 'word-1', 'hint-1'),
+			('2', 'l1', 'word-2', 'hint-2')`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = db.Exec("INSERT INTO games (id, list_id, created_at, updated_at) VALUES ('g1', 'l1', 1, 1)")
+		if err != nil {
+			t.Fatal(err)
+		}
+		// dealt as an option but not picked yet
+		_, err = db.Exec(`INSERT INTO game_turns (id, game_id, teller_id, option_a, option_b, option_c, created_at)
+			VALUES ('t1', 'g1', 'teller', '1', '2', '2', 1)`)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err = repo.DeleteWord(context.Background(), "1"); !errors.Is(err, ErrWordInUse) {
+			t.Errorf("expected ErrWordInUse but got %v", err)
+		}
+		if err = repo.UpdateWord(context.Background(), "2", "renamed", "hint-2"); !errors.Is(err, ErrWordInUse) {
+			t.Errorf("expected ErrWordInUse renaming a dealt word but got %v", err)
+		}
+	})
+
+	t.Run("UnitOfWork WordRepository rolls back", func(t *testing.T) {
+		db := newTestDB(t)
+		uow, err := NewUnitOfWorkFactory(db).New(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = uow.WordRepository().CreateList(context.Background(), "Draft", ""); err != nil {
+			t.Fatal(err)
+		}
+		if err = uow.Rollback(); err != nil {
+			t.Fatal(err)
+		}
+
+		lists, err := NewWordRepository(db).GetLists(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(lists) != 0 {
+			t.Errorf("expected rolled back list to be gone, got %+v", lists)
+		}
+	})
 }
 
 func TestGameRepository(t *testing.T) {
@@ -294,7 +414,7 @@ func TestGameRepository(t *testing.T) {
 		seedList(t, db, "list-1", "Test List")
 
 		now := time.Now()
-		game, err := repo.Create(context.Background(), "list-1")
+		game, err := repo.Create(context.Background(), "list-1", model.GameSettings{})
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -311,13 +431,221 @@ func TestGameRepository(t *testing.T) {
 			t.Errorf("expected updated_at after %v but got %v", now, game.UpdatedAt)
 		}
 	})
+	t.Run("Create persists settings", func(t *testing.T) {
+		db := newTestDB(t)
+		repo := NewGameRepository(db)
+		seedList(t, db, "list-1", "Test List")
+
+		settings := model.GameSettings{
+			TurnDuration: 90 * time.Second,
+			PickDuration: 15 * time.Second,
+			MinPlayers:   3,
+			MaxPlayers:   6,
+			Rounds:       4,
+			Scoring:      "timed",
+			Teams:        true,
+			StealDelay:   20 * time.Second,
+			Reveals:      "50,80",
+		}
+		game, err := repo.Create(context.Background(), "list-1", settings)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := repo.FindByID(context.Background(), game.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Settings != settings {
+			t.Errorf("expected settings %+v but got %+v", settings, got.Settings)
+		}
+	})
+	t.Run("invite code, host and lock", func(t *testing.T) {
+		db := newTestDB(t)
+		repo := NewGameRepository(db)
+		seedList(t, db, "list-1", "Test List")
+		ctx := context.Background()
+
+		now := time.Now()
+		_, err := db.Exec("INSERT INTO users (id, nickname, created_at, updated_at) VALUES ('host-id', 'host', ?, ?);", now.UnixMicro(), now.UnixMicro())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		game, err := repo.Create(ctx, "list-1", model.GameSettings{Private: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(game.InviteCode) != model.InviteCodeLength {
+			t.Fatalf("expected a %d character invite code but got %q", model.InviteCodeLength, game.InviteCode)
+		}
+		if err = repo.SetHost(ctx, game.ID, "host-id"); err != nil {
+			t.Fatal(err)
+		}
+		if err = repo.SetLocked(ctx, game.ID, true); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := repo.FindByInviteCode(ctx, game.InviteCode)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.ID != game.ID || got.HostID != "host-id" || !got.Locked || !got.Settings.Private {
+			t.Errorf("expected private locked game %s hosted by host-id but got %+v", game.ID, got)
+		}
+
+		if _, err = repo.FindByInviteCode(ctx, "NOPE22"); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("expected sql.ErrNoRows for an unknown code but got %v", err)
+		}
+	})
+	t.Run("SetStatus and ClaimNextGame", func(t *testing.T) {
+		db := newTestDB(t)
+		repo := NewGameRepository(db)
+		seedList(t, db, "list-1", "Test List")
+		ctx := context.Background()
+
+		game, err := repo.Create(ctx, "list-1", model.GameSettings{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if game.Status != model.LobbyGameStatus {
+			t.Errorf("expected new game status %s but got %s", model.LobbyGameStatus, game.Status)
+		}
+		first, _ := repo.Create(ctx, "list-1", model.GameSettings{})
+		second, _ := repo.Create(ctx, "list-1", model.GameSettings{})
+
+		if err = repo.SetStatus(ctx, game.ID, model.FinishedGameStatus); err != nil {
+			t.Fatal(err)
+		}
+		winner, err := repo.ClaimNextGame(ctx, game.ID, first.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if winner != first.ID {
+			t.Errorf("expected first claim %s to win but got %s", first.ID, winner)
+		}
+		winner, err = repo.ClaimNextGame(ctx, game.ID, second.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if winner != first.ID {
+			t.Errorf("expected later claim to return %s but got %s", first.ID, winner)
+		}
+
+		got, err := repo.FindByID(ctx, game.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Status != model.FinishedGameStatus || got.NextGameID != first.ID {
+			t.Errorf("expected finished game pointing at %s but got %+v", first.ID, got)
+		}
+	})
+	t.Run("GetTurns oldest first", func(t *testing.T) {
+		db := newTestDB(t)
+		repo := NewGameRepository(db)
+		seedList(t, db, "list-1", "Test List")
+		ctx := context.Background()
+
+		game, err := repo.Create(ctx, "list-1", model.GameSettings{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = db.Exec("INSERT INTO words (id, word, hint) VALUES ('word-id', 'word', 'hint');")
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, teller := range []string{"teller-1", "teller-2"} {
+			_, err = repo.AddTurn(ctx, AddTurnParams{GameID: game.ID, TellerID: teller, OptionA: "word-id", OptionB: "word-id", OptionC: "word-id"})
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		turns, err := repo.GetTurns(ctx, game.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(turns) != 2 || turns[0].TellerID != "teller-1" || turns[1].TellerID != "teller-2" {
+			t.Errorf("expected turns by teller-1 then teller-2 but got %+v", turns)
+		}
+	})
+	t.Run("turn hint board append, replace and undo", func(t *testing.T) {
+		db := newTestDB(t)
+		repo := NewGameRepository(db)
+		seedList(t, db, "list-1", "Test List")
+		ctx := context.Background()
+
+		game, err := repo.Create(ctx, "list-1", model.GameSettings{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = db.Exec("INSERT INTO words (id, word, hint) VALUES ('word-id', 'word', 'hint');")
+		if err != nil {
+			t.Fatal(err)
+		}
+		turn, err := repo.AddTurn(ctx, AddTurnParams{GameID: game.ID, TellerID: "teller", OptionA: "word-id", OptionB: "word-id", OptionC: "word-id"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = repo.SetTurnWord(ctx, turn.ID, "word-id", "🍎", time.Time{}); err != nil {
+			t.Fatal(err)
+		}
+
+		steps := []struct {
+			name string
+			do   func() (string, error)
+			want string
+		}{
+			{"append", func() (string, error) { return repo.AppendTurnHint(ctx, turn.ID, "🍌") }, "🍎🍌"},
+			{"replace", func() (string, error) { return repo.ReplaceTurnHint(ctx, turn.ID, "🔥") }, "🔥"},
+			{"undo replace", func() (string, error) { return repo.UndoTurnHint(ctx, turn.ID) }, "🍎🍌"},
+			{"undo append", func() (string, error) { return repo.UndoTurnHint(ctx, turn.ID) }, "🍎"},
+			{"undo with empty history", func() (string, error) { return repo.UndoTurnHint(ctx, turn.ID) }, "🍎"},
+		}
+		for _, step := range steps {
+			got, err := step.do()
+			if err != nil {
+				t.Fatalf("%s: %v", step.name, err)
+			}
+			if got != step.want {
+				t.Errorf("%s: expected board %q but got %q", step.name, step.want, got)
+			}
+		}
+
+		if _, err = repo.AppendTurnHint(ctx, turn.ID, "🍇"); err != nil {
+			t.Fatal(err)
+		}
+		edits, err := repo.GetHintEdits(ctx, game.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Undone edits leave the stack; only the append that stayed is left.
+		if len(edits) != 1 || edits[0].TurnID != turn.ID || edits[0].Previous != "🍎" || edits[0].At.IsZero() {
+			t.Errorf("expected one edit from %q but got %+v", "🍎", edits)
+		}
+		if _, err = repo.UndoTurnHint(ctx, turn.ID); err != nil {
+			t.Fatal(err)
+		}
+
+		latest, err := repo.GetLatestTurn(ctx, game.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if latest.EmojiHint != "🍎" {
+			t.Errorf("expected stored board %q but got %q", "🍎", latest.EmojiHint)
+		}
+
+		if _, err = repo.AppendTurnHint(ctx, "missing-turn", "🍌"); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("expected sql.ErrNoRows for unknown turn but got %v", err)
+		}
+	})
 	t.Run("AddPlayer", func(t *testing.T) {
 		db := newTestDB(t)
 		repo := NewGameRepository(db)
 		seedList(t, db, "list-1", "Test List")
 
 		now := time.Now()
-		game, err := repo.Create(context.Background(), "list-1")
+		game, err := repo.Create(context.Background(), "list-1", model.GameSettings{})
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -364,7 +692,7 @@ func TestGameRepository(t *testing.T) {
 		seedList(t, db, "list-1", "Test List")
 
 		now := time.Now()
-		game, err := repo.Create(context.Background(), "list-1")
+		game, err := repo.Create(context.Background(), "list-1", model.GameSettings{})
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -410,13 +738,313 @@ func TestGameRepository(t *testing.T) {
 		}
 
 	})
+	t.Run("SetPlayerState keeps kicked players out", func(t *testing.T) {
+		db := newTestDB(t)
+		repo := NewGameRepository(db)
+		seedList(t, db, "list-1", "Test List")
+		ctx := context.Background()
+
+		now := time.Now()
+		game, err := repo.Create(ctx, "list-1", model.GameSettings{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = db.Exec("INSERT INTO users (id, nickname, created_at, updated_at) VALUES ('user-id', 'user-nickname', ?, ?);", now.UnixMicro(), now.UnixMicro())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = repo.AddPlayer(ctx, game.ID, "user-id"); err != nil {
+			t.Fatal(err)
+		}
+
+		if err = repo.SetPlayerState(ctx, game.ID, "user-id", model.KickedPlayerState); err != nil {
+			t.Fatal(err)
+		}
+		// A late disconnect must not turn the kick into a plain leave.
+		_ = repo.SetPlayerState(ctx, game.ID, "user-id", model.InactivePlayerState)
+
+		players, err := repo.GetPlayers(ctx, game.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if players[0].State != model.KickedPlayerState {
+			t.Errorf("expected player state %s but got %s", model.KickedPlayerState, players[0].State)
+		}
+	})
+	t.Run("SeenPlayer", func(t *testing.T) {
+		db := newTestDB(t)
+		repo := NewGameRepository(db)
+		seedList(t, db, "list-1", "Test List")
+		ctx := context.Background()
+
+		now := time.Now()
+		game, err := repo.Create(ctx, "list-1", model.GameSettings{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = db.Exec("INSERT INTO users (id, nickname, created_at, updated_at) VALUES ('user-id', 'user-nickname', ?, ?);", now.UnixMicro(), now.UnixMicro())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = repo.AddPlayer(ctx, game.ID, "user-id"); err != nil {
+			t.Fatal(err)
+		}
+
+		players, err := repo.GetPlayers(ctx, game.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !players[0].LastSeenAt.IsZero() {
+			t.Errorf("expected no last seen before a connection but got %v", players[0].LastSeenAt)
+		}
+
+		later := now.Add(time.Minute)
+		if err = repo.SeenPlayer(ctx, game.ID, "user-id", later); err != nil {
+			t.Fatal(err)
+		}
+		// A late write from a slower instance never moves it back.
+		if err = repo.SeenPlayer(ctx, game.ID, "user-id", now); err != nil {
+			t.Fatal(err)
+		}
+
+		players, err = repo.GetPlayers(ctx, game.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !players[0].LastSeenAt.Equal(later.Truncate(time.Microsecond)) {
+			t.Errorf("expected last seen %v but got %v", later, players[0].LastSeenAt)
+		}
+	})
+
+	t.Run("SetPlayerTeam", func(t *testing.T) {
+		db := newTestDB(t)
+		repo := NewGameRepository(db)
+		seedList(t, db, "list-1", "Test List")
+		ctx := context.Background()
+
+		now := time.Now()
+		game, err := repo.Create(ctx, "list-1", model.GameSettings{Teams: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = db.Exec("INSERT INTO users (id, nickname, created_at, updated_at) VALUES ('user-id', 'user-nickname', ?, ?);", now.UnixMicro(), now.UnixMicro())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = repo.AddPlayer(ctx, game.ID, "user-id"); err != nil {
+			t.Fatal(err)
+		}
+
+		players, err := repo.GetPlayers(ctx, game.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if players[0].Team != "" {
+			t.Errorf("expected no team before assignment but got %q", players[0].Team)
+		}
+
+		if err = repo.SetPlayerTeam(ctx, game.ID, "user-id", model.BlueTeam); err != nil {
+			t.Fatal(err)
+		}
+		// The side survives leaving, so a rejoin lands on the same team.
+		if err = repo.SetPlayerState(ctx, game.ID, "user-id", model.InactivePlayerState); err != nil {
+			t.Fatal(err)
+		}
+
+		players, err = repo.GetPlayers(ctx, game.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if players[0].Team != model.BlueTeam {
+			t.Errorf("expected team %s but got %q", model.BlueTeam, players[0].Team)
+		}
+	})
+	t.Run("Spectators", func(t *testing.T) {
+		db := newTestDB(t)
+		repo := NewGameRepository(db)
+		seedList(t, db, "list-1", "Test List")
+		ctx := context.Background()
+
+		now := time.Now()
+		game, err := repo.Create(ctx, "list-1", model.GameSettings{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = db.Exec("INSERT INTO users (id, nickname, created_at, updated_at) VALUES ('user-id', 'user-nickname', ?, ?);", now.UnixMicro(), now.UnixMicro())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = repo.AddSpectator(ctx, game.ID, "user-id", true); err != nil {
+			t.Fatal(err)
+		}
+
+		players, err := repo.GetPlayers(ctx, game.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if players[0].State != model.SpectatorPlayerState || players[0].QueuedAt.IsZero() {
+			t.Fatalf("expected a queued spectator but got %+v", players[0])
+		}
+		queuedAt := players[0].QueuedAt
+
+		// Asking again keeps the place in line; opting out gives it up.
+		if err = repo.SetSpectator(ctx, game.ID, "user-id", true); err != nil {
+			t.Fatal(err)
+		}
+		players, _ = repo.GetPlayers(ctx, game.ID)
+		if !players[0].QueuedAt.Equal(queuedAt) {
+			t.Errorf("expected queued_at to stay %v but got %v", queuedAt, players[0].QueuedAt)
+		}
+		if err = repo.SetSpectator(ctx, game.ID, "user-id", false); err != nil {
+			t.Fatal(err)
+		}
+		players, _ = repo.GetPlayers(ctx, game.ID)
+		if !players[0].QueuedAt.IsZero() {
+			t.Errorf("expected no queued_at but got %v", players[0].QueuedAt)
+		}
+
+		if err = repo.SetSpectator(ctx, game.ID, "user-id", true); err != nil {
+			t.Fatal(err)
+		}
+		if err = repo.SetPlayerState(ctx, game.ID, "user-id", model.ActivePlayerState); err != nil {
+			t.Fatal(err)
+		}
+		players, _ = repo.GetPlayers(ctx, game.ID)
+		if players[0].State != model.ActivePlayerState || !players[0].QueuedAt.IsZero() {
+			t.Errorf("expected a seated player out of the queue but got %+v", players[0])
+		}
+	})
+	t.Run("PromoteSpectator", func(t *testing.T) {
+		db := newTestDB(t)
+		repo := NewGameRepository(db)
+		seedList(t, db, "list-1", "Test List")
+		ctx := context.Background()
+
+		now := time.Now()
+		game, err := repo.Create(ctx, "list-1", model.GameSettings{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, id := range []string{"seated", "queued", "watcher"} {
+			_, err = db.Exec("INSERT INTO users (id, nickname, created_at, updated_at) VALUES (?, ?, ?, ?);", id, id, now.UnixMicro(), now.UnixMicro())
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err = repo.AddPlayer(ctx, game.ID, "seated"); err != nil {
+			t.Fatal(err)
+		}
+		if err = repo.AddSpectator(ctx, game.ID, "queued", true); err != nil {
+			t.Fatal(err)
+		}
+		if err = repo.AddSpectator(ctx, game.ID, "watcher", false); err != nil {
+			t.Fatal(err)
+		}
+
+		steps := []struct {
+			name       string
+			userID     string
+			maxPlayers int
+			want       bool
+		}{
+			{"room full", "queued", 1, false},
+			{"not in the queue", "watcher", 2, false},
+			{"free seat", "queued", 2, true},
+			{"already seated", "queued", 3, false},
+		}
+		for _, step := range steps {
+			got, err := repo.PromoteSpectator(ctx, game.ID, step.userID, step.maxPlayers)
+			if err != nil {
+				t.Fatalf("%s: %v", step.name, err)
+			}
+			if got != step.want {
+				t.Errorf("%s: expected seated=%v but got %v", step.name, step.want, got)
+			}
+		}
+
+		players, err := repo.GetPlayers(ctx, game.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, p := range players {
+			if p.ID == "queued" && (p.State != model.ActivePlayerState || !p.QueuedAt.IsZero()) {
+				t.Errorf("expected the promoted spectator seated and out of the queue but got %+v", p)
+			}
+		}
+	})
+	t.Run("SeatPlayer", func(t *testing.T) {
+		db := newTestDB(t)
+		repo := NewGameRepository(db)
+		seedList(t, db, "list-1", "Test List")
+		ctx := context.Background()
+
+		now := time.Now()
+		game, err := repo.Create(ctx, "list-1", model.GameSettings{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, id := range []string{"first", "back", "late", "watcher"} {
+			_, err = db.Exec("INSERT INTO users (id, nickname, created_at, updated_at) VALUES (?, ?, ?, ?);", id, id, now.UnixMicro(), now.UnixMicro())
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err = repo.AddPlayer(ctx, game.ID, "back"); err != nil {
+			t.Fatal(err)
+		}
+		if err = repo.SetPlayerState(ctx, game.ID, "back", model.InactivePlayerState); err != nil {
+			t.Fatal(err)
+		}
+		if err = repo.AddSpectator(ctx, game.ID, "watcher", false); err != nil {
+			t.Fatal(err)
+		}
+
+		steps := []struct {
+			name       string
+			userID     string
+			maxPlayers int
+			want       bool
+		}{
+			{"newcomer", "first", 2, true},
+			{"already seated", "first", 3, false},
+			{"spectators go through PromoteSpectator", "watcher", 3, false},
+			{"returning player", "back", 2, true},
+			{"room full", "late", 2, false},
+		}
+		for _, step := range steps {
+			got, err := repo.SeatPlayer(ctx, game.ID, step.userID, step.maxPlayers)
+			if err != nil {
+				t.Fatalf("%s: %v", step.name, err)
+			}
+			if got != step.want {
+				t.Errorf("%s: expected seated=%v but got %v", step.name, step.want, got)
+			}
+		}
+
+		players, err := repo.GetPlayers(ctx, game.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		states := map[string]model.PlayerState{}
+		for _, p := range players {
+			states[p.ID] = p.State
+		}
+		want := map[string]model.PlayerState{
+			"first":   model.ActivePlayerState,
+			"back":    model.ActivePlayerState,
+			"watcher": model.SpectatorPlayerState,
+		}
+		if !maps.Equal(states, want) {
+			t.Errorf("expected %v but got %v", want, states)
+		}
+	})
 	t.Run("GetPlayers", func(t *testing.T) {
 		db := newTestDB(t)
 		repo := NewGameRepository(db)
 		seedList(t, db, "list-1", "Test List")
 
 		now := time.Now()
-		game, err := repo.Create(context.Background(), "list-1")
+		game, err := repo.Create(context.Background(), "list-1", model.GameSettings{})
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -484,7 +1112,7 @@ func TestGameRepository(t *testing.T) {
 		seedList(t, db, "list-1", "Test List")
 
 		now := time.Now()
-		game, err := repo.Create(context.Background(), "list-1")
+		game, err := repo.Create(context.Background(), "list-1", model.GameSettings{})
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -609,9 +1237,11 @@ func TestGameRepository(t *testing.T) {
 			t.Fatal(err)
 		}
 
+		pickBy := time.UnixMicro(now.Add(30 * time.Second).UnixMicro())
 		turn, err := repo.AddTurn(context.Background(), AddTurnParams{
 			GameID: "game-id", TellerID: "teller-1",
 			OptionA: "word-id", OptionB: "w2", OptionC: "w3",
+			PickDeadline: pickBy,
 		})
 		if err != nil {
 			t.Fatal(err)
@@ -623,7 +1253,8 @@ func TestGameRepository(t *testing.T) {
 			t.Errorf("teller: got %q", turn.TellerID)
 		}
 
-		if err := repo.SetTurnWord(context.Background(), turn.ID, "w2", "🍎🍌"); err != nil {
+		endBy := time.UnixMicro(now.Add(time.Minute).UnixMicro())
+		if err := repo.SetTurnWord(context.Background(), turn.ID, "w2", "🍎🍌", endBy); err != nil {
 			t.Fatal(err)
 		}
 		got, err := repo.GetLatestTurn(context.Background(), "game-id")
@@ -639,6 +1270,145 @@ func TestGameRepository(t *testing.T) {
 		if got.StartedAt.IsZero() {
 			t.Error("started_at should be set after pick")
 		}
+		if !got.PickDeadline.Equal(pickBy) || !got.EndDeadline.Equal(endBy) {
+			t.Errorf("deadlines: got pick %v end %v, want %v and %v", got.PickDeadline, got.EndDeadline, pickBy, endBy)
+		}
+		if !got.EndedAt.IsZero() {
+			t.Error("ended_at should be unset while the turn runs")
+		}
+		if len(got.Revealed) != 0 {
+			t.Errorf("revealed should start empty, got %v", got.Revealed)
+		}
+
+		if err := repo.SetTurnRevealed(context.Background(), turn.ID, []int{3, 0}); err != nil {
+			t.Fatal(err)
+		}
+		got, err = repo.GetLatestTurn(context.Background(), "game-id")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(got.Revealed, []int{3, 0}) {
+			t.Errorf("revealed: got %v want [3 0]", got.Revealed)
+		}
+
+		endedAt := time.UnixMicro(now.Add(40 * time.Second).UnixMicro())
+		if err := repo.EndTurn(context.Background(), "game-id", endedAt); err != nil {
+			t.Fatal(err)
+		}
+		// Ending again (e.g. a recovered loop replaying the turn end) keeps the first stamp.
+		if err := repo.EndTurn(context.Background(), "game-id", endedAt.Add(time.Minute)); err != nil {
+			t.Fatal(err)
+		}
+		got, err = repo.GetLatestTurn(context.Background(), "game-id")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !got.EndedAt.Equal(endedAt) {
+			t.Errorf("ended_at: got %v want %v", got.EndedAt, endedAt)
+		}
+	})
+	t.Run("RerollTurnOptions and skip votes", func(t *testing.T) {
+		db := newTestDB(t)
+		repo := NewGameRepository(db)
+		ctx := context.Background()
+
+		now := time.Now()
+		_, err := db.Exec("INSERT INTO games (id, created_at, updated_at) VALUES ('game-id', ?, ?);", now.UnixMicro(), now.UnixMicro())
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = db.Exec("INSERT INTO words (id, word, hint) VALUES ('w1', 'w1', 'h'), ('w2', 'w2', 'h'), ('w3', 'w3', 'h'), ('w4', 'w4', 'h');")
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = db.Exec("INSERT INTO users (id, nickname, created_at, updated_at) VALUES ('user-id', 'user-nickname', ?, ?);", now.UnixMicro(), now.UnixMicro())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		turn, err := repo.AddTurn(ctx, AddTurnParams{GameID: "game-id", TellerID: "teller-1", OptionA: "w1", OptionB: "w2", OptionC: "w3"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := repo.RerollTurnOptions(ctx, turn.ID, "w4", "w4", "w4"); err != nil {
+			t.Fatal(err)
+		}
+		got, err := repo.GetLatestTurn(ctx, "game-id")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !got.Rerolled || got.OptionA != "w4" || got.OptionC != "w4" {
+			t.Errorf("expected rerolled options w4 but got %+v", got)
+		}
+		if err := repo.RerollTurnOptions(ctx, turn.ID, "w1", "w2", "w3"); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("second reroll: expected sql.ErrNoRows but got %v", err)
+		}
+
+		// Voting twice counts once.
+		for range 2 {
+			if err := repo.AddSkipVote(ctx, turn.ID, "user-id"); err != nil {
+				t.Fatal(err)
+			}
+		}
+		votes, err := repo.GetSkipVotes(ctx, turn.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(votes, []string{"user-id"}) {
+			t.Errorf("votes: got %v want [user-id]", votes)
+		}
+	})
+	t.Run("ListPlayingIDs", func(t *testing.T) {
+		db := newTestDB(t)
+		repo := NewGameRepository(db)
+
+		now := time.Now().UnixMicro()
+		_, err := db.Exec(`INSERT INTO games (id, status, created_at, updated_at) VALUES
+			('lobby', 'lobby', ?, ?), ('playing', 'playing', ?, ?), ('finished', 'finished', ?, ?);`,
+			now, now, now, now, now, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ids, err := repo.ListPlayingIDs(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(ids) != 1 || ids[0] != "playing" {
+			t.Errorf("expected [playing] but got %v", ids)
+		}
+	})
+	t.Run("ListOpenGameIDs", func(t *testing.T) {
+		db := newTestDB(t)
+		repo := NewGameRepository(db)
+
+		now := time.Now().UnixMicro()
+		_, err := db.Exec(`INSERT INTO games (id, status, created_at, updated_at) VALUES
+			('lobby', 'lobby', ?, ?), ('playing', 'playing', ?, ?), ('finished', 'finished', ?, ?), ('kicked', 'playing', ?, ?);`,
+			now, now, now, now, now, now, now, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = db.Exec("INSERT INTO users (id, nickname, created_at, updated_at) VALUES ('user-id', 'user-nickname', ?, ?);", now, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = db.Exec(`INSERT INTO players (game_id, player_id, state, joined_at) VALUES
+			('lobby', 'user-id', 'active', ?), ('playing', 'user-id', 'inactive', ?),
+			('finished', 'user-id', 'active', ?), ('kicked', 'user-id', 'kicked', ?);`,
+			now, now, now, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ids, err := repo.ListOpenGameIDs(context.Background(), "user-id")
+		if err != nil {
+			t.Fatal(err)
+		}
+		slices.Sort(ids)
+		if !slices.Equal(ids, []string{"lobby", "playing"}) {
+			t.Errorf("expected [lobby playing] but got %v", ids)
+		}
 	})
 	t.Run("GetLatestTurn", func(t *testing.T) {
 		db := newTestDB(t)
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"emojix/model"
 	"errors"
 	"testing"
 )
@@ -22,7 +23,7 @@ func TestUnitOfWork(t *testing.T) {
 			t.Fatal(err)
 		}
 
-		game, err := uow.GameRepository().Create(context.Background(), "list-1")
+		game, err := uow.GameRepository().Create(context.Background(), "list-1", model.GameSettings{})
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -55,7 +56,7 @@ func TestUnitOfWork(t *testing.T) {
 			t.Fatal(err)
 		}
 
-		game, err := uow.GameRepository().Create(context.Background(), "list-1")
+		game, err := uow.GameRepository().Create(context.Background(), "list-1", model.GameSettings{})
 		if err != nil {
 			t.Fatal(err)
 		}
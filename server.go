@@ -2,14 +2,19 @@ package emojix
 
 import (
 	"context"
+	"emojix/config"
 	"emojix/model"
 	"emojix/usecase"
 	"errors"
 	"fmt"
+	"html"
 	"io"
 	"log"
+	"log/slog"
+	"math"
 	"net/http"
-	"os"
+	"net/url"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -17,25 +22,33 @@ import (
 type webServer struct {
 	view          View
 	emojixUsecase usecase.EmojixUsecase
-	// kickDelay is how long the Sse handler waits before kicking an
-	// inactive user. It is a field (rather than a package var) so server
-	// tests can inject a near-zero duration without touching global state.
-	kickDelay time.Duration
+	cfg           config.Config
+	sessions      *sessionSigner
 }
 
-func NewWebServer(emojixUsecase usecase.EmojixUsecase, view View) *webServer {
+func NewWebServer(emojixUsecase usecase.EmojixUsecase, view View, cfg config.Config) (*webServer, error) {
+	sessions, err := newSessionSigner(cfg.SessionKeys)
+	if err != nil {
+		return nil, err
+	}
+	if len(cfg.SessionKeys) == 0 {
+		slog.Warn("no session keys configured, sessions end on restart")
+	}
 	return &webServer{
 		view:          view,
 		emojixUsecase: emojixUsecase,
-		kickDelay:     defaultKickDelay,
-	}
+		cfg:           cfg,
+		sessions:      sessions,
+	}, nil
 }
 
-// mux returns the router with every route registered. It is shared by Start
-// and by routing tests so the test exercises the real route table.
-func (e *webServer) mux() *http.ServeMux {
+// mux returns the router with every route registered, behind the CSRF check.
+// It is shared by Start and by routing tests so the test exercises the real
+// route table.
+func (e *webServer) mux() http.Handler {
 	mux := http.NewServeMux()
-	mux.Handle("GET /static/", http.FileServer(http.Dir("./")))
+	mux.Handle("GET /static/", http.StripPrefix("/static/", http.FileServer(http.Dir(e.cfg.StaticDir))))
+	mux.HandleFunc("POST /profile", e.UpdateProfile)
 	mux.HandleFunc("POST /game/new", e.NewGame)
 	mux.HandleFunc("GET /game/join", e.JoinGame)
 	mux.HandleFunc("GET /game/{id}/join", e.JoinGame)
@@ -45,130 +58,291 @@ func (e *webServer) mux() *http.ServeMux {
 	mux.HandleFunc("POST /game/{id}/message", e.Message)
 	mux.HandleFunc("POST /game/{id}/guess", e.Guess)
 	mux.HandleFunc("POST /game/{id}/pick", e.PickWord)
+	mux.HandleFunc("POST /game/{id}/reroll", e.RerollWords)
+	mux.HandleFunc("POST /game/{id}/giveup", e.GiveUpTurn)
+	mux.HandleFunc("POST /game/{id}/voteskip", e.VoteSkip)
+	mux.HandleFunc("POST /game/{id}/hint", e.Hint)
+	mux.HandleFunc("POST /game/{id}/rematch", e.Rematch)
+	mux.HandleFunc("GET /game/{id}/history", e.History)
+	mux.HandleFunc("POST /game/{id}/kick", e.KickPlayer)
+	mux.HandleFunc("POST /game/{id}/lock", e.LockRoom)
+	mux.HandleFunc("POST /game/{id}/host", e.TransferHost)
+	mux.HandleFunc("POST /game/{id}/skip", e.SkipTurn)
+	mux.HandleFunc("POST /game/{id}/presence", e.Presence)
+	mux.HandleFunc("POST /game/{id}/team", e.PickTeam)
+	mux.HandleFunc("POST /game/{id}/spectate", e.Spectate)
+	mux.HandleFunc("POST /game/{id}/play", e.Play)
+	mux.HandleFunc("GET /join/{code}", e.JoinByCode)
 	mux.HandleFunc("GET /game/{id}/sse", e.Sse)
+	mux.HandleFunc("GET /lists", e.Lists)
+	mux.HandleFunc("POST /lists", e.CreateList)
+	mux.HandleFunc("GET /lists/{id}", e.List)
+	mux.HandleFunc("POST /lists/{id}/words", e.AddWord)
+	mux.HandleFunc("POST /lists/{id}/words/{wordID}", e.UpdateWord)
+	mux.HandleFunc("POST /lists/{id}/words/{wordID}/delete", e.DeleteWord)
+	e.registerAPI(mux)
 	mux.HandleFunc("GET /init", e.InitSession)
 	mux.HandleFunc("GET /", e.Index)
-	return mux
+	return e.csrf(mux)
 }
 
-func (e *webServer) Start() {
-	log.Fatal(http.ListenAndServe("0.0.0.0:9000", e.mux()))
-}
+// Start serves on addr until ctx is done, then shuts down gracefully: the
+// usecase stops taking joins, stops its loops and ends the SSE streams, after
+// which in-flight requests get to finish. Both share the timeout.
+func (e *webServer) Start(ctx context.Context, addr string, timeout time.Duration) error {
+	srv := &http.Server{Addr: addr, Handler: e.mux()}
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- srv.ListenAndServe()
+	}()
 
-func (e *webServer) handleError(w http.ResponseWriter, err error, msg string) {
-	log.Printf("%s: %v\n", msg, err)
-	w.WriteHeader(http.StatusInternalServerError)
-	_ = e.view.renderErrorPage(w)
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+	}
+
+	log.Println("shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	drainErr := e.emojixUsecase.Shutdown(shutdownCtx)
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	return drainErr
 }
 
-const userIdCookieKey = "userid"
+// errorTarget is the element in base.gohtml inline errors are swapped into.
+const errorTarget = "#error-flash"
+
+// handleError answers err with the status its usecase.ErrorKind maps to.
+// HTMX requests get a small fragment retargeted at errorTarget, so the page
+// they came from stays put; full page loads get the error page. Only
+// unclassified errors are logged (with msg), and their details stay private.
+func (e *webServer) handleError(w http.ResponseWriter, r *http.Request, err error, msg string) {
+	status := errorStatus(err)
+	param := ErrorViewParam{Status: status}
+	setRetryAfter(w, err)
+	if status == http.StatusInternalServerError {
+		log.Printf("%s: %v\n", msg, err)
+	} else {
+		param.Message = err.Error()
+	}
 
-const nicknameCookieKey = "nickname"
+	if r.Header.Get("HX-Request") == "true" {
+		w.Header().Set("HX-Retarget", errorTarget)
+		w.Header().Set("HX-Reswap", "innerHTML")
+		w.WriteHeader(status)
+		_ = e.view.renderErrorFragment(w, param)
+		return
+	}
+	w.WriteHeader(status)
+	_ = e.view.renderErrorPage(w, param)
+}
 
-// defaultKickDelay is how long the Sse handler waits before kicking an
-// inactive user in production.
-const defaultKickDelay = time.Second * 30
+// setRetryAfter tells a client refused by a rate limit when to come back.
+func setRetryAfter(w http.ResponseWriter, err error) {
+	var slow *usecase.SlowDownError
+	if errors.As(err, &slow) {
+		secs := int(math.Ceil(slow.RetryAfter.Seconds()))
+		w.Header().Set("Retry-After", strconv.Itoa(max(secs, 1)))
+	}
+}
 
-type Session struct {
-	UserID   string
-	Nickname string
+func errorStatus(err error) int {
+	switch usecase.KindOf(err) {
+	case usecase.KindValidation:
+		return http.StatusBadRequest
+	case usecase.KindForbidden:
+		return http.StatusForbidden
+	case usecase.KindNotFound:
+		return http.StatusNotFound
+	case usecase.KindConflict:
+		return http.StatusConflict
+	case usecase.KindGone:
+		return http.StatusGone
+	case usecase.KindUnavailable:
+		return http.StatusServiceUnavailable
+	case usecase.KindRateLimited:
+		return http.StatusTooManyRequests
+	default:
+		return http.StatusInternalServerError
+	}
 }
 
-func (e *webServer) getSession(w http.ResponseWriter, r *http.Request) (Session, error) {
-	redirectToInit := func() {
-		// POST/non-GET targets are not safe to replay after /init (which always
-		// finishes with a GET redirect), so fall back to home in that case.
-		from := r.URL.Path
-		if r.Method != http.MethodGet && r.Method != http.MethodHead {
-			from = "/"
+func (e *webServer) InitSession(w http.ResponseWriter, r *http.Request) {
+	fromUrl := r.URL.Query().Get("from")
+	if fromUrl == "" {
+		fromUrl = "/"
+	}
+
+	// Reuse a still-valid session instead of minting a new user every visit.
+	if _, err := e.sessionUser(w, r); err == nil {
+		http.Redirect(w, r, fromUrl, http.StatusFound)
+		return
+	}
+
+	user, err := e.emojixUsecase.InitUser(r.Context())
+	if err != nil {
+		e.handleError(w, r, err, "failed to init user")
+		return
+	}
+
+	w.Header().Add("Set-Cookie", e.setCookie(sessionCookieKey, e.sessions.sign(user.ID)))
+	for _, key := range []string{legacyUserIDCookieKey, legacyNicknameCookieKey} {
+		if _, err := r.Cookie(key); err == nil {
+			w.Header().Add("Set-Cookie", expireCookie(key))
 		}
-		toUrl := fmt.Sprintf("/init?from=%s", from)
-		http.Redirect(w, r, toUrl, http.StatusFound)
 	}
 
-	userIdCookie, err := r.Cookie(userIdCookieKey)
+	http.Redirect(w, r, fromUrl, http.StatusFound)
+}
+
+func (e *webServer) Index(w http.ResponseWriter, r *http.Request) {
+	session, err := e.getSession(w, r)
+	if err != nil {
+		log.Println("no session redirecting to /init")
+		return
+	}
+
+	lists, err := e.emojixUsecase.ListWordLists(r.Context())
 	if err != nil {
-		redirectToInit()
-		return Session{}, err
+		e.handleError(w, r, err, "failed to load word lists")
+		return
 	}
 
-	nicknameCookie, err := r.Cookie(nicknameCookieKey)
+	err = e.view.renderIndexPage(w, IndexPageViewParam{
+		PageViewParam: PageViewParam{CSRFToken: session.CSRFToken},
+		Title:         "Emojix!",
+		Nickname:      session.Nickname,
+		Avatar:        session.Avatar,
+		Lists:         lists,
+		Settings:      e.cfg.GameDefaults,
+	})
 	if err != nil {
-		redirectToInit()
-		return Session{}, err
+		e.handleError(w, r, err, "failed to render template")
+		return
 	}
+}
 
-	// Cookies can outlive the DB (common when resetting local state). If the
-	// user row is gone, force a fresh /init instead of failing later with an FK error.
-	_, err = e.emojixUsecase.GetUser(r.Context(), userIdCookie.Value)
+// UpdateProfile saves the nickname and avatar from the lobby form and goes
+// back to the lobby.
+func (e *webServer) UpdateProfile(w http.ResponseWriter, r *http.Request) {
+	session, err := e.getSession(w, r)
 	if err != nil {
-		redirectToInit()
-		return Session{}, err
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		e.handleError(w, r, err, "failed to parse form")
+		return
 	}
 
-	return Session{
-		UserID:   userIdCookie.Value,
-		Nickname: nicknameCookie.Value,
-	}, nil
+	_, err = e.emojixUsecase.UpdateProfile(r.Context(), session.UserID, r.PostForm.Get("nickname"), r.PostForm.Get("avatar"))
+	if err != nil {
+		e.handleError(w, r, err, "failed to update profile")
+		return
+	}
+
+	http.Redirect(w, r, "/", http.StatusSeeOther)
 }
 
-func setCookie(key string, value string) string {
-	cookieOptions := []string{"Path=/", "HttpOnly"}
+func (e *webServer) Lists(w http.ResponseWriter, r *http.Request) {
+	session, err := e.getSession(w, r)
+	if err != nil {
+		return
+	}
 
-	// TODO: setup a general config and consume here
-	if os.Getenv("ENV") == "prod" {
-		cookieOptions = append(cookieOptions, "Secure")
+	lists, err := e.emojixUsecase.ListWordLists(r.Context())
+	if err != nil {
+		e.handleError(w, r, err, "failed to load word lists")
+		return
 	}
 
-	return fmt.Sprintf("%s=%s; %s", key, value, strings.Join(cookieOptions, "; "))
+	page := PageViewParam{CSRFToken: session.CSRFToken}
+	if err = e.view.renderListsPage(w, ListsPageViewParam{PageViewParam: page, Lists: lists}); err != nil {
+		e.handleError(w, r, err, "failed to render template")
+	}
 }
 
-func (e *webServer) InitSession(w http.ResponseWriter, r *http.Request) {
-	fromUrl := r.URL.Query().Get("from")
-	if fromUrl == "" {
-		fromUrl = "/"
+func (e *webServer) CreateList(w http.ResponseWriter, r *http.Request) {
+	session, err := e.getSession(w, r)
+	if err != nil {
+		return
 	}
 
-	// Reuse a still-valid session instead of minting a new user every visit.
-	if userIDCookie, err := r.Cookie(userIdCookieKey); err == nil {
-		if _, err := r.Cookie(nicknameCookieKey); err == nil {
-			if _, err := e.emojixUsecase.GetUser(r.Context(), userIDCookie.Value); err == nil {
-				http.Redirect(w, r, fromUrl, http.StatusFound)
-				return
-			}
-		}
+	list, err := e.emojixUsecase.CreateWordList(r.Context(), session.UserID, r.FormValue("title"))
+	if err != nil {
+		e.handleError(w, r, err, "failed to create list")
+		return
 	}
 
-	user, err := e.emojixUsecase.InitUser(r.Context())
+	http.Redirect(w, r, fmt.Sprintf("/lists/%s", list.ID), http.StatusSeeOther)
+}
+
+func (e *webServer) List(w http.ResponseWriter, r *http.Request) {
+	session, err := e.getSession(w, r)
 	if err != nil {
-		e.handleError(w, err, "failed to init user")
 		return
 	}
 
-	w.Header().Add("Set-Cookie", setCookie(userIdCookieKey, user.ID))
-	w.Header().Add("Set-Cookie", setCookie(nicknameCookieKey, user.Nickname))
+	list, words, err := e.emojixUsecase.GetWordList(r.Context(), r.PathValue("id"))
+	if err != nil {
+		e.handleError(w, r, err, "failed to load word list")
+		return
+	}
 
-	http.Redirect(w, r, fromUrl, http.StatusFound)
+	page := PageViewParam{CSRFToken: session.CSRFToken}
+	param := ListPageViewParam{PageViewParam: page, List: list, Words: words, CanEdit: list.OwnerID != "" && list.OwnerID == session.UserID}
+	if err = e.view.renderListPage(w, param); err != nil {
+		e.handleError(w, r, err, "failed to render template")
+	}
 }
 
-func (e *webServer) Index(w http.ResponseWriter, r *http.Request) {
+func (e *webServer) AddWord(w http.ResponseWriter, r *http.Request) {
 	session, err := e.getSession(w, r)
 	if err != nil {
-		log.Println("no session redirecting to /init")
 		return
 	}
+	listID := r.PathValue("id")
 
-	lists, err := e.emojixUsecase.ListWordLists(r.Context())
+	_, err = e.emojixUsecase.AddWord(r.Context(), session.UserID, listID, r.FormValue("word"), r.FormValue("hint"))
+	if err != nil {
+		e.handleError(w, r, err, "failed to add word")
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/lists/%s", listID), http.StatusSeeOther)
+}
+
+func (e *webServer) UpdateWord(w http.ResponseWriter, r *http.Request) {
+	session, err := e.getSession(w, r)
+	if err != nil {
+		return
+	}
+	listID := r.PathValue("id")
+
+	err = e.emojixUsecase.UpdateWord(r.Context(), session.UserID, r.PathValue("wordID"), r.FormValue("word"), r.FormValue("hint"))
 	if err != nil {
-		e.handleError(w, err, "failed to load word lists")
+		e.handleError(w, r, err, "failed to update word")
 		return
 	}
 
-	err = e.view.renderIndexPage(w, IndexPageViewParam{Title: "Emojix!", Nickname: session.Nickname, Lists: lists})
+	http.Redirect(w, r, fmt.Sprintf("/lists/%s", listID), http.StatusSeeOther)
+}
+
+func (e *webServer) DeleteWord(w http.ResponseWriter, r *http.Request) {
+	session, err := e.getSession(w, r)
 	if err != nil {
-		e.handleError(w, err, "failed to render template")
 		return
 	}
+	listID := r.PathValue("id")
+
+	if err := e.emojixUsecase.DeleteWord(r.Context(), session.UserID, r.PathValue("wordID")); err != nil {
+		e.handleError(w, r, err, "failed to delete word")
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/lists/%s", listID), http.StatusSeeOther)
 }
 
 func (e *webServer) JoinGame(w http.ResponseWriter, r *http.Request) {
@@ -183,9 +357,17 @@ func (e *webServer) JoinGame(w http.ResponseWriter, r *http.Request) {
 
 	ctx := r.Context()
 
-	err = e.emojixUsecase.JoinGame(ctx, gameID, session.UserID)
-	if err != nil {
-		e.handleError(w, err, "failed to join")
+	// The lobby's code field takes either a game id or an invite code.
+	if r.PathValue("id") == "" && usecase.IsInviteCode(gameID) {
+		e.joinByCode(w, r, session, gameID)
+		return
+	}
+
+	// Joining twice (a reload, a second tab) just lands on the game.
+	spectate := r.URL.Query().Get("spectate") != ""
+	err = e.emojixUsecase.JoinGame(ctx, gameID, session.UserID, spectate)
+	if err != nil && !errors.Is(err, usecase.ErrJoinGameUserAlreadyJoined) {
+		e.handleError(w, r, err, "failed to join")
 		return
 	}
 
@@ -193,6 +375,25 @@ func (e *webServer) JoinGame(w http.ResponseWriter, r *http.Request) {
 	http.Redirect(w, r, gameUrl, http.StatusFound)
 }
 
+// JoinByCode is the shareable invite link; it is the only way for a new
+// player into a private room.
+func (e *webServer) JoinByCode(w http.ResponseWriter, r *http.Request) {
+	session, err := e.getSession(w, r)
+	if err != nil {
+		return
+	}
+	e.joinByCode(w, r, session, r.PathValue("code"))
+}
+
+func (e *webServer) joinByCode(w http.ResponseWriter, r *http.Request, session Session, code string) {
+	game, err := e.emojixUsecase.JoinGameByCode(r.Context(), code, session.UserID)
+	if err != nil {
+		e.handleError(w, r, err, "failed to join")
+		return
+	}
+	http.Redirect(w, r, fmt.Sprintf("/game/%s", game.ID), http.StatusFound)
+}
+
 func (e *webServer) NewGame(w http.ResponseWriter, r *http.Request) {
 	session, err := e.getSession(w, r)
 	if err != nil {
@@ -201,24 +402,62 @@ func (e *webServer) NewGame(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	if err := r.ParseForm(); err != nil {
-		e.handleError(w, err, "failed to parse form")
+		e.handleError(w, r, err, "failed to parse form")
 		return
 	}
 	listID := r.PostForm.Get("list-id")
 	if listID == "" {
-		http.Error(w, "list-id required", http.StatusBadRequest)
+		e.handleError(w, r, usecase.NewError(usecase.KindValidation, "list-id required"), "")
+		return
+	}
+	settings, err := parseGameSettings(r.PostForm)
+	if err != nil {
+		e.handleError(w, r, err, "")
 		return
 	}
 
-	game, err := e.emojixUsecase.InitGame(ctx, session.UserID, listID)
+	game, err := e.emojixUsecase.InitGame(ctx, session.UserID, listID, settings)
 	if err != nil {
-		e.handleError(w, err, "failed to create game")
+		e.handleError(w, r, err, "failed to create game")
 		return
 	}
 
 	http.Redirect(w, r, fmt.Sprintf("/game/%s", game.ID), http.StatusSeeOther)
 }
 
+// parseGameSettings reads the optional room rules from the new-game form.
+// Blank fields stay zero so the usecase applies its defaults.
+func parseGameSettings(form url.Values) (model.GameSettings, error) {
+	settings := model.GameSettings{}
+	fields := []struct {
+		key string
+		set func(n int)
+	}{
+		{"turn-seconds", func(n int) { settings.TurnDuration = time.Duration(n) * time.Second }},
+		{"pick-seconds", func(n int) { settings.PickDuration = time.Duration(n) * time.Second }},
+		{"min-players", func(n int) { settings.MinPlayers = n }},
+		{"max-players", func(n int) { settings.MaxPlayers = n }},
+		{"rounds", func(n int) { settings.Rounds = n }},
+		{"steal-seconds", func(n int) { settings.StealDelay = time.Duration(n) * time.Second }},
+	}
+	for _, f := range fields {
+		v := strings.TrimSpace(form.Get(f.key))
+		if v == "" {
+			continue
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			return settings, fmt.Errorf("%w: %s must be a positive number", usecase.ErrInvalidGameSettings, f.key)
+		}
+		f.set(n)
+	}
+	settings.Scoring = strings.TrimSpace(form.Get("scoring"))
+	settings.Reveals = strings.TrimSpace(form.Get("reveals"))
+	settings.Private = form.Get("private") != ""
+	settings.Teams = form.Get("teams") != ""
+	return settings, nil
+}
+
 func (e *webServer) Game(w http.ResponseWriter, r *http.Request) {
 	session, err := e.getSession(w, r)
 	if err != nil {
@@ -231,24 +470,28 @@ func (e *webServer) Game(w http.ResponseWriter, r *http.Request) {
 	gameState, err := e.emojixUsecase.GameState(ctx, gameID, session.UserID)
 	if err != nil {
 		if errors.Is(err, usecase.ErrUserNotInGame) {
-			if joinErr := e.emojixUsecase.JoinGame(ctx, gameID, session.UserID); joinErr != nil {
-				e.handleError(w, joinErr, "failed to join")
+			if joinErr := e.emojixUsecase.JoinGame(ctx, gameID, session.UserID, false); joinErr != nil {
+				e.handleError(w, r, joinErr, "failed to join")
 				return
 			}
 			http.Redirect(w, r, fmt.Sprintf("/game/%s", gameID), http.StatusFound)
 			return
 		}
-		e.handleError(w, err, "failed to load game")
+		e.handleError(w, r, err, "failed to load game")
 		return
 	}
 
 	pageData := GamePageViewParam{
+		PageViewParam:     PageViewParam{CSRFToken: session.CSRFToken},
 		GameID:            gameState.GameID,
+		ShareBase:         e.cfg.BaseURL,
 		Leaderboard:       gameState.Leaderboard,
 		Messages:          gameState.Messages,
 		MaskedWord:        strings.Split(gameState.Word, ""),
 		EmojiHint:         gameState.Hint,
 		TurnStartedAt:     gameState.TurnStartedAt,
+		TurnDuration:      gameState.Settings.TurnDuration,
+		PickDuration:      gameState.Settings.PickDuration,
 		AwaitingPick:      gameState.AwaitingPick,
 		WaitingForPlayers: gameState.WaitingForPlayers,
 		IsTeller:          gameState.IsTeller,
@@ -258,12 +501,174 @@ func (e *webServer) Game(w http.ResponseWriter, r *http.Request) {
 		WordOptions:       gameState.WordOptions,
 		TurnEnded:         gameState.TurnEnded,
 		EmojiKeyboard:     TellerEmojiKeyboard,
+		GameOver:          gameState.Status == model.FinishedGameStatus,
+		Podium:            gameState.Podium,
+		TurnResults:       gameState.TurnResults,
+		IsHost:            gameState.IsHost,
+		HostID:            gameState.HostID,
+		InviteCode:        gameState.InviteCode,
+		Private:           gameState.Settings.Private,
+		Locked:            gameState.Locked,
+		Teams:             gameState.Settings.Teams,
+		TeamLeaderboard:   gameState.TeamLeaderboard,
+		MyTeam:            gameState.MyTeam,
+		TellerTeam:        gameState.TellerTeam,
+		StealOpensAt:      gameState.StealOpensAt,
+		IsSpectator:       gameState.IsSpectator,
+		QueuePosition:     gameState.QueuePosition,
+		SpectatorCount:    gameState.SpectatorCount,
+		CanReroll:         gameState.CanReroll,
+		SkipVotes:         gameState.SkipVotes,
+		SkipVotesNeeded:   gameState.SkipVotesNeeded,
+		VotedSkip:         gameState.VotedSkip,
 	}
 	err = e.view.renderGamePage(w, pageData)
 	if err != nil {
-		e.handleError(w, err, "failed to render page")
+		e.handleError(w, r, err, "failed to render page")
+		return
+	}
+}
+
+// Rematch sends the caller to the follow-up game of a finished one; the Game
+// handler seats them on arrival.
+func (e *webServer) Rematch(w http.ResponseWriter, r *http.Request) {
+	session, err := e.getSession(w, r)
+	if err != nil {
 		return
 	}
+	gameID := r.PathValue("id")
+
+	game, err := e.emojixUsecase.Rematch(r.Context(), gameID, session.UserID)
+	if err != nil {
+		e.handleError(w, r, err, "failed to start rematch")
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/game/%s", game.ID), http.StatusSeeOther)
+}
+
+// History shows a finished or abandoned game turn by turn, with a replay of
+// its events.
+func (e *webServer) History(w http.ResponseWriter, r *http.Request) {
+	session, err := e.getSession(w, r)
+	if err != nil {
+		return
+	}
+
+	history, err := e.emojixUsecase.GameHistory(r.Context(), r.PathValue("id"), session.UserID)
+	if err != nil {
+		e.handleError(w, r, err, "failed to load game history")
+		return
+	}
+
+	page := PageViewParam{CSRFToken: session.CSRFToken}
+	if err = e.view.renderHistoryPage(w, HistoryPageViewParam{PageViewParam: page, History: history}); err != nil {
+		e.handleError(w, r, err, "failed to render template")
+	}
+}
+
+func (e *webServer) KickPlayer(w http.ResponseWriter, r *http.Request) {
+	e.hostAction(w, r, func(ctx context.Context, gameID, userID string) error {
+		return e.emojixUsecase.KickPlayer(ctx, gameID, userID, r.FormValue("player-id"))
+	})
+}
+
+// Presence records that the player's tab went to the background ("away") or
+// came back ("back").
+func (e *webServer) Presence(w http.ResponseWriter, r *http.Request) {
+	session, err := e.getSession(w, r)
+	if err != nil {
+		return
+	}
+
+	var away bool
+	switch r.FormValue("state") {
+	case "away":
+		away = true
+	case "back":
+	default:
+		e.handleError(w, r, usecase.NewError(usecase.KindValidation, "state must be away or back"), "bad presence state")
+		return
+	}
+
+	if err = e.emojixUsecase.SetAway(r.Context(), r.PathValue("id"), session.UserID, away); err != nil {
+		e.handleError(w, r, err, "failed to set presence")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (e *webServer) LockRoom(w http.ResponseWriter, r *http.Request) {
+	e.hostAction(w, r, func(ctx context.Context, gameID, userID string) error {
+		return e.emojixUsecase.LockRoom(ctx, gameID, userID, r.FormValue("locked") == "true")
+	})
+}
+
+func (e *webServer) TransferHost(w http.ResponseWriter, r *http.Request) {
+	e.hostAction(w, r, func(ctx context.Context, gameID, userID string) error {
+		return e.emojixUsecase.TransferHost(ctx, gameID, userID, r.FormValue("player-id"))
+	})
+}
+
+func (e *webServer) SkipTurn(w http.ResponseWriter, r *http.Request) {
+	e.hostAction(w, r, func(ctx context.Context, gameID, userID string) error {
+		return e.emojixUsecase.SkipTurn(ctx, gameID, userID)
+	})
+}
+
+// PickTeam moves the caller to the posted team while the room is in the
+// lobby; the room hears about it over SSE.
+func (e *webServer) PickTeam(w http.ResponseWriter, r *http.Request) {
+	session, err := e.getSession(w, r)
+	if err != nil {
+		return
+	}
+
+	if err = e.emojixUsecase.PickTeam(r.Context(), r.PathValue("id"), session.UserID, r.FormValue("team")); err != nil {
+		e.handleError(w, r, err, "failed to pick team")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Spectate moves the caller to the stands; Play queues a spectator for a
+// seat. The page swaps over on the resulting SSE event.
+func (e *webServer) Spectate(w http.ResponseWriter, r *http.Request) {
+	e.playerAction(w, r, e.emojixUsecase.Spectate, "failed to spectate")
+}
+
+func (e *webServer) Play(w http.ResponseWriter, r *http.Request) {
+	e.playerAction(w, r, e.emojixUsecase.Play, "failed to queue for a seat")
+}
+
+// playerAction runs an action on the caller's own seat or turn and answers
+// 204; the page updates from the resulting SSE event.
+func (e *webServer) playerAction(w http.ResponseWriter, r *http.Request, action func(ctx context.Context, gameID, userID string) error, msg string) {
+	session, err := e.getSession(w, r)
+	if err != nil {
+		return
+	}
+
+	if err = action(r.Context(), r.PathValue("id"), session.UserID); err != nil {
+		e.handleError(w, r, err, msg)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// hostAction runs a host-only control and answers 204; the room learns the
+// outcome over SSE.
+func (e *webServer) hostAction(w http.ResponseWriter, r *http.Request, action func(ctx context.Context, gameID, userID string) error) {
+	session, err := e.getSession(w, r)
+	if err != nil {
+		return
+	}
+
+	if err = action(r.Context(), r.PathValue("id"), session.UserID); err != nil {
+		e.handleError(w, r, err, "failed to run host action")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
 }
 
 func (e *webServer) Message(w http.ResponseWriter, r *http.Request) {
@@ -278,7 +683,7 @@ func (e *webServer) Message(w http.ResponseWriter, r *http.Request) {
 	// get message content from form body content field
 	err = r.ParseForm()
 	if err != nil {
-		e.handleError(w, err, "failed to parse form")
+		e.handleError(w, r, err, "failed to parse form")
 		return
 	}
 
@@ -286,14 +691,14 @@ func (e *webServer) Message(w http.ResponseWriter, r *http.Request) {
 
 	err = e.emojixUsecase.Message(ctx, gameID, session.UserID, content)
 	if err != nil {
-		e.handleError(w, err, "failed to send message")
+		e.handleError(w, r, err, "failed to send message")
 		return
 	}
 
-	msg := model.GameStateMessage{Me: true, Content: content, Nickname: session.Nickname}
+	msg := model.GameStateMessage{PlayerID: session.UserID, Me: true, Content: content, Nickname: session.Nickname}
 	err = e.view.renderGameMsg(w, msg)
 	if err != nil {
-		e.handleError(w, err, "failed to render")
+		e.handleError(w, r, err, "failed to render")
 		return
 	}
 }
@@ -305,17 +710,76 @@ func (e *webServer) PickWord(w http.ResponseWriter, r *http.Request) {
 	}
 	gameID := r.PathValue("id")
 	if err := r.ParseForm(); err != nil {
-		e.handleError(w, err, "failed to parse form")
+		e.handleError(w, r, err, "failed to parse form")
 		return
 	}
 	wordID := r.PostForm.Get("word-id")
 	if err := e.emojixUsecase.PickWord(r.Context(), gameID, session.UserID, wordID); err != nil {
-		e.handleError(w, err, "failed to pick word")
+		e.handleError(w, r, err, "failed to pick word")
 		return
 	}
 	http.Redirect(w, r, fmt.Sprintf("/game/%s", gameID), http.StatusSeeOther)
 }
 
+// RerollWords deals the teller new options and reloads the page, like
+// PickWord, so it works without JS.
+func (e *webServer) RerollWords(w http.ResponseWriter, r *http.Request) {
+	session, err := e.getSession(w, r)
+	if err != nil {
+		return
+	}
+	gameID := r.PathValue("id")
+	if err := e.emojixUsecase.RerollWords(r.Context(), gameID, session.UserID); err != nil {
+		e.handleError(w, r, err, "failed to reroll words")
+		return
+	}
+	http.Redirect(w, r, fmt.Sprintf("/game/%s", gameID), http.StatusSeeOther)
+}
+
+// GiveUpTurn ends the caller's turn as teller; VoteSkip adds a guesser's
+// vote to skip it.
+func (e *webServer) GiveUpTurn(w http.ResponseWriter, r *http.Request) {
+	e.playerAction(w, r, e.emojixUsecase.GiveUpTurn, "failed to give up the turn")
+}
+
+func (e *webServer) VoteSkip(w http.ResponseWriter, r *http.Request) {
+	e.playerAction(w, r, e.emojixUsecase.VoteSkip, "failed to vote to skip")
+}
+
+// Hint edits the teller's emoji board. The new board reaches every client,
+// the teller included, through the hintupdated SSE event, so the response is
+// empty.
+func (e *webServer) Hint(w http.ResponseWriter, r *http.Request) {
+	session, err := e.getSession(w, r)
+	if err != nil {
+		return
+	}
+	gameID := r.PathValue("id")
+	if err := r.ParseForm(); err != nil {
+		e.handleError(w, r, err, "failed to parse form")
+		return
+	}
+	content := r.PostForm.Get("content")
+	ctx := r.Context()
+
+	switch r.PostForm.Get("action") {
+	case "append":
+		_, err = e.emojixUsecase.AppendHint(ctx, gameID, session.UserID, content)
+	case "replace":
+		_, err = e.emojixUsecase.ReplaceHint(ctx, gameID, session.UserID, content)
+	case "undo":
+		_, err = e.emojixUsecase.UndoHint(ctx, gameID, session.UserID)
+	default:
+		err = usecase.NewError(usecase.KindValidation, "action must be append, replace or undo")
+	}
+	if err != nil {
+		e.handleError(w, r, err, "failed to edit hint")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
 func (e *webServer) Guess(w http.ResponseWriter, r *http.Request) {
 	session, err := e.getSession(w, r)
 	if err != nil {
@@ -327,7 +791,7 @@ func (e *webServer) Guess(w http.ResponseWriter, r *http.Request) {
 	// get message content from form body content field
 	err = r.ParseForm()
 	if err != nil {
-		e.handleError(w, err, "failed to parse form")
+		e.handleError(w, r, err, "failed to parse form")
 		return
 	}
 
@@ -336,7 +800,7 @@ func (e *webServer) Guess(w http.ResponseWriter, r *http.Request) {
 	// process message
 	correct, err := e.emojixUsecase.Guess(ctx, gameID, session.UserID, content)
 	if err != nil {
-		e.handleError(w, err, "failed to process guess")
+		e.handleError(w, r, err, "failed to process guess")
 		return
 	}
 
@@ -347,12 +811,13 @@ func (e *webServer) Guess(w http.ResponseWriter, r *http.Request) {
 		msg.IsSystem = true
 	} else {
 		w.Header().Set("Hx-Trigger", "wrongguess")
+		msg.PlayerID = session.UserID
 		msg.Content = content
 		msg.IsGuess = true
 	}
 	err = e.view.renderGameMsg(w, msg)
 	if err != nil {
-		e.handleError(w, err, "failed to render")
+		e.handleError(w, r, err, "failed to render")
 		return
 	}
 }
@@ -367,14 +832,20 @@ func (e *webServer) Leaderboard(w http.ResponseWriter, r *http.Request) {
 
 	leaderboardEntries, err := e.emojixUsecase.Leaderboard(ctx, gameID, session.UserID)
 	if err != nil {
-		e.handleError(w, err, "failed to fetch leaderboard")
+		e.handleError(w, r, err, "failed to fetch leaderboard")
+		return
+	}
+
+	teams, err := e.emojixUsecase.TeamLeaderboard(ctx, gameID, session.UserID)
+	if err != nil {
+		e.handleError(w, r, err, "failed to fetch team leaderboard")
 		return
 	}
 
-	vieaParam := GameLeaderboardViewParam{leaderboardEntries}
+	vieaParam := GameLeaderboardViewParam{Leaderboard: leaderboardEntries, TeamLeaderboard: teams}
 	err = e.view.renderGameLeaderboard(w, vieaParam)
 	if err != nil {
-		e.handleError(w, err, "failed to render leaderboard")
+		e.handleError(w, r, err, "failed to render leaderboard")
 		return
 	}
 }
@@ -389,35 +860,71 @@ func (e *webServer) GameWord(w http.ResponseWriter, r *http.Request) {
 
 	gameWord, err := e.emojixUsecase.GameWord(ctx, gameID, session.UserID)
 	if err != nil {
-		e.handleError(w, err, "failed to fetch word")
+		e.handleError(w, r, err, "failed to fetch word")
 		return
 	}
 
 	pageParam := GameWordViewParam{strings.Split(gameWord, "")}
 	err = e.view.renderGameWord(w, pageParam)
 	if err != nil {
-		e.handleError(w, err, "failed to render word")
+		e.handleError(w, r, err, "failed to render word")
 		return
 	}
 }
 
 func (e *webServer) Sse(w http.ResponseWriter, r *http.Request) {
-	userIdCookie, err := r.Cookie(userIdCookieKey)
+	user, err := e.sessionUser(w, r)
 	if err != nil {
-		e.handleError(w, err, "no user id")
+		// EventSource can't follow a redirect to /init; a reload will.
+		http.Error(w, "invalid session", http.StatusUnauthorized)
 		return
 	}
-	userID := userIdCookie.Value
+	userID := user.ID
 
 	gameID := r.PathValue("id")
+	if err := e.emojixUsecase.CheckMember(r.Context(), gameID, userID); err != nil {
+		e.handleError(w, r, err, "failed to check membership")
+		return
+	}
 
-	// TODO: validate session id and game id
+	e.streamGame(w, r, gameID, userID, func(notifType, data string) (string, error) {
+		switch notifType {
+		case "msg":
+			msgNotif := usecase.GameMsgNotification{}
+			if err := msgNotif.ParseData(data); err != nil {
+				return "", err
+			}
 
+			gameMsg := model.GameStateMessage{
+				Me:       userID == msgNotif.UserID,
+				Nickname: msgNotif.Nickname,
+				Content:  msgNotif.Content,
+				IsSystem: msgNotif.IsSystem,
+			}
+			if !msgNotif.IsSystem {
+				gameMsg.PlayerID = msgNotif.UserID
+			}
+			var sseContent strings.Builder
+			if err := e.view.renderGameMsg(&sseContent, gameMsg); err != nil {
+				return "", err
+			}
+			return sseContent.String(), nil
+		case "hintupdated":
+			// Swapped straight into .emoji-display as HTML.
+			return html.EscapeString(data), nil
+		}
+		return data, nil
+	})
+}
+
+// streamGame sends gameID's notifications to userID as server-sent events
+// until the client goes away; format turns each one's data into the event's.
+func (e *webServer) streamGame(w http.ResponseWriter, r *http.Request, gameID, userID string, format func(notifType, data string) (string, error)) {
 	w.Header().Set("Content-Type", "text/event-stream")
 	w.Header().Set("Cache-Control", "no-cache")
 	w.Header().Set("X-Accel-Buffering", "no")
 
-	log.Println("sse connected", userID, gameID)
+	slog.Debug("sse connected", "user", userID, "game", gameID)
 
 	rc := http.NewResponseController(w)
 	if rc == nil {
@@ -426,67 +933,50 @@ func (e *webServer) Sse(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	sendSseMsg := func(msgType string, content string) error {
+	// A reconnecting EventSource sends back the last id it saw, so the
+	// usecase can replay whatever was published while it was away.
+	lastEventID, _ := strconv.ParseUint(r.Header.Get("Last-Event-ID"), 10, 64)
+
+	sendSseMsg := func(eventID uint64, msgType string, content string) error {
 		safeContent := strings.ReplaceAll(content, "\n", "")
-		sseContent := fmt.Sprintf("event: %s\ndata: %s\n\n", msgType, safeContent)
+		var sseContent string
+		if eventID > 0 {
+			sseContent = fmt.Sprintf("id: %d\nevent: %s\ndata: %s\n\n", eventID, msgType, safeContent)
+		} else {
+			sseContent = fmt.Sprintf("event: %s\ndata: %s\n\n", msgType, safeContent)
+		}
 		io.WriteString(w, sseContent)
 		err := rc.Flush()
 		return err
 
 	}
 
-	err = sendSseMsg("init", "")
+	err := sendSseMsg(0, "init", "")
 	if err != nil {
 		log.Printf("failed to flush with err: %v", err)
 		return
 	}
 
-	err = e.emojixUsecase.GameUpdates(r.Context(), gameID, userID, func(notifType string, data string) error {
-		if notifType == "msg" {
-			msgNotif := usecase.GameMsgNotification{}
-
-			err := msgNotif.ParseData(data)
-			if err != nil {
-				return err
-			}
-
-			gameMsg := model.GameStateMessage{
-				Me:       userID == msgNotif.UserID,
-				Nickname: msgNotif.Nickname,
-				Content:  msgNotif.Content,
-				IsSystem: msgNotif.IsSystem,
-			}
-			var sseContent strings.Builder
-			err = e.view.renderGameMsg(&sseContent, gameMsg)
-			if err != nil {
+	err = e.emojixUsecase.GameUpdates(r.Context(), gameID, userID, lastEventID, func(eventID uint64, notifType string, data string) error {
+		if notifType == usecase.HeartbeatEvent {
+			// A comment line: keeps proxies from timing the stream out
+			// and never reaches the page.
+			if _, err := io.WriteString(w, ": heartbeat\n\n"); err != nil {
 				return err
 			}
+			return rc.Flush()
+		}
 
-			sseMsg := sseContent.String()
-
-			err = sendSseMsg(notifType, sseMsg)
-
+		content, err := format(notifType, data)
+		if err != nil {
 			return err
 		}
-
-		err := sendSseMsg(notifType, data)
-
-		return err
+		return sendSseMsg(eventID, notifType, content)
 	})
 
 	if err != nil {
 		log.Printf("failed to send message: %v", err)
 	}
-
-	go func() {
-		// TODO: experiment and find a better wait amount
-		// NOTE: should be higher than the turn start wait time
-		time.Sleep(e.kickDelay)
-
-		ctx := context.Background()
-		err := e.emojixUsecase.KickInactiveUser(ctx, gameID, userID)
-		if err != nil {
-			log.Println("failed to kick inactive user", err)
-		}
-	}()
+	// Presence takes it from here: the player is marked inactive only if
+	// no tab reconnects within the grace period.
 }
@@ -2,9 +2,11 @@ package emojix
 
 import (
 	"context"
+	"emojix/config"
 	"emojix/model"
 	"emojix/usecase"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -16,14 +18,16 @@ import (
 
 // --- test helpers -------------------------------------------------------
 
+// testSessions signs the session cookies of handler tests.
+var testSessions, _ = newSessionSigner([]string{"test-session-key-0123456789abcdef"})
+
 func newServer(uc *MockEmojixUsecase, view *MockView) *webServer {
-	return &webServer{view: view, emojixUsecase: uc, kickDelay: 10 * time.Millisecond}
+	return &webServer{view: view, emojixUsecase: uc, cfg: config.Default(), sessions: testSessions}
 }
 
-// withSession returns r with both session cookies set.
-func withSession(r *http.Request, userID, nickname string) *http.Request {
-	r.AddCookie(&http.Cookie{Name: userIdCookieKey, Value: userID})
-	r.AddCookie(&http.Cookie{Name: nicknameCookieKey, Value: nickname})
+// withSession returns r with a session cookie signed for userID.
+func withSession(r *http.Request, userID string) *http.Request {
+	r.AddCookie(&http.Cookie{Name: sessionCookieKey, Value: testSessions.sign(userID)})
 	return r
 }
 
@@ -45,10 +49,13 @@ var errSentinel = errors.New("boom")
 
 func TestIndex_HasSession_Renders(t *testing.T) {
 	uc := newMockUsecase()
+	uc.GetUserFn = func(ctx context.Context, userID string) (model.User, error) {
+		return model.User{ID: userID, Nickname: "sillyCat"}, nil
+	}
 	view := &MockView{}
 	srv := newServer(uc, view)
 
-	r := withSession(newReq("GET", "/", nil), "u1", "sillyCat")
+	r := withSession(newReq("GET", "/", nil), "u1")
 	w := httptest.NewRecorder()
 
 	srv.Index(w, r)
@@ -93,7 +100,7 @@ func TestIndex_StaleUser_RedirectsToInit(t *testing.T) {
 	view := &MockView{}
 	srv := newServer(uc, view)
 
-	r := withSession(newReq("GET", "/", nil), "ghost", "OldNick")
+	r := withSession(newReq("GET", "/", nil), "ghost")
 	w := httptest.NewRecorder()
 
 	srv.Index(w, r)
@@ -118,7 +125,7 @@ func TestIndex_RenderError_500(t *testing.T) {
 	view.renderIndexPageFn = func(io.Writer, IndexPageViewParam) error { return errSentinel }
 	srv := newServer(uc, view)
 
-	r := withSession(newReq("GET", "/", nil), "u1", "nick")
+	r := withSession(newReq("GET", "/", nil), "u1")
 	w := httptest.NewRecorder()
 
 	srv.Index(w, r)
@@ -156,16 +163,38 @@ func TestInitSession_SetsCookiesAndRedirects(t *testing.T) {
 		t.Errorf("Location = %q, want /game/x", loc)
 	}
 	cookies := w.Header()["Set-Cookie"]
-	if len(cookies) != 2 {
-		t.Fatalf("Set-Cookie entries = %d, want 2: %v", len(cookies), cookies)
+	if len(cookies) != 1 {
+		t.Fatalf("Set-Cookie entries = %d, want 1: %v", len(cookies), cookies)
+	}
+	want := "session=" + testSessions.sign("u1") + "; Path=/; HttpOnly; SameSite=Lax"
+	if cookies[0] != want {
+		t.Errorf("Set-Cookie = %q, want %q", cookies[0], want)
 	}
-	wantUserID := "userid=u1; Path=/; HttpOnly"
-	wantNick := "nickname=sillyCat; Path=/; HttpOnly"
-	if cookies[0] != wantUserID {
-		t.Errorf("Set-Cookie[0] = %q, want %q", cookies[0], wantUserID)
+}
+
+func TestInitSession_ExpiresLegacyCookies(t *testing.T) {
+	uc := newMockUsecase()
+	uc.InitUserFn = func(ctx context.Context) (model.User, error) {
+		return model.User{ID: "fresh", Nickname: "NewNick"}, nil
+	}
+	srv := newServer(uc, &MockView{})
+
+	// Unsigned cookies from before: not a session, so a new user is minted.
+	r := newReq("GET", "/init", nil)
+	r.AddCookie(&http.Cookie{Name: "userid", Value: "u1"})
+	r.AddCookie(&http.Cookie{Name: "nickname", Value: "nick"})
+	w := httptest.NewRecorder()
+
+	srv.InitSession(w, r)
+
+	if uc.GetUserCalls != 0 || uc.InitUserCalls != 1 {
+		t.Fatalf("GetUser calls = %d, InitUser calls = %d; want 0, 1", uc.GetUserCalls, uc.InitUserCalls)
 	}
-	if cookies[1] != wantNick {
-		t.Errorf("Set-Cookie[1] = %q, want %q", cookies[1], wantNick)
+	got := strings.Join(w.Header()["Set-Cookie"], "\n")
+	for _, want := range []string{"userid=; Path=/; Max-Age=0", "nickname=; Path=/; Max-Age=0"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Set-Cookie missing %q:\n%s", want, got)
+		}
 	}
 }
 
@@ -173,7 +202,7 @@ func TestInitSession_ValidExistingSession_SkipsInitUser(t *testing.T) {
 	uc := newMockUsecase()
 	srv := newServer(uc, &MockView{})
 
-	r := withSession(newReq("GET", "/init?from=/game/x", nil), "u1", "nick")
+	r := withSession(newReq("GET", "/init?from=/game/x", nil), "u1")
 	w := httptest.NewRecorder()
 
 	srv.InitSession(w, r)
@@ -205,7 +234,7 @@ func TestInitSession_StaleSession_CreatesNewUser(t *testing.T) {
 	}
 	srv := newServer(uc, &MockView{})
 
-	r := withSession(newReq("GET", "/init?from=/", nil), "ghost", "OldNick")
+	r := withSession(newReq("GET", "/init?from=/", nil), "ghost")
 	w := httptest.NewRecorder()
 
 	srv.InitSession(w, r)
@@ -214,11 +243,8 @@ func TestInitSession_StaleSession_CreatesNewUser(t *testing.T) {
 		t.Fatalf("InitUserCalls = %d, want 1", uc.InitUserCalls)
 	}
 	cookies := w.Header()["Set-Cookie"]
-	if len(cookies) != 2 {
-		t.Fatalf("Set-Cookie entries = %d, want 2: %v", len(cookies), cookies)
-	}
-	if cookies[0] != "userid=fresh; Path=/; HttpOnly" {
-		t.Errorf("Set-Cookie[0] = %q", cookies[0])
+	if len(cookies) != 1 || !strings.HasPrefix(cookies[0], "session="+testSessions.sign("fresh")+";") {
+		t.Errorf("Set-Cookie = %v, want a session for fresh", cookies)
 	}
 }
 
@@ -239,13 +265,13 @@ func TestInitSession_DefaultFromRedirect(t *testing.T) {
 	}
 }
 
-func TestInitSession_ProdSetsSecureCookie(t *testing.T) {
-	t.Setenv("ENV", "prod")
+func TestInitSession_SecureCookies(t *testing.T) {
 	uc := newMockUsecase()
 	uc.InitUserFn = func(ctx context.Context) (model.User, error) {
 		return model.User{ID: "u1", Nickname: "nick"}, nil
 	}
 	srv := newServer(uc, &MockView{})
+	srv.cfg.SecureCookies = true
 
 	r := newReq("GET", "/init", nil)
 	w := httptest.NewRecorder()
@@ -253,8 +279,8 @@ func TestInitSession_ProdSetsSecureCookie(t *testing.T) {
 	srv.InitSession(w, r)
 
 	cookies := w.Header()["Set-Cookie"]
-	if len(cookies) != 2 {
-		t.Fatalf("Set-Cookie entries = %d, want 2", len(cookies))
+	if len(cookies) != 1 {
+		t.Fatalf("Set-Cookie entries = %d, want 1", len(cookies))
 	}
 	for _, c := range cookies {
 		if !strings.HasSuffix(c, "; Secure") {
@@ -291,7 +317,7 @@ func TestJoinGame_PathID_Redirects(t *testing.T) {
 	view := &MockView{}
 	srv := newServer(uc, view)
 
-	r := setGameID(withSession(newReq("GET", "/game/g1/join", nil), "u1", "nick"), "g1")
+	r := setGameID(withSession(newReq("GET", "/game/g1/join", nil), "u1"), "g1")
 	w := httptest.NewRecorder()
 
 	srv.JoinGame(w, r)
@@ -311,7 +337,7 @@ func TestJoinGame_QueryID_Redirects(t *testing.T) {
 	uc := newMockUsecase()
 	srv := newServer(uc, &MockView{})
 
-	r := withSession(newReq("GET", "/game/join?game-id=g2", nil), "u1", "nick")
+	r := withSession(newReq("GET", "/game/join?game-id=g2", nil), "u1")
 	w := httptest.NewRecorder()
 
 	srv.JoinGame(w, r)
@@ -324,7 +350,24 @@ func TestJoinGame_QueryID_Redirects(t *testing.T) {
 	}
 }
 
-func TestJoinGame_AlreadyJoined_500(t *testing.T) {
+func TestJoinGame_Spectate(t *testing.T) {
+	uc := newMockUsecase()
+	srv := newServer(uc, &MockView{})
+
+	r := withSession(newReq("GET", "/game/join?game-id=g2&spectate=on", nil), "u1")
+	w := httptest.NewRecorder()
+
+	srv.JoinGame(w, r)
+
+	if !uc.JoinGameLastSpectate {
+		t.Error("expected the join to ask for the stands")
+	}
+	if loc := w.Header().Get("Location"); loc != "/game/g2" {
+		t.Errorf("Location = %q, want /game/g2", loc)
+	}
+}
+
+func TestJoinGame_AlreadyJoined_RedirectsToGame(t *testing.T) {
 	uc := newMockUsecase()
 	uc.JoinGameFn = func(ctx context.Context, gameID, userID string) error {
 		return usecase.ErrJoinGameUserAlreadyJoined
@@ -332,20 +375,23 @@ func TestJoinGame_AlreadyJoined_500(t *testing.T) {
 	view := &MockView{}
 	srv := newServer(uc, view)
 
-	r := setGameID(withSession(newReq("GET", "/game/g1/join", nil), "u1", "nick"), "g1")
+	r := setGameID(withSession(newReq("GET", "/game/g1/join", nil), "u1"), "g1")
 	w := httptest.NewRecorder()
 
 	srv.JoinGame(w, r)
 
-	if w.Code != http.StatusInternalServerError {
-		t.Fatalf("status = %d, want 500 (current behavior pins the UX gap as backlog)", w.Code)
+	if w.Code != http.StatusFound {
+		t.Fatalf("status = %d, want 302", w.Code)
 	}
-	if view.renderErrorPageCalls != 1 {
-		t.Errorf("renderErrorPageCalls = %d, want 1", view.renderErrorPageCalls)
+	if loc := w.Header().Get("Location"); loc != "/game/g1" {
+		t.Errorf("Location = %q, want /game/g1", loc)
+	}
+	if view.renderErrorPageCalls != 0 {
+		t.Errorf("renderErrorPageCalls = %d, want 0", view.renderErrorPageCalls)
 	}
 }
 
-func TestJoinGame_RoomFull_500(t *testing.T) {
+func TestJoinGame_RoomFull_409(t *testing.T) {
 	uc := newMockUsecase()
 	uc.JoinGameFn = func(ctx context.Context, gameID, userID string) error {
 		return usecase.ErrJoinGameRoomFull
@@ -353,13 +399,16 @@ func TestJoinGame_RoomFull_500(t *testing.T) {
 	view := &MockView{}
 	srv := newServer(uc, view)
 
-	r := setGameID(withSession(newReq("GET", "/game/g1/join", nil), "u1", "nick"), "g1")
+	r := setGameID(withSession(newReq("GET", "/game/g1/join", nil), "u1"), "g1")
 	w := httptest.NewRecorder()
 
 	srv.JoinGame(w, r)
 
-	if w.Code != http.StatusInternalServerError {
-		t.Fatalf("status = %d, want 500", w.Code)
+	if w.Code != http.StatusConflict {
+		t.Fatalf("status = %d, want 409", w.Code)
+	}
+	if p := view.renderErrorPageLastParam; p.Message != usecase.ErrJoinGameRoomFull.Error() {
+		t.Errorf("error page message = %q, want %q", p.Message, usecase.ErrJoinGameRoomFull.Error())
 	}
 }
 
@@ -387,14 +436,14 @@ func TestJoinGame_NoSession_Redirects(t *testing.T) {
 
 func TestNewGame_Redirects303(t *testing.T) {
 	uc := newMockUsecase()
-	uc.InitGameFn = func(ctx context.Context, userID string, listID string) (model.Game, error) {
+	uc.InitGameFn = func(ctx context.Context, userID string, listID string, settings model.GameSettings) (model.Game, error) {
 		return model.Game{ID: "g9"}, nil
 	}
 	view := &MockView{}
 	srv := newServer(uc, view)
 
 	body := strings.NewReader("list-id=action")
-	r := withSession(newReq("POST", "/game/new", body), "u1", "nick")
+	r := withSession(newReq("POST", "/game/new", body), "u1")
 	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 	w := httptest.NewRecorder()
 
@@ -411,6 +460,59 @@ func TestNewGame_Redirects303(t *testing.T) {
 	}
 }
 
+func TestNewGame_PassesRoomSettings(t *testing.T) {
+	uc := newMockUsecase()
+	srv := newServer(uc, &MockView{})
+
+	body := strings.NewReader("list-id=action&turn-seconds=90&pick-seconds=&max-players=6&scoring=timed&teams=on&steal-seconds=15&reveals=50,80")
+	r := withSession(newReq("POST", "/game/new", body), "u1")
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+
+	srv.NewGame(w, r)
+
+	if w.Code != http.StatusSeeOther {
+		t.Fatalf("status = %d, want 303", w.Code)
+	}
+	want := model.GameSettings{TurnDuration: 90 * time.Second, MaxPlayers: 6, Scoring: "timed", Teams: true, StealDelay: 15 * time.Second, Reveals: "50,80"}
+	if uc.InitGameLastSettings != want {
+		t.Errorf("settings = %+v, want %+v", uc.InitGameLastSettings, want)
+	}
+}
+
+func TestNewGame_InvalidSettings_400(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		body string
+		err  error
+	}{
+		{"not a number", "list-id=action&turn-seconds=soon", nil},
+		{"usecase rejects", "list-id=action&turn-seconds=1", fmt.Errorf("%w: too short", usecase.ErrInvalidGameSettings)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			uc := newMockUsecase()
+			uc.InitGameFn = func(ctx context.Context, userID, listID string, settings model.GameSettings) (model.Game, error) {
+				return model.Game{}, tc.err
+			}
+			view := &MockView{}
+			srv := newServer(uc, view)
+
+			r := withSession(newReq("POST", "/game/new", strings.NewReader(tc.body)), "u1")
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			w := httptest.NewRecorder()
+
+			srv.NewGame(w, r)
+
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want 400", w.Code)
+			}
+			if p := view.renderErrorPageLastParam; p.Status != http.StatusBadRequest || p.Message == "" {
+				t.Errorf("error page param = %+v, want 400 with the reason", p)
+			}
+		})
+	}
+}
+
 func TestNewGame_NoSession_Redirects(t *testing.T) {
 	uc := newMockUsecase()
 	srv := newServer(uc, &MockView{})
@@ -438,7 +540,7 @@ func TestNewGame_StaleUser_RedirectsToInit(t *testing.T) {
 	}
 	srv := newServer(uc, &MockView{})
 
-	r := withSession(newReq("POST", "/game/new", nil), "ghost", "OldNick")
+	r := withSession(newReq("POST", "/game/new", nil), "ghost")
 	w := httptest.NewRecorder()
 
 	srv.NewGame(w, r)
@@ -459,14 +561,14 @@ func TestNewGame_StaleUser_RedirectsToInit(t *testing.T) {
 
 func TestNewGame_InitGameError_500(t *testing.T) {
 	uc := newMockUsecase()
-	uc.InitGameFn = func(ctx context.Context, userID string, listID string) (model.Game, error) {
+	uc.InitGameFn = func(ctx context.Context, userID string, listID string, settings model.GameSettings) (model.Game, error) {
 		return model.Game{}, errSentinel
 	}
 	view := &MockView{}
 	srv := newServer(uc, view)
 
 	body := strings.NewReader("list-id=action")
-	r := withSession(newReq("POST", "/game/new", body), "u1", "nick")
+	r := withSession(newReq("POST", "/game/new", body), "u1")
 	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 	w := httptest.NewRecorder()
 
@@ -502,7 +604,7 @@ func TestGame_RendersGamePageWithMaskedWord(t *testing.T) {
 	view := &MockView{}
 	srv := newServer(uc, view)
 
-	r := setGameID(withSession(newReq("GET", "/game/g1", nil), "u1", "nick"), "g1")
+	r := setGameID(withSession(newReq("GET", "/game/g1", nil), "u1"), "g1")
 	w := httptest.NewRecorder()
 
 	srv.Game(w, r)
@@ -545,7 +647,7 @@ func TestGame_TurnEnded_RendersInlineWaiting(t *testing.T) {
 	view := &MockView{}
 	srv := newServer(uc, view)
 
-	r := setGameID(withSession(newReq("GET", "/game/g1", nil), "u1", "nick"), "g1")
+	r := setGameID(withSession(newReq("GET", "/game/g1", nil), "u1"), "g1")
 	w := httptest.NewRecorder()
 
 	srv.Game(w, r)
@@ -572,7 +674,7 @@ func TestGame_GameStateError_500(t *testing.T) {
 	view := &MockView{}
 	srv := newServer(uc, view)
 
-	r := setGameID(withSession(newReq("GET", "/game/g1", nil), "u1", "nick"), "g1")
+	r := setGameID(withSession(newReq("GET", "/game/g1", nil), "u1"), "g1")
 	w := httptest.NewRecorder()
 
 	srv.Game(w, r)
@@ -596,7 +698,7 @@ func TestGame_NotInGame_JoinsAndRedirects(t *testing.T) {
 	view := &MockView{}
 	srv := newServer(uc, view)
 
-	r := setGameID(withSession(newReq("GET", "/game/g1", nil), "u2", "guest"), "g1")
+	r := setGameID(withSession(newReq("GET", "/game/g1", nil), "u2"), "g1")
 	w := httptest.NewRecorder()
 
 	srv.Game(w, r)
@@ -615,7 +717,7 @@ func TestGame_NotInGame_JoinsAndRedirects(t *testing.T) {
 	}
 }
 
-func TestGame_NotInGame_RoomFull_500(t *testing.T) {
+func TestGame_NotInGame_RoomFull_409(t *testing.T) {
 	uc := newMockUsecase()
 	uc.GameStateFn = func(ctx context.Context, gameID, userID string) (model.GameState, error) {
 		return model.GameState{}, usecase.ErrUserNotInGame
@@ -626,7 +728,7 @@ func TestGame_NotInGame_RoomFull_500(t *testing.T) {
 	view := &MockView{}
 	srv := newServer(uc, view)
 
-	r := setGameID(withSession(newReq("GET", "/game/g1", nil), "u2", "guest"), "g1")
+	r := setGameID(withSession(newReq("GET", "/game/g1", nil), "u2"), "g1")
 	w := httptest.NewRecorder()
 
 	srv.Game(w, r)
@@ -634,8 +736,8 @@ func TestGame_NotInGame_RoomFull_500(t *testing.T) {
 	if uc.JoinGameCalls != 1 {
 		t.Fatalf("JoinGameCalls = %d, want 1", uc.JoinGameCalls)
 	}
-	if w.Code != http.StatusInternalServerError {
-		t.Fatalf("status = %d, want 500", w.Code)
+	if w.Code != http.StatusConflict {
+		t.Fatalf("status = %d, want 409", w.Code)
 	}
 	if view.renderErrorPageCalls != 1 {
 		t.Errorf("renderErrorPageCalls = %d, want 1", view.renderErrorPageCalls)
@@ -649,12 +751,15 @@ func TestGame_NotInGame_RoomFull_500(t *testing.T) {
 
 func TestMessage_RendersGameMsgForCurrentUser(t *testing.T) {
 	uc := newMockUsecase()
+	uc.GetUserFn = func(ctx context.Context, userID string) (model.User, error) {
+		return model.User{ID: userID, Nickname: "sillyCat"}, nil
+	}
 	view := &MockView{}
 	srv := newServer(uc, view)
 
 	form := "content=hi+there"
 	r := setGameID(
-		withSession(newReq("POST", "/game/g1/message", strings.NewReader(form)), "u1", "sillyCat"),
+		withSession(newReq("POST", "/game/g1/message", strings.NewReader(form)), "u1"),
 		"g1",
 	)
 	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
@@ -692,7 +797,7 @@ func TestMessage_UsecaseError_500(t *testing.T) {
 	srv := newServer(uc, view)
 
 	r := setGameID(
-		withSession(newReq("POST", "/game/g1/message", strings.NewReader("content=x")), "u1", "nick"),
+		withSession(newReq("POST", "/game/g1/message", strings.NewReader("content=x")), "u1"),
 		"g1",
 	)
 	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
@@ -709,6 +814,9 @@ func TestMessage_UsecaseError_500(t *testing.T) {
 
 func TestGuess_Correct_SetsHxTriggerAndRendersSystemMsg(t *testing.T) {
 	uc := newMockUsecase()
+	uc.GetUserFn = func(ctx context.Context, userID string) (model.User, error) {
+		return model.User{ID: userID, Nickname: "sillyCat"}, nil
+	}
 	uc.GuessFn = func(ctx context.Context, gameID, userID, content string) (bool, error) {
 		return true, nil
 	}
@@ -716,7 +824,7 @@ func TestGuess_Correct_SetsHxTriggerAndRendersSystemMsg(t *testing.T) {
 	srv := newServer(uc, view)
 
 	r := setGameID(
-		withSession(newReq("POST", "/game/g1/guess", strings.NewReader("content=apple")), "u1", "sillyCat"),
+		withSession(newReq("POST", "/game/g1/guess", strings.NewReader("content=apple")), "u1"),
 		"g1",
 	)
 	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
@@ -757,7 +865,7 @@ func TestGuess_Wrong_SetsWrongguessAndRendersGuessMsg(t *testing.T) {
 	srv := newServer(uc, view)
 
 	r := setGameID(
-		withSession(newReq("POST", "/game/g1/guess", strings.NewReader("content=nope")), "u1", "sillyCat"),
+		withSession(newReq("POST", "/game/g1/guess", strings.NewReader("content=nope")), "u1"),
 		"g1",
 	)
 	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
@@ -789,7 +897,7 @@ func TestGuess_UsecaseError_500(t *testing.T) {
 	srv := newServer(uc, view)
 
 	r := setGameID(
-		withSession(newReq("POST", "/game/g1/guess", strings.NewReader("content=x")), "u1", "nick"),
+		withSession(newReq("POST", "/game/g1/guess", strings.NewReader("content=x")), "u1"),
 		"g1",
 	)
 	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
@@ -816,7 +924,7 @@ func TestLeaderboard_Renders(t *testing.T) {
 	view := &MockView{}
 	srv := newServer(uc, view)
 
-	r := setGameID(withSession(newReq("GET", "/game/g1/leaderboard", nil), "u1", "nick"), "g1")
+	r := setGameID(withSession(newReq("GET", "/game/g1/leaderboard", nil), "u1"), "g1")
 	w := httptest.NewRecorder()
 
 	srv.Leaderboard(w, r)
@@ -840,7 +948,7 @@ func TestLeaderboard_UsecaseError_500(t *testing.T) {
 	view := &MockView{}
 	srv := newServer(uc, view)
 
-	r := setGameID(withSession(newReq("GET", "/game/g1/leaderboard", nil), "u1", "nick"), "g1")
+	r := setGameID(withSession(newReq("GET", "/game/g1/leaderboard", nil), "u1"), "g1")
 	w := httptest.NewRecorder()
 
 	srv.Leaderboard(w, r)
@@ -863,7 +971,7 @@ func TestGameWord_RendersMasked(t *testing.T) {
 	view := &MockView{}
 	srv := newServer(uc, view)
 
-	r := setGameID(withSession(newReq("GET", "/game/g1/word", nil), "u1", "nick"), "g1")
+	r := setGameID(withSession(newReq("GET", "/game/g1/word", nil), "u1"), "g1")
 	w := httptest.NewRecorder()
 
 	srv.GameWord(w, r)
@@ -887,7 +995,7 @@ func TestGameWord_UsecaseError_500(t *testing.T) {
 	view := &MockView{}
 	srv := newServer(uc, view)
 
-	r := setGameID(withSession(newReq("GET", "/game/g1/word", nil), "u1", "nick"), "g1")
+	r := setGameID(withSession(newReq("GET", "/game/g1/word", nil), "u1"), "g1")
 	w := httptest.NewRecorder()
 
 	srv.GameWord(w, r)
@@ -902,23 +1010,18 @@ func TestGameWord_UsecaseError_500(t *testing.T) {
 
 // --- Sse ---------------------------------------------------------------
 
-func TestSse_HeadersInitEventAndKickOnContextCancel(t *testing.T) {
+func TestSse_HeadersAndInitEvent(t *testing.T) {
 	uc := newMockUsecase()
-	kicked := make(chan struct{})
-	uc.GameUpdatesFn = func(ctx context.Context, gameID, userID string, h usecase.GameUpdateHandler) error {
+	uc.GameUpdatesFn = func(ctx context.Context, gameID, userID string, lastEventID uint64, h usecase.GameUpdateHandler) error {
 		<-ctx.Done()
 		return nil
 	}
-	uc.KickInactiveUserFn = func(ctx context.Context, gameID, userID string) error {
-		close(kicked)
-		return nil
-	}
 	view := &MockView{}
 	srv := newServer(uc, view)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	r := httptest.NewRequestWithContext(ctx, "GET", "/game/g1/sse", nil)
-	r.AddCookie(&http.Cookie{Name: userIdCookieKey, Value: "u1"})
+	r.AddCookie(&http.Cookie{Name: sessionCookieKey, Value: testSessions.sign("u1")})
 	r.SetPathValue("id", "g1")
 	w := httptest.NewRecorder()
 
@@ -951,40 +1054,150 @@ func TestSse_HeadersInitEventAndKickOnContextCancel(t *testing.T) {
 	if !strings.Contains(w.Body.String(), "event: init\n") {
 		t.Errorf("body %q missing init event", w.Body.String())
 	}
-
-	// kick goroutine fires after the (near-zero) delay
-	select {
-	case <-kicked:
-	case <-time.After(2 * time.Second):
-		t.Fatal("KickInactiveUser was not called")
+	if uc.GameUpdatesLastGameID != "g1" || uc.GameUpdatesLastUserID != "u1" {
+		t.Errorf("GameUpdates args = %q %q, want g1 u1", uc.GameUpdatesLastGameID, uc.GameUpdatesLastUserID)
 	}
-	if uc.KickInactiveUserCalls != 1 {
-		t.Errorf("KickInactiveUserCalls = %d, want 1", uc.KickInactiveUserCalls)
+}
+
+func TestSse_HeartbeatIsAComment(t *testing.T) {
+	uc := newMockUsecase()
+	uc.GameUpdatesFn = func(ctx context.Context, gameID, userID string, lastEventID uint64, h usecase.GameUpdateHandler) error {
+		return h(0, usecase.HeartbeatEvent, "")
 	}
-	if uc.KickInactiveUserLastGameID != "g1" || uc.KickInactiveUserLastUserID != "u1" {
-		t.Errorf("KickInactiveUser args = g1=%q u1=%q", uc.KickInactiveUserLastGameID, uc.KickInactiveUserLastUserID)
+	srv := newServer(uc, &MockView{})
+
+	r := httptest.NewRequest("GET", "/game/g1/sse", nil)
+	r.AddCookie(&http.Cookie{Name: sessionCookieKey, Value: testSessions.sign("u1")})
+	r.SetPathValue("id", "g1")
+	w := httptest.NewRecorder()
+
+	srv.Sse(w, r)
+
+	body := w.Body.String()
+	if !strings.Contains(body, ": heartbeat\n\n") || strings.Contains(body, "event: heartbeat") {
+		t.Errorf("body %q, want a heartbeat comment and no heartbeat event", body)
 	}
 }
 
-func TestSse_MissingCookie_500(t *testing.T) {
+func TestSse_LastEventID_PassedOnAndIDsWritten(t *testing.T) {
 	uc := newMockUsecase()
-	view := &MockView{}
-	srv := newServer(uc, view)
+	uc.GameUpdatesFn = func(ctx context.Context, gameID, userID string, lastEventID uint64, h usecase.GameUpdateHandler) error {
+		if err := h(13, "join", "p2,nick"); err != nil {
+			return err
+		}
+		return h(14, "resync", "")
+	}
+	srv := newServer(uc, &MockView{})
 
-	r := httptest.NewRequest("GET", "http://example.com/game/g1/sse", nil)
+	r := httptest.NewRequest("GET", "/game/g1/sse", nil)
+	r.AddCookie(&http.Cookie{Name: sessionCookieKey, Value: testSessions.sign("u1")})
+	r.Header.Set("Last-Event-ID", "12")
 	r.SetPathValue("id", "g1")
 	w := httptest.NewRecorder()
 
 	srv.Sse(w, r)
 
-	if w.Code != http.StatusInternalServerError {
-		t.Fatalf("status = %d, want 500", w.Code)
+	if uc.GameUpdatesLastEventID != 12 {
+		t.Errorf("lastEventID = %d, want 12", uc.GameUpdatesLastEventID)
 	}
-	if view.renderErrorPageCalls != 1 {
-		t.Errorf("renderErrorPageCalls = %d, want 1", view.renderErrorPageCalls)
+	body := w.Body.String()
+	for _, want := range []string{"event: init\ndata: \n\n", "id: 13\nevent: join\ndata: p2,nick\n\n", "id: 14\nevent: resync\n"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("body %q missing %q", body, want)
+		}
 	}
-	if uc.GameUpdatesCalls != 0 {
-		t.Errorf("GameUpdatesCalls = %d, want 0", uc.GameUpdatesCalls)
+}
+
+func TestSse_InvalidSession_401(t *testing.T) {
+	forger, _ := newSessionSigner([]string{"someone-elses-key-0123456789abcdef"})
+	cases := []struct {
+		name   string
+		cookie string // "" sends none
+	}{
+		{"missing", ""},
+		{"unsigned user id", "u1"},
+		{"forged signature", forger.sign("u1")},
+		{"signature for another user", "u1." + strings.SplitN(testSessions.sign("u2"), ".", 2)[1]},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			uc := newMockUsecase()
+			srv := newServer(uc, &MockView{})
+
+			r := httptest.NewRequest("GET", "/game/g1/sse", nil)
+			if tc.cookie != "" {
+				r.AddCookie(&http.Cookie{Name: sessionCookieKey, Value: tc.cookie})
+			}
+			r.SetPathValue("id", "g1")
+			w := httptest.NewRecorder()
+
+			srv.Sse(w, r)
+
+			if w.Code != http.StatusUnauthorized {
+				t.Fatalf("status = %d, want 401", w.Code)
+			}
+			if uc.GetUserCalls != 0 || uc.GameUpdatesCalls != 0 {
+				t.Errorf("GetUser calls = %d, GameUpdates calls = %d; want none", uc.GetUserCalls, uc.GameUpdatesCalls)
+			}
+		})
+	}
+}
+
+func TestSse_NotMember_403(t *testing.T) {
+	for _, memberErr := range []error{usecase.ErrUserNotInGame, usecase.ErrPlayerKicked} {
+		uc := newMockUsecase()
+		uc.CheckMemberFn = func(ctx context.Context, gameID, userID string) error {
+			if gameID != "g1" || userID != "u1" {
+				t.Errorf("CheckMember(%q, %q), want g1 u1", gameID, userID)
+			}
+			return memberErr
+		}
+		srv := newServer(uc, &MockView{})
+
+		r := httptest.NewRequest("GET", "/game/g1/sse", nil)
+		r.AddCookie(&http.Cookie{Name: sessionCookieKey, Value: testSessions.sign("u1")})
+		r.SetPathValue("id", "g1")
+		w := httptest.NewRecorder()
+
+		srv.Sse(w, r)
+
+		if w.Code != http.StatusForbidden {
+			t.Errorf("%v: status = %d, want 403", memberErr, w.Code)
+		}
+		if uc.GameUpdatesCalls != 0 || strings.Contains(w.Body.String(), "event:") {
+			t.Errorf("%v: stream started for a non-member", memberErr)
+		}
+	}
+}
+
+func TestPresence_SetsAway(t *testing.T) {
+	for _, tc := range []struct {
+		state    string
+		wantCode int
+		wantAway bool
+		calls    int
+	}{
+		{"away", http.StatusNoContent, true, 1},
+		{"back", http.StatusNoContent, false, 1},
+		{"asleep", http.StatusBadRequest, false, 0},
+	} {
+		t.Run(tc.state, func(t *testing.T) {
+			uc := newMockUsecase()
+			srv := newServer(uc, &MockView{})
+
+			r := setGameID(withSession(newReq("POST", "/game/g1/presence", strings.NewReader("state="+tc.state)), "u1"), "g1")
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			w := httptest.NewRecorder()
+
+			srv.Presence(w, r)
+
+			if w.Code != tc.wantCode {
+				t.Fatalf("status = %d, want %d", w.Code, tc.wantCode)
+			}
+			if uc.SetAwayCalls != tc.calls || uc.SetAwayLastAway != tc.wantAway {
+				t.Errorf("SetAway calls = %d away = %v, want %d %v", uc.SetAwayCalls, uc.SetAwayLastAway, tc.calls, tc.wantAway)
+			}
+		})
 	}
 }
 
@@ -1029,8 +1242,7 @@ func TestRouting_SmokeTest(t *testing.T) {
 
 	// /game/join?game-id=g1 (query-id variant) with session cookies — expect 302.
 	req, _ := http.NewRequest("GET", ts.URL+"/game/join?game-id=g1", nil)
-	req.AddCookie(&http.Cookie{Name: userIdCookieKey, Value: "u1"})
-	req.AddCookie(&http.Cookie{Name: nicknameCookieKey, Value: "nick"})
+	req.AddCookie(&http.Cookie{Name: sessionCookieKey, Value: testSessions.sign("u1")})
 	resp2, err := client.Do(req)
 	if err != nil {
 		t.Fatalf("GET /game/join: %v", err)
@@ -1040,3 +1252,690 @@ func TestRouting_SmokeTest(t *testing.T) {
 		t.Errorf("GET /game/join status = %d, want 302", resp2.StatusCode)
 	}
 }
+
+func TestRematch_Redirects303(t *testing.T) {
+	uc := newMockUsecase()
+	uc.RematchFn = func(ctx context.Context, gameID, userID string) (model.Game, error) {
+		return model.Game{ID: "g2"}, nil
+	}
+	srv := newServer(uc, &MockView{})
+
+	r := setGameID(withSession(newReq("POST", "/game/g1/rematch", nil), "u1"), "g1")
+	w := httptest.NewRecorder()
+
+	srv.Rematch(w, r)
+
+	if w.Code != http.StatusSeeOther {
+		t.Fatalf("status = %d, want 303", w.Code)
+	}
+	if loc := w.Header().Get("Location"); loc != "/game/g2" {
+		t.Errorf("Location = %q, want /game/g2", loc)
+	}
+	if uc.RematchLastGameID != "g1" || uc.RematchLastUserID != "u1" {
+		t.Errorf("Rematch args = (%q, %q), want (g1, u1)", uc.RematchLastGameID, uc.RematchLastUserID)
+	}
+}
+
+func TestRematch_GameNotFinished_409(t *testing.T) {
+	uc := newMockUsecase()
+	uc.RematchFn = func(ctx context.Context, gameID, userID string) (model.Game, error) {
+		return model.Game{}, usecase.ErrGameNotFinished
+	}
+	srv := newServer(uc, &MockView{})
+
+	r := setGameID(withSession(newReq("POST", "/game/g1/rematch", nil), "u1"), "g1")
+	w := httptest.NewRecorder()
+
+	srv.Rematch(w, r)
+
+	if w.Code != http.StatusConflict {
+		t.Fatalf("status = %d, want 409", w.Code)
+	}
+}
+
+func TestHistory_RendersPage(t *testing.T) {
+	uc := newMockUsecase()
+	view := &MockView{}
+	srv := newServer(uc, view)
+
+	r := setGameID(withSession(newReq("GET", "/game/g1/history", nil), "u1"), "g1")
+	w := httptest.NewRecorder()
+
+	srv.History(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+	if uc.GameHistoryLastGameID != "g1" {
+		t.Errorf("GameHistory gameID = %q, want g1", uc.GameHistoryLastGameID)
+	}
+	if view.renderHistoryPageCalls != 1 || view.renderHistoryPageLastParam.History.GameID != "g1" {
+		t.Errorf("renderHistoryPage calls = %d, param = %+v", view.renderHistoryPageCalls, view.renderHistoryPageLastParam)
+	}
+}
+
+func TestHistory_GameNotFinished_409(t *testing.T) {
+	uc := newMockUsecase()
+	uc.GameHistoryFn = func(ctx context.Context, gameID, userID string) (model.GameHistory, error) {
+		return model.GameHistory{}, usecase.ErrGameNotFinished
+	}
+	view := &MockView{}
+	srv := newServer(uc, view)
+
+	r := setGameID(withSession(newReq("GET", "/game/g1/history", nil), "u1"), "g1")
+	w := httptest.NewRecorder()
+
+	srv.History(w, r)
+
+	if w.Code != http.StatusConflict {
+		t.Fatalf("status = %d, want 409", w.Code)
+	}
+	if view.renderHistoryPageCalls != 0 {
+		t.Error("expected no history page render")
+	}
+}
+
+func TestJoinGame_Finished_410(t *testing.T) {
+	uc := newMockUsecase()
+	uc.JoinGameFn = func(ctx context.Context, gameID, userID string) error {
+		return usecase.ErrGameFinished
+	}
+	srv := newServer(uc, &MockView{})
+
+	r := setGameID(withSession(newReq("GET", "/game/g1/join", nil), "u1"), "g1")
+	w := httptest.NewRecorder()
+
+	srv.JoinGame(w, r)
+
+	if w.Code != http.StatusGone {
+		t.Fatalf("status = %d, want 410", w.Code)
+	}
+}
+
+func TestHint_AppendRoutesToUsecase_204(t *testing.T) {
+	uc := newMockUsecase()
+	srv := newServer(uc, &MockView{})
+
+	body := strings.NewReader("action=append&content=%F0%9F%8D%8C")
+	r := setGameID(withSession(newReq("POST", "/game/g1/hint", body), "u1"), "g1")
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+
+	srv.Hint(w, r)
+
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("status = %d, want 204", w.Code)
+	}
+	if uc.HintLastAction != "append" || uc.HintLastGameID != "g1" || uc.HintLastUserID != "u1" || uc.HintLastValue != "🍌" {
+		t.Errorf("hint call = (%q, %q, %q, %q), want (append, g1, u1, 🍌)", uc.HintLastAction, uc.HintLastGameID, uc.HintLastUserID, uc.HintLastValue)
+	}
+}
+
+func TestHint_ErrorStatuses(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		body string
+		err  error
+		want int
+	}{
+		{"unknown action", "action=shuffle", nil, http.StatusBadRequest},
+		{"not the teller", "action=undo", usecase.ErrHintNotTeller, http.StatusForbidden},
+		{"not emoji", "action=replace&content=abc", usecase.ErrTellerEmojiOnly, http.StatusBadRequest},
+		{"unexpected", "action=undo", errSentinel, http.StatusInternalServerError},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			uc := newMockUsecase()
+			uc.HintFn = func(ctx context.Context, action, gameID, userID, content string) (string, error) {
+				return "", tc.err
+			}
+			srv := newServer(uc, &MockView{})
+
+			r := setGameID(withSession(newReq("POST", "/game/g1/hint", strings.NewReader(tc.body)), "u1"), "g1")
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			w := httptest.NewRecorder()
+
+			srv.Hint(w, r)
+
+			if w.Code != tc.want {
+				t.Fatalf("status = %d, want %d", w.Code, tc.want)
+			}
+		})
+	}
+}
+
+// --- Error mapping ------------------------------------------------------
+
+func TestGuess_HTMXRefusal_RetargetsInlineError(t *testing.T) {
+	uc := newMockUsecase()
+	uc.GuessFn = func(ctx context.Context, gameID, userID, content string) (bool, error) {
+		return false, usecase.ErrTellerCannotGuess
+	}
+	view := &MockView{}
+	srv := newServer(uc, view)
+
+	r := setGameID(withSession(newReq("POST", "/game/g1/guess", strings.NewReader("content=x")), "u1"), "g1")
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.Header.Set("HX-Request", "true")
+	w := httptest.NewRecorder()
+
+	srv.Guess(w, r)
+
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("status = %d, want 403", w.Code)
+	}
+	if got := w.Header().Get("HX-Retarget"); got != errorTarget {
+		t.Errorf("HX-Retarget = %q, want %q", got, errorTarget)
+	}
+	if got := w.Header().Get("HX-Reswap"); got != "innerHTML" {
+		t.Errorf("HX-Reswap = %q, want innerHTML", got)
+	}
+	if view.renderErrorFragmentCalls != 1 || view.renderErrorPageCalls != 0 {
+		t.Errorf("fragment calls = %d, page calls = %d, want 1 and 0", view.renderErrorFragmentCalls, view.renderErrorPageCalls)
+	}
+	if view.renderGameMsgCalls != 0 {
+		t.Errorf("renderGameMsgCalls = %d, want 0", view.renderGameMsgCalls)
+	}
+}
+
+func TestGuess_SlowDown_429WithRetryAfter(t *testing.T) {
+	uc := newMockUsecase()
+	uc.GuessFn = func(ctx context.Context, gameID, userID, word string) (bool, error) {
+		return false, &usecase.SlowDownError{RetryAfter: 1500 * time.Millisecond}
+	}
+	view := &MockView{}
+	srv := newServer(uc, view)
+
+	r := setGameID(withSession(newReq("POST", "/game/g1/guess", strings.NewReader("content=apple")), "u1"), "g1")
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.Header.Set("HX-Request", "true")
+	w := httptest.NewRecorder()
+
+	srv.Guess(w, r)
+
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("status = %d, want 429", w.Code)
+	}
+	if got := w.Header().Get("Retry-After"); got != "2" {
+		t.Errorf("Retry-After = %q, want 2", got)
+	}
+	if p := view.renderErrorFragmentLastParam; p.Message != "slow down, try again in 2s" {
+		t.Errorf("fragment message = %q", p.Message)
+	}
+}
+
+func TestMessage_HTMXInternalError_HidesDetails(t *testing.T) {
+	uc := newMockUsecase()
+	uc.MessageFn = func(ctx context.Context, gameID, userID, content string) error {
+		return errSentinel
+	}
+	view := &MockView{}
+	srv := newServer(uc, view)
+
+	r := setGameID(withSession(newReq("POST", "/game/g1/message", strings.NewReader("content=x")), "u1"), "g1")
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.Header.Set("HX-Request", "true")
+	w := httptest.NewRecorder()
+
+	srv.Message(w, r)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500", w.Code)
+	}
+	if p := view.renderErrorFragmentLastParam; p.Message != "" {
+		t.Errorf("fragment message = %q, want none for internal errors", p.Message)
+	}
+}
+
+func TestErrorStatus_ByKind(t *testing.T) {
+	for _, tc := range []struct {
+		err  error
+		want int
+	}{
+		{usecase.ErrEmptyMessage, http.StatusBadRequest},
+		{usecase.ErrNotTeller, http.StatusForbidden},
+		{usecase.ErrWordListNotFound, http.StatusNotFound},
+		{usecase.ErrJoinGameRoomFull, http.StatusConflict},
+		{fmt.Errorf("join: %w", usecase.ErrGameFinished), http.StatusGone},
+		{usecase.ErrShuttingDown, http.StatusServiceUnavailable},
+		{&usecase.SlowDownError{RetryAfter: time.Second}, http.StatusTooManyRequests},
+		{errSentinel, http.StatusInternalServerError},
+	} {
+		if got := errorStatus(tc.err); got != tc.want {
+			t.Errorf("errorStatus(%v) = %d, want %d", tc.err, got, tc.want)
+		}
+	}
+}
+
+// --- Private rooms and host controls ----------------------------------
+
+func TestJoinByCode_RedirectsToGame(t *testing.T) {
+	uc := newMockUsecase()
+	uc.JoinGameByCodeFn = func(ctx context.Context, code, userID string) (model.Game, error) {
+		return model.Game{ID: "g1", InviteCode: code}, nil
+	}
+	srv := newServer(uc, &MockView{})
+
+	r := withSession(newReq("GET", "/join/ABC234", nil), "u1")
+	r.SetPathValue("code", "ABC234")
+	w := httptest.NewRecorder()
+
+	srv.JoinByCode(w, r)
+
+	if uc.JoinGameByCodeLastCode != "ABC234" {
+		t.Fatalf("JoinGameByCodeLastCode = %q, want ABC234", uc.JoinGameByCodeLastCode)
+	}
+	if loc := w.Header().Get("Location"); loc != "/game/g1" {
+		t.Errorf("Location = %q, want /game/g1", loc)
+	}
+}
+
+func TestJoinGame_QueryInviteCode_UsesCode(t *testing.T) {
+	uc := newMockUsecase()
+	srv := newServer(uc, &MockView{})
+
+	r := withSession(newReq("GET", "/game/join?game-id=abc234", nil), "u1")
+	w := httptest.NewRecorder()
+
+	srv.JoinGame(w, r)
+
+	if uc.JoinGameByCodeCalls != 1 || uc.JoinGameCalls != 0 {
+		t.Fatalf("JoinGameByCode calls = %d, JoinGame calls = %d, want 1 and 0", uc.JoinGameByCodeCalls, uc.JoinGameCalls)
+	}
+}
+
+func TestJoinGame_RefusalStatuses(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"private", usecase.ErrInviteRequired, http.StatusForbidden},
+		{"locked", usecase.ErrRoomLocked, http.StatusForbidden},
+		{"kicked", usecase.ErrPlayerKicked, http.StatusForbidden},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			uc := newMockUsecase()
+			uc.JoinGameFn = func(ctx context.Context, gameID, userID string) error {
+				return tc.err
+			}
+			srv := newServer(uc, &MockView{})
+
+			r := setGameID(withSession(newReq("GET", "/game/g1/join", nil), "u1"), "g1")
+			w := httptest.NewRecorder()
+
+			srv.JoinGame(w, r)
+
+			if w.Code != tc.want {
+				t.Fatalf("status = %d, want %d", w.Code, tc.want)
+			}
+		})
+	}
+}
+
+func TestHostActions_RouteToUsecase_204(t *testing.T) {
+	for _, tc := range []struct {
+		action  string
+		body    string
+		handler func(*webServer) http.HandlerFunc
+		value   string
+	}{
+		{"kick", "player-id=u2", func(e *webServer) http.HandlerFunc { return e.KickPlayer }, "u2"},
+		{"lock", "locked=true", func(e *webServer) http.HandlerFunc { return e.LockRoom }, "true"},
+		{"host", "player-id=u2", func(e *webServer) http.HandlerFunc { return e.TransferHost }, "u2"},
+		{"skip", "", func(e *webServer) http.HandlerFunc { return e.SkipTurn }, ""},
+	} {
+		t.Run(tc.action, func(t *testing.T) {
+			uc := newMockUsecase()
+			srv := newServer(uc, &MockView{})
+
+			r := setGameID(withSession(newReq("POST", "/game/g1/"+tc.action, strings.NewReader(tc.body)), "u1"), "g1")
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			w := httptest.NewRecorder()
+
+			tc.handler(srv)(w, r)
+
+			if w.Code != http.StatusNoContent {
+				t.Fatalf("status = %d, want 204", w.Code)
+			}
+			if uc.HostActionLastAction != tc.action || uc.HostActionLastGameID != "g1" || uc.HostActionLastHostID != "u1" || uc.HostActionLastValue != tc.value {
+				t.Errorf("host call = (%q, %q, %q, %q), want (%s, g1, u1, %q)", uc.HostActionLastAction, uc.HostActionLastGameID, uc.HostActionLastHostID, uc.HostActionLastValue, tc.action, tc.value)
+			}
+		})
+	}
+}
+
+func TestHostActions_ErrorStatuses(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"not the host", usecase.ErrNotHost, http.StatusForbidden},
+		{"kick self", usecase.ErrKickHost, http.StatusBadRequest},
+		{"not seated", usecase.ErrPlayerNotInGame, http.StatusBadRequest},
+		{"finished", usecase.ErrGameFinished, http.StatusGone},
+		{"unexpected", errSentinel, http.StatusInternalServerError},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			uc := newMockUsecase()
+			uc.HostActionFn = func(ctx context.Context, action, gameID, hostID, value string) error {
+				return tc.err
+			}
+			srv := newServer(uc, &MockView{})
+
+			r := setGameID(withSession(newReq("POST", "/game/g1/kick", strings.NewReader("player-id=u2")), "u1"), "g1")
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			w := httptest.NewRecorder()
+
+			srv.KickPlayer(w, r)
+
+			if w.Code != tc.want {
+				t.Fatalf("status = %d, want %d", w.Code, tc.want)
+			}
+		})
+	}
+}
+
+func TestPickTeam_RoutesToUsecase_204(t *testing.T) {
+	uc := newMockUsecase()
+	srv := newServer(uc, &MockView{})
+
+	r := setGameID(withSession(newReq("POST", "/game/g1/team", strings.NewReader("team=blue")), "u1"), "g1")
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+
+	srv.PickTeam(w, r)
+
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("status = %d, want 204", w.Code)
+	}
+	if uc.PickTeamLastGameID != "g1" || uc.PickTeamLastUserID != "u1" || uc.PickTeamLastTeam != model.BlueTeam {
+		t.Errorf("PickTeam call = (%q, %q, %q), want (g1, u1, blue)", uc.PickTeamLastGameID, uc.PickTeamLastUserID, uc.PickTeamLastTeam)
+	}
+}
+
+func TestPickTeam_TeamsLocked_409(t *testing.T) {
+	uc := newMockUsecase()
+	uc.PickTeamFn = func(ctx context.Context, gameID, userID string, team model.Team) error {
+		return usecase.ErrTeamsLocked
+	}
+	srv := newServer(uc, &MockView{})
+
+	r := setGameID(withSession(newReq("POST", "/game/g1/team", strings.NewReader("team=red")), "u1"), "g1")
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+
+	srv.PickTeam(w, r)
+
+	if w.Code != http.StatusConflict {
+		t.Fatalf("status = %d, want 409", w.Code)
+	}
+}
+
+func TestSeatActions_RouteToUsecase_204(t *testing.T) {
+	for _, action := range []string{"spectate", "play"} {
+		t.Run(action, func(t *testing.T) {
+			uc := newMockUsecase()
+			srv := newServer(uc, &MockView{})
+
+			r := setGameID(withSession(newReq("POST", "/game/g1/"+action, nil), "u1"), "g1")
+			w := httptest.NewRecorder()
+
+			if action == "spectate" {
+				srv.Spectate(w, r)
+			} else {
+				srv.Play(w, r)
+			}
+
+			if w.Code != http.StatusNoContent {
+				t.Fatalf("status = %d, want 204", w.Code)
+			}
+			if uc.SeatLastAction != action || uc.SeatLastGameID != "g1" || uc.SeatLastUserID != "u1" {
+				t.Errorf("seat call = (%q, %q, %q), want (%s, g1, u1)", uc.SeatLastAction, uc.SeatLastGameID, uc.SeatLastUserID, action)
+			}
+		})
+	}
+}
+
+func TestTurnActions_RouteToUsecase_204(t *testing.T) {
+	for _, action := range []string{"giveup", "voteskip"} {
+		t.Run(action, func(t *testing.T) {
+			uc := newMockUsecase()
+			srv := newServer(uc, &MockView{})
+
+			r := setGameID(withSession(newReq("POST", "/game/g1/"+action, nil), "u1"), "g1")
+			w := httptest.NewRecorder()
+
+			if action == "giveup" {
+				srv.GiveUpTurn(w, r)
+			} else {
+				srv.VoteSkip(w, r)
+			}
+
+			if w.Code != http.StatusNoContent {
+				t.Fatalf("status = %d, want 204", w.Code)
+			}
+			if uc.TurnActionLastAction != action || uc.TurnActionLastGameID != "g1" || uc.TurnActionLastUserID != "u1" {
+				t.Errorf("turn action = (%q, %q, %q), want (%s, g1, u1)", uc.TurnActionLastAction, uc.TurnActionLastGameID, uc.TurnActionLastUserID, action)
+			}
+		})
+	}
+}
+
+func TestRerollWords_RedirectsToGame(t *testing.T) {
+	uc := newMockUsecase()
+	srv := newServer(uc, &MockView{})
+
+	r := setGameID(withSession(newReq("POST", "/game/g1/reroll", nil), "u1"), "g1")
+	w := httptest.NewRecorder()
+
+	srv.RerollWords(w, r)
+
+	if w.Code != http.StatusSeeOther {
+		t.Fatalf("status = %d, want 303", w.Code)
+	}
+	if loc := w.Header().Get("Location"); loc != "/game/g1" {
+		t.Errorf("Location = %q, want /game/g1", loc)
+	}
+	if uc.TurnActionLastAction != "reroll" {
+		t.Errorf("turn action = %q, want reroll", uc.TurnActionLastAction)
+	}
+}
+
+func TestSpectate_Teller_409(t *testing.T) {
+	uc := newMockUsecase()
+	uc.SeatFn = func(ctx context.Context, action, gameID, userID string) error {
+		return usecase.ErrTellerCannotSpectate
+	}
+	srv := newServer(uc, &MockView{})
+
+	r := setGameID(withSession(newReq("POST", "/game/g1/spectate", nil), "u1"), "g1")
+	w := httptest.NewRecorder()
+
+	srv.Spectate(w, r)
+
+	if w.Code != http.StatusConflict {
+		t.Fatalf("status = %d, want 409", w.Code)
+	}
+}
+
+// --- Word lists --------------------------------------------------------
+
+func TestList_RendersWords(t *testing.T) {
+	uc := newMockUsecase()
+	uc.GetWordListFn = func(ctx context.Context, listID string) (model.WordList, []model.Word, error) {
+		return model.WordList{ID: listID, Title: "Fruit"}, []model.Word{{ID: "w1", Word: "Apple", Hint: "🍎"}}, nil
+	}
+	view := &MockView{}
+	srv := newServer(uc, view)
+
+	r := setGameID(withSession(newReq("GET", "/lists/l1", nil), "u1"), "l1")
+	w := httptest.NewRecorder()
+
+	srv.List(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+	p := view.renderListPageLastParam
+	if p.List.Title != "Fruit" || len(p.Words) != 1 {
+		t.Errorf("renderListPage param = %+v", p)
+	}
+	if p.CanEdit {
+		t.Error("expected an ownerless list to be read-only")
+	}
+}
+
+func TestList_OwnerCanEdit(t *testing.T) {
+	uc := newMockUsecase()
+	uc.GetWordListFn = func(ctx context.Context, listID string) (model.WordList, []model.Word, error) {
+		return model.WordList{ID: listID, Title: "Fruit", OwnerID: "u1"}, nil, nil
+	}
+	view := &MockView{}
+	srv := newServer(uc, view)
+
+	for userID, want := range map[string]bool{"u1": true, "u2": false} {
+		r := setGameID(withSession(newReq("GET", "/lists/l1", nil), userID), "l1")
+		srv.List(httptest.NewRecorder(), r)
+		if got := view.renderListPageLastParam.CanEdit; got != want {
+			t.Errorf("%s: CanEdit = %v, want %v", userID, got, want)
+		}
+	}
+}
+
+func TestWordRoutes_Redirect303(t *testing.T) {
+	uc := newMockUsecase()
+	srv := newServer(uc, &MockView{})
+	ts := httptest.NewServer(srv.mux())
+	defer ts.Close()
+	client := &http.Client{
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	for _, path := range []string{"/lists/l1/words", "/lists/l1/words/w1", "/lists/l1/words/w1/delete"} {
+		req, _ := http.NewRequest("POST", ts.URL+path, strings.NewReader("word=Kiwi&hint=🥝"))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Set(csrfHeader, testSessions.csrfToken("u1"))
+		req.AddCookie(&http.Cookie{Name: sessionCookieKey, Value: testSessions.sign("u1")})
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("POST %s: %v", path, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusSeeOther || resp.Header.Get("Location") != "/lists/l1" {
+			t.Errorf("POST %s = %d %q, want 303 /lists/l1", path, resp.StatusCode, resp.Header.Get("Location"))
+		}
+	}
+	if uc.AddWordLastUserID != "u1" || uc.AddWordLastListID != "l1" || uc.AddWordLastWord != "Kiwi" || uc.AddWordLastHint != "🥝" {
+		t.Errorf("AddWord args = (%q, %q, %q, %q)", uc.AddWordLastUserID, uc.AddWordLastListID, uc.AddWordLastWord, uc.AddWordLastHint)
+	}
+	if uc.UpdateWordLastWordID != "w1" || uc.DeleteWordLastWordID != "w1" {
+		t.Errorf("word ids = update %q, delete %q, want w1", uc.UpdateWordLastWordID, uc.DeleteWordLastWordID)
+	}
+}
+
+func TestAddWord_ErrorStatuses(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"invalid", fmt.Errorf("%w: hint must be emoji only", usecase.ErrInvalidWord), http.StatusBadRequest},
+		{"duplicate", usecase.ErrDuplicateWord, http.StatusConflict},
+		{"missing list", usecase.ErrWordListNotFound, http.StatusNotFound},
+		{"not the owner", usecase.ErrNotListOwner, http.StatusForbidden},
+		{"unexpected", errSentinel, http.StatusInternalServerError},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			uc := newMockUsecase()
+			uc.AddWordFn = func(ctx context.Context, userID, listID, word, hint string) (model.Word, error) {
+				return model.Word{}, tc.err
+			}
+			srv := newServer(uc, &MockView{})
+
+			r := setGameID(withSession(newReq("POST", "/lists/l1/words", strings.NewReader("word=Kiwi&hint=x")), "u1"), "l1")
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			w := httptest.NewRecorder()
+
+			srv.AddWord(w, r)
+
+			if w.Code != tc.want {
+				t.Fatalf("status = %d, want %d", w.Code, tc.want)
+			}
+		})
+	}
+}
+
+func TestDeleteWord_InUse_409(t *testing.T) {
+	uc := newMockUsecase()
+	uc.DeleteWordFn = func(ctx context.Context, userID, wordID string) error {
+		return usecase.ErrWordInUse
+	}
+	srv := newServer(uc, &MockView{})
+
+	r := withSession(newReq("POST", "/lists/l1/words/w1/delete", nil), "u1")
+	r.SetPathValue("id", "l1")
+	r.SetPathValue("wordID", "w1")
+	w := httptest.NewRecorder()
+
+	srv.DeleteWord(w, r)
+
+	if w.Code != http.StatusConflict {
+		t.Fatalf("status = %d, want 409", w.Code)
+	}
+}
+
+// --- Profile ------------------------------------------------------------
+
+func TestUpdateProfile_Redirects303(t *testing.T) {
+	uc := newMockUsecase()
+	srv := newServer(uc, &MockView{})
+
+	r := withSession(newReq("POST", "/profile", strings.NewReader("nickname=Sly+Otter&avatar=%F0%9F%A6%A6")), "u1")
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+
+	srv.UpdateProfile(w, r)
+
+	if w.Code != http.StatusSeeOther {
+		t.Fatalf("status = %d, want 303", w.Code)
+	}
+	if loc := w.Header().Get("Location"); loc != "/" {
+		t.Errorf("Location = %q, want /", loc)
+	}
+	if uc.UpdateProfileLastUserID != "u1" || uc.UpdateProfileLastNickname != "Sly Otter" || uc.UpdateProfileLastAvatar != "🦦" {
+		t.Errorf("UpdateProfile args = (%q, %q, %q), want (u1, Sly Otter, 🦦)", uc.UpdateProfileLastUserID, uc.UpdateProfileLastNickname, uc.UpdateProfileLastAvatar)
+	}
+}
+
+func TestUpdateProfile_ErrorStatuses(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"invalid nickname", usecase.ErrNicknameChars, http.StatusBadRequest},
+		{"taken", usecase.ErrNicknameTaken, http.StatusConflict},
+		{"unexpected", errSentinel, http.StatusInternalServerError},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			uc := newMockUsecase()
+			uc.UpdateProfileFn = func(ctx context.Context, userID, nickname, avatar string) (model.User, error) {
+				return model.User{}, tc.err
+			}
+			srv := newServer(uc, &MockView{})
+
+			r := withSession(newReq("POST", "/profile", strings.NewReader("nickname=x")), "u1")
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			w := httptest.NewRecorder()
+
+			srv.UpdateProfile(w, r)
+
+			if w.Code != tc.want {
+				t.Errorf("status = %d, want %d", w.Code, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"sync"
+)
+
+// Broker topics. Each consumer reads every message and keeps its own topic.
+const (
+	EventsTopic = "events" // game events for SSE subscribers
+	LoopTopic   = "loop"   // control signals for whoever runs a game's loop
+)
+
+// BrokerMessage is one published message. Seq is assigned by the broker and
+// increases across all topics and games; GameSeq counts up per game on the
+// events topic and is what subscribers see as event ids.
+type BrokerMessage struct {
+	Seq     uint64
+	GameSeq uint64
+	Topic   string
+	GameID  string
+	Except  string // events: everyone but this user
+	Only    string // events: just this user
+	Type    string
+	Data    string
+}
+
+func (m BrokerMessage) GetType() string { return m.Type }
+func (m BrokerMessage) GetData() string { return m.Data }
+
+// Broker carries messages between server instances so every instance can
+// serve every game.
+type Broker interface {
+	// Publish hands msg to every subscriber, this instance's included.
+	Publish(ctx context.Context, msg BrokerMessage) error
+	// Subscribe delivers, in Seq order, every message published from now
+	// on. The channel is closed once ctx is done.
+	Subscribe(ctx context.Context) (<-chan BrokerMessage, error)
+}
+
+type memorySub struct {
+	ctx context.Context
+	ch  chan BrokerMessage
+}
+
+// memoryBroker is a Broker for instances sharing one process, as in tests.
+type memoryBroker struct {
+	mu      sync.Mutex
+	seq     uint64
+	gameSeq map[string]uint64
+	subs    []*memorySub
+}
+
+func NewMemoryBroker() Broker {
+	return &memoryBroker{gameSeq: map[string]uint64{}}
+}
+
+func (b *memoryBroker) Publish(ctx context.Context, msg BrokerMessage) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.seq++
+	msg.Seq = b.seq
+	if msg.Topic == EventsTopic {
+		b.gameSeq[msg.GameID]++
+		msg.GameSeq = b.gameSeq[msg.GameID]
+	}
+	for _, s := range b.subs {
+		select {
+		case s.ch <- msg:
+		case <-s.ctx.Done():
+		}
+	}
+	return nil
+}
+
+func (b *memoryBroker) Subscribe(ctx context.Context) (<-chan BrokerMessage, error) {
+	s := &memorySub{ctx: ctx, ch: make(chan BrokerMessage, 256)}
+	b.mu.Lock()
+	b.subs = append(b.subs, s)
+	b.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		b.mu.Lock()
+		b.subs = slices.DeleteFunc(b.subs, func(o *memorySub) bool { return o == s })
+		b.mu.Unlock()
+		close(s.ch)
+	}()
+	return s.ch, nil
+}
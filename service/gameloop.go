@@ -11,6 +11,12 @@ import (
 // It runs synchronously in the GameLoop's goroutine.
 type OnTurnEndHandler func(ctx context.Context, gameID string)
 
+// OnRevealHandler is called during a turn's play phase: once when the turn
+// timer is armed, then again whenever the delay it last returned has passed.
+// A delay <= 0 means no more calls this turn. It runs synchronously in the
+// GameLoop's goroutine.
+type OnRevealHandler func(ctx context.Context, gameID string) (next time.Duration)
+
 // Clock interface for time-based operations. Allows deterministic testing.
 type Clock interface {
 	After(d time.Duration) <-chan time.Time
@@ -35,6 +41,12 @@ type GameLoop interface {
 	// Logs a warning and returns early if gameID already has an active loop.
 	Start(ctx context.Context, gameID string, turnDuration, pickDuration time.Duration)
 
+	// Resume starts gameID's loop partway through a turn, e.g. after a
+	// restart: in play phase when picked is true, in pick phase otherwise,
+	// with remaining left on that phase's timer (<= 0 ends it right away).
+	// Later phases use the full durations. Same rules as Start otherwise.
+	Resume(ctx context.Context, gameID string, turnDuration, pickDuration time.Duration, picked bool, remaining time.Duration)
+
 	// Running reports whether gameID has an active loop.
 	Running(gameID string) bool
 
@@ -46,10 +58,18 @@ type GameLoop interface {
 	// Thread-safe, non-blocking.
 	EndGameTurn(gameID string)
 
+	// SkipTurn ends the current pick phase or turn early, as if its timer
+	// expired. Thread-safe, non-blocking.
+	SkipTurn(gameID string)
+
 	// SetOnTurnEndHandler sets the handler called when a turn ends.
 	// Must be called before Start.
 	SetOnTurnEndHandler(handler OnTurnEndHandler)
 
+	// SetOnRevealHandler sets the handler the play phase calls on its own
+	// schedule (see OnRevealHandler). Must be called before Start.
+	SetOnRevealHandler(handler OnRevealHandler)
+
 	// StopGame cancels a specific game's loop (e.g., game ended, all players left).
 	StopGame(gameID string)
 
@@ -62,9 +82,11 @@ type gameLoop struct {
 	beginChs  map[string]chan struct{} // gameID -> begin-turn signal
 	endChs    map[string]chan struct{} // gameID -> end-turn signal
 	armedChs  map[string]chan struct{} // gameID -> closed when turn timer is armed
+	skipChs   map[string]chan struct{} // gameID -> skip-pick signal
 	cancels   map[string]context.CancelFunc
 	clock     Clock
 	onTurnEnd OnTurnEndHandler
+	onReveal  OnRevealHandler
 }
 
 // NewRealClock creates a new RealClock that uses real time.
@@ -77,6 +99,7 @@ func NewGameLoop(clock Clock) GameLoop {
 		beginChs: make(map[string]chan struct{}),
 		endChs:   make(map[string]chan struct{}),
 		armedChs: make(map[string]chan struct{}),
+		skipChs:  make(map[string]chan struct{}),
 		cancels:  make(map[string]context.CancelFunc),
 		clock:    clock,
 	}
@@ -86,6 +109,10 @@ func (l *gameLoop) SetOnTurnEndHandler(handler OnTurnEndHandler) {
 	l.onTurnEnd = handler
 }
 
+func (l *gameLoop) SetOnRevealHandler(handler OnRevealHandler) {
+	l.onReveal = handler
+}
+
 func (l *gameLoop) Running(gameID string) bool {
 	l.mu.Lock()
 	defer l.mu.Unlock()
@@ -94,6 +121,21 @@ func (l *gameLoop) Running(gameID string) bool {
 }
 
 func (l *gameLoop) Start(ctx context.Context, gameID string, turnDuration, pickDuration time.Duration) {
+	l.start(ctx, gameID, turnDuration, pickDuration, phase{remaining: pickDuration})
+}
+
+func (l *gameLoop) Resume(ctx context.Context, gameID string, turnDuration, pickDuration time.Duration, picked bool, remaining time.Duration) {
+	l.start(ctx, gameID, turnDuration, pickDuration, phase{picked: picked, remaining: remaining})
+}
+
+// phase is where a loop begins: the pick or the play phase, and how long its
+// timer runs.
+type phase struct {
+	picked    bool
+	remaining time.Duration
+}
+
+func (l *gameLoop) start(ctx context.Context, gameID string, turnDuration, pickDuration time.Duration, first phase) {
 	l.mu.Lock()
 	if _, ok := l.cancels[gameID]; ok {
 		l.mu.Unlock()
@@ -107,9 +149,11 @@ func (l *gameLoop) Start(ctx context.Context, gameID string, turnDuration, pickD
 	// while a turn is active so EndGameTurn during pick phase is a no-op.
 	beginCh := make(chan struct{}, 1)
 	l.beginChs[gameID] = beginCh
+	skipCh := make(chan struct{}, 1)
+	l.skipChs[gameID] = skipCh
 	l.mu.Unlock()
 
-	go l.run(ctx, gameID, turnDuration, pickDuration, beginCh)
+	go l.run(ctx, gameID, turnDuration, pickDuration, first, beginCh, skipCh)
 }
 
 func (l *gameLoop) BeginTurn(gameID string) {
@@ -149,6 +193,25 @@ func (l *gameLoop) EndGameTurn(gameID string) {
 	}
 }
 
+func (l *gameLoop) SkipTurn(gameID string) {
+	l.mu.Lock()
+	// Play phase ends like EndGameTurn; pick phase has its own channel.
+	ch, ok := l.endChs[gameID]
+	if !ok {
+		ch, ok = l.skipChs[gameID]
+	}
+	l.mu.Unlock()
+
+	if !ok {
+		return
+	}
+
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
+
 func (l *gameLoop) StopGame(gameID string) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
@@ -168,6 +231,7 @@ func (l *gameLoop) StopGame(gameID string) {
 	}
 	delete(l.beginChs, gameID)
 	delete(l.endChs, gameID)
+	delete(l.skipChs, gameID)
 }
 
 func (l *gameLoop) Stop() {
@@ -187,20 +251,31 @@ func (l *gameLoop) Stop() {
 	l.beginChs = make(map[string]chan struct{})
 	l.endChs = make(map[string]chan struct{})
 	l.armedChs = make(map[string]chan struct{})
+	l.skipChs = make(map[string]chan struct{})
 	l.cancels = make(map[string]context.CancelFunc)
 }
 
-func (l *gameLoop) run(ctx context.Context, gameID string, turnDuration, pickDuration time.Duration, beginCh chan struct{}) {
+func (l *gameLoop) run(ctx context.Context, gameID string, turnDuration, pickDuration time.Duration, first phase, beginCh, skipCh chan struct{}) {
+	pickFor, playFor := first.remaining, turnDuration
+	if first.picked {
+		pickFor, playFor = pickDuration, first.remaining
+	}
 	for {
-		// Wait for teller pick; skip to next teller if they stall.
-		pickTimer := l.clock.After(pickDuration)
-		selected := false
-		select {
-		case <-ctx.Done():
-			return
-		case <-beginCh:
-			selected = true
-		case <-pickTimer:
+		// Wait for teller pick; skip to next teller if they stall. A loop
+		// resumed in play phase goes straight to the turn timer.
+		selected := first.picked
+		first.picked = false
+		if !selected {
+			pickTimer := l.clock.After(pickFor)
+			pickFor = pickDuration
+			select {
+			case <-ctx.Done():
+				return
+			case <-beginCh:
+				selected = true
+			case <-pickTimer:
+			case <-skipCh:
+			}
 		}
 
 		if !selected {
@@ -212,9 +287,16 @@ func (l *gameLoop) run(ctx context.Context, gameID string, turnDuration, pickDur
 			continue
 		}
 
+		// A skip that raced the pick must not carry over to the next pick phase.
+		select {
+		case <-skipCh:
+		default:
+		}
+
 		endCh := make(chan struct{}, 1)
 		// Register timer before signaling armed so Advance after BeginTurn is reliable.
-		timerCh := l.clock.After(turnDuration)
+		timerCh := l.clock.After(playFor)
+		playFor = turnDuration
 
 		l.mu.Lock()
 		l.endChs[gameID] = endCh
@@ -229,11 +311,19 @@ func (l *gameLoop) run(ctx context.Context, gameID string, turnDuration, pickDur
 			close(armed)
 		}
 
-		select {
-		case <-ctx.Done():
-			return
-		case <-endCh:
-		case <-timerCh:
+		reveal := l.nextReveal(gameID)
+	play:
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-endCh:
+				break play
+			case <-timerCh:
+				break play
+			case <-reveal:
+				reveal = l.nextReveal(gameID)
+			}
 		}
 
 		l.mu.Lock()
@@ -246,6 +336,19 @@ func (l *gameLoop) run(ctx context.Context, gameID string, turnDuration, pickDur
 	}
 }
 
+// nextReveal runs the reveal handler and returns when to run it again; nil
+// (never ready) when there is no handler or it has nothing left this turn.
+func (l *gameLoop) nextReveal(gameID string) <-chan time.Time {
+	if l.onReveal == nil {
+		return nil
+	}
+	next := l.onReveal(context.Background(), gameID)
+	if next <= 0 {
+		return nil
+	}
+	return l.clock.After(next)
+}
+
 func (l *gameLoop) resetBegin(gameID string, beginCh *chan struct{}) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
@@ -116,6 +116,87 @@ func TestGameLoop_EndGameTurn(t *testing.T) {
 	}
 }
 
+func TestGameLoop_RevealSchedule(t *testing.T) {
+	fc := servicetest.NewFakeClock()
+	reveals := make(chan time.Time, 4)
+	ended := make(chan string, 1)
+
+	gl := service.NewGameLoop(fc)
+	gl.SetOnTurnEndHandler(func(ctx context.Context, gameID string) {
+		ended <- gameID
+	})
+	// Reveal at 30s and 48s into the turn, then stop asking.
+	left := []time.Duration{30 * time.Second, 18 * time.Second}
+	gl.SetOnRevealHandler(func(ctx context.Context, gameID string) time.Duration {
+		reveals <- fc.Now()
+		if len(left) == 0 {
+			return 0
+		}
+		next := left[0]
+		left = left[1:]
+		return next
+	})
+
+	gl.Start(context.Background(), "g1", testTurn, testPick)
+	gl.BeginTurn("g1")
+	started := <-reveals
+
+	waitForTimers(t, fc, 3) // stale pick timer, turn timer, first reveal
+	fc.Advance(30 * time.Second)
+	if at := <-reveals; at.Sub(started) != 30*time.Second {
+		t.Fatalf("first reveal after %v, want 30s", at.Sub(started))
+	}
+	waitForTimers(t, fc, 2)
+	fc.Advance(18 * time.Second)
+	if at := <-reveals; at.Sub(started) != 48*time.Second {
+		t.Fatalf("second reveal after %v, want 48s", at.Sub(started))
+	}
+
+	fc.Advance(12 * time.Second)
+	select {
+	case <-ended:
+	case <-time.After(time.Second):
+		t.Fatal("OnTurnEnd not called at the end of a turn with reveals")
+	}
+	select {
+	case <-reveals:
+		t.Fatal("reveal handler called after it returned 0")
+	default:
+	}
+}
+
+func TestGameLoop_SkipTurnDuringPick(t *testing.T) {
+	fc := servicetest.NewFakeClock()
+	calls := make(chan string, 1)
+
+	gl := service.NewGameLoop(fc)
+	gl.SetOnTurnEndHandler(func(ctx context.Context, gameID string) {
+		calls <- gameID
+	})
+
+	gl.Start(context.Background(), "g1", testTurn, testPick)
+	gl.EndGameTurn("g1") // no-op while picking
+	gl.SkipTurn("g1")
+
+	select {
+	case id := <-calls:
+		if id != "g1" {
+			t.Fatalf("expected g1, got %s", id)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("OnTurnEnd not called after SkipTurn")
+	}
+
+	gl.BeginTurn("g1")
+	gl.SkipTurn("g1")
+
+	select {
+	case <-calls:
+	case <-time.After(time.Second):
+		t.Fatal("OnTurnEnd not called after SkipTurn during play")
+	}
+}
+
 func TestGameLoop_TimeoutBeforeAllGuessed(t *testing.T) {
 	fc := servicetest.NewFakeClock()
 	calls := make(chan string, 1)
@@ -311,3 +392,62 @@ func TestGameLoop_MultipleTurns(t *testing.T) {
 	default:
 	}
 }
+
+func TestGameLoop_ResumeMidTurn(t *testing.T) {
+	fc := servicetest.NewFakeClock()
+	calls := make(chan string, 1)
+
+	gl := service.NewGameLoop(fc)
+	gl.SetOnTurnEndHandler(func(ctx context.Context, gameID string) {
+		calls <- gameID
+	})
+
+	// Picked before the restart, 10s of play left.
+	gl.Resume(context.Background(), "g1", testTurn, testPick, true, 10*time.Second)
+	for fc.PendingTimers() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	fc.Advance(11 * time.Second)
+
+	select {
+	case <-calls:
+	case <-time.After(time.Second):
+		t.Fatal("OnTurnEnd not called once the remaining play time ran out")
+	}
+
+	// The next pick phase gets the full window again.
+	for fc.PendingTimers() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	fc.Advance(testPick - time.Second)
+	select {
+	case <-calls:
+		t.Fatal("next pick phase used the resumed remaining time")
+	case <-time.After(20 * time.Millisecond):
+	}
+	gl.Stop()
+}
+
+func TestGameLoop_ResumeExpiredPick(t *testing.T) {
+	fc := servicetest.NewFakeClock()
+	calls := make(chan string, 1)
+
+	gl := service.NewGameLoop(fc)
+	gl.SetOnTurnEndHandler(func(ctx context.Context, gameID string) {
+		calls <- gameID
+	})
+
+	// The pick deadline passed while the server was down.
+	gl.Resume(context.Background(), "g1", testTurn, testPick, false, -time.Minute)
+	for fc.PendingTimers() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	fc.Advance(0)
+
+	select {
+	case <-calls:
+	case <-time.After(time.Second):
+		t.Fatal("expected an expired pick phase to end right away")
+	}
+	gl.Stop()
+}
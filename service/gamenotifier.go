@@ -1,18 +1,34 @@
 package service
 
 import (
+	"cmp"
+	"context"
 	"crypto/rand"
 	"encoding/hex"
+	"fmt"
 	"log"
 	"slices"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 type GameNotifier interface {
 	Pub(gameID string, userID string, notif GameNotification)
 	PubAll(gameID string, notif GameNotification)
+	// PubTo delivers notif only to userID's subscriptions in gameID.
+	PubTo(gameID string, userID string, notif GameNotification)
 	Sub(gameID string, userID string) (chan GameNotification, func())
+	// Resume subscribes like Sub and also returns the events published to
+	// userID after lastEventID. When those have fallen out of the replay
+	// buffer the only event returned is a ResyncNotification.
+	Resume(gameID string, userID string, lastEventID uint64) ([]Event, chan GameNotification, func())
 	Subs(gameID string) []string
+	// Stats reports how often slow subscribers overflowed their queue.
+	Stats() NotifierStats
+	// Close sends final to every subscriber on this instance and closes their
+	// channels; later subscriptions get a closed channel straight away.
+	Close(final GameNotification)
 }
 
 type GameNotification interface {
@@ -37,15 +53,228 @@ func generateRandomID() string {
 	return id
 }
 
+// Event is a published notification stamped with its per-game sequence
+// number. Subscribers receive Events, so a consumer that needs the id can
+// type-assert for it.
+type Event struct {
+	ID uint64
+	GameNotification
+}
+
+// ResyncNotification tells a resuming subscriber that the events it missed are
+// gone and it has to reload the full game state.
+type ResyncNotification struct{}
+
+func (ResyncNotification) GetType() string { return "resync" }
+func (ResyncNotification) GetData() string { return "" }
+
+const (
+	// DefaultReplayBuffer is how many events per game are kept for replay.
+	DefaultReplayBuffer = 256
+	// DefaultReplayTTL is how long a game's events are kept after its last
+	// publish once nobody here is subscribed to it.
+	DefaultReplayTTL = 10 * time.Minute
+	// DefaultQueueSize is how many undelivered events a subscriber may have
+	// queued before the overflow policy kicks in.
+	DefaultQueueSize = 64
+)
+
+// OverflowPolicy decides what happens to a subscriber whose queue is full.
+// Publishing never waits on a slow subscriber.
+type OverflowPolicy int
+
+const (
+	// Disconnect closes the subscriber's channel. A browser reconnects with
+	// Last-Event-ID and catches up from the replay buffer, so nothing is lost
+	// unless it stays away too long.
+	Disconnect OverflowPolicy = iota
+	// DropOldest discards the oldest queued event to make room.
+	DropOldest
+	// Coalesce discards queued events of the same type as the new one,
+	// falling back to DropOldest. It suits clients that treat events as
+	// "refetch" signals rather than data.
+	Coalesce
+)
+
+func (p OverflowPolicy) String() string {
+	switch p {
+	case DropOldest:
+		return "drop-oldest"
+	case Coalesce:
+		return "coalesce"
+	default:
+		return "disconnect"
+	}
+}
+
+// ParseOverflowPolicy reads a policy name as printed by String.
+func ParseOverflowPolicy(s string) (OverflowPolicy, error) {
+	for _, p := range []OverflowPolicy{Disconnect, DropOldest, Coalesce} {
+		if p.String() == s {
+			return p, nil
+		}
+	}
+	return Disconnect, fmt.Errorf("unknown overflow policy %q", s)
+}
+
+// NotifierStats counts what the overflow policy did. Published counts
+// events, not deliveries.
+type NotifierStats struct {
+	Published    uint64
+	Dropped      uint64
+	Coalesced    uint64
+	Disconnected uint64
+}
+
+// loggedEvent remembers who an event was meant for, so a replay delivers it
+// to the same audience as the live publish did.
+type loggedEvent struct {
+	Event
+	except string // Pub: everyone but the sender
+	only   string // PubTo: just this user
+}
+
+func (le loggedEvent) visibleTo(userID string) bool {
+	if le.only != "" {
+		return le.only == userID
+	}
+	return le.except != userID
+}
+
+// gameLog is one game's ring of recent events and its sequence. Everything
+// published to the game after floor is either in the ring or was never
+// visible here.
+type gameLog struct {
+	floor uint64
+	seq   uint64 // the game's newest event id
+	ring  []loggedEvent
+	next  int
+	full  bool
+	// touched is the last publish, or when the last subscriber left.
+	touched time.Time
+}
+
+// append logs le, which must be newer than seq. A jump past seq+1 means the
+// events in between were never seen here, so the ring starts over after them.
+func (gl *gameLog) append(le loggedEvent) {
+	if le.ID > gl.seq+1 {
+		gl.floor = le.ID - 1
+		gl.next, gl.full = 0, false
+	}
+	gl.seq = le.ID
+	if gl.full {
+		gl.floor = gl.ring[gl.next].ID
+	}
+	gl.ring[gl.next] = le
+	gl.next = (gl.next + 1) % len(gl.ring)
+	if gl.next == 0 {
+		gl.full = true
+	}
+}
+
+// since returns the buffered events after lastID in order, and false when
+// some of them were already overwritten or never seen.
+func (gl *gameLog) since(lastID uint64) ([]loggedEvent, bool) {
+	if lastID < gl.floor || lastID > gl.seq {
+		// too old for the ring, or from an id space this process never
+		// handed out
+		return nil, false
+	}
+	ordered := gl.ring[:gl.next]
+	if gl.full {
+		ordered = append(slices.Clone(gl.ring[gl.next:]), ordered...)
+	}
+	idx, _ := slices.BinarySearchFunc(ordered, lastID+1, func(le loggedEvent, id uint64) int {
+		return cmp.Compare(le.ID, id)
+	})
+	return ordered[idx:], true
+}
+
 type gameSub struct {
 	SubID     string
 	UserID    string
 	GameID    string
 	NotifChan chan GameNotification
+
+	// mu serialises sends so the overflow policy can rearrange the queue;
+	// closed is set once Disconnect has closed NotifChan.
+	mu     sync.Mutex
+	closed bool
 }
+
 type gameNotifier struct {
 	mu   sync.RWMutex
-	subs []gameSub
+	subs map[string][]*gameSub // by game id, in subscription order
+	// logs outlive their subscribers so a lone player, or a whole room
+	// after a network blip, can still resume; idle ones expire after
+	// replayTTL.
+	logs       map[string]*gameLog
+	sweptAt    time.Time
+	clock      Clock
+	broker     Broker
+	bufferSize int
+	replayTTL  time.Duration
+	queueSize  int
+	overflow   OverflowPolicy
+	closed     bool
+
+	published    atomic.Uint64
+	dropped      atomic.Uint64
+	coalesced    atomic.Uint64
+	disconnected atomic.Uint64
+}
+
+// NotifierOption configures a GameNotifier.
+type NotifierOption func(*gameNotifier)
+
+// WithReplayBuffer sets how many events per game are kept for Resume.
+func WithReplayBuffer(n int) NotifierOption {
+	return func(gn *gameNotifier) {
+		if n > 0 {
+			gn.bufferSize = n
+		}
+	}
+}
+
+// WithReplayTTL sets how long an idle game's events are kept for Resume once
+// nobody here is subscribed to it.
+func WithReplayTTL(d time.Duration) NotifierOption {
+	return func(gn *gameNotifier) {
+		if d > 0 {
+			gn.replayTTL = d
+		}
+	}
+}
+
+// WithNotifierClock sets the clock replay TTLs are measured with.
+func WithNotifierClock(c Clock) NotifierOption {
+	return func(gn *gameNotifier) {
+		gn.clock = c
+	}
+}
+
+// WithBroker routes every publish through b so subscribers on other server
+// instances see it too; each game's ids then come from the broker.
+func WithBroker(b Broker) NotifierOption {
+	return func(gn *gameNotifier) {
+		gn.broker = b
+	}
+}
+
+// WithQueueSize sets how many events a subscriber may have queued.
+func WithQueueSize(n int) NotifierOption {
+	return func(gn *gameNotifier) {
+		if n > 0 {
+			gn.queueSize = n
+		}
+	}
+}
+
+// WithOverflowPolicy sets what happens when a subscriber's queue is full.
+func WithOverflowPolicy(p OverflowPolicy) NotifierOption {
+	return func(gn *gameNotifier) {
+		gn.overflow = p
+	}
 }
 
 func (gn *gameNotifier) Subs(gameID string) []string {
@@ -54,11 +283,7 @@ func (gn *gameNotifier) Subs(gameID string) []string {
 
 	subs := []string{}
 
-	for _, sub := range gn.subs {
-		if sub.GameID != gameID {
-			continue
-		}
-
+	for _, sub := range gn.subs[gameID] {
 		hasUserID := slices.Contains(subs, sub.UserID)
 
 		if hasUserID {
@@ -72,58 +297,306 @@ func (gn *gameNotifier) Subs(gameID string) []string {
 	return subs
 }
 
-func NewGameNotifier() GameNotifier {
-	// we need to listen for channel in order to sub/unsub people as well
-	// also think about one channel to funnel all the messages and filter at the sub side
-	return &gameNotifier{subs: []gameSub{}}
+func (gn *gameNotifier) Stats() NotifierStats {
+	return NotifierStats{
+		Published:    gn.published.Load(),
+		Dropped:      gn.dropped.Load(),
+		Coalesced:    gn.coalesced.Load(),
+		Disconnected: gn.disconnected.Load(),
+	}
+}
+
+// NewGameNotifier fails only when it cannot subscribe to its broker, as
+// every publish would then vanish.
+func NewGameNotifier(opts ...NotifierOption) (GameNotifier, error) {
+	gn := &gameNotifier{
+		subs:       map[string][]*gameSub{},
+		logs:       map[string]*gameLog{},
+		clock:      NewRealClock(),
+		bufferSize: DefaultReplayBuffer,
+		replayTTL:  DefaultReplayTTL,
+		queueSize:  DefaultQueueSize,
+	}
+	for _, opt := range opts {
+		opt(gn)
+	}
+	if gn.broker != nil {
+		if err := gn.consume(context.Background()); err != nil {
+			return nil, fmt.Errorf("notifier: subscribe to broker: %w", err)
+		}
+	}
+	return gn, nil
+}
+
+// consume dispatches broker events to this instance's subscribers.
+func (gn *gameNotifier) consume(ctx context.Context) error {
+	msgs, err := gn.broker.Subscribe(ctx)
+	if err != nil {
+		return err
+	}
+	go func() {
+		for msg := range msgs {
+			if msg.Topic != EventsTopic {
+				continue
+			}
+			le := loggedEvent{Event: Event{ID: msg.GameSeq, GameNotification: msg}, except: msg.Except, only: msg.Only}
+			gn.dispatch(msg.GameID, le)
+		}
+	}()
+	return nil
 }
 
 func (gn *gameNotifier) Sub(gameID string, userID string) (chan GameNotification, func()) {
-	ch := make(chan GameNotification)
-	subID := generateRandomID()
-	gs := gameSub{subID, userID, gameID, ch}
+	_, ch, cleanup := gn.Resume(gameID, userID, 0)
+	return ch, cleanup
+}
 
+func (gn *gameNotifier) Resume(gameID string, userID string, lastEventID uint64) ([]Event, chan GameNotification, func()) {
+	ch := make(chan GameNotification, gn.queueSize)
+	gs := &gameSub{SubID: generateRandomID(), UserID: userID, GameID: gameID, NotifChan: ch}
+
+	// Snapshot and subscribe under one lock so nothing published in between
+	// is either lost or delivered twice.
 	gn.mu.Lock()
-	gn.subs = append(gn.subs, gs)
+	if gn.closed {
+		gn.mu.Unlock()
+		close(ch)
+		return nil, ch, func() {}
+	}
+	gn.expire()
+	var missed []Event
+	if lastEventID > 0 {
+		gl := gn.gameLog(gameID)
+		if logged, ok := gl.since(lastEventID); ok {
+			for _, le := range logged {
+				if le.visibleTo(userID) {
+					missed = append(missed, le.Event)
+				}
+			}
+		} else {
+			missed = []Event{{ID: gl.seq, GameNotification: ResyncNotification{}}}
+		}
+	}
+	gn.subs[gameID] = append(gn.subs[gameID], gs)
 	gn.mu.Unlock()
 
-	return ch, func() {
+	return missed, ch, func() {
 		gn.mu.Lock()
 		defer gn.mu.Unlock()
-		gn.subs = slices.DeleteFunc(gn.subs, func(s gameSub) bool {
-			return s.SubID == subID
-		})
+		gn.remove(gs)
+		if len(gn.subs[gameID]) == 0 {
+			delete(gn.subs, gameID)
+			// the log stays for whoever comes back within replayTTL
+			if gl, ok := gn.logs[gameID]; ok {
+				gl.touched = gn.clock.Now()
+			}
+		}
 	}
 }
 
-func (gn *gameNotifier) Pub(gameID string, userID string, notif GameNotification) {
-	gn.mu.RLock()
-	targets := []gameSub{}
-	for _, s := range gn.subs {
-		if s.GameID != gameID || s.UserID == userID {
-			continue
+func (gn *gameNotifier) Close(final GameNotification) {
+	gn.mu.Lock()
+	gn.closed = true
+	var all []*gameSub
+	for _, subs := range gn.subs {
+		all = append(all, subs...)
+	}
+	gn.mu.Unlock()
+
+	// Not logged or numbered: it is about this process, not the game.
+	ev := Event{GameNotification: final}
+	for _, s := range all {
+		s.mu.Lock()
+		if !s.closed {
+			select {
+			case s.NotifChan <- ev:
+			default:
+				// Full: make room (the reader may beat us to it); it is
+				// about to be cut off anyway.
+				select {
+				case <-s.NotifChan:
+				default:
+				}
+				s.NotifChan <- ev
+			}
+			s.closed = true
+			close(s.NotifChan)
 		}
-		targets = append(targets, s)
+		s.mu.Unlock()
 	}
-	gn.mu.RUnlock()
+}
 
-	for _, s := range targets {
-		s.NotifChan <- notif
+// remove drops gs from its game's subscribers. Callers hold mu.
+func (gn *gameNotifier) remove(gs *gameSub) {
+	gn.subs[gs.GameID] = slices.DeleteFunc(gn.subs[gs.GameID], func(s *gameSub) bool {
+		return s == gs
+	})
+}
+
+// gameLog returns gameID's log, creating it. Callers hold mu.
+//
+// Ids count up per game. Locally a new log starts from the current time, so
+// ids handed out before a restart or before the log expired are never reused
+// and resuming from one gets a resync. With a broker the broker numbers each
+// game's events, so a new log starts empty and waits for the next one.
+func (gn *gameNotifier) gameLog(gameID string) *gameLog {
+	gl, ok := gn.logs[gameID]
+	if !ok {
+		now := gn.clock.Now()
+		gl = &gameLog{ring: make([]loggedEvent, gn.bufferSize), touched: now}
+		if gn.broker == nil {
+			gl.floor = uint64(now.UnixMicro())
+			gl.seq = gl.floor
+		}
+		gn.logs[gameID] = gl
 	}
+	return gl
 }
 
-func (gn *gameNotifier) PubAll(gameID string, notif GameNotification) {
-	gn.mu.RLock()
-	targets := []gameSub{}
-	for _, s := range gn.subs {
-		if s.GameID != gameID {
-			continue
+// expire drops the logs of games nobody here is subscribed to that have been
+// idle for replayTTL, checking at most every quarter TTL. Callers hold mu.
+func (gn *gameNotifier) expire() {
+	now := gn.clock.Now()
+	if now.Sub(gn.sweptAt) < gn.replayTTL/4 {
+		return
+	}
+	gn.sweptAt = now
+	for gameID, gl := range gn.logs {
+		if len(gn.subs[gameID]) == 0 && now.Sub(gl.touched) >= gn.replayTTL {
+			delete(gn.logs, gameID)
 		}
-		targets = append(targets, s)
 	}
-	gn.mu.RUnlock()
+}
 
+// publish hands le to the broker, or dispatches it here when there is none.
+func (gn *gameNotifier) publish(gameID string, le loggedEvent) {
+	if gn.broker == nil {
+		gn.dispatch(gameID, le)
+		return
+	}
+	msg := BrokerMessage{
+		Topic:  EventsTopic,
+		GameID: gameID,
+		Except: le.except,
+		Only:   le.only,
+		Type:   le.GetType(),
+		Data:   le.GetData(),
+	}
+	if err := gn.broker.Publish(context.Background(), msg); err != nil {
+		log.Printf("notifier: publish %s to broker: %v\n", msg.Type, err)
+	}
+}
+
+// dispatch stamps le with the game's next id unless the broker gave it one,
+// logs it and queues it for the subscribers it is visible to. Games nobody
+// here is subscribed to are logged too, for players who come back.
+func (gn *gameNotifier) dispatch(gameID string, le loggedEvent) {
+	gn.mu.Lock()
+	gn.expire()
+	gl := gn.gameLog(gameID)
+	if le.ID == 0 {
+		le.ID = gl.seq + 1
+	} else if le.ID <= gl.seq {
+		// the broker hands each game's events over in order, so this
+		// one was seen already
+		gn.mu.Unlock()
+		return
+	}
+	gl.append(le)
+	gl.touched = gn.clock.Now()
+	targets := []*gameSub{}
+	for _, s := range gn.subs[gameID] {
+		if le.visibleTo(s.UserID) {
+			targets = append(targets, s)
+		}
+	}
+	gn.mu.Unlock()
+
+	gn.published.Add(1)
 	for _, s := range targets {
-		s.NotifChan <- notif
+		gn.deliver(s, le.Event)
+	}
+}
+
+// deliver queues ev for s without ever blocking, applying the overflow policy
+// when the queue is full.
+func (gn *gameNotifier) deliver(s *gameSub, ev Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	select {
+	case s.NotifChan <- ev:
+		return
+	default:
+	}
+
+	switch gn.overflow {
+	case DropOldest:
+		gn.dropOldest(s, ev)
+	case Coalesce:
+		// The reader only ever takes from the queue, so once drained
+		// everything kept plus ev fits again.
+		queued := drain(s.NotifChan)
+		kept := slices.DeleteFunc(slices.Clone(queued), func(q GameNotification) bool {
+			return q.GetType() == ev.GetType()
+		})
+		if len(kept) == len(queued) && len(kept) > 0 {
+			kept = kept[1:]
+			gn.dropped.Add(1)
+		} else {
+			gn.coalesced.Add(uint64(len(queued) - len(kept)))
+		}
+		for _, q := range kept {
+			s.NotifChan <- q
+		}
+		s.NotifChan <- ev
+	default:
+		log.Printf("notifier: disconnecting slow subscriber %s in game %s\n", s.UserID, s.GameID)
+		s.closed = true
+		close(s.NotifChan)
+		gn.disconnected.Add(1)
+		gn.mu.Lock()
+		gn.remove(s)
+		gn.mu.Unlock()
 	}
 }
+
+func (gn *gameNotifier) dropOldest(s *gameSub, ev Event) {
+	select {
+	case <-s.NotifChan:
+	default:
+	}
+	gn.dropped.Add(1)
+	select {
+	case s.NotifChan <- ev:
+	default:
+		// only the reader takes from the queue, so this can't happen
+		gn.dropped.Add(1)
+	}
+}
+
+func drain(ch chan GameNotification) []GameNotification {
+	var queued []GameNotification
+	for {
+		select {
+		case q := <-ch:
+			queued = append(queued, q)
+		default:
+			return queued
+		}
+	}
+}
+
+func (gn *gameNotifier) Pub(gameID string, userID string, notif GameNotification) {
+	gn.publish(gameID, loggedEvent{Event: Event{GameNotification: notif}, except: userID})
+}
+
+func (gn *gameNotifier) PubAll(gameID string, notif GameNotification) {
+	gn.publish(gameID, loggedEvent{Event: Event{GameNotification: notif}})
+}
+
+func (gn *gameNotifier) PubTo(gameID string, userID string, notif GameNotification) {
+	gn.publish(gameID, loggedEvent{Event: Event{GameNotification: notif}, only: userID})
+}
@@ -1,9 +1,13 @@
 package service_test
 
 import (
+	"context"
 	"emojix/service"
+	"emojix/service/servicetest"
+	"errors"
 	"fmt"
 	"testing"
+	"time"
 )
 
 type testNotif struct {
@@ -20,7 +24,10 @@ func (t testNotif) GetType() string {
 }
 
 func TestGameNotifierPubSub(t *testing.T) {
-	notifier := service.NewGameNotifier()
+	notifier, err := service.NewGameNotifier()
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	subCh, _ := notifier.Sub("some-game-id", "some-user-id")
 
@@ -51,7 +58,10 @@ func TestGameNotifierPubSub(t *testing.T) {
 
 func TestGameNotifierSubs(t *testing.T) {
 
-	notifier := service.NewGameNotifier()
+	notifier, err := service.NewGameNotifier()
+	if err != nil {
+		t.Fatal(err)
+	}
 	_, _ = notifier.Sub("some-game-id", "user-1")
 	_, _ = notifier.Sub("some-game-id", "user-2")
 	_, _ = notifier.Sub("some-game-id", "user-3")
@@ -78,3 +88,316 @@ func TestGameNotifierSubs(t *testing.T) {
 		t.Errorf("expected user '%s' but got %s", expectedUser, subs[2])
 	}
 }
+
+func TestGameNotifierPubTo(t *testing.T) {
+	notifier, err := service.NewGameNotifier()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetCh, _ := notifier.Sub("some-game-id", "user-1")
+	otherCh, _ := notifier.Sub("some-game-id", "user-2")
+
+	go notifier.PubTo("some-game-id", "user-1", testNotif{notiftype: "close", content: "secrt"})
+
+	msg := <-targetCh
+	if msg.GetType() != "close" {
+		t.Errorf("expected close notif but got %s", msg.GetType())
+	}
+
+	select {
+	case msg := <-otherCh:
+		t.Errorf("expected no notif for other user but got %s", msg.GetType())
+	default:
+	}
+}
+
+func TestGameNotifierResume(t *testing.T) {
+	notifier, err := service.NewGameNotifier()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ch, cleanup := notifier.Sub("some-game-id", "user-1")
+	notifier.PubAll("some-game-id", testNotif{notiftype: "test-1"})
+	notifier.Pub("some-game-id", "user-1", testNotif{notiftype: "own-msg"})
+	notifier.PubTo("some-game-id", "user-2", testNotif{notiftype: "close"})
+	notifier.PubAll("some-game-id", testNotif{notiftype: "test-2"})
+	notifier.PubTo("some-game-id", "user-1", testNotif{notiftype: "test-3"})
+
+	first := (<-ch).(service.Event)
+	cleanup() // user-1 drops after the first event
+
+	missed, _, _ := notifier.Resume("some-game-id", "user-1", first.ID)
+
+	want := []struct {
+		id  uint64
+		typ string
+	}{{first.ID + 3, "test-2"}, {first.ID + 4, "test-3"}}
+	if len(missed) != len(want) {
+		t.Fatalf("expected %d missed events but got %v", len(want), missed)
+	}
+	for i, w := range want {
+		if missed[i].ID != w.id || missed[i].GetType() != w.typ {
+			t.Errorf("missed[%d] = %d %s, want %d %s", i, missed[i].ID, missed[i].GetType(), w.id, w.typ)
+		}
+	}
+
+	upToDate, _, _ := notifier.Resume("some-game-id", "user-1", first.ID+4)
+	if len(upToDate) != 0 {
+		t.Errorf("expected nothing to replay but got %v", upToDate)
+	}
+}
+
+func TestGameNotifierResumeAlone(t *testing.T) {
+	clock := servicetest.NewFakeClock()
+	notifier, err := service.NewGameNotifier(service.WithNotifierClock(clock), service.WithReplayTTL(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ch, cleanup := notifier.Sub("some-game-id", "user-1")
+	notifier.PubAll("some-game-id", testNotif{notiftype: "test-1"})
+	first := (<-ch).(service.Event)
+	cleanup() // the only subscriber drops
+
+	notifier.PubAll("some-game-id", testNotif{notiftype: "test-2"})
+	missed, _, cleanup := notifier.Resume("some-game-id", "user-1", first.ID)
+	if len(missed) != 1 || missed[0].ID != first.ID+1 || missed[0].GetType() != "test-2" {
+		t.Fatalf("expected test-2 at id %d but got %v", first.ID+1, missed)
+	}
+	cleanup()
+
+	// Once the game has sat idle for the TTL its log is gone.
+	clock.Advance(time.Minute)
+	notifier.PubAll("other-game-id", testNotif{notiftype: "sweep"})
+	missed, _, _ = notifier.Resume("some-game-id", "user-1", first.ID)
+	if len(missed) != 1 || missed[0].GetType() != "resync" {
+		t.Errorf("expected a resync after the TTL but got %v", missed)
+	}
+}
+
+func TestGameNotifierPerGameIDs(t *testing.T) {
+	notifier, err := service.NewGameNotifier()
+	if err != nil {
+		t.Fatal(err)
+	}
+	chA, _ := notifier.Sub("game-a", "user-1")
+
+	notifier.PubAll("game-a", testNotif{notiftype: "a-1"})
+	notifier.PubAll("game-b", testNotif{notiftype: "b-1"})
+	notifier.PubAll("game-a", testNotif{notiftype: "a-2"})
+
+	a1, a2 := (<-chA).(service.Event), (<-chA).(service.Event)
+	if a2.ID != a1.ID+1 {
+		t.Errorf("expected game-a's ids to be consecutive but got %d then %d", a1.ID, a2.ID)
+	}
+}
+
+func TestGameNotifierResumeGap(t *testing.T) {
+	notifier, err := service.NewGameNotifier(service.WithReplayBuffer(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ch, _ := notifier.Sub("some-game-id", "user-2")
+
+	ids := []uint64{}
+	for i := 1; i <= 4; i++ {
+		notifier.PubAll("some-game-id", testNotif{notiftype: fmt.Sprintf("test-%d", i)})
+		ids = append(ids, (<-ch).(service.Event).ID)
+	}
+
+	missed, _, _ := notifier.Resume("some-game-id", "user-1", ids[1])
+	if len(missed) != 2 || missed[0].ID != ids[2] {
+		t.Fatalf("expected the last two events but got %v", missed)
+	}
+
+	// Before the ring, or from an id space this notifier never used.
+	for _, lastID := range []uint64{ids[0], ids[3] + 100} {
+		missed, _, _ = notifier.Resume("some-game-id", "user-1", lastID)
+		if len(missed) != 1 || missed[0].GetType() != "resync" || missed[0].ID != ids[3] {
+			t.Errorf("last id %d: expected a single resync at id %d but got %v", lastID, ids[3], missed)
+		}
+	}
+}
+
+func TestGameNotifierOverflow(t *testing.T) {
+	drain := func(ch chan service.GameNotification) []string {
+		got := []string{}
+		for {
+			select {
+			case msg, ok := <-ch:
+				if !ok {
+					return append(got, "closed")
+				}
+				got = append(got, msg.GetType())
+			default:
+				return got
+			}
+		}
+	}
+
+	cases := []struct {
+		name      string
+		policy    service.OverflowPolicy
+		published []string
+		want      []string
+		wantStats service.NotifierStats
+	}{
+		{
+			name:      "disconnect",
+			policy:    service.Disconnect,
+			published: []string{"join", "msg", "left"},
+			want:      []string{"join", "msg", "closed"},
+			wantStats: service.NotifierStats{Published: 3, Disconnected: 1},
+		},
+		{
+			name:      "drop oldest",
+			policy:    service.DropOldest,
+			published: []string{"join", "msg", "left"},
+			want:      []string{"msg", "left"},
+			wantStats: service.NotifierStats{Published: 3, Dropped: 1},
+		},
+		{
+			name:      "coalesce",
+			policy:    service.Coalesce,
+			published: []string{"join", "msg", "join"},
+			want:      []string{"msg", "join"},
+			wantStats: service.NotifierStats{Published: 3, Coalesced: 1},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			notifier, err := service.NewGameNotifier(service.WithQueueSize(2), service.WithOverflowPolicy(tc.policy))
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, _ = notifier.Sub("some-game-id", "fast-user")
+			ch, _ := notifier.Sub("some-game-id", "slow-user")
+
+			// Nobody reads: a blocking fan-out would hang here.
+			for _, typ := range tc.published {
+				notifier.PubTo("some-game-id", "slow-user", testNotif{notiftype: typ})
+			}
+
+			if got := fmt.Sprint(drain(ch)); got != fmt.Sprint(tc.want) {
+				t.Errorf("expected %s but got %s", fmt.Sprint(tc.want), got)
+			}
+			if stats := notifier.Stats(); stats != tc.wantStats {
+				t.Errorf("expected stats %+v but got %+v", tc.wantStats, stats)
+			}
+			if subs := notifier.Subs("some-game-id"); tc.policy == service.Disconnect && len(subs) != 1 {
+				t.Errorf("expected the slow subscriber to be dropped but got %v", subs)
+			}
+		})
+	}
+}
+
+func TestParseOverflowPolicy(t *testing.T) {
+	for _, p := range []service.OverflowPolicy{service.Disconnect, service.DropOldest, service.Coalesce} {
+		got, err := service.ParseOverflowPolicy(p.String())
+		if err != nil || got != p {
+			t.Errorf("ParseOverflowPolicy(%q) = %v, %v", p.String(), got, err)
+		}
+	}
+	if _, err := service.ParseOverflowPolicy("block"); err == nil {
+		t.Error("expected an error for an unknown policy")
+	}
+}
+
+// downBroker refuses every subscription, as a broker whose database is gone.
+type downBroker struct{}
+
+func (downBroker) Publish(ctx context.Context, msg service.BrokerMessage) error {
+	return errors.New("broker down")
+}
+
+func (downBroker) Subscribe(ctx context.Context) (<-chan service.BrokerMessage, error) {
+	return nil, errors.New("broker down")
+}
+
+func TestGameNotifierBrokerDown(t *testing.T) {
+	if _, err := service.NewGameNotifier(service.WithBroker(downBroker{})); err == nil {
+		t.Fatal("expected an error when the broker refuses the subscription")
+	}
+}
+
+func TestGameNotifierBroker(t *testing.T) {
+	broker := service.NewMemoryBroker()
+	instanceA, err := service.NewGameNotifier(service.WithBroker(broker))
+	if err != nil {
+		t.Fatal(err)
+	}
+	instanceB, err := service.NewGameNotifier(service.WithBroker(broker))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chA, _ := instanceA.Sub("some-game-id", "user-1")
+	chB, _ := instanceB.Sub("some-game-id", "user-2")
+
+	instanceA.Pub("some-game-id", "user-1", testNotif{notiftype: "msg", content: "hi"})
+	instanceB.PubTo("some-game-id", "user-1", testNotif{notiftype: "close"})
+
+	recv := func(ch chan service.GameNotification) service.Event {
+		t.Helper()
+		select {
+		case msg := <-ch:
+			return msg.(service.Event)
+		case <-time.After(time.Second):
+			t.Fatal("expected an event from the other instance")
+			return service.Event{}
+		}
+	}
+
+	msg := recv(chB)
+	if msg.GetType() != "msg" || msg.GetData() != "hi" {
+		t.Errorf("expected msg/hi on instance B but got %s/%s", msg.GetType(), msg.GetData())
+	}
+	closeNotif := recv(chA)
+	if closeNotif.GetType() != "close" || closeNotif.ID <= msg.ID {
+		t.Errorf("expected close after id %d on instance A but got %s at %d", msg.ID, closeNotif.GetType(), closeNotif.ID)
+	}
+
+	if closeNotif.ID != msg.ID+1 {
+		t.Errorf("expected the game's ids to be consecutive but got %d then %d", msg.ID, closeNotif.ID)
+	}
+
+	// Both instances share the broker's ids, so either can resume.
+	missed, _, _ := instanceB.Resume("some-game-id", "user-1", msg.ID)
+	if len(missed) != 1 || missed[0].GetType() != "close" {
+		t.Errorf("expected instance B to replay close but got %v", missed)
+	}
+}
+
+func TestGameNotifierClose(t *testing.T) {
+	notifier, err := service.NewGameNotifier(service.WithQueueSize(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	idle, _ := notifier.Sub("g1", "idle")
+	full, _ := notifier.Sub("g2", "full")
+	notifier.PubAll("g2", testNotif{notiftype: "queued"}) // fills full's queue
+
+	notifier.Close(testNotif{notiftype: "bye"})
+
+	for name, ch := range map[string]chan service.GameNotification{"idle": idle, "full": full} {
+		var got []string
+		for n := range ch {
+			got = append(got, n.GetType())
+		}
+		if len(got) != 1 || got[0] != "bye" {
+			t.Errorf("%s: expected only the final event before the close, got %v", name, got)
+		}
+	}
+
+	late, _ := notifier.Sub("g1", "late")
+	select {
+	case _, ok := <-late:
+		if ok {
+			t.Error("expected a subscription after Close to be closed")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("subscription after Close was left open")
+	}
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// LeaseStore hands out per-game leases so only one server instance drives a
+// game's loop at a time.
+type LeaseStore interface {
+	// Acquire takes gameID's lease for owner, or renews it, until ttl from
+	// now. It reports false while another owner holds an unexpired lease.
+	Acquire(ctx context.Context, gameID, owner string, ttl time.Duration) (bool, error)
+	// Release gives up owner's lease on gameID, if it still holds it.
+	Release(ctx context.Context, gameID, owner string) error
+	// Holder returns the owner of gameID's unexpired lease, or "" when
+	// nobody holds one.
+	Holder(ctx context.Context, gameID string) (string, error)
+}
+
+type lease struct {
+	owner     string
+	expiresAt time.Time
+}
+
+type memoryLeaseStore struct {
+	mu     sync.Mutex
+	clock  Clock
+	leases map[string]lease
+}
+
+// NewMemoryLeaseStore returns a LeaseStore for instances sharing one process.
+func NewMemoryLeaseStore(clock Clock) LeaseStore {
+	return &memoryLeaseStore{clock: clock, leases: map[string]lease{}}
+}
+
+func (s *memoryLeaseStore) Acquire(ctx context.Context, gameID, owner string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.clock.Now()
+	if l, ok := s.leases[gameID]; ok && l.owner != owner && now.Before(l.expiresAt) {
+		return false, nil
+	}
+	s.leases[gameID] = lease{owner: owner, expiresAt: now.Add(ttl)}
+	return true, nil
+}
+
+func (s *memoryLeaseStore) Release(ctx context.Context, gameID, owner string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if l, ok := s.leases[gameID]; ok && l.owner == owner {
+		delete(s.leases, gameID)
+	}
+	return nil
+}
+
+func (s *memoryLeaseStore) Holder(ctx context.Context, gameID string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if l, ok := s.leases[gameID]; ok && s.clock.Now().Before(l.expiresAt) {
+		return l.owner, nil
+	}
+	return "", nil
+}
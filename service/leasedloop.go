@@ -0,0 +1,247 @@
+package service
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// DefaultLeaseTTL is how long a game's loop lease lasts without renewal; the
+// owner renews it at a third of that.
+const DefaultLeaseTTL = 30 * time.Second
+
+// Loop control signals sent over the broker to a game's owner.
+const (
+	loopBegin = "begin"
+	loopEnd   = "end"
+	loopSkip  = "skip"
+	loopStop  = "stop"
+)
+
+// leasedGameLoop runs a game's timers only on the instance holding its lease.
+// Other instances forward BeginTurn, EndGameTurn, SkipTurn and StopGame to the
+// owner over the broker. BeginTurn therefore only blocks until armed when
+// this instance is the owner.
+type leasedGameLoop struct {
+	local  GameLoop
+	leases LeaseStore
+	broker Broker
+	clock  Clock
+	owner  string
+	ttl    time.Duration
+
+	mu      sync.Mutex
+	renewal map[string]context.CancelFunc // gameID -> stops lease renewal
+	cancel  context.CancelFunc
+}
+
+// LeasedLoopOption configures NewLeasedGameLoop.
+type LeasedLoopOption func(*leasedGameLoop)
+
+// WithLeaseOwner names this instance in the lease store. Keep it stable across
+// restarts so a restarted instance knows the leases its previous run left
+// behind are stale; instances running side by side need distinct names. The
+// default is random per process.
+func WithLeaseOwner(owner string) LeasedLoopOption {
+	return func(l *leasedGameLoop) {
+		if owner != "" {
+			l.owner = owner
+		}
+	}
+}
+
+// NewLeasedGameLoop wraps local so that, of all instances sharing leases and
+// broker, only the one that wins a game's lease drives it.
+func NewLeasedGameLoop(local GameLoop, leases LeaseStore, broker Broker, clock Clock, opts ...LeasedLoopOption) (GameLoop, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	l := &leasedGameLoop{
+		local:   local,
+		leases:  leases,
+		broker:  broker,
+		clock:   clock,
+		owner:   generateRandomID(),
+		ttl:     DefaultLeaseTTL,
+		renewal: map[string]context.CancelFunc{},
+		cancel:  cancel,
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	msgs, err := broker.Subscribe(ctx)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	go l.consume(msgs)
+	return l, nil
+}
+
+func (l *leasedGameLoop) consume(msgs <-chan BrokerMessage) {
+	for msg := range msgs {
+		if msg.Topic != LoopTopic || !l.local.Running(msg.GameID) {
+			continue
+		}
+		switch msg.Type {
+		case loopBegin:
+			l.local.BeginTurn(msg.GameID)
+		case loopEnd:
+			l.local.EndGameTurn(msg.GameID)
+		case loopSkip:
+			l.local.SkipTurn(msg.GameID)
+		case loopStop:
+			l.StopGame(msg.GameID)
+		}
+	}
+}
+
+func (l *leasedGameLoop) SetOnTurnEndHandler(handler OnTurnEndHandler) {
+	l.local.SetOnTurnEndHandler(handler)
+}
+
+func (l *leasedGameLoop) SetOnRevealHandler(handler OnRevealHandler) {
+	l.local.SetOnRevealHandler(handler)
+}
+
+func (l *leasedGameLoop) Start(ctx context.Context, gameID string, turnDuration, pickDuration time.Duration) {
+	l.own(ctx, gameID, func() {
+		l.local.Start(ctx, gameID, turnDuration, pickDuration)
+	})
+}
+
+func (l *leasedGameLoop) Resume(ctx context.Context, gameID string, turnDuration, pickDuration time.Duration, picked bool, remaining time.Duration) {
+	l.own(ctx, gameID, func() {
+		l.local.Resume(ctx, gameID, turnDuration, pickDuration, picked, remaining)
+	})
+}
+
+// own runs start once this instance holds gameID's lease and keeps the lease
+// renewed; without the lease, or when it already drives the game, it does
+// nothing.
+func (l *leasedGameLoop) own(ctx context.Context, gameID string, start func()) {
+	l.mu.Lock()
+	_, driving := l.renewal[gameID]
+	l.mu.Unlock()
+	if driving {
+		return
+	}
+
+	ok, err := l.leases.Acquire(ctx, gameID, l.owner, l.ttl)
+	if err != nil {
+		log.Printf("loop lease for %s: %v", gameID, err)
+		return
+	}
+	if !ok {
+		return // another instance drives it
+	}
+	start()
+
+	renewCtx, stop := context.WithCancel(context.Background())
+	l.mu.Lock()
+	if prev, ok := l.renewal[gameID]; ok {
+		prev() // a concurrent Start got here first; one renewal is enough
+	}
+	l.renewal[gameID] = stop
+	l.mu.Unlock()
+	go l.renew(renewCtx, gameID)
+}
+
+// renew keeps gameID's lease alive; losing it (say, after a long stall) means
+// another instance may already be driving the game, so the loop stops here.
+func (l *leasedGameLoop) renew(ctx context.Context, gameID string) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-l.clock.After(l.ttl / 3):
+		}
+		ok, err := l.leases.Acquire(ctx, gameID, l.owner, l.ttl)
+		if err != nil {
+			log.Printf("renew loop lease for %s: %v", gameID, err)
+			continue
+		}
+		if !ok {
+			log.Printf("lost loop lease for %s", gameID)
+			l.stopLocal(gameID, false)
+			return
+		}
+	}
+}
+
+// Running reports whether some instance drives gameID: this one, or another
+// holding an unexpired lease. A lease under this instance's own name that its
+// loop isn't running was left by a previous run, so it doesn't count.
+func (l *leasedGameLoop) Running(gameID string) bool {
+	if l.local.Running(gameID) {
+		return true
+	}
+	holder, err := l.leases.Holder(context.Background(), gameID)
+	if err != nil {
+		log.Printf("loop lease for %s: %v", gameID, err)
+	}
+	return holder != "" && holder != l.owner
+}
+
+// forward runs signal here when this instance owns gameID's loop and sends
+// it to the owner otherwise.
+func (l *leasedGameLoop) forward(gameID, signal string, here func(string)) {
+	if l.local.Running(gameID) {
+		here(gameID)
+		return
+	}
+	msg := BrokerMessage{Topic: LoopTopic, GameID: gameID, Type: signal}
+	if err := l.broker.Publish(context.Background(), msg); err != nil {
+		log.Printf("forward %s for %s: %v", signal, gameID, err)
+	}
+}
+
+func (l *leasedGameLoop) BeginTurn(gameID string) {
+	l.forward(gameID, loopBegin, l.local.BeginTurn)
+}
+
+func (l *leasedGameLoop) EndGameTurn(gameID string) {
+	l.forward(gameID, loopEnd, l.local.EndGameTurn)
+}
+
+func (l *leasedGameLoop) SkipTurn(gameID string) {
+	l.forward(gameID, loopSkip, l.local.SkipTurn)
+}
+
+func (l *leasedGameLoop) StopGame(gameID string) {
+	l.forward(gameID, loopStop, func(gameID string) {
+		l.stopLocal(gameID, true)
+	})
+}
+
+// stopLocal stops this instance's loop for gameID and its lease renewal,
+// giving the lease back when it still holds it.
+func (l *leasedGameLoop) stopLocal(gameID string, release bool) {
+	l.mu.Lock()
+	if stop, ok := l.renewal[gameID]; ok {
+		stop()
+		delete(l.renewal, gameID)
+	}
+	l.mu.Unlock()
+
+	l.local.StopGame(gameID)
+	if release {
+		if err := l.leases.Release(context.Background(), gameID, l.owner); err != nil {
+			log.Printf("release loop lease for %s: %v", gameID, err)
+		}
+	}
+}
+
+func (l *leasedGameLoop) Stop() {
+	l.cancel()
+	l.mu.Lock()
+	games := make([]string, 0, len(l.renewal))
+	for gameID := range l.renewal {
+		games = append(games, gameID)
+	}
+	l.mu.Unlock()
+
+	for _, gameID := range games {
+		l.stopLocal(gameID, true)
+	}
+	l.local.Stop()
+}
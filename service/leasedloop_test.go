@@ -0,0 +1,153 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"emojix/service"
+	"emojix/service/servicetest"
+)
+
+// newInstances builds two leased loops sharing a broker and a lease store,
+// standing in for two server processes. Each reports turn ends on its own
+// channel.
+func newInstances(t *testing.T) (a, b service.GameLoop, endsA, endsB chan string) {
+	t.Helper()
+	fc := servicetest.NewFakeClock()
+	broker := service.NewMemoryBroker()
+	leases := service.NewMemoryLeaseStore(fc)
+
+	instance := func() (service.GameLoop, chan string) {
+		ends := make(chan string, 4)
+		gl, err := service.NewLeasedGameLoop(service.NewGameLoop(fc), leases, broker, fc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		gl.SetOnTurnEndHandler(func(ctx context.Context, gameID string) {
+			ends <- gameID
+		})
+		t.Cleanup(gl.Stop)
+		return gl, ends
+	}
+	a, endsA = instance()
+	b, endsB = instance()
+	return a, b, endsA, endsB
+}
+
+func TestLeasedGameLoop_OneOwner(t *testing.T) {
+	a, b, endsA, endsB := newInstances(t)
+
+	a.Start(context.Background(), "g1", testTurn, testPick)
+	b.Start(context.Background(), "g1", testTurn, testPick) // lease taken: no-op
+
+	if !b.Running("g1") {
+		t.Error("expected the game to count as running on the other instance")
+	}
+
+	// The skip lands on B but only A's loop runs the turn end.
+	b.SkipTurn("g1")
+	select {
+	case id := <-endsA:
+		if id != "g1" {
+			t.Fatalf("expected g1, got %s", id)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("owner did not end the turn forwarded from the other instance")
+	}
+	select {
+	case <-endsB:
+		t.Fatal("expected the non-owner never to run the turn end")
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestLeasedGameLoop_StopReleasesLease(t *testing.T) {
+	a, b, _, endsB := newInstances(t)
+
+	a.Start(context.Background(), "g1", testTurn, testPick)
+	a.StopGame("g1")
+	if a.Running("g1") {
+		t.Fatal("expected the stopped game's lease to be released")
+	}
+
+	b.Start(context.Background(), "g1", testTurn, testPick)
+	b.SkipTurn("g1")
+	select {
+	case <-endsB:
+	case <-time.After(time.Second):
+		t.Fatal("expected the other instance to take the game over")
+	}
+}
+
+func TestLeasedGameLoop_StartTwice(t *testing.T) {
+	ctx := context.Background()
+	fc := servicetest.NewFakeClock()
+	leases := service.NewMemoryLeaseStore(fc)
+	gl, err := service.NewLeasedGameLoop(service.NewGameLoop(fc), leases, service.NewMemoryBroker(), fc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(gl.Stop)
+
+	gl.Start(ctx, "g1", testTurn, testPick)
+	gl.Start(ctx, "g1", testTurn, testPick) // already driving it: no-op
+	gl.StopGame("g1")
+
+	// A renewal left over from the second Start would take the lease back.
+	for range 6 {
+		fc.Advance(service.DefaultLeaseTTL / 3)
+		time.Sleep(time.Millisecond)
+	}
+	if holder, _ := leases.Holder(ctx, "g1"); holder != "" {
+		t.Errorf("expected the stopped game's lease to stay free, held by %q", holder)
+	}
+}
+
+func TestLeasedGameLoop_StaleLeases(t *testing.T) {
+	ctx := context.Background()
+	fc := servicetest.NewFakeClock()
+	leases := service.NewMemoryLeaseStore(fc)
+	newLoop := func(owner string) service.GameLoop {
+		gl, err := service.NewLeasedGameLoop(service.NewGameLoop(fc), leases, service.NewMemoryBroker(), fc,
+			service.WithLeaseOwner(owner))
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(gl.Stop)
+		return gl
+	}
+
+	t.Run("a crashed owner's game frees up once its lease lapses", func(t *testing.T) {
+		if ok, _ := leases.Acquire(ctx, "g1", "crashed", service.DefaultLeaseTTL); !ok {
+			t.Fatal("expected the lease to be free")
+		}
+		gl := newLoop("survivor")
+		if !gl.Running("g1") {
+			t.Fatal("expected a live lease elsewhere to count as running")
+		}
+
+		fc.Advance(service.DefaultLeaseTTL)
+		if gl.Running("g1") {
+			t.Fatal("expected an expired lease not to count")
+		}
+		gl.Start(ctx, "g1", testTurn, testPick)
+		if holder, _ := leases.Holder(ctx, "g1"); holder != "survivor" {
+			t.Errorf("expected the survivor to take the lease, got %q", holder)
+		}
+	})
+
+	t.Run("a restarted instance ignores the lease its last run left", func(t *testing.T) {
+		if ok, _ := leases.Acquire(ctx, "g2", "instance-1", service.DefaultLeaseTTL); !ok {
+			t.Fatal("expected the lease to be free")
+		}
+		gl := newLoop("instance-1")
+		if gl.Running("g2") {
+			t.Fatal("expected the previous run's lease not to count")
+		}
+		gl.Start(ctx, "g2", testTurn, testPick)
+		if !gl.Running("g2") {
+			t.Error("expected the restarted instance to drive the game again")
+		}
+	})
+}
@@ -0,0 +1,255 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	// HeartbeatInterval is how often a live stream should prove itself with
+	// a beat; DefaultStaleAfter drops a connection after three missed ones.
+	HeartbeatInterval  = 15 * time.Second
+	DefaultStaleAfter  = 3 * HeartbeatInterval
+	DefaultGracePeriod = 30 * time.Second
+	DefaultSweepEvery  = 5 * time.Second
+)
+
+// PresenceState is what a user's connections say about them.
+type PresenceState int
+
+const (
+	Offline PresenceState = iota
+	Online
+	Away
+)
+
+func (s PresenceState) String() string {
+	switch s {
+	case Online:
+		return "online"
+	case Away:
+		return "away"
+	default:
+		return "offline"
+	}
+}
+
+// OnLeaveHandler is called when a user has had no connection to a game for
+// the grace period; goneAt is when their last one ended. It runs
+// synchronously in the Presence scheduler.
+type OnLeaveHandler func(ctx context.Context, gameID, userID string, goneAt time.Time)
+
+type Presence interface {
+	// Connect registers one live connection (a browser tab, say) for userID
+	// in gameID. beat records that it is still alive; release ends it. A
+	// user stays online while any of their connections is.
+	Connect(gameID, userID string) (beat func(), release func())
+
+	// SetAway marks a connected user away or back and reports whether that
+	// changed their state.
+	SetAway(gameID, userID string, away bool) bool
+
+	State(gameID, userID string) PresenceState
+
+	// Away lists gameID's connected users that are away.
+	Away(gameID string) []string
+
+	// SetOnLeaveHandler sets the handler called when a user leaves.
+	// Must be called before Run.
+	SetOnLeaveHandler(handler OnLeaveHandler)
+
+	// Run sweeps on the clock until ctx is done: connections without a beat
+	// for the stale period are dropped, and users left without one for the
+	// grace period go to the OnLeave handler.
+	Run(ctx context.Context)
+}
+
+type presenceKey struct {
+	gameID string
+	userID string
+}
+
+type presenceEntry struct {
+	conns  map[uint64]time.Time // connection -> last beat
+	away   bool
+	goneAt time.Time // when the last connection went; zero while connected
+}
+
+type presence struct {
+	mu       sync.Mutex
+	entries  map[presenceKey]*presenceEntry
+	nextConn uint64
+	clock    Clock
+	onLeave  OnLeaveHandler
+
+	grace      time.Duration
+	staleAfter time.Duration
+	sweepEvery time.Duration
+}
+
+// PresenceOption configures a Presence.
+type PresenceOption func(*presence)
+
+// WithGracePeriod sets how long a user may be without a connection before
+// they are handed to the OnLeave handler. It covers reloads and reconnects.
+func WithGracePeriod(d time.Duration) PresenceOption {
+	return func(p *presence) {
+		p.grace = d
+	}
+}
+
+// WithStaleAfter sets how long a connection may go without a beat.
+func WithStaleAfter(d time.Duration) PresenceOption {
+	return func(p *presence) {
+		p.staleAfter = d
+	}
+}
+
+// WithSweepInterval sets how often Run checks for stale connections and
+// departed users.
+func WithSweepInterval(d time.Duration) PresenceOption {
+	return func(p *presence) {
+		p.sweepEvery = d
+	}
+}
+
+func NewPresence(clock Clock, opts ...PresenceOption) Presence {
+	p := &presence{
+		entries:    map[presenceKey]*presenceEntry{},
+		clock:      clock,
+		grace:      DefaultGracePeriod,
+		staleAfter: DefaultStaleAfter,
+		sweepEvery: DefaultSweepEvery,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+func (p *presence) SetOnLeaveHandler(handler OnLeaveHandler) {
+	p.onLeave = handler
+}
+
+func (p *presence) Connect(gameID, userID string) (func(), func()) {
+	key := presenceKey{gameID, userID}
+
+	p.mu.Lock()
+	p.nextConn++
+	connID := p.nextConn
+	entry, ok := p.entries[key]
+	if !ok {
+		entry = &presenceEntry{conns: map[uint64]time.Time{}}
+		p.entries[key] = entry
+	}
+	entry.conns[connID] = p.clock.Now()
+	entry.goneAt = time.Time{}
+	p.mu.Unlock()
+
+	beat := func() {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		if _, live := entry.conns[connID]; live {
+			entry.conns[connID] = p.clock.Now()
+		}
+	}
+	release := func() {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		p.dropConn(entry, connID)
+	}
+	return beat, release
+}
+
+// dropConn ends one connection and starts the grace period if it was the
+// user's last. Callers hold mu.
+func (p *presence) dropConn(entry *presenceEntry, connID uint64) {
+	if _, live := entry.conns[connID]; !live {
+		return
+	}
+	delete(entry.conns, connID)
+	if len(entry.conns) == 0 {
+		entry.goneAt = p.clock.Now()
+		entry.away = false
+	}
+}
+
+func (p *presence) SetAway(gameID, userID string, away bool) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	entry, ok := p.entries[presenceKey{gameID, userID}]
+	if !ok || len(entry.conns) == 0 || entry.away == away {
+		return false
+	}
+	entry.away = away
+	return true
+}
+
+func (p *presence) State(gameID, userID string) PresenceState {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	entry, ok := p.entries[presenceKey{gameID, userID}]
+	switch {
+	case !ok || len(entry.conns) == 0:
+		return Offline
+	case entry.away:
+		return Away
+	default:
+		return Online
+	}
+}
+
+func (p *presence) Away(gameID string) []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	away := []string{}
+	for key, entry := range p.entries {
+		if key.gameID == gameID && entry.away && len(entry.conns) > 0 {
+			away = append(away, key.userID)
+		}
+	}
+	slices.Sort(away)
+	return away
+}
+
+func (p *presence) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-p.clock.After(p.sweepEvery):
+			p.sweep(ctx)
+		}
+	}
+}
+
+func (p *presence) sweep(ctx context.Context) {
+	now := p.clock.Now()
+	left := []presenceKey{}
+	goneAt := []time.Time{}
+
+	p.mu.Lock()
+	for key, entry := range p.entries {
+		for connID, lastBeat := range entry.conns {
+			if now.Sub(lastBeat) >= p.staleAfter {
+				p.dropConn(entry, connID)
+			}
+		}
+		if len(entry.conns) == 0 && now.Sub(entry.goneAt) >= p.grace {
+			delete(p.entries, key)
+			left = append(left, key)
+			goneAt = append(goneAt, entry.goneAt)
+		}
+	}
+	p.mu.Unlock()
+
+	if p.onLeave == nil {
+		return
+	}
+	// Outside the lock: the handler may well ask us who is still here.
+	for i, key := range left {
+		p.onLeave(ctx, key.gameID, key.userID, goneAt[i])
+	}
+}
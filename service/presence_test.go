@@ -0,0 +1,149 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"emojix/service"
+	"emojix/service/servicetest"
+)
+
+// sweepAfter advances the clock once Run is waiting on it, then waits for the
+// sweep to finish (Run is back waiting on its next tick).
+func sweepAfter(t *testing.T, fc *servicetest.FakeClock, d time.Duration) {
+	t.Helper()
+	waitPending := func() {
+		deadline := time.Now().Add(time.Second)
+		for fc.PendingTimers() == 0 {
+			if time.Now().After(deadline) {
+				t.Fatal("presence scheduler is not waiting on the clock")
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+	waitPending()
+	fc.Advance(d)
+	waitPending()
+}
+
+func startPresence(t *testing.T, fc *servicetest.FakeClock) (service.Presence, chan string) {
+	t.Helper()
+	left := make(chan string, 4)
+	p := service.NewPresence(fc,
+		service.WithGracePeriod(30*time.Second),
+		service.WithStaleAfter(45*time.Second),
+		service.WithSweepInterval(10*time.Second),
+	)
+	p.SetOnLeaveHandler(func(ctx context.Context, gameID, userID string, goneAt time.Time) {
+		left <- gameID + "/" + userID
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go p.Run(ctx)
+	return p, left
+}
+
+func assertNoLeave(t *testing.T, left chan string) {
+	t.Helper()
+	select {
+	case who := <-left:
+		t.Fatalf("expected nobody to leave but %s did", who)
+	default:
+	}
+}
+
+func TestPresence_LeavesAfterGracePeriod(t *testing.T) {
+	fc := servicetest.NewFakeClock()
+	p, left := startPresence(t, fc)
+
+	_, release := p.Connect("g1", "u1")
+	release()
+	if state := p.State("g1", "u1"); state != service.Offline {
+		t.Errorf("expected offline but got %s", state)
+	}
+
+	sweepAfter(t, fc, 20*time.Second)
+	assertNoLeave(t, left)
+
+	sweepAfter(t, fc, 10*time.Second)
+	if who := <-left; who != "g1/u1" {
+		t.Errorf("expected g1/u1 to leave but got %s", who)
+	}
+}
+
+func TestPresence_ReconnectWithinGrace(t *testing.T) {
+	fc := servicetest.NewFakeClock()
+	p, left := startPresence(t, fc)
+
+	_, release := p.Connect("g1", "u1")
+	release()
+	sweepAfter(t, fc, 20*time.Second)
+	_, _ = p.Connect("g1", "u1") // page reload
+	sweepAfter(t, fc, 60*time.Second)
+
+	// The new connection went stale at 45s, so the grace period is running
+	// again but hasn't expired.
+	assertNoLeave(t, left)
+}
+
+func TestPresence_MultipleTabs(t *testing.T) {
+	fc := servicetest.NewFakeClock()
+	p, left := startPresence(t, fc)
+
+	beat, _ := p.Connect("g1", "u1")
+	_, closeTab := p.Connect("g1", "u1")
+	closeTab()
+
+	for range 6 {
+		beat()
+		sweepAfter(t, fc, 10*time.Second)
+	}
+	assertNoLeave(t, left)
+	if state := p.State("g1", "u1"); state != service.Online {
+		t.Errorf("expected online but got %s", state)
+	}
+}
+
+func TestPresence_StaleConnectionIsDropped(t *testing.T) {
+	fc := servicetest.NewFakeClock()
+	p, left := startPresence(t, fc)
+
+	_, _ = p.Connect("g1", "u1") // never beats, never releases
+
+	sweepAfter(t, fc, 50*time.Second)
+	if state := p.State("g1", "u1"); state != service.Offline {
+		t.Errorf("expected offline once stale but got %s", state)
+	}
+	sweepAfter(t, fc, 30*time.Second)
+	if who := <-left; who != "g1/u1" {
+		t.Errorf("expected g1/u1 to leave but got %s", who)
+	}
+}
+
+func TestPresence_AwayAndBack(t *testing.T) {
+	p := service.NewPresence(servicetest.NewFakeClock())
+
+	if p.SetAway("g1", "u1", true) {
+		t.Error("expected a user without a connection not to go away")
+	}
+
+	_, release := p.Connect("g1", "u1")
+	if !p.SetAway("g1", "u1", true) || p.SetAway("g1", "u1", true) {
+		t.Error("expected only the first SetAway to change state")
+	}
+	if state := p.State("g1", "u1"); state != service.Away {
+		t.Errorf("expected away but got %s", state)
+	}
+	if away := p.Away("g1"); len(away) != 1 || away[0] != "u1" {
+		t.Errorf("expected [u1] away but got %v", away)
+	}
+
+	if !p.SetAway("g1", "u1", false) {
+		t.Error("expected coming back to change state")
+	}
+	release()
+	if away := p.Away("g1"); len(away) != 0 {
+		t.Errorf("expected nobody away but got %v", away)
+	}
+}
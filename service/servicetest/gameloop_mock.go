@@ -13,14 +13,19 @@ type MockGameLoop struct {
 
 	StartMock                 func(ctx context.Context, gameID string, turnDuration, pickDuration time.Duration)
 	StartCalled               bool
+	ResumeMock                func(ctx context.Context, gameID string, turnDuration, pickDuration time.Duration, picked bool, remaining time.Duration)
+	ResumeCalled              bool
 	RunningMock               func(gameID string) bool
 	BeginTurnMock             func(gameID string)
 	BeginTurnCalled           bool
 	EndGameTurnMock           func(gameID string)
 	EndGameTurnCalled         bool
+	SkipTurnMock              func(gameID string)
+	SkipTurnCalled            bool
 	SetOnTurnEndHandlerMock   func(handler service.OnTurnEndHandler)
 	SetOnTurnEndHandlerCalled bool
 	OnTurnEndHandler          service.OnTurnEndHandler
+	OnRevealHandler           service.OnRevealHandler
 	StopGameMock              func(gameID string)
 	StopGameCalled            bool
 	StopMock                  func()
@@ -34,6 +39,13 @@ func (m *MockGameLoop) Start(ctx context.Context, gameID string, turnDuration, p
 	}
 }
 
+func (m *MockGameLoop) Resume(ctx context.Context, gameID string, turnDuration, pickDuration time.Duration, picked bool, remaining time.Duration) {
+	m.ResumeCalled = true
+	if m.ResumeMock != nil {
+		m.ResumeMock(ctx, gameID, turnDuration, pickDuration, picked, remaining)
+	}
+}
+
 func (m *MockGameLoop) Running(gameID string) bool {
 	if m.RunningMock != nil {
 		return m.RunningMock(gameID)
@@ -55,6 +67,13 @@ func (m *MockGameLoop) EndGameTurn(gameID string) {
 	}
 }
 
+func (m *MockGameLoop) SkipTurn(gameID string) {
+	m.SkipTurnCalled = true
+	if m.SkipTurnMock != nil {
+		m.SkipTurnMock(gameID)
+	}
+}
+
 func (m *MockGameLoop) SetOnTurnEndHandler(handler service.OnTurnEndHandler) {
 	m.SetOnTurnEndHandlerCalled = true
 	m.OnTurnEndHandler = handler
@@ -71,6 +90,19 @@ func (m *MockGameLoop) FireOnTurnEnd(ctx context.Context, gameID string) {
 	}
 }
 
+func (m *MockGameLoop) SetOnRevealHandler(handler service.OnRevealHandler) {
+	m.OnRevealHandler = handler
+}
+
+// FireOnReveal invokes the handler captured by SetOnRevealHandler and
+// returns its next delay, or 0 without one.
+func (m *MockGameLoop) FireOnReveal(ctx context.Context, gameID string) time.Duration {
+	if m.OnRevealHandler == nil {
+		return 0
+	}
+	return m.OnRevealHandler(ctx, gameID)
+}
+
 func (m *MockGameLoop) StopGame(gameID string) {
 	m.StopGameCalled = true
 	if m.StopGameMock != nil {
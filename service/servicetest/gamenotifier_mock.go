@@ -7,15 +7,16 @@ import (
 
 // MockGameNotifier is a test double for service.GameNotifier.
 //
-// Concurrency: PubCalled and PubAllCalled are guarded by mu so production
+// Concurrency: PubCalled, PubAllCalled and PubToCalled are guarded by mu so production
 // code that spawns `go notifier.Pub(...)` does not race with assertions.
 // Tests should additionally wait for the spawned goroutine to finish via
 // channels (T02 pattern) before asserting on the *Called flags.
 //
 // Default behavior when a Mock field is nil:
-//   - PubMock / PubAllMock: no-op (publishes legitimately "do nothing" in
+//   - PubMock / PubAllMock / PubToMock: no-op (publishes legitimately "do nothing" in
 //     negative test cases), the *Called flag is still set.
-//   - SubMock / SubsMock: panic — these must return values, so an unset
+//   - CloseMock: no-op, the CloseCalled flag is still set.
+//   - SubMock / ResumeMock / SubsMock: panic — these must return values, so an unset
 //     mock panicking is the correct "you forgot to wire it" signal.
 //
 // The embedded service.GameNotifier interface is deliberately left nil so that adding
@@ -31,9 +32,15 @@ type MockGameNotifier struct {
 	PubCalled    bool
 	PubAllMock   func(gameID string, notif service.GameNotification)
 	PubAllCalled bool
+	PubToMock    func(gameID string, userID string, notif service.GameNotification)
+	PubToCalled  bool
 
-	SubMock  func(gameID string, userID string) (chan service.GameNotification, func())
-	SubsMock func(gameID string) []string
+	SubMock    func(gameID string, userID string) (chan service.GameNotification, func())
+	ResumeMock func(gameID string, userID string, lastEventID uint64) ([]service.Event, chan service.GameNotification, func())
+	SubsMock   func(gameID string) []string
+
+	CloseMock   func(final service.GameNotification)
+	CloseCalled bool
 }
 
 func (m *MockGameNotifier) Pub(gameID string, userID string, notif service.GameNotification) {
@@ -54,10 +61,32 @@ func (m *MockGameNotifier) PubAll(gameID string, notif service.GameNotification)
 	}
 }
 
+func (m *MockGameNotifier) PubTo(gameID string, userID string, notif service.GameNotification) {
+	m.mu.Lock()
+	m.PubToCalled = true
+	m.mu.Unlock()
+	if m.PubToMock != nil {
+		m.PubToMock(gameID, userID, notif)
+	}
+}
+
 func (m *MockGameNotifier) Sub(gameID string, userID string) (chan service.GameNotification, func()) {
 	return m.SubMock(gameID, userID)
 }
 
+func (m *MockGameNotifier) Resume(gameID string, userID string, lastEventID uint64) ([]service.Event, chan service.GameNotification, func()) {
+	return m.ResumeMock(gameID, userID, lastEventID)
+}
+
 func (m *MockGameNotifier) Subs(gameID string) []string {
 	return m.SubsMock(gameID)
 }
+
+func (m *MockGameNotifier) Close(final service.GameNotification) {
+	m.mu.Lock()
+	m.CloseCalled = true
+	m.mu.Unlock()
+	if m.CloseMock != nil {
+		m.CloseMock(final)
+	}
+}
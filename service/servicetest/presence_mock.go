@@ -0,0 +1,57 @@
+package servicetest
+
+import (
+	"context"
+	"emojix/service"
+)
+
+// MockPresence is a mock implementation of service.Presence for testing.
+// Connect hands out no-op beat/release funcs and State reports Offline unless
+// mocked; OnLeaveHandler keeps the registered handler so tests can fire it.
+type MockPresence struct {
+	service.Presence
+
+	ConnectMock    func(gameID, userID string) (func(), func())
+	ConnectCalled  bool
+	SetAwayMock    func(gameID, userID string, away bool) bool
+	StateMock      func(gameID, userID string) service.PresenceState
+	AwayMock       func(gameID string) []string
+	OnLeaveHandler service.OnLeaveHandler
+}
+
+func (m *MockPresence) Connect(gameID, userID string) (func(), func()) {
+	m.ConnectCalled = true
+	if m.ConnectMock != nil {
+		return m.ConnectMock(gameID, userID)
+	}
+	return func() {}, func() {}
+}
+
+func (m *MockPresence) SetAway(gameID, userID string, away bool) bool {
+	if m.SetAwayMock != nil {
+		return m.SetAwayMock(gameID, userID, away)
+	}
+	return false
+}
+
+func (m *MockPresence) State(gameID, userID string) service.PresenceState {
+	if m.StateMock != nil {
+		return m.StateMock(gameID, userID)
+	}
+	return service.Offline
+}
+
+func (m *MockPresence) Away(gameID string) []string {
+	if m.AwayMock != nil {
+		return m.AwayMock(gameID)
+	}
+	return []string{}
+}
+
+func (m *MockPresence) SetOnLeaveHandler(handler service.OnLeaveHandler) {
+	m.OnLeaveHandler = handler
+}
+
+func (m *MockPresence) Run(ctx context.Context) {
+	<-ctx.Done()
+}
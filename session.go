@@ -0,0 +1,142 @@
+package emojix
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"emojix/model"
+)
+
+const sessionCookieKey = "session"
+
+// Cookies from before sessions were signed; InitSession expires them.
+const (
+	legacyUserIDCookieKey   = "userid"
+	legacyNicknameCookieKey = "nickname"
+)
+
+var errInvalidSession = errors.New("invalid session token")
+
+type Session struct {
+	UserID    string
+	Nickname  string
+	Avatar    string
+	CSRFToken string // for the pages rendered in this request
+}
+
+// sessionSigner issues "<user id>.<mac>" tokens. The first key signs; every
+// key verifies, so a key being rotated out keeps working until its tokens
+// have been reissued.
+type sessionSigner struct {
+	keys [][]byte
+}
+
+// newSessionSigner signs with keys, or with a random key when there are
+// none, in which case sessions end with the process.
+func newSessionSigner(keys []string) (*sessionSigner, error) {
+	s := &sessionSigner{}
+	for _, k := range keys {
+		s.keys = append(s.keys, []byte(k))
+	}
+	if len(s.keys) == 0 {
+		key := make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, err
+		}
+		s.keys = [][]byte{key}
+	}
+	return s, nil
+}
+
+// mac binds userID to purpose, so a MAC minted for one use (a session
+// cookie, a CSRF token) is never accepted as another.
+func (s *sessionSigner) mac(key []byte, purpose, userID string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte("emojix " + purpose + "\x00" + userID))
+	return h.Sum(nil)
+}
+
+func (s *sessionSigner) sign(userID string) string {
+	return userID + "." + base64.RawURLEncoding.EncodeToString(s.mac(s.keys[0], "session", userID))
+}
+
+// verify returns the user id token was issued for. stale reports a token
+// signed by an older key, which the caller should reissue.
+func (s *sessionSigner) verify(token string) (userID string, stale bool, err error) {
+	i := strings.LastIndexByte(token, '.')
+	if i <= 0 {
+		return "", false, errInvalidSession
+	}
+	userID = token[:i]
+	sig, err := base64.RawURLEncoding.DecodeString(token[i+1:])
+	if err != nil {
+		return "", false, errInvalidSession
+	}
+	for n, key := range s.keys {
+		if hmac.Equal(sig, s.mac(key, "session", userID)) {
+			return userID, n > 0, nil
+		}
+	}
+	return "", false, errInvalidSession
+}
+
+// sessionUser loads the user behind the session cookie, reissuing the
+// cookie when it was signed by a rotated-out key.
+func (e *webServer) sessionUser(w http.ResponseWriter, r *http.Request) (model.User, error) {
+	c, err := r.Cookie(sessionCookieKey)
+	if err != nil {
+		return model.User{}, err
+	}
+	userID, stale, err := e.sessions.verify(c.Value)
+	if err != nil {
+		return model.User{}, err
+	}
+	// Cookies can outlive the DB (common when resetting local state). If the
+	// user row is gone, force a fresh /init instead of failing later with an FK error.
+	user, err := e.emojixUsecase.GetUser(r.Context(), userID)
+	if err != nil {
+		return model.User{}, err
+	}
+	if stale {
+		w.Header().Add("Set-Cookie", e.setCookie(sessionCookieKey, e.sessions.sign(user.ID)))
+	}
+	return user, nil
+}
+
+func (e *webServer) getSession(w http.ResponseWriter, r *http.Request) (Session, error) {
+	user, err := e.sessionUser(w, r)
+	if err != nil {
+		// POST/non-GET targets are not safe to replay after /init (which always
+		// finishes with a GET redirect), so fall back to home in that case.
+		from := r.URL.Path
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			from = "/"
+		}
+		toUrl := fmt.Sprintf("/init?from=%s", from)
+		http.Redirect(w, r, toUrl, http.StatusFound)
+		return Session{}, err
+	}
+
+	// The nickname always comes from the user row, never from the client.
+	return Session{UserID: user.ID, Nickname: user.Nickname, Avatar: user.Avatar, CSRFToken: e.sessions.csrfToken(user.ID)}, nil
+}
+
+func (e *webServer) setCookie(key string, value string) string {
+	cookieOptions := []string{"Path=/", "HttpOnly", "SameSite=Lax"}
+
+	if e.cfg.SecureCookies {
+		cookieOptions = append(cookieOptions, "Secure")
+	}
+
+	return fmt.Sprintf("%s=%s; %s", key, value, strings.Join(cookieOptions, "; "))
+}
+
+func expireCookie(key string) string {
+	return fmt.Sprintf("%s=; Path=/; Max-Age=0", key)
+}
@@ -0,0 +1,63 @@
+package emojix
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSessionSigner_Rotation(t *testing.T) {
+	oldKey := "old-session-key-0123456789abcdefgh"
+	newKey := "new-session-key-0123456789abcdefgh"
+	before, _ := newSessionSigner([]string{oldKey})
+	after, _ := newSessionSigner([]string{newKey, oldKey})
+	retired, _ := newSessionSigner([]string{newKey})
+
+	token := before.sign("u1")
+
+	userID, stale, err := after.verify(token)
+	if err != nil || userID != "u1" || !stale {
+		t.Errorf("verify with old key still listed = %q, %v, %v; want u1, stale", userID, stale, err)
+	}
+	userID, stale, err = after.verify(after.sign("u1"))
+	if err != nil || userID != "u1" || stale {
+		t.Errorf("verify fresh token = %q, %v, %v; want u1, not stale", userID, stale, err)
+	}
+	if _, _, err := retired.verify(token); err == nil {
+		t.Error("token signed by a dropped key still verifies")
+	}
+}
+
+func TestSessionSigner_RandomKeyWhenNoneConfigured(t *testing.T) {
+	a, _ := newSessionSigner(nil)
+	b, _ := newSessionSigner(nil)
+
+	if _, _, err := a.verify(a.sign("u1")); err != nil {
+		t.Fatalf("verify own token: %v", err)
+	}
+	if _, _, err := b.verify(a.sign("u1")); err == nil {
+		t.Error("two processes without keys accept each other's tokens")
+	}
+}
+
+func TestGetSession_StaleKeyReissuesCookie(t *testing.T) {
+	oldKey := "old-session-key-0123456789abcdefgh"
+	before, _ := newSessionSigner([]string{oldKey})
+	srv := newServer(newMockUsecase(), &MockView{})
+	srv.sessions, _ = newSessionSigner([]string{"new-session-key-0123456789abcdefgh", oldKey})
+
+	r := newReq("GET", "/", nil)
+	r.AddCookie(&http.Cookie{Name: sessionCookieKey, Value: before.sign("u1")})
+	w := httptest.NewRecorder()
+
+	session, err := srv.getSession(w, r)
+
+	if err != nil || session.UserID != "u1" {
+		t.Fatalf("getSession = %+v, %v; want u1", session, err)
+	}
+	cookies := w.Header()["Set-Cookie"]
+	if len(cookies) != 1 || !strings.HasPrefix(cookies[0], "session="+srv.sessions.sign("u1")+";") {
+		t.Errorf("Set-Cookie = %v, want the session re-signed with the new key", cookies)
+	}
+}
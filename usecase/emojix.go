@@ -11,38 +11,113 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"maps"
 	mathRand "math/rand"
-	"regexp"
 	"slices"
 	"strings"
+	"sync"
 	"time"
 	"unicode"
 )
 
-type GameUpdateHandler = func(notifType string, data string) error
+// GameUpdateHandler receives one game event; eventID is 0 for notifications
+// that carry no sequence number.
+type GameUpdateHandler = func(eventID uint64, notifType string, data string) error
+
+// HeartbeatEvent is the notifType GameUpdates uses for keep-alives. It is
+// not a game event; transports write it as a comment.
+const HeartbeatEvent = "heartbeat"
 
 // ErrUserNotFound is returned when a user id is not present in the store
 // (e.g. stale cookie after a DB reset).
-var ErrUserNotFound = errors.New("user not found")
+var ErrUserNotFound = NewError(KindNotFound, "user not found")
 
-// ErrUserNotInGame is returned when the caller is not an active player in the game.
-var ErrUserNotInGame = errors.New("user not in the game")
+// ErrUserNotInGame is returned when the caller is neither an active player
+// nor a spectator in the game.
+var ErrUserNotInGame = NewError(KindForbidden, "user not in the game")
 
 type EmojixUsecase interface {
 	InitUser(ctx context.Context) (model.User, error)
 	GetUser(ctx context.Context, userID string) (model.User, error)
+	// UpdateProfile sets the nickname and avatar (a single emoji, or empty)
+	// and broadcasts a NickChangedNotification to the user's open games.
+	UpdateProfile(ctx context.Context, userID, nickname, avatar string) (model.User, error)
 	ListWordLists(ctx context.Context) ([]model.WordList, error)
-	InitGame(ctx context.Context, userID string, listID string) (model.Game, error)
-	JoinGame(ctx context.Context, gameID string, userID string) error
+	// GetWordList, CreateWordList, AddWord, UpdateWord, DeleteWord and
+	// ImportWords author lists; hints must be emoji-only and words unique
+	// within their list. Only a list's creator (userID) may change it, and
+	// never a word a game has dealt. ImportWords is the operator's tool and
+	// skips the owner check.
+	GetWordList(ctx context.Context, listID string) (model.WordList, []model.Word, error)
+	CreateWordList(ctx context.Context, userID, title string) (model.WordList, error)
+	AddWord(ctx context.Context, userID, listID, word, hint string) (model.Word, error)
+	UpdateWord(ctx context.Context, userID, wordID, word, hint string) error
+	DeleteWord(ctx context.Context, userID, wordID string) error
+	ImportWords(ctx context.Context, params ImportParams) (ImportReport, error)
+	// InitGame creates a game with the given rules; zero settings fields take
+	// DefaultGameSettings.
+	InitGame(ctx context.Context, userID string, listID string, settings model.GameSettings) (model.Game, error)
+	// JoinGame seats userID; new players need JoinGameByCode for a private room.
+	JoinGame(ctx context.Context, gameID string, userID string, spectate bool) error
+	JoinGameByCode(ctx context.Context, code string, userID string) (model.Game, error)
+	// KickPlayer, LockRoom, TransferHost and SkipTurn are host-only
+	// (ErrNotHost) and broadcast their effect to the room.
+	KickPlayer(ctx context.Context, gameID, hostID, playerID string) error
+	LockRoom(ctx context.Context, gameID, hostID string, locked bool) error
+	TransferHost(ctx context.Context, gameID, hostID, newHostID string) error
+	SkipTurn(ctx context.Context, gameID, hostID string) error
 	PickWord(ctx context.Context, gameID string, userID string, wordID string) error
+	// RerollWords swaps the teller's options once per turn before the pick;
+	// GiveUpTurn lets the teller end the turn early. VoteSkip ends it once
+	// a majority of active guessers have voted.
+	RerollWords(ctx context.Context, gameID, userID string) error
+	GiveUpTurn(ctx context.Context, gameID, userID string) error
+	VoteSkip(ctx context.Context, gameID, userID string) error
 	// Guess records a guess. correct is true when the guess matches the word.
 	Guess(ctx context.Context, gameID string, userID string, word string) (correct bool, err error)
 	Message(ctx context.Context, gameID string, userID string, word string) error
+	// AppendHint, ReplaceHint and UndoHint edit the current turn's emoji board;
+	// only the teller may call them. Each returns the board after the edit.
+	AppendHint(ctx context.Context, gameID, userID, emoji string) (string, error)
+	ReplaceHint(ctx context.Context, gameID, userID, hint string) (string, error)
+	UndoHint(ctx context.Context, gameID, userID string) (string, error)
 	GameState(ctx context.Context, gameID string, userID string) (model.GameState, error)
-	GameUpdates(ctx context.Context, gameID string, userID string, handler GameUpdateHandler) error
-	KickInactiveUser(ctx context.Context, gameID, userID string) error
+	// GameUpdates streams gameID's events to handler until ctx is done. A
+	// non-zero lastEventID first replays what the user missed since then.
+	// While it runs the user counts as connected; handler receives a
+	// HeartbeatEvent whenever the stream has been quiet for a while.
+	GameUpdates(ctx context.Context, gameID string, userID string, lastEventID uint64, handler GameUpdateHandler) error
+	// CheckMember reports whether userID has a seat in gameID: joined once
+	// and not kicked. Inactive players count, since their stream reconnects.
+	CheckMember(ctx context.Context, gameID, userID string) error
+	// SetAway marks a connected player away (tab hidden) or back.
+	SetAway(ctx context.Context, gameID, userID string, away bool) error
 	Leaderboard(ctx context.Context, gameID, userID string) ([]model.LeaderboardEntry, error)
+	// PickTeam moves userID to the red or blue side of a team game while it
+	// is still in the lobby; TeamLeaderboard sums scores per side.
+	PickTeam(ctx context.Context, gameID, userID string, team model.Team) error
+	TeamLeaderboard(ctx context.Context, gameID, userID string) ([]model.TeamScore, error)
+	// Spectate moves a player to the stands, where they watch without
+	// guessing or telling; Play queues a spectator for the next free seat.
+	Spectate(ctx context.Context, gameID, userID string) error
+	Play(ctx context.Context, gameID, userID string) error
 	GameWord(ctx context.Context, gameID, userID string) (string, error)
+	// Rematch returns a new game with the same list and settings as the
+	// finished gameID; every caller gets the same rematch.
+	Rematch(ctx context.Context, gameID string, userID string) (model.Game, error)
+	// GameHistory reads a finished game back turn by turn, with every event
+	// in time order for a replay.
+	GameHistory(ctx context.Context, gameID, userID string) (model.GameHistory, error)
+	// RecoverGames resumes the timers of games left running by a previous
+	// process; call it once at startup.
+	RecoverGames(ctx context.Context) error
+	// WatchGames repeats RecoverGames every interval until ctx ends, taking
+	// over games whose owning instance went away.
+	WatchGames(ctx context.Context, every time.Duration)
+	// Shutdown refuses new joins, stops every game loop, waits (until ctx is
+	// done) for turn ends under way and ends all SSE streams with a
+	// ServerRestartNotification.
+	Shutdown(ctx context.Context) error
 }
 
 func NewEmojixUsecase(
@@ -53,20 +128,41 @@ func NewEmojixUsecase(
 	gameNotifier service.GameNotifier,
 	gameLoop service.GameLoop,
 	clock service.Clock,
+	opts ...Option,
 ) EmojixUsecase {
 	uc := &emojixUsecase{
-		userRepo,
-		gameRepo,
-		wordRepo,
-		unitOfWorkFactory,
-		gameNotifier,
-		gameLoop,
-		clock,
+		userRepo:          userRepo,
+		gameRepo:          gameRepo,
+		wordRepo:          wordRepo,
+		unitOfWorkFactory: unitOfWorkFactory,
+		gameNotifier:      gameNotifier,
+		gameLoop:          gameLoop,
+		clock:             clock,
+		guessMatcher:      DefaultGuessMatcher,
+		scoringPolicies:   maps.Clone(defaultScoringPolicies),
+		gameDefaults:      DefaultGameSettings(),
+		rateLimits:        DefaultRateLimits(),
+	}
+	for _, opt := range opts {
+		opt(uc)
 	}
 
 	gameLoop.SetOnTurnEndHandler(func(ctx context.Context, gameID string) {
+		if !uc.beginTurnEnd() {
+			return // shutting down; the next start recovers the turn
+		}
+		defer uc.turnEnds.Done()
 		uc.onTurnEnd(ctx, gameID)
 	})
+	gameLoop.SetOnRevealHandler(uc.onReveal)
+	if uc.presence == nil {
+		uc.presence = service.NewPresence(clock)
+	}
+	uc.presence.SetOnLeaveHandler(func(ctx context.Context, gameID, userID string, goneAt time.Time) {
+		if err := uc.markInactive(ctx, gameID, userID, goneAt); err != nil {
+			log.Printf("failed to mark %s inactive in %s: %v", userID, gameID, err)
+		}
+	})
 
 	return uc
 }
@@ -78,20 +174,111 @@ type emojixUsecase struct {
 	unitOfWorkFactory repository.UnitOfWorkFactory
 	gameNotifier      service.GameNotifier
 	gameLoop          service.GameLoop
+	presence          service.Presence
 	clock             service.Clock
+	guessMatcher      GuessMatcher
+	scoringPolicies   map[string]ScoringPolicy
+	gameDefaults      model.GameSettings
+	rateLimits        RateLimits
+	limiter           rateLimiter
+
+	// shutdownMu guards shuttingDown and turnEnds.Add, so Shutdown's Wait
+	// never races a turn end starting.
+	shutdownMu   sync.Mutex
+	shuttingDown bool
+	turnEnds     sync.WaitGroup
+}
+
+// Option customizes an emojixUsecase built by NewEmojixUsecase.
+type Option func(*emojixUsecase)
+
+// WithGuessMatcher replaces DefaultGuessMatcher for Guess and chat masking.
+func WithGuessMatcher(m GuessMatcher) Option {
+	return func(e *emojixUsecase) {
+		e.guessMatcher = m
+	}
+}
+
+// WithPresence replaces the default Presence. Whoever supplies it runs its
+// scheduler; the default one is never run, so nobody is ever marked inactive.
+func WithPresence(p service.Presence) Option {
+	return func(e *emojixUsecase) {
+		e.presence = p
+	}
 }
 
-func (e *emojixUsecase) GameUpdates(ctx context.Context, gameID string, userID string, handler GameUpdateHandler) error {
-	gameSubCh, cleanup := e.gameNotifier.Sub(gameID, userID)
+// WithGameDefaults replaces DefaultGameSettings as the rules for whatever a
+// room's creator leaves unset. Zero fields in d keep the built-in default.
+func WithGameDefaults(d model.GameSettings) Option {
+	return func(e *emojixUsecase) {
+		e.gameDefaults = e.withDefaults(d)
+	}
+}
+
+// WithScoringPolicy registers p under name so games can select it through
+// GameSettings.Scoring. Registering a built-in name replaces it.
+func WithScoringPolicy(name string, p ScoringPolicy) Option {
+	return func(e *emojixUsecase) {
+		e.scoringPolicies[name] = p
+	}
+}
+
+// scoringPolicy returns the policy for a stored setting; unknown names (e.g. a
+// policy removed since the game was created) fall back to classic.
+func (e *emojixUsecase) scoringPolicy(name string) ScoringPolicy {
+	if p, ok := e.scoringPolicies[name]; ok {
+		return p
+	}
+	return e.scoringPolicies[ClassicScoring]
+}
+
+func (e *emojixUsecase) GameUpdates(ctx context.Context, gameID string, userID string, lastEventID uint64, handler GameUpdateHandler) error {
+	var missed []service.Event
+	var gameSubCh chan service.GameNotification
+	var cleanup func()
+	if lastEventID > 0 {
+		missed, gameSubCh, cleanup = e.gameNotifier.Resume(gameID, userID, lastEventID)
+	} else {
+		gameSubCh, cleanup = e.gameNotifier.Sub(gameID, userID)
+	}
 	defer cleanup()
+	beat, release := e.presence.Connect(gameID, userID)
+	defer release()
+	e.seen(ctx, gameID, userID)
+
+	for _, ev := range missed {
+		if err := handler(ev.ID, ev.GetType(), ev.GetData()); err != nil {
+			return err
+		}
+	}
+	heartbeat := e.clock.After(service.HeartbeatInterval)
 	for {
 
 		select {
-		case notif := <-gameSubCh:
-			err := handler(notif.GetType(), notif.GetData())
+		case <-heartbeat:
+			// A write to a dead connection fails here, ending the stream;
+			// one that goes through proves the client is still there.
+			if err := handler(0, HeartbeatEvent, ""); err != nil {
+				return err
+			}
+			beat()
+			e.seen(ctx, gameID, userID)
+			heartbeat = e.clock.After(service.HeartbeatInterval)
+		case notif, ok := <-gameSubCh:
+			if !ok {
+				// dropped as a slow subscriber; the client reconnects
+				// and resumes from its last event id
+				return nil
+			}
+			var id uint64
+			if ev, ok := notif.(service.Event); ok {
+				id = ev.ID
+			}
+			err := handler(id, notif.GetType(), notif.GetData())
 			if err != nil {
 				return err
 			}
+			beat()
 		case <-ctx.Done():
 			return nil
 		}
@@ -110,15 +297,40 @@ func (gmn *UserLeftNotification) GetData() string {
 	return fmt.Sprintf("%s", gmn.UserID)
 }
 
-func (e *emojixUsecase) KickInactiveUser(ctx context.Context, gameID, userID string) error {
-	activePlayers := e.gameNotifier.Subs(gameID)
-	if slices.Contains(activePlayers, userID) {
-		return nil
+// seen records a live connection in the shared last-seen, which tells other
+// instances the user is still here.
+func (e *emojixUsecase) seen(ctx context.Context, gameID, userID string) {
+	if err := e.gameRepo.SeenPlayer(ctx, gameID, userID, e.clock.Now()); err != nil {
+		log.Printf("failed to record %s as seen in %s: %v", userID, gameID, err)
 	}
-	err := e.gameRepo.SetPlayerState(ctx, gameID, userID, model.InactivePlayerState)
+}
+
+// markInactive is the Presence OnLeave handler: userID has had no connection
+// to gameID on this instance since goneAt, for the whole grace period.
+func (e *emojixUsecase) markInactive(ctx context.Context, gameID, userID string, goneAt time.Time) error {
+	if e.presence.State(gameID, userID) != service.Offline {
+		return nil // came back while the sweep was running
+	}
+	// Presence only knows this instance's connections. A beat recorded
+	// after ours ended came from a connection held somewhere else.
+	players, err := e.gameRepo.GetPlayers(ctx, gameID)
+	if err != nil {
+		return err
+	}
+	for _, p := range players {
+		if p.ID == userID && p.LastSeenAt.After(goneAt) {
+			return nil
+		}
+	}
+	err = e.gameRepo.SetPlayerState(ctx, gameID, userID, model.InactivePlayerState)
 
 	go e.gameNotifier.Pub(gameID, userID, &UserLeftNotification{userID})
 
+	if err == nil {
+		e.handOffHost(ctx, gameID, userID)
+		e.promoteSpectators(ctx, gameID)
+	}
+
 	return err
 }
 
@@ -128,24 +340,30 @@ func GotItMessage(nickname string) string {
 	return nickname + " got it!"
 }
 
-// MaskMessage rewrites a stored chat/guess line for display. Correct guesses
-// become a system announcement for everyone.
-func MaskMessage(content, word, nickname string) (display string, isSystem bool) {
-	if strings.EqualFold(content, word) {
+// CloseMessage is the line other players see instead of a near miss, which
+// would nearly spell the word.
+func CloseMessage(nickname string) string {
+	return nickname + " is close"
+}
+
+// maskMessage rewrites a stored chat/guess line for display. Correct guesses,
+// as judged by matcher, become a system announcement, and near misses a system
+// line without the guess; only the author (mine) still sees their near miss.
+func maskMessage(matcher GuessMatcher, content, word, nickname string, mine bool) (string, bool) {
+	switch matcher.Match(content, word) {
+	case GuessCorrect:
 		return GotItMessage(nickname), true
+	case GuessClose:
+		if !mine {
+			return CloseMessage(nickname), true
+		}
 	}
 	return content, false
 }
 
-const (
-	turnDuration      = time.Second * 60
-	pickDuration      = time.Second * 10 // skip teller if they don't pick
-	minPlayersToStart = 2                // host alone waits for a second player
-)
-
 // ErrNoWords is returned when a new turn cannot be created because the word
 // repository has no words to pick from.
-var ErrNoWords = errors.New("no words available to pick for a new turn")
+var ErrNoWords = NewError(KindConflict, "no words available to pick for a new turn")
 
 func (e *emojixUsecase) ListWordLists(ctx context.Context) ([]model.WordList, error) {
 	return e.wordRepo.GetLists(ctx)
@@ -159,11 +377,28 @@ func (e *emojixUsecase) GameState(ctx context.Context, gameID string, currentUse
 	}
 
 	activePlayers := e.filterActivePlayers(players)
-	err = e.isPlayerInGame(currentUserID, activePlayers)
+	err = e.isPlayerInGame(currentUserID, e.filterPresentPlayers(players))
 	if err != nil {
 		return gameState, err
 	}
 
+	game, err := e.gameRepo.FindByID(ctx, gameID)
+	if err != nil {
+		return gameState, err
+	}
+	gameState.Settings = e.withDefaults(game.Settings)
+	gameState.Status = game.Status
+	gameState.HostID = game.HostID
+	gameState.IsHost = game.HostID != "" && game.HostID == currentUserID
+	gameState.InviteCode = game.InviteCode
+	gameState.Locked = game.Locked
+	gameState.IsSpectator = isSpectator(players, currentUserID)
+	gameState.QueuePosition = queuePosition(players, currentUserID)
+	gameState.SpectatorCount = countSpectators(players)
+	if game.Settings.Teams {
+		gameState.MyTeam = teamOf(players, currentUserID)
+	}
+
 	messages, err := e.gameRepo.GetMessages(ctx, gameID)
 	if err != nil {
 		return gameState, err
@@ -173,6 +408,9 @@ func (e *emojixUsecase) GameState(ctx context.Context, gameID string, currentUse
 	if err != nil {
 		return gameState, err
 	}
+	if game.Settings.Teams {
+		gameState.TeamLeaderboard = buildTeamLeaderboard(players, scores, currentUserID)
+	}
 
 	latestTurn, err := e.gameRepo.GetLatestTurn(ctx, gameID)
 	if err != nil {
@@ -180,7 +418,7 @@ func (e *emojixUsecase) GameState(ctx context.Context, gameID string, currentUse
 			gameState.GameID = gameID
 			gameState.CurrentUserID = currentUserID
 			gameState.WaitingForPlayers = true
-			gameState.Leaderboard = e.buildLeaderboard(currentUserID, "", "", scores, activePlayers)
+			gameState.Leaderboard = e.buildLeaderboard(gameID, currentUserID, "", "", scores, activePlayers)
 			return gameState, nil
 		}
 		return gameState, err
@@ -192,10 +430,27 @@ func (e *emojixUsecase) GameState(ctx context.Context, gameID string, currentUse
 	gameState.IsTeller = latestTurn.TellerID == currentUserID
 	gameState.AwaitingPick = latestTurn.WordID == ""
 	gameState.TellerNickname = tellerNickname(activePlayers, latestTurn.TellerID)
+	if game.Settings.Teams {
+		gameState.TellerTeam = teamOf(players, latestTurn.TellerID)
+	}
 
-	leaderboard := e.buildLeaderboard(currentUserID, latestTurn.ID, latestTurn.TellerID, scores, activePlayers)
+	leaderboard := e.buildLeaderboard(gameID, currentUserID, latestTurn.ID, latestTurn.TellerID, scores, activePlayers)
 	gameState.Leaderboard = leaderboard
 
+	if game.Status == model.FinishedGameStatus {
+		turns, err := e.gameRepo.GetTurns(ctx, gameID)
+		if err != nil {
+			return gameState, err
+		}
+		gameState.TurnResults, err = e.buildTurnResults(ctx, turns, players, scores)
+		if err != nil {
+			return gameState, err
+		}
+		gameState.Podium = buildPodium(leaderboard)
+		gameState.TurnEnded = true
+		return gameState, nil
+	}
+
 	if gameState.AwaitingPick {
 		// Pick countdown starts when the turn row is created.
 		gameState.TurnStartedAt = latestTurn.CreatedAt
@@ -205,6 +460,7 @@ func (e *emojixUsecase) GameState(ctx context.Context, gameID string, currentUse
 				return gameState, err
 			}
 			gameState.WordOptions = opts
+			gameState.CanReroll = !latestTurn.Rerolled
 		}
 		return gameState, nil
 	}
@@ -221,6 +477,16 @@ func (e *emojixUsecase) GameState(ctx context.Context, gameID string, currentUse
 		gameState.Hint = word.Hint
 	}
 	gameState.TurnStartedAt = latestTurn.StartedAt
+	votes, err := e.gameRepo.GetSkipVotes(ctx, latestTurn.ID)
+	if err != nil {
+		return gameState, err
+	}
+	gameState.SkipVotes = countSkipVotes(votes, activePlayers, latestTurn.TellerID)
+	gameState.SkipVotesNeeded = skipVotesNeeded(e.countGuessers(activePlayers, latestTurn.TellerID))
+	gameState.VotedSkip = slices.Contains(votes, currentUserID)
+	if game.Settings.Teams {
+		gameState.StealOpensAt = stealOpensAt(latestTurn, gameState.Settings)
+	}
 	gameState.LetterCount, gameState.WordCount = wordShape(word.Word)
 
 	// check if turn ended and decide to mask word or not
@@ -247,31 +513,32 @@ func (e *emojixUsecase) GameState(ctx context.Context, gameID string, currentUse
 		allGuessed = false
 	}
 
-	wordMaskRegex := regexp.MustCompile(`\w`)
 	gameWord := word.Word
-	// Teller always sees the real word; others only after guessing.
+	// Teller always sees the real word; others only after guessing, apart
+	// from the letters revealed so far.
 	if !gameState.IsTeller && !currPlayerEntry.GuessedWord {
-		gameWord = wordMaskRegex.ReplaceAllString(gameWord, "*")
+		gameWord = maskWord(gameWord, latestTurn.Revealed)
 	}
 
-	turnEndTime := gameState.TurnStartedAt.Add(turnDuration)
-	now := e.clock.Now()
-	turnTimedOut := !latestTurn.StartedAt.IsZero() && now.After(turnEndTime)
-
-	gameState.TurnEnded = allGuessed || turnTimedOut
+	// The loop stamps EndedAt when the turn closes, whatever the reason, so
+	// the wall clock never decides this on its own.
+	gameState.TurnEnded = allGuessed || !latestTurn.EndedAt.IsZero()
 	gameState.Word = gameWord
 
 	// prepare messages (repo order is oldest→newest; keep newest at bottom)
 	gameMessages := []model.GameStateMessage{}
 	for _, msg := range messages {
 		le := leaderboardEntryMap[msg.PlayerID]
-		display, isSystem := MaskMessage(msg.Content, word.Word, le.Nickname)
+		display, isSystem := maskMessage(e.guessMatcher, msg.Content, word.Word, le.Nickname, le.Me)
 		gm := model.GameStateMessage{
 			Me:       le.Me,
 			Content:  display,
 			Nickname: le.Nickname,
 			IsSystem: isSystem,
 		}
+		if !isSystem {
+			gm.PlayerID = msg.PlayerID
+		}
 		gameMessages = append(gameMessages, gm)
 	}
 	gameState.Messages = gameMessages
@@ -322,16 +589,28 @@ func generateRandomID() (string, error) {
 }
 
 // NICKNAME Generation
+//
+// Adjective + animal gives a few thousand names, so two players in a room
+// rarely collide; every combination stays within maxNicknameRunes.
 var animals = []string{
-	"cat",
-	"dog",
-	"mouse",
+	"cat", "dog", "mouse", "otter", "panda", "koala", "tiger", "lion",
+	"zebra", "giraffe", "hippo", "rhino", "camel", "llama", "alpaca", "moose",
+	"beaver", "badger", "ferret", "hedgehog", "rabbit", "hamster", "squirrel",
+	"raccoon", "fox", "wolf", "bear", "seal", "walrus", "penguin", "puffin",
+	"owl", "eagle", "falcon", "parrot", "toucan", "flamingo", "pelican",
+	"swan", "duck", "goose", "frog", "toad", "turtle", "gecko", "iguana",
+	"octopus", "squid", "lobster", "crab", "dolphin", "whale", "shark",
+	"narwhal", "bison", "yak",
 }
 
 var adjectives = []string{
-	"silly",
-	"handsome",
-	"angry",
+	"silly", "handsome", "angry", "happy", "sleepy", "grumpy", "brave",
+	"clever", "curious", "dizzy", "eager", "fancy", "fluffy", "gentle",
+	"giddy", "jolly", "jumpy", "lazy", "lucky", "merry", "mighty", "nimble",
+	"noisy", "polite", "proud", "quick", "quiet", "rapid", "shy", "sneaky",
+	"speedy", "spicy", "sunny", "swift", "tiny", "witty", "zesty", "bold",
+	"bouncy", "breezy", "bubbly", "calm", "cheery", "chilly", "cosmic",
+	"crafty", "cuddly", "daring", "dreamy", "fierce", "frosty", "funky",
 }
 
 func pickRandItem(items []string) string {
@@ -393,7 +672,28 @@ func pickWordOptions(unused []model.Word, n int) []model.Word {
 	return shuffled[:n]
 }
 
-func (e *emojixUsecase) InitGame(ctx context.Context, userID string, listID string) (model.Game, error) {
+// dealWordOptions picks a turn's three options from unused (not empty),
+// repeating the last one when the list is nearly out.
+func dealWordOptions(unused []model.Word) []model.Word {
+	options := pickWordOptions(unused, 3)
+	for len(options) < 3 {
+		options = append(options, options[len(options)-1])
+	}
+	return options
+}
+
+func (e *emojixUsecase) InitGame(ctx context.Context, userID string, listID string, settings model.GameSettings) (model.Game, error) {
+	if e.draining() {
+		return model.Game{}, ErrShuttingDown
+	}
+	settings = e.withDefaults(settings)
+	if err := ValidateGameSettings(settings); err != nil {
+		return model.Game{}, err
+	}
+	if _, ok := e.scoringPolicies[settings.Scoring]; !ok {
+		return model.Game{}, fmt.Errorf("%w: unknown scoring %q", ErrInvalidGameSettings, settings.Scoring)
+	}
+
 	uow, err := e.unitOfWorkFactory.New(ctx)
 	if err != nil {
 		return model.Game{}, err
@@ -402,7 +702,7 @@ func (e *emojixUsecase) InitGame(ctx context.Context, userID string, listID stri
 
 	gameRepo := uow.GameRepository()
 
-	game, err := gameRepo.Create(ctx, listID)
+	game, err := gameRepo.Create(ctx, listID, settings)
 	if err != nil {
 		return model.Game{}, err
 	}
@@ -412,6 +712,17 @@ func (e *emojixUsecase) InitGame(ctx context.Context, userID string, listID stri
 		return model.Game{}, err
 	}
 
+	if settings.Teams {
+		if err = gameRepo.SetPlayerTeam(ctx, game.ID, userID, model.RedTeam); err != nil {
+			return model.Game{}, err
+		}
+	}
+
+	if err = gameRepo.SetHost(ctx, game.ID, userID); err != nil {
+		return model.Game{}, err
+	}
+	game.HostID = userID
+
 	if err = uow.Commit(); err != nil {
 		return model.Game{}, err
 	}
@@ -428,40 +739,47 @@ func (e *emojixUsecase) tryStartGame(ctx context.Context, gameID string) {
 	if e.gameLoop.Running(gameID) {
 		return
 	}
-	players, err := e.gameRepo.GetPlayers(ctx, gameID)
+	game, err := e.gameRepo.FindByID(ctx, gameID)
 	if err != nil {
-		log.Printf("tryStartGame GetPlayers: %v", err)
+		log.Printf("tryStartGame FindByID: %v", err)
 		return
 	}
-	if len(e.filterActivePlayers(players)) < minPlayersToStart {
+	if game.Status == model.FinishedGameStatus {
 		return
 	}
-	game, err := e.gameRepo.FindByID(ctx, gameID)
+	settings := e.withDefaults(game.Settings)
+	players, err := e.gameRepo.GetPlayers(ctx, gameID)
 	if err != nil {
-		log.Printf("tryStartGame FindByID: %v", err)
+		log.Printf("tryStartGame GetPlayers: %v", err)
 		return
 	}
-	if err := e.newGameTurn(ctx, e.gameRepo, gameID, game.ListID); err != nil {
+	if len(e.filterActivePlayers(players)) < settings.MinPlayers {
+		return
+	}
+	if err := e.newGameTurn(ctx, e.gameRepo, game); err != nil {
 		log.Printf("tryStartGame newGameTurn: %v", err)
 		return
 	}
-	e.gameLoop.Start(context.Background(), gameID, turnDuration, pickDuration)
+	if game.Status != model.PlayingGameStatus {
+		if err := e.gameRepo.SetStatus(ctx, gameID, model.PlayingGameStatus); err != nil {
+			log.Printf("tryStartGame SetStatus: %v", err)
+		}
+	}
+	e.gameLoop.Start(context.Background(), gameID, settings.TurnDuration, settings.PickDuration)
 	go e.gameNotifier.PubAll(gameID, &NewTurnNotification{})
 }
 
 var (
-	ErrNotTeller       = errors.New("only the teller can pick the word")
-	ErrAlreadyPicked   = errors.New("word already picked for this turn")
-	ErrInvalidOption   = errors.New("word is not one of the turn options")
-	ErrTellerEmojiOnly = errors.New("teller may only send emoji")
-	ErrEmptyMessage    = errors.New("message is empty")
-	ErrPickFirst       = errors.New("pick a word before sending hints")
+	ErrNotTeller         = NewError(KindForbidden, "only the teller can pick the word")
+	ErrAlreadyPicked     = NewError(KindConflict, "word already picked for this turn")
+	ErrInvalidOption     = NewError(KindValidation, "word is not one of the turn options")
+	ErrTellerEmojiOnly   = NewError(KindValidation, "teller may only send emoji")
+	ErrEmptyMessage      = NewError(KindValidation, "message is empty")
+	ErrPickFirst         = NewError(KindConflict, "pick a word before sending hints")
+	ErrTurnNotStarted    = NewError(KindConflict, "turn has not started yet")
+	ErrTellerCannotGuess = NewError(KindForbidden, "teller cannot guess")
 )
 
-// tellerMessagePenalty is taken from the teller's current-turn points only
-// (not banked totals). Floor is 0 — messages are free once turn points are gone.
-const tellerMessagePenalty = 2
-
 type WordPickedNotification struct{}
 
 func (n *WordPickedNotification) GetType() string { return "wordpicked" }
@@ -491,8 +809,13 @@ func (e *emojixUsecase) PickWord(ctx context.Context, gameID, userID, wordID str
 	if err != nil {
 		return err
 	}
+	game, err := e.gameRepo.FindByID(ctx, gameID)
+	if err != nil {
+		return err
+	}
 
-	if err := e.gameRepo.SetTurnWord(ctx, turn.ID, wordID, word.Hint); err != nil {
+	endDeadline := e.clock.Now().Add(e.withDefaults(game.Settings).TurnDuration)
+	if err := e.gameRepo.SetTurnWord(ctx, turn.ID, wordID, word.Hint, endDeadline); err != nil {
 		return err
 	}
 
@@ -546,6 +869,20 @@ func (gmn *GameCorrectGuessNotification) GetData() string {
 	return fmt.Sprintf("%s,%s", gmn.UserID, gmn.Nickname)
 }
 
+// GameCloseGuessNotification tells a single guesser their guess was a near
+// miss. It is sent with PubTo and never broadcast.
+type GameCloseGuessNotification struct {
+	Content string
+}
+
+func (gmn *GameCloseGuessNotification) GetType() string {
+	return "close"
+}
+
+func (gmn *GameCloseGuessNotification) GetData() string {
+	return gmn.Content
+}
+
 type GameTurnEndNotification struct {
 }
 
@@ -558,6 +895,17 @@ func (gmn *GameTurnEndNotification) GetData() string {
 }
 
 func (e *emojixUsecase) Guess(ctx context.Context, gameID string, userID string, content string) (bool, error) {
+	players, err := e.gameRepo.GetPlayers(ctx, gameID)
+	if err != nil {
+		return false, err
+	}
+	if err := e.canPlay(players, userID); err != nil {
+		return false, err
+	}
+	if err := e.allow(guessAction, e.rateLimits.Guess, gameID, userID); err != nil {
+		return false, err
+	}
+
 	currPlayer, err := e.userRepo.FindByID(ctx, userID)
 	if err != nil {
 		return false, err
@@ -568,11 +916,14 @@ func (e *emojixUsecase) Guess(ctx context.Context, gameID string, userID string,
 		return false, err
 	}
 	if turn.WordID == "" {
-		return false, errors.New("turn has not started yet")
+		return false, ErrTurnNotStarted
+	}
+	if !turn.EndedAt.IsZero() {
+		return false, ErrTurnOver
 	}
 	// Teller already knows the word; ignore their guesses.
 	if turn.TellerID == userID {
-		return false, errors.New("teller cannot guess")
+		return false, ErrTellerCannotGuess
 	}
 	turnID := turn.ID
 
@@ -582,6 +933,31 @@ func (e *emojixUsecase) Guess(ctx context.Context, gameID string, userID string,
 	}
 	gameWord := word.Word
 
+	game, err := e.gameRepo.FindByID(ctx, gameID)
+	if err != nil {
+		return false, err
+	}
+	settings := e.withDefaults(game.Settings)
+
+	// In team games the other side waits for the steal window, and a steal
+	// earns the teller nothing.
+	steal := false
+	if settings.Teams {
+		steal = teamOf(players, userID) != teamOf(players, turn.TellerID)
+		if steal && e.clock.Now().Before(stealOpensAt(turn, settings)) {
+			return false, ErrStealNotOpen
+		}
+	}
+
+	policy := e.scoringPolicy(settings.Scoring)
+	ev := ScoringEvent{
+		PlayerID:     userID,
+		TellerID:     turn.TellerID,
+		Elapsed:      e.clock.Now().Sub(turn.StartedAt),
+		TurnDuration: settings.TurnDuration,
+		Reveals:      len(turn.Revealed),
+	}
+
 	uow, err := e.unitOfWorkFactory.New(ctx)
 	if err != nil {
 		return false, err
@@ -595,20 +971,30 @@ func (e *emojixUsecase) Guess(ctx context.Context, gameID string, userID string,
 		return false, err
 	}
 
-	// TODO: make fancier word comparison
-	guessedWord := strings.EqualFold(content, gameWord)
-	if !guessedWord {
+	verdict := e.guessMatcher.Match(content, gameWord)
+	if verdict != GuessCorrect {
+		if err = addAwards(ctx, gameRepo, gameID, msg.ID, turnID, policy.WrongGuess(ev)); err != nil {
+			return false, err
+		}
 		// Only publish after a successful commit so a failed commit does not
 		// broadcast a chat message that was never persisted.
 		if err = uow.Commit(); err != nil {
 			return false, err
 		}
-		go e.gameNotifier.Pub(gameID, userID, &GameMsgNotification{UserID: userID, Nickname: currPlayer.Nickname, Content: content})
+		// Everyone else gets the masked line; the guesser has their own.
+		display, isSystem := maskMessage(e.guessMatcher, content, gameWord, currPlayer.Nickname, false)
+		go e.gameNotifier.Pub(gameID, userID, &GameMsgNotification{UserID: userID, Nickname: currPlayer.Nickname, Content: display, IsSystem: isSystem})
+		if cd := e.rateLimits.WrongGuessCooldown; cd > 0 {
+			e.limiter.cooldown(rateKey{guessAction, gameID, userID}, cd, e.clock.Now())
+		}
+		if verdict == GuessClose {
+			go e.gameNotifier.PubTo(gameID, userID, &GameCloseGuessNotification{Content: content})
+		}
 		return false, nil
 	}
 
 	// check if the turn is ended
-	players, err := gameRepo.GetPlayers(ctx, gameID)
+	players, err = gameRepo.GetPlayers(ctx, gameID)
 	if err != nil {
 		return false, err
 	}
@@ -621,20 +1007,16 @@ func (e *emojixUsecase) Guess(ctx context.Context, gameID string, userID string,
 	// Duplicate-correct-guess: this user already scored on this turn. Idempotent
 	// no-op — no second AddScore, no guessed notif, no EndGameTurn. The
 	// SendMessage above is still committed so the chat record stays consistent.
-	for _, s := range scores {
-		if s.PlayerID == userID && s.TurnID == turnID {
-			return true, uow.Commit()
-		}
+	if guessedTurn(scores, userID, turnID) {
+		return true, uow.Commit()
 	}
 
 	// Count distinct non-teller players who already guessed this turn. Teller
 	// rows (bonus / message penalty) must not count — they are not guesses.
 	// Current user is about to be scored, so total after this AddScore is len+1.
-	// TODO: the scoring formula below drifts from the README (+5 base, +1/sec
-	// left, -1 wrong guess). Alignment is a separate backlog decision.
 	guessedPlayers := map[string]struct{}{}
 	for _, s := range scores {
-		if s.TurnID == turnID && s.PlayerID != turn.TellerID {
+		if s.TurnID == turnID && s.PlayerID != turn.TellerID && s.Score > 0 {
 			guessedPlayers[s.PlayerID] = struct{}{}
 		}
 	}
@@ -642,29 +1024,15 @@ func (e *emojixUsecase) Guess(ctx context.Context, gameID string, userID string,
 
 	// Use active guessers only (exclude teller and inactive).
 	activePlayers := e.filterActivePlayers(players)
-	guesserCount := e.countGuessers(activePlayers, turn.TellerID)
-	if guesserCount == 0 {
-		guesserCount = 1 // avoid div by zero; solo edge case
-	}
-	pointCoeff := guesserCount / totalGuessers
-	if pointCoeff < 1 {
-		pointCoeff = 1
-	}
-	basePoint := 10
-	point := basePoint * pointCoeff
+	ev.ActiveGuessers = e.countGuessers(activePlayers, turn.TellerID)
+	ev.PriorCorrect = len(guessedPlayers)
 
-	err = gameRepo.AddScore(ctx, gameID, userID, msg.ID, turnID, point)
-	if err != nil {
-		return false, err
+	awards := policy.CorrectGuess(ev)
+	if steal {
+		awards = slices.DeleteFunc(awards, func(a ScoreAward) bool { return a.PlayerID == turn.TellerID })
 	}
-
-	// Teller scores per correct guess (more solvers → more teller points over the turn).
-	const tellerPointsPerCorrectGuess = 5
-	if turn.TellerID != "" {
-		err = gameRepo.AddScore(ctx, gameID, turn.TellerID, msg.ID, turnID, tellerPointsPerCorrectGuess)
-		if err != nil {
-			return false, err
-		}
+	if err = addAwards(ctx, gameRepo, gameID, msg.ID, turnID, awards); err != nil {
+		return false, err
 	}
 
 	err = uow.Commit()
@@ -686,6 +1054,9 @@ func (e *emojixUsecase) Guess(ctx context.Context, gameID string, userID string,
 }
 
 func (e *emojixUsecase) onTurnEnd(ctx context.Context, gameID string) {
+	if err := e.gameRepo.EndTurn(ctx, gameID, e.clock.Now()); err != nil {
+		log.Printf("failed to mark turn ended in %s: %v", gameID, err)
+	}
 	e.gameNotifier.PubAll(gameID, &GameTurnEndNotification{})
 	<-e.clock.After(5 * time.Second)
 
@@ -695,24 +1066,43 @@ func (e *emojixUsecase) onTurnEnd(ctx context.Context, gameID string) {
 		e.gameLoop.StopGame(gameID)
 		return
 	}
-	if len(e.filterActivePlayers(players)) < minPlayersToStart {
+
+	game, err := e.gameRepo.FindByID(ctx, gameID)
+	if err != nil {
+		log.Printf("failed to load game for new turn: %v", err)
+		e.gameLoop.StopGame(gameID)
+		return
+	}
+
+	settings := e.withDefaults(game.Settings)
+	activePlayers := e.filterActivePlayers(players)
+	if len(activePlayers) < settings.MinPlayers {
 		// Pause until another player joins (tryStartGame on JoinGame).
 		e.gameLoop.StopGame(gameID)
 		return
 	}
 
-	game, err := e.gameRepo.FindByID(ctx, gameID)
+	turns, err := e.gameRepo.GetTurns(ctx, gameID)
 	if err != nil {
-		log.Printf("failed to load game for new turn: %v", err)
+		log.Printf("failed to load turns for new turn: %v", err)
 		e.gameLoop.StopGame(gameID)
 		return
 	}
+	if roundsComplete(turns, activePlayers, settings.Rounds) {
+		e.finishGame(ctx, gameID)
+		return
+	}
 
-	err = e.newGameTurn(ctx, e.gameRepo, gameID, game.ListID)
+	err = e.newGameTurn(ctx, e.gameRepo, game)
+	if errors.Is(err, ErrNoWords) {
+		// List ran dry before the last round; end the match with what was played.
+		e.finishGame(ctx, gameID)
+		return
+	}
 	if err != nil {
 		log.Printf("failed to create new turn, retrying: %v", err)
 		<-e.clock.After(time.Second)
-		err = e.newGameTurn(ctx, e.gameRepo, gameID, game.ListID)
+		err = e.newGameTurn(ctx, e.gameRepo, game)
 	}
 	if err != nil {
 		log.Printf("failed to create new turn after retry, stopping game: %v", err)
@@ -722,8 +1112,9 @@ func (e *emojixUsecase) onTurnEnd(ctx context.Context, gameID string) {
 	e.gameNotifier.PubAll(gameID, &NewTurnNotification{})
 }
 
-func (e *emojixUsecase) newGameTurn(ctx context.Context, gr repository.GameRepository, gameID, listID string) error {
-	unused, err := e.wordRepo.GetUnusedByList(ctx, listID, gameID)
+func (e *emojixUsecase) newGameTurn(ctx context.Context, gr repository.GameRepository, game model.Game) error {
+	gameID := game.ID
+	unused, err := e.wordRepo.GetUnusedByList(ctx, game.ListID, gameID)
 	if err != nil {
 		return err
 	}
@@ -731,11 +1122,7 @@ func (e *emojixUsecase) newGameTurn(ctx context.Context, gr repository.GameRepos
 		return ErrNoWords
 	}
 
-	options := pickWordOptions(unused, 3)
-	// Pad to 3 slots by repeating the last option when the list is nearly empty.
-	for len(options) < 3 {
-		options = append(options, options[len(options)-1])
-	}
+	options := dealWordOptions(unused)
 
 	players, err := gr.GetPlayers(ctx, gameID)
 	if err != nil {
@@ -745,7 +1132,7 @@ func (e *emojixUsecase) newGameTurn(ctx context.Context, gr repository.GameRepos
 	if len(active) == 0 {
 		return errors.New("no active players")
 	}
-	// Stable teller rotation by join order.
+	// Stable teller rotation by join order (within each side in team games).
 	slices.SortFunc(active, func(a, b model.Player) int {
 		return a.JoinedAt.Compare(b.JoinedAt)
 	})
@@ -753,7 +1140,7 @@ func (e *emojixUsecase) newGameTurn(ctx context.Context, gr repository.GameRepos
 	if err != nil {
 		return err
 	}
-	teller := active[turnCount%len(active)]
+	teller := pickTeller(active, turnCount, game.Settings.Teams)
 
 	_, err = gr.AddTurn(ctx, repository.AddTurnParams{
 		GameID:   gameID,
@@ -761,6 +1148,8 @@ func (e *emojixUsecase) newGameTurn(ctx context.Context, gr repository.GameRepos
 		OptionA:  options[0].ID,
 		OptionB:  options[1].ID,
 		OptionC:  options[2].ID,
+		// The loop arms its pick timer right after this returns.
+		PickDeadline: e.clock.Now().Add(e.withDefaults(game.Settings).PickDuration),
 	})
 	return err
 }
@@ -790,6 +1179,16 @@ func (e *emojixUsecase) Message(ctx context.Context, gameID string, userID strin
 	if content == "" {
 		return ErrEmptyMessage
 	}
+	players, err := e.gameRepo.GetPlayers(ctx, gameID)
+	if err != nil {
+		return err
+	}
+	if err := e.canPlay(players, userID); err != nil {
+		return err
+	}
+	if err := e.allow(messageAction, e.rateLimits.Message, gameID, userID); err != nil {
+		return err
+	}
 
 	turn, err := e.gameRepo.GetLatestTurn(ctx, gameID)
 	if err != nil {
@@ -809,51 +1208,99 @@ func (e *emojixUsecase) Message(ctx context.Context, gameID string, userID strin
 		return ErrTellerEmojiOnly
 	}
 
-	msg, err := e.gameRepo.SendMessage(ctx, gameID, turn.ID, userID, content)
+	// A guesser typing the word, or nearly, into chat must not give it
+	// away: the room gets the line masked as history will show it.
+	display, isSystem := content, false
+	if !isTeller && turn.WordID != "" {
+		word, err := e.wordRepo.FindByID(ctx, turn.WordID)
+		if err != nil {
+			return err
+		}
+		display, isSystem = maskMessage(e.guessMatcher, content, word.Word, currPlayer.Nickname, false)
+	}
+
+	if isTeller {
+		err = e.tellerMessage(ctx, gameID, turn, userID, content)
+	} else {
+		_, err = e.gameRepo.SendMessage(ctx, gameID, turn.ID, userID, content)
+	}
 	if err != nil {
 		return err
 	}
 
-	if isTeller {
-		scores, err := e.gameRepo.GetScores(ctx, gameID)
-		if err != nil {
-			return err
-		}
-		turnPts := 0
-		for _, s := range scores {
-			if s.PlayerID == userID && s.TurnID == turn.ID {
-				turnPts += s.Score
-			}
-		}
-		if turnPts > 0 {
-			penalty := tellerMessagePenalty
-			if penalty > turnPts {
-				penalty = turnPts
-			}
-			// ponytail: non-atomic message+penalty; UOW if we ever see partial fails
-			if err := e.gameRepo.AddScore(ctx, gameID, userID, msg.ID, turn.ID, -penalty); err != nil {
-				return err
-			}
+	go e.gameNotifier.Pub(gameID, userID, &GameMsgNotification{UserID: userID, Nickname: currPlayer.Nickname, Content: display, IsSystem: isSystem})
+
+	return nil
+}
+
+// tellerMessage stores a teller hint and its scoring penalty atomically.
+func (e *emojixUsecase) tellerMessage(ctx context.Context, gameID string, turn model.GameTurn, userID, content string) error {
+	game, err := e.gameRepo.FindByID(ctx, gameID)
+	if err != nil {
+		return err
+	}
+
+	uow, err := e.unitOfWorkFactory.New(ctx)
+	if err != nil {
+		return err
+	}
+	defer uow.Rollback()
+
+	gameRepo := uow.GameRepository()
+
+	msg, err := gameRepo.SendMessage(ctx, gameID, turn.ID, userID, content)
+	if err != nil {
+		return err
+	}
+
+	scores, err := gameRepo.GetScores(ctx, gameID)
+	if err != nil {
+		return err
+	}
+	ev := ScoringEvent{PlayerID: userID, TellerID: userID}
+	for _, s := range scores {
+		if s.PlayerID == userID && s.TurnID == turn.ID {
+			ev.TurnPoints += s.Score
 		}
 	}
 
-	go e.gameNotifier.Pub(gameID, userID, &GameMsgNotification{UserID: userID, Nickname: currPlayer.Nickname, Content: content})
+	awards := e.scoringPolicy(e.withDefaults(game.Settings).Scoring).TellerMessage(ev)
+	if err = addAwards(ctx, gameRepo, gameID, msg.ID, turn.ID, awards); err != nil {
+		return err
+	}
 
+	return uow.Commit()
+}
+
+// addAwards writes each policy award as a score row tied to messageID.
+func addAwards(ctx context.Context, gr repository.GameRepository, gameID, messageID, turnID string, awards []ScoreAward) error {
+	for _, a := range awards {
+		if err := gr.AddScore(ctx, gameID, a.PlayerID, messageID, turnID, a.Points); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-func (e *emojixUsecase) buildLeaderboard(currentUserID string, latestTurnID string, tellerID string, scores []model.Score, activePlayers []model.Player) []model.LeaderboardEntry {
+// guessedTurn reports whether playerID solved turnID. Only a positive row
+// counts: wrong-guess and teller-message penalties are negative.
+func guessedTurn(scores []model.Score, playerID, turnID string) bool {
+	for _, s := range scores {
+		if s.PlayerID == playerID && s.TurnID == turnID && s.Score > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *emojixUsecase) buildLeaderboard(gameID string, currentUserID string, latestTurnID string, tellerID string, scores []model.Score, activePlayers []model.Player) []model.LeaderboardEntry {
 	leaderboardEntries := []model.LeaderboardEntry{}
+	away := e.presence.Away(gameID)
 	isGuessedWord := func(playerID string) bool {
 		if playerID == tellerID {
 			return true // teller counts as "done" for display
 		}
-		for _, score := range scores {
-			if score.PlayerID == playerID && score.TurnID == latestTurnID {
-				return true
-			}
-		}
-		return false
+		return guessedTurn(scores, playerID, latestTurnID)
 	}
 
 	scoreMap := map[string]int{}
@@ -869,10 +1316,13 @@ func (e *emojixUsecase) buildLeaderboard(currentUserID string, latestTurnID stri
 		entry := model.LeaderboardEntry{
 			PlayerID:    player.ID,
 			Nickname:    player.Nickname,
+			Avatar:      player.Avatar,
+			Team:        player.Team,
 			Me:          player.ID == currentUserID,
 			GuessedWord: isGuessedWord(player.ID),
 			IsTeller:    player.ID == tellerID,
 			Score:       score,
+			Away:        slices.Contains(away, player.ID),
 		}
 
 		leaderboardEntries = append(leaderboardEntries, entry)
@@ -905,7 +1355,7 @@ func tellerNickname(players []model.Player, tellerID string) string {
 func (e *emojixUsecase) filterActivePlayers(players []model.Player) []model.Player {
 	activePlayers := []model.Player{}
 	for _, p := range players {
-		if p.State == model.InactivePlayerState {
+		if p.State == model.InactivePlayerState || p.State == model.KickedPlayerState || p.State == model.SpectatorPlayerState {
 			continue
 		}
 
@@ -929,6 +1379,23 @@ func (e *emojixUsecase) isPlayerInGame(currentUserID string, activePlayers []mod
 	return nil
 }
 
+// canPlay checks userID may guess or chat: spectators only watch, kicked
+// players are out, and anyone else needs an active seat.
+func (e *emojixUsecase) canPlay(players []model.Player, userID string) error {
+	for _, p := range players {
+		if p.ID != userID {
+			continue
+		}
+		switch p.State {
+		case model.SpectatorPlayerState:
+			return ErrSpectatorsWatchOnly
+		case model.KickedPlayerState:
+			return ErrPlayerKicked
+		}
+	}
+	return e.isPlayerInGame(userID, e.filterActivePlayers(players))
+}
+
 func (e *emojixUsecase) Leaderboard(ctx context.Context, gameID, currentUserID string) ([]model.LeaderboardEntry, error) {
 	leaderboardEntries := []model.LeaderboardEntry{}
 	players, err := e.gameRepo.GetPlayers(ctx, gameID)
@@ -937,7 +1404,7 @@ func (e *emojixUsecase) Leaderboard(ctx context.Context, gameID, currentUserID s
 	}
 
 	activePlayers := e.filterActivePlayers(players)
-	err = e.isPlayerInGame(currentUserID, activePlayers)
+	err = e.isPlayerInGame(currentUserID, e.filterPresentPlayers(players))
 	if err != nil {
 		return leaderboardEntries, err
 	}
@@ -950,12 +1417,12 @@ func (e *emojixUsecase) Leaderboard(ctx context.Context, gameID, currentUserID s
 	latestTurn, err := e.gameRepo.GetLatestTurn(ctx, gameID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return e.buildLeaderboard(currentUserID, "", "", scores, activePlayers), nil
+			return e.buildLeaderboard(gameID, currentUserID, "", "", scores, activePlayers), nil
 		}
 		return leaderboardEntries, err
 	}
 
-	leaderboardEntries = e.buildLeaderboard(currentUserID, latestTurn.ID, latestTurn.TellerID, scores, activePlayers)
+	leaderboardEntries = e.buildLeaderboard(gameID, currentUserID, latestTurn.ID, latestTurn.TellerID, scores, activePlayers)
 
 	return leaderboardEntries, nil
 }
@@ -983,19 +1450,12 @@ func (e *emojixUsecase) GameWord(ctx context.Context, gameID, currentUserID stri
 		return "", err
 	}
 
-	guessedWord := false
-	for _, score := range scores {
-		if score.PlayerID == currentUserID && score.TurnID == latestTurn.ID {
-			guessedWord = true
-			break
-		}
-	}
+	guessedWord := guessedTurn(scores, currentUserID, latestTurn.ID)
 
-	wordMaskRegex := regexp.MustCompile(`\w`)
 	gameWord := word.Word
 
 	if !guessedWord {
-		gameWord = wordMaskRegex.ReplaceAllString(gameWord, "*")
+		gameWord = maskWord(gameWord, latestTurn.Revealed)
 	}
 
 	return gameWord, nil
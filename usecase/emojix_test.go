@@ -13,6 +13,7 @@ import (
 	"fmt"
 	"reflect"
 	"runtime"
+	"strings"
 	"testing"
 	"time"
 )
@@ -42,25 +43,14 @@ func TestGotItMessage(t *testing.T) {
 	}
 }
 
-func TestMaskMessage(t *testing.T) {
-	t.Run("correct guess becomes system line", func(t *testing.T) {
-		got, sys := usecase.MaskMessage("Secret", "secret", "Ada")
-		if !sys {
-			t.Fatal("expected isSystem")
-		}
-		if got != "Ada got it!" {
-			t.Errorf("display = %q", got)
-		}
-	})
-	t.Run("normal chat unchanged", func(t *testing.T) {
-		got, sys := usecase.MaskMessage("hello", "secret", "Ada")
-		if sys {
-			t.Fatal("expected not system")
-		}
-		if got != "hello" {
-			t.Errorf("display = %q", got)
-		}
-	})
+// exactMatcher accepts only the word exactly as stored and has no near misses.
+type exactMatcher struct{}
+
+func (exactMatcher) Match(guess, word string) usecase.GuessVerdict {
+	if guess == word {
+		return usecase.GuessCorrect
+	}
+	return usecase.GuessWrong
 }
 
 // assertGameState reports every mismatch between the expected and actual
@@ -264,8 +254,10 @@ func TestGameState(t *testing.T) {
 			},
 			GetMessagesMock: func(ctx context.Context, id string) ([]model.Message, error) {
 				return []model.Message{
+					{ID: "close-1", PlayerID: "p-1", Content: "Some Wrd"},
 					{ID: "guess-msg-id", PlayerID: "p-1", Content: "Some Word"},
 					{ID: "chat-1", PlayerID: "p-2", Content: "hello"},
+					{ID: "close-2", PlayerID: "p-2", Content: "Sone Word"},
 				}, nil
 			},
 		}
@@ -291,8 +283,10 @@ func TestGameState(t *testing.T) {
 			LetterCount:   8,
 			WordCount:     2,
 			Messages: []model.GameStateMessage{
+				{Nickname: "Player1", Me: false, Content: "Player1 is close", IsSystem: true},
 				{Nickname: "Player1", Me: false, Content: "Player1 got it!", IsSystem: true},
 				{Nickname: "Player2", Me: true, Content: "hello", IsSystem: false},
+				{PlayerID: "p-2", Nickname: "Player2", Me: true, Content: "Sone Word", IsSystem: false},
 			},
 			Leaderboard: []model.LeaderboardEntry{
 				{PlayerID: "p-1", Nickname: "Player1", Me: false, GuessedWord: true, Score: 10},
@@ -301,8 +295,8 @@ func TestGameState(t *testing.T) {
 		}, gameState)
 	})
 
-	// NOTE: the timeout branch of TurnEnded is covered separately by
-	// TestGameState_TurnTimedOut (uses the T13 FakeClock seam).
+	// NOTE: the EndedAt branch of TurnEnded is covered separately by
+	// TestGameState_TurnEnded.
 
 	t.Run("marks teller nickname and word shape", func(t *testing.T) {
 		mgr := &repotest.MockGameRepository{
@@ -613,13 +607,13 @@ func TestGameUpdates(t *testing.T) {
 					return ch, func() { cleanupCount++ }
 				},
 			}
-			uc := usecase.NewEmojixUsecase(nil, nil, nil, nil, mgn, &servicetest.MockGameLoop{}, service.NewRealClock())
+			uc := usecase.NewEmojixUsecase(nil, &repotest.MockGameRepository{}, nil, nil, mgn, &servicetest.MockGameLoop{}, service.NewRealClock())
 
 			ctx, cancel := context.WithCancel(context.Background())
 			defer cancel()
 
 			var gotType, gotData string
-			handler := func(notifType, content string) error {
+			handler := func(eventID uint64, notifType, content string) error {
 				gotType = notifType
 				gotData = content
 				return tc.handlerErr
@@ -637,7 +631,7 @@ func TestGameUpdates(t *testing.T) {
 				}()
 			}
 
-			err := uc.GameUpdates(ctx, "some-game-id", "some-user-id", handler)
+			err := uc.GameUpdates(ctx, "some-game-id", "some-user-id", 0, handler)
 
 			if tc.wantErr != nil {
 				if err == nil || err.Error() != tc.wantErr.Error() {
@@ -663,61 +657,108 @@ func TestGameUpdates(t *testing.T) {
 	}
 }
 
-func TestGameState_TurnTimedOut(t *testing.T) {
-	clock := servicetest.NewFakeClock()
-	turnStartedAt := clock.Now()
-
-	mgr := &repotest.MockGameRepository{
-		GetPlayersMock: func(ctx context.Context, id string) ([]model.Player, error) {
-			return []model.Player{{ID: "some-user-id", Nickname: "SomeNick"}}, nil
-		},
-		GetLatestTurnMock: func(ctx context.Context, id string) (model.GameTurn, error) {
-			return model.GameTurn{
-				ID:        "some-turn-id",
-				WordID:    "some-word-id",
-				TellerID:  "teller-other",
-				CreatedAt: turnStartedAt,
-				StartedAt: turnStartedAt,
-			}, nil
-		},
-		GetMessagesMock: func(ctx context.Context, id string) ([]model.Message, error) {
-			return []model.Message{}, nil
-		},
-		GetScoresMock: func(ctx context.Context, id string) ([]model.Score, error) {
-			return []model.Score{}, nil
-		},
-	}
-
-	mwr := &repotest.MockWordRepository{
-		FindByIDMock: func(ctx context.Context, id string) (model.Word, error) {
-			return model.Word{ID: "some-word-id", Word: "Some Word", Hint: "Some Hint"}, nil
+func TestGameUpdates_ResumeReplaysMissedEvents(t *testing.T) {
+	ch := make(chan service.GameNotification, 1)
+	mgn := &servicetest.MockGameNotifier{
+		ResumeMock: func(gameID, userID string, lastEventID uint64) ([]service.Event, chan service.GameNotification, func()) {
+			assertCalledWith(t, "LastEventID", uint64(7), lastEventID)
+			missed := []service.Event{
+				{ID: 8, GameNotification: &usecase.GameJoinNotification{Nickname: "nick-1", PlayerID: "player-1"}},
+				{ID: 9, GameNotification: &usecase.UserLeftNotification{UserID: "u1"}},
+			}
+			return missed, ch, func() {}
 		},
 	}
+	uc := usecase.NewEmojixUsecase(nil, &repotest.MockGameRepository{}, nil, nil, mgn, &servicetest.MockGameLoop{}, service.NewRealClock())
 
-	// Advance the fake clock past the turn duration. turnDuration is 60s.
-	clock.Advance(time.Minute + time.Second)
-
-	emojixUsecase := usecase.NewEmojixUsecase(
-		nil,
-		mgr,
-		mwr,
-		nil,
-		nil,
-		&servicetest.MockGameLoop{},
-		clock,
-	)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch <- service.Event{ID: 10, GameNotification: &usecase.GameTurnEndNotification{}}
 
-	gameState, err := emojixUsecase.GameState(context.Background(), "some-game-id", "some-user-id")
+	var got []string
+	err := uc.GameUpdates(ctx, "some-game-id", "some-user-id", 7, func(eventID uint64, notifType, data string) error {
+		got = append(got, fmt.Sprintf("%d:%s", eventID, notifType))
+		if len(got) == 3 {
+			cancel()
+		}
+		return nil
+	})
 	if err != nil {
-		t.Fatal(err)
+		t.Fatalf("expected no error, got %v", err)
 	}
+	assertValue(t, "Events", "8:join 9:left 10:turnended", strings.Join(got, " "))
+}
 
-	if !gameState.TurnEnded {
-		t.Errorf("expected TurnEnded to be true after the clock advanced past turnDuration, but got false")
+func TestGameState_TurnEnded(t *testing.T) {
+	cases := []struct {
+		name  string
+		ended bool
+		want  bool
+	}{
+		// The deadline passing is not enough: the loop may be paused or
+		// running elsewhere, and only it decides when the turn is over.
+		{name: "past the deadline but not ended", ended: false, want: false},
+		{name: "ended by the loop", ended: true, want: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			clock := servicetest.NewFakeClock()
+			turnStartedAt := clock.Now()
+
+			mgr := &repotest.MockGameRepository{
+				GetPlayersMock: func(ctx context.Context, id string) ([]model.Player, error) {
+					return []model.Player{{ID: "some-user-id", Nickname: "SomeNick"}}, nil
+				},
+				GetLatestTurnMock: func(ctx context.Context, id string) (model.GameTurn, error) {
+					turn := model.GameTurn{
+						ID:        "some-turn-id",
+						WordID:    "some-word-id",
+						TellerID:  "teller-other",
+						CreatedAt: turnStartedAt,
+						StartedAt: turnStartedAt,
+					}
+					if tc.ended {
+						turn.EndedAt = turnStartedAt.Add(time.Minute)
+					}
+					return turn, nil
+				},
+				GetMessagesMock: func(ctx context.Context, id string) ([]model.Message, error) {
+					return []model.Message{}, nil
+				},
+				GetScoresMock: func(ctx context.Context, id string) ([]model.Score, error) {
+					return []model.Score{}, nil
+				},
+			}
+
+			mwr := &repotest.MockWordRepository{
+				FindByIDMock: func(ctx context.Context, id string) (model.Word, error) {
+					return model.Word{ID: "some-word-id", Word: "Some Word", Hint: "Some Hint"}, nil
+				},
+			}
+
+			// Advance the fake clock past the turn duration. turnDuration is 60s.
+			clock.Advance(time.Minute + time.Second)
+
+			emojixUsecase := usecase.NewEmojixUsecase(
+				nil,
+				mgr,
+				mwr,
+				nil,
+				nil,
+				&servicetest.MockGameLoop{},
+				clock,
+			)
+
+			gameState, err := emojixUsecase.GameState(context.Background(), "some-game-id", "some-user-id")
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertValue(t, "TurnEnded", tc.want, gameState.TurnEnded)
+		})
 	}
 }
 
-func newInitGameUsecase(t *testing.T, mur repository.UserRepository, mgr *repotest.MockGameRepository, mwr *repotest.MockWordRepository, gl *servicetest.MockGameLoop, commitErr error, newErr error) (usecase.EmojixUsecase, *repotest.MockUnitOfWork) {
+func newInitGameUsecase(t *testing.T, mur repository.UserRepository, mgr *repotest.MockGameRepository, mwr *repotest.MockWordRepository, gl *servicetest.MockGameLoop, commitErr error, newErr error, opts ...usecase.Option) (usecase.EmojixUsecase, *repotest.MockUnitOfWork) {
 	t.Helper()
 	uow := &repotest.MockUnitOfWork{
 		GameRepositoryMock: mgr,
@@ -732,7 +773,7 @@ func newInitGameUsecase(t *testing.T, mur repository.UserRepository, mgr *repote
 			return uow, newErr
 		},
 	}
-	uc := usecase.NewEmojixUsecase(mur, mgr, mwr, factory, nil, gl, service.NewRealClock())
+	uc := usecase.NewEmojixUsecase(mur, mgr, mwr, factory, nil, gl, service.NewRealClock(), opts...)
 	return uc, uow
 }
 
@@ -741,7 +782,7 @@ func TestInitGame(t *testing.T) {
 
 	t.Run("happy path waits for second player (no start yet)", func(t *testing.T) {
 		mgr := &repotest.MockGameRepository{
-			CreateMock: func(ctx context.Context, listID string) (model.Game, error) {
+			CreateMock: func(ctx context.Context, listID string, settings model.GameSettings) (model.Game, error) {
 				return model.Game{ID: "game-1"}, nil
 			},
 			AddPlayerMock: func(ctx context.Context, gameID, playerID string) error {
@@ -757,7 +798,7 @@ func TestInitGame(t *testing.T) {
 		gl := &servicetest.MockGameLoop{}
 		uc, uow := newInitGameUsecase(t, nil, mgr, mwr, gl, nil, nil)
 
-		game, err := uc.InitGame(context.Background(), userID, "list-1")
+		game, err := uc.InitGame(context.Background(), userID, "list-1", model.GameSettings{})
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -778,6 +819,84 @@ func TestInitGame(t *testing.T) {
 		}
 	})
 
+	t.Run("zero settings are persisted as defaults", func(t *testing.T) {
+		var got model.GameSettings
+		mgr := &repotest.MockGameRepository{
+			CreateMock: func(ctx context.Context, listID string, settings model.GameSettings) (model.Game, error) {
+				got = settings
+				return model.Game{ID: "game-1", Settings: settings}, nil
+			},
+			AddPlayerMock: func(ctx context.Context, gameID, playerID string) error { return nil },
+			GetPlayersMock: func(ctx context.Context, id string) ([]model.Player, error) {
+				return []model.Player{{ID: userID, State: model.ActivePlayerState}}, nil
+			},
+		}
+		uc, _ := newInitGameUsecase(t, nil, mgr, &repotest.MockWordRepository{}, &servicetest.MockGameLoop{}, nil, nil)
+
+		_, err := uc.InitGame(context.Background(), userID, "list-1", model.GameSettings{MaxPlayers: 4})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := usecase.DefaultGameSettings()
+		want.MaxPlayers = 4
+		assertValue(t, "Settings", want, got)
+	})
+
+	t.Run("server defaults fill unset settings", func(t *testing.T) {
+		var got model.GameSettings
+		mgr := &repotest.MockGameRepository{
+			CreateMock: func(ctx context.Context, listID string, settings model.GameSettings) (model.Game, error) {
+				got = settings
+				return model.Game{ID: "game-1", Settings: settings}, nil
+			},
+			AddPlayerMock: func(ctx context.Context, gameID, playerID string) error { return nil },
+			GetPlayersMock: func(ctx context.Context, id string) ([]model.Player, error) {
+				return []model.Player{{ID: userID, State: model.ActivePlayerState}}, nil
+			},
+		}
+		uc, _ := newInitGameUsecase(t, nil, mgr, &repotest.MockWordRepository{}, &servicetest.MockGameLoop{}, nil, nil,
+			usecase.WithGameDefaults(model.GameSettings{TurnDuration: 90 * time.Second, Rounds: 3}))
+
+		_, err := uc.InitGame(context.Background(), userID, "list-1", model.GameSettings{Rounds: 2})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := usecase.DefaultGameSettings()
+		want.TurnDuration = 90 * time.Second
+		want.Rounds = 2
+		assertValue(t, "Settings", want, got)
+	})
+
+	t.Run("invalid settings rejected before any write", func(t *testing.T) {
+		cases := []model.GameSettings{
+			{TurnDuration: time.Second},
+			{PickDuration: time.Hour},
+			{MaxPlayers: 1},
+			{MaxPlayers: 100},
+			{MinPlayers: 5, MaxPlayers: 4},
+			{Rounds: 11},
+			{Teams: true, StealDelay: -time.Second},
+			{Teams: true, TurnDuration: 30 * time.Second, StealDelay: 30 * time.Second},
+			{Scoring: "golf"},
+			{Reveals: "80,50"},
+			{Reveals: "0"},
+			{Reveals: "10,20,30,40,50,60"},
+		}
+		for _, settings := range cases {
+			mgr := &repotest.MockGameRepository{}
+			gl := &servicetest.MockGameLoop{}
+			uc, _ := newInitGameUsecase(t, nil, mgr, &repotest.MockWordRepository{}, gl, nil, nil)
+
+			_, err := uc.InitGame(context.Background(), userID, "list-1", settings)
+			if !errors.Is(err, usecase.ErrInvalidGameSettings) {
+				t.Errorf("settings %+v: expected ErrInvalidGameSettings, got %v", settings, err)
+			}
+			if mgr.CreateCalled || gl.StartCalled {
+				t.Errorf("settings %+v: no writes or start expected", settings)
+			}
+		}
+	})
+
 	t.Run("uow.New fails", func(t *testing.T) {
 		mgr := &repotest.MockGameRepository{}
 		mwr := &repotest.MockWordRepository{}
@@ -785,7 +904,7 @@ func TestInitGame(t *testing.T) {
 		newErr := errors.New("uow new failed")
 		uc, _ := newInitGameUsecase(t, nil, mgr, mwr, gl, nil, newErr)
 
-		_, err := uc.InitGame(context.Background(), userID, "list-1")
+		_, err := uc.InitGame(context.Background(), userID, "list-1", model.GameSettings{})
 		if !errors.Is(err, newErr) {
 			t.Fatalf("expected newErr, got %v", err)
 		}
@@ -799,7 +918,7 @@ func TestInitGame(t *testing.T) {
 
 	t.Run("gameRepo.Create fails rolls back and does not start", func(t *testing.T) {
 		mgr := &repotest.MockGameRepository{
-			CreateMock: func(ctx context.Context, listID string) (model.Game, error) {
+			CreateMock: func(ctx context.Context, listID string, settings model.GameSettings) (model.Game, error) {
 				return model.Game{}, errors.New("create failed")
 			},
 		}
@@ -807,7 +926,7 @@ func TestInitGame(t *testing.T) {
 		gl := &servicetest.MockGameLoop{}
 		uc, uow := newInitGameUsecase(t, nil, mgr, mwr, gl, nil, nil)
 
-		_, err := uc.InitGame(context.Background(), userID, "list-1")
+		_, err := uc.InitGame(context.Background(), userID, "list-1", model.GameSettings{})
 		if err == nil {
 			t.Fatal("expected error from Create")
 		}
@@ -1020,6 +1139,9 @@ func TestGuess(t *testing.T) {
 	// baseGameRepo wires the latest turn + a SendMessage that returns a message.
 	baseGameRepo := func() *repotest.MockGameRepository {
 		return &repotest.MockGameRepository{
+			GetPlayersMock: func(ctx context.Context, id string) ([]model.Player, error) {
+				return []model.Player{{ID: userID, State: model.ActivePlayerState}}, nil
+			},
 			GetLatestTurnMock: func(ctx context.Context, id string) (model.GameTurn, error) {
 				assertCalledWith(t, "GameID", gameID, id)
 				return model.GameTurn{ID: turnID, WordID: wordID, TellerID: "teller-other", StartedAt: time.Now().Add(-time.Second)}, nil
@@ -1032,7 +1154,6 @@ func TestGuess(t *testing.T) {
 
 	t.Run("wrong guess publishes raw content after commit and scores nothing", func(t *testing.T) {
 		mgr := baseGameRepo()
-		mgr.GetPlayersMock = func(ctx context.Context, id string) ([]model.Player, error) { return nil, nil }
 		mgr.GetScoresMock = func(ctx context.Context, id string) ([]model.Score, error) { return nil, nil }
 		mur := &repotest.MockUserRepository{
 			FindByIDMock: func(ctx context.Context, id string) (model.User, error) {
@@ -1071,6 +1192,106 @@ func TestGuess(t *testing.T) {
 		}
 	})
 
+	t.Run("timed scoring writes the wrong-guess penalty in the unit of work", func(t *testing.T) {
+		mgr := baseGameRepo()
+		mgr.FindByIDMock = func(ctx context.Context, id string) (model.Game, error) {
+			return model.Game{ID: id, Settings: model.GameSettings{Scoring: usecase.TimedScoring}}, nil
+		}
+		var gotUser, gotMsg string
+		var gotScore int
+		mgr.AddScoreMock = func(ctx context.Context, g, u, msg, turn string, point int) error {
+			gotUser, gotMsg, gotScore = u, msg, point
+			return nil
+		}
+		mur := &repotest.MockUserRepository{
+			FindByIDMock: func(ctx context.Context, id string) (model.User, error) {
+				return model.User{ID: userID, Nickname: "Nick1"}, nil
+			},
+		}
+		pubCh := make(chan service.GameNotification, 1)
+		mgn := &servicetest.MockGameNotifier{
+			PubMock: func(g, u string, n service.GameNotification) { pubCh <- n },
+		}
+		uc, uow := newGuessUsecase(mur, mgr, baseWordRepo(), mgn, &servicetest.MockGameLoop{}, nil)
+
+		if _, err := uc.Guess(context.Background(), gameID, userID, "nope"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		assertValue(t, "AddScore user", userID, gotUser)
+		assertValue(t, "AddScore message", "msg-1", gotMsg)
+		assertValue(t, "AddScore points", -1, gotScore)
+		if !uow.CommitCalled {
+			t.Error("expected the penalty to be committed with the message")
+		}
+		drainPub(t, pubCh, 1)
+	})
+
+	t.Run("close guess is told only to the guesser", func(t *testing.T) {
+		mgr := baseGameRepo()
+		mur := &repotest.MockUserRepository{
+			FindByIDMock: func(ctx context.Context, id string) (model.User, error) {
+				return model.User{ID: userID, Nickname: "Nick1"}, nil
+			},
+		}
+		pubCh := make(chan service.GameNotification, 1)
+		pubToCh := make(chan service.GameNotification, 1)
+		mgn := &servicetest.MockGameNotifier{
+			PubMock: func(g, u string, n service.GameNotification) { pubCh <- n },
+			PubToMock: func(g, u string, n service.GameNotification) {
+				assertCalledWith(t, "UserID", userID, u)
+				pubToCh <- n
+			},
+		}
+		gl := &servicetest.MockGameLoop{}
+		uc, _ := newGuessUsecase(mur, mgr, baseWordRepo(), mgn, gl, nil)
+
+		correct, err := uc.Guess(context.Background(), gameID, userID, "secrt")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if correct {
+			t.Error("close guess must not be correct")
+		}
+		if mgr.AddScoreCalled {
+			t.Error("AddScore must not be called on a close guess")
+		}
+		if pub := drainPub(t, pubCh, 1)[0]; pub.GetType() != "msg" || pub.GetData() != userID+",Nick1,Nick1 is close,1" {
+			t.Errorf("broadcast: got %q %q, want the near miss masked", pub.GetType(), pub.GetData())
+		}
+		close := drainPub(t, pubToCh, 1)[0]
+		if close.GetType() != "close" || close.GetData() != "secrt" {
+			t.Errorf("private notif: got %q %q", close.GetType(), close.GetData())
+		}
+	})
+
+	t.Run("fuzzy match scores like an exact guess", func(t *testing.T) {
+		mgr := baseGameRepo()
+		mgr.GetPlayersMock = func(ctx context.Context, id string) ([]model.Player, error) {
+			return []model.Player{{ID: userID, Nickname: "Nick1", State: model.ActivePlayerState}}, nil
+		}
+		mgr.GetScoresMock = func(ctx context.Context, id string) ([]model.Score, error) { return nil, nil }
+		mgr.AddScoreMock = func(ctx context.Context, g, u, msg, turn string, point int) error { return nil }
+		mur := &repotest.MockUserRepository{
+			FindByIDMock: func(ctx context.Context, id string) (model.User, error) {
+				return model.User{ID: userID, Nickname: "Nick1"}, nil
+			},
+		}
+		mgn := &servicetest.MockGameNotifier{}
+		gl := &servicetest.MockGameLoop{}
+		uc, _ := newGuessUsecase(mur, mgr, baseWordRepo(), mgn, gl, nil)
+
+		correct, err := uc.Guess(context.Background(), gameID, userID, " SECRET! ")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !correct {
+			t.Error("expected normalized guess to be correct")
+		}
+		if !mgr.AddScoreCalled {
+			t.Error("expected AddScore on a fuzzy-correct guess")
+		}
+	})
+
 	t.Run("correct first guess scores points and pubs guessed but does not end turn", func(t *testing.T) {
 		mgr := baseGameRepo()
 		mgr.GetPlayersMock = func(ctx context.Context, id string) ([]model.Player, error) {
@@ -1148,8 +1369,8 @@ func TestGuess(t *testing.T) {
 		}
 		mgr.GetScoresMock = func(ctx context.Context, id string) ([]model.Score, error) {
 			return []model.Score{
-				{PlayerID: "p-2", TurnID: turnID},
-				{PlayerID: "p-3", TurnID: turnID},
+				{PlayerID: "p-2", TurnID: turnID, Score: 10},
+				{PlayerID: "p-3", TurnID: turnID, Score: 10},
 			}, nil
 		}
 		endGameTurnCalled := make(chan struct{}, 1)
@@ -1309,6 +1530,30 @@ func TestGuess(t *testing.T) {
 		assertNoPub(t, pubCh)
 	})
 
+	t.Run("guess after the turn ended is refused without writes or pub", func(t *testing.T) {
+		mgr := baseGameRepo()
+		mgr.GetLatestTurnMock = func(ctx context.Context, id string) (model.GameTurn, error) {
+			return model.GameTurn{ID: turnID, WordID: wordID, TellerID: "teller-other", StartedAt: time.Now().Add(-time.Minute), EndedAt: time.Now()}, nil
+		}
+		mur := &repotest.MockUserRepository{
+			FindByIDMock: func(ctx context.Context, id string) (model.User, error) {
+				return model.User{ID: userID, Nickname: "Nick1"}, nil
+			},
+		}
+		pubCh := make(chan service.GameNotification, 1)
+		mgn := &servicetest.MockGameNotifier{PubMock: func(g, u string, n service.GameNotification) { pubCh <- n }}
+		uc, uow := newGuessUsecase(mur, mgr, baseWordRepo(), mgn, &servicetest.MockGameLoop{}, nil)
+
+		_, err := uc.Guess(context.Background(), gameID, userID, theWord)
+		if !errors.Is(err, usecase.ErrTurnOver) {
+			t.Fatalf("err = %v, want ErrTurnOver", err)
+		}
+		if mgr.SendMessageCalled || uow.CommitCalled || mgr.AddScoreCalled {
+			t.Error("no writes expected once the turn is over")
+		}
+		assertNoPub(t, pubCh)
+	})
+
 	t.Run("SendMessage fails propagates without AddScore or pub", func(t *testing.T) {
 		mgr := baseGameRepo()
 		mgr.SendMessageMock = func(ctx context.Context, g, turn, u, content string) (model.Message, error) {
@@ -1365,8 +1610,8 @@ func TestGuess(t *testing.T) {
 		}
 		mgr.GetScoresMock = func(ctx context.Context, id string) ([]model.Score, error) {
 			return []model.Score{
-				{PlayerID: "p-2", TurnID: turnID},
-				{PlayerID: "p-3", TurnID: turnID},
+				{PlayerID: "p-2", TurnID: turnID, Score: 10},
+				{PlayerID: "p-3", TurnID: turnID, Score: 10},
 			}, nil
 		}
 		endGameTurnCalled := make(chan struct{}, 1)
@@ -1403,6 +1648,9 @@ func TestMessage(t *testing.T) {
 		userID = "p-1"
 		turnID = "turn-1"
 	)
+	seated := func(ctx context.Context, id string) ([]model.Player, error) {
+		return []model.Player{{ID: userID, State: model.ActivePlayerState}}, nil
+	}
 	murFor := func(nick string, err error) *repotest.MockUserRepository {
 		return &repotest.MockUserRepository{
 			FindByIDMock: func(ctx context.Context, id string) (model.User, error) {
@@ -1414,6 +1662,7 @@ func TestMessage(t *testing.T) {
 
 	t.Run("happy path persists and pubs raw content; ParseData round-trips", func(t *testing.T) {
 		mgr := &repotest.MockGameRepository{
+			GetPlayersMock: seated,
 			GetLatestTurnMock: func(ctx context.Context, id string) (model.GameTurn, error) {
 				assertCalledWith(t, "GameID", gameID, id)
 				return model.GameTurn{TellerID: "teller-other", StartedAt: time.Now().Add(-time.Second), ID: turnID}, nil
@@ -1458,13 +1707,9 @@ func TestMessage(t *testing.T) {
 		}
 	})
 
-	t.Run("content equal to the secret word is published unmasked", func(t *testing.T) {
-		// NOTE: Message deliberately does NOT mask chat content, even when it
-		// equals the secret word. This is a game-integrity gap (a user can
-		// type the literal word and it is broadcast unmasked). Pinning current
-		// behavior here; masking is a behavior decision tracked as backlog.
-		// TODO(backlog): mask chat content matching the secret word in Message.
+	t.Run("content matching the secret word is masked before broadcast", func(t *testing.T) {
 		mgr := &repotest.MockGameRepository{
+			GetPlayersMock: seated,
 			GetLatestTurnMock: func(ctx context.Context, id string) (model.GameTurn, error) {
 				return model.GameTurn{TellerID: "teller-other", StartedAt: time.Now().Add(-time.Second), ID: turnID, WordID: "w-1"}, nil
 			},
@@ -1472,7 +1717,6 @@ func TestMessage(t *testing.T) {
 				return model.Message{ID: "m-1"}, nil
 			},
 		}
-		// Provided for documentation; Message does not consult the word repo.
 		mwr := &repotest.MockWordRepository{
 			FindByIDMock: func(ctx context.Context, id string) (model.Word, error) {
 				return model.Word{ID: "w-1", Word: "Secret"}, nil
@@ -1482,17 +1726,58 @@ func TestMessage(t *testing.T) {
 		mgn := &servicetest.MockGameNotifier{PubMock: func(g, u string, n service.GameNotification) { pubCh <- n }}
 		uc := usecase.NewEmojixUsecase(murFor("Nick1", nil), mgr, mwr, nil, mgn, &servicetest.MockGameLoop{}, service.NewRealClock())
 
-		if err := uc.Message(context.Background(), gameID, userID, "Secret"); err != nil {
-			t.Fatalf("unexpected error: %v", err)
+		for content, want := range map[string]string{
+			"Secret": userID + ",Nick1,Nick1 got it!,1",
+			"secrt":  userID + ",Nick1,Nick1 is close,1",
+			"hello":  userID + ",Nick1,hello",
+		} {
+			if err := uc.Message(context.Background(), gameID, userID, content); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := drainPub(t, pubCh, 1)[0].GetData(); got != want {
+				t.Errorf("%s: pub data = %q, want %q", content, got, want)
+			}
 		}
-		n := drainPub(t, pubCh, 1)[0]
-		if got := n.GetData(); got != userID+",Nick1,Secret" {
-			t.Errorf("expected unmasked secret word in pub data, got %q", got)
+	})
+
+	t.Run("masking follows the configured matcher", func(t *testing.T) {
+		mgr := &repotest.MockGameRepository{
+			GetPlayersMock: seated,
+			GetLatestTurnMock: func(ctx context.Context, id string) (model.GameTurn, error) {
+				return model.GameTurn{TellerID: "teller-other", StartedAt: time.Now().Add(-time.Second), ID: turnID, WordID: "w-1"}, nil
+			},
+			SendMessageMock: func(ctx context.Context, g, turn, u, content string) (model.Message, error) {
+				return model.Message{ID: "m-1"}, nil
+			},
+		}
+		mwr := &repotest.MockWordRepository{
+			FindByIDMock: func(ctx context.Context, id string) (model.Word, error) {
+				return model.Word{ID: "w-1", Word: "Secret"}, nil
+			},
+		}
+		pubCh := make(chan service.GameNotification, 1)
+		mgn := &servicetest.MockGameNotifier{PubMock: func(g, u string, n service.GameNotification) { pubCh <- n }}
+		uc := usecase.NewEmojixUsecase(murFor("Nick1", nil), mgr, mwr, nil, mgn, &servicetest.MockGameLoop{}, service.NewRealClock(),
+			usecase.WithGuessMatcher(exactMatcher{}))
+
+		// The default matcher would take SECRET and call secrt close.
+		for content, want := range map[string]string{
+			"Secret": userID + ",Nick1,Nick1 got it!,1",
+			"secrt":  userID + ",Nick1,secrt",
+			"SECRET": userID + ",Nick1,SECRET",
+		} {
+			if err := uc.Message(context.Background(), gameID, userID, content); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := drainPub(t, pubCh, 1)[0].GetData(); got != want {
+				t.Errorf("%s: pub data = %q, want %q", content, got, want)
+			}
 		}
 	})
 
 	t.Run("GetLatestTurn fails propagates without SendMessage or pub", func(t *testing.T) {
 		mgr := &repotest.MockGameRepository{
+			GetPlayersMock: seated,
 			GetLatestTurnMock: func(ctx context.Context, id string) (model.GameTurn, error) {
 				return model.GameTurn{TellerID: "teller-other", StartedAt: time.Now().Add(-time.Second)}, errors.New("turn failed")
 			},
@@ -1517,6 +1802,7 @@ func TestMessage(t *testing.T) {
 
 	t.Run("userRepo.FindByID fails propagates without SendMessage or pub", func(t *testing.T) {
 		mgr := &repotest.MockGameRepository{
+			GetPlayersMock: seated,
 			GetLatestTurnMock: func(ctx context.Context, id string) (model.GameTurn, error) {
 				return model.GameTurn{TellerID: "teller-other", StartedAt: time.Now().Add(-time.Second), ID: turnID}, nil
 			},
@@ -1541,6 +1827,7 @@ func TestMessage(t *testing.T) {
 
 	t.Run("SendMessage fails propagates without pub", func(t *testing.T) {
 		mgr := &repotest.MockGameRepository{
+			GetPlayersMock: seated,
 			GetLatestTurnMock: func(ctx context.Context, id string) (model.GameTurn, error) {
 				return model.GameTurn{TellerID: "teller-other", StartedAt: time.Now().Add(-time.Second), ID: turnID}, nil
 			},
@@ -1561,6 +1848,7 @@ func TestMessage(t *testing.T) {
 
 	t.Run("empty content rejected", func(t *testing.T) {
 		mgr := &repotest.MockGameRepository{
+			GetPlayersMock: seated,
 			GetLatestTurnMock: func(ctx context.Context, id string) (model.GameTurn, error) {
 				t.Error("must not load turn for empty message")
 				return model.GameTurn{}, nil
@@ -1575,6 +1863,7 @@ func TestMessage(t *testing.T) {
 
 	t.Run("teller cannot message before picking a word", func(t *testing.T) {
 		mgr := &repotest.MockGameRepository{
+			GetPlayersMock: seated,
 			GetLatestTurnMock: func(ctx context.Context, id string) (model.GameTurn, error) {
 				return model.GameTurn{TellerID: userID, ID: turnID, WordID: ""}, nil
 			},
@@ -1593,6 +1882,7 @@ func TestMessage(t *testing.T) {
 	t.Run("teller emoji message takes penalty from current-turn points only", func(t *testing.T) {
 		var gotScore int
 		mgr := &repotest.MockGameRepository{
+			GetPlayersMock: seated,
 			GetLatestTurnMock: func(ctx context.Context, id string) (model.GameTurn, error) {
 				return model.GameTurn{TellerID: userID, StartedAt: time.Now().Add(-time.Second), ID: turnID, WordID: "w-1"}, nil
 			},
@@ -1616,7 +1906,7 @@ func TestMessage(t *testing.T) {
 		}
 		pubCh := make(chan service.GameNotification, 1)
 		mgn := &servicetest.MockGameNotifier{PubMock: func(g, u string, n service.GameNotification) { pubCh <- n }}
-		uc := usecase.NewEmojixUsecase(murFor("Teller", nil), mgr, nil, nil, mgn, &servicetest.MockGameLoop{}, service.NewRealClock())
+		uc, _ := newGuessUsecase(murFor("Teller", nil), mgr, nil, mgn, &servicetest.MockGameLoop{}, nil)
 
 		if err := uc.Message(context.Background(), gameID, userID, "🔥🍎"); err != nil {
 			t.Fatalf("Message: %v", err)
@@ -1633,6 +1923,7 @@ func TestMessage(t *testing.T) {
 	t.Run("teller emoji penalty clamps to remaining turn points", func(t *testing.T) {
 		var gotScore int
 		mgr := &repotest.MockGameRepository{
+			GetPlayersMock: seated,
 			GetLatestTurnMock: func(ctx context.Context, id string) (model.GameTurn, error) {
 				return model.GameTurn{TellerID: userID, StartedAt: time.Now().Add(-time.Second), ID: turnID, WordID: "w-1"}, nil
 			},
@@ -1647,7 +1938,7 @@ func TestMessage(t *testing.T) {
 				return nil
 			},
 		}
-		uc := usecase.NewEmojixUsecase(murFor("Teller", nil), mgr, nil, nil, &servicetest.MockGameNotifier{}, &servicetest.MockGameLoop{}, service.NewRealClock())
+		uc, _ := newGuessUsecase(murFor("Teller", nil), mgr, nil, &servicetest.MockGameNotifier{}, &servicetest.MockGameLoop{}, nil)
 		if err := uc.Message(context.Background(), gameID, userID, "👍"); err != nil {
 			t.Fatalf("Message: %v", err)
 		}
@@ -1658,6 +1949,7 @@ func TestMessage(t *testing.T) {
 
 	t.Run("teller emoji with zero turn points applies no penalty", func(t *testing.T) {
 		mgr := &repotest.MockGameRepository{
+			GetPlayersMock: seated,
 			GetLatestTurnMock: func(ctx context.Context, id string) (model.GameTurn, error) {
 				return model.GameTurn{TellerID: userID, StartedAt: time.Now().Add(-time.Second), ID: turnID, WordID: "w-1"}, nil
 			},
@@ -1672,7 +1964,7 @@ func TestMessage(t *testing.T) {
 				return nil
 			},
 		}
-		uc := usecase.NewEmojixUsecase(murFor("Teller", nil), mgr, nil, nil, &servicetest.MockGameNotifier{}, &servicetest.MockGameLoop{}, service.NewRealClock())
+		uc, _ := newGuessUsecase(murFor("Teller", nil), mgr, nil, &servicetest.MockGameNotifier{}, &servicetest.MockGameLoop{}, nil)
 		if err := uc.Message(context.Background(), gameID, userID, "👍"); err != nil {
 			t.Fatalf("Message: %v", err)
 		}
@@ -1683,6 +1975,7 @@ func TestMessage(t *testing.T) {
 
 	t.Run("teller text message rejected without send or score", func(t *testing.T) {
 		mgr := &repotest.MockGameRepository{
+			GetPlayersMock: seated,
 			GetLatestTurnMock: func(ctx context.Context, id string) (model.GameTurn, error) {
 				return model.GameTurn{TellerID: userID, StartedAt: time.Now().Add(-time.Second), ID: turnID, WordID: "w-1"}, nil
 			},
@@ -1708,6 +2001,7 @@ func TestMessage(t *testing.T) {
 
 	t.Run("guesser free text does not apply score penalty", func(t *testing.T) {
 		mgr := &repotest.MockGameRepository{
+			GetPlayersMock: seated,
 			GetLatestTurnMock: func(ctx context.Context, id string) (model.GameTurn, error) {
 				return model.GameTurn{TellerID: "teller-other", StartedAt: time.Now().Add(-time.Second), ID: turnID}, nil
 			},
@@ -1935,7 +2229,7 @@ func TestGameWord(t *testing.T) {
 			},
 			GetScoresMock: func(ctx context.Context, id string) ([]model.Score, error) {
 				// a score for another player, but not the current user
-				return []model.Score{{PlayerID: "p-2", TurnID: turnID}}, nil
+				return []model.Score{{PlayerID: "p-2", TurnID: turnID, Score: 10}}, nil
 			},
 		}
 		uc := usecase.NewEmojixUsecase(nil, mgr, wordRepoFor(model.Word{ID: wordID, Word: "Secret"}), nil, nil, &servicetest.MockGameLoop{}, service.NewRealClock())
@@ -1955,7 +2249,7 @@ func TestGameWord(t *testing.T) {
 				return model.GameTurn{ID: turnID, WordID: wordID, TellerID: "teller-other", StartedAt: time.Now().Add(-time.Second)}, nil
 			},
 			GetScoresMock: func(ctx context.Context, id string) ([]model.Score, error) {
-				return []model.Score{{PlayerID: userID, TurnID: turnID}}, nil
+				return []model.Score{{PlayerID: userID, TurnID: turnID, Score: 10}}, nil
 			},
 		}
 		uc := usecase.NewEmojixUsecase(nil, mgr, wordRepoFor(model.Word{ID: wordID, Word: "Secret"}), nil, nil, &servicetest.MockGameLoop{}, service.NewRealClock())
@@ -1969,8 +2263,8 @@ func TestGameWord(t *testing.T) {
 		}
 	})
 
-	t.Run("non-word chars leak unmasked through the regex", func(t *testing.T) {
-		// The mask regex is `\w`, so spaces/punctuation/emoji are not replaced.
+	t.Run("non-word chars leak unmasked through the mask", func(t *testing.T) {
+		// The mask only covers `\w` characters, so spaces/punctuation/emoji are not replaced.
 		// This documents the limitation; a better masking scheme is backlog.
 		// TODO(backlog): replace the `\w`-based mask with a scheme that hides
 		// the whole word regardless of character class.
@@ -2116,7 +2410,7 @@ func TestOnTurnEnd(t *testing.T) {
 			PubAllMock: func(g string, n service.GameNotification) {
 				assertCalledWith(t, "GameID", gameID, g)
 				switch n.GetType() {
-				case "turnended", "newturn":
+				case "turnended", "newturn", "gameover":
 				default:
 					t.Errorf("PubAll notif type: got %q", n.GetType())
 				}
@@ -2224,10 +2518,10 @@ func TestOnTurnEnd(t *testing.T) {
 		}
 	})
 
-	t.Run("empty word list: two GetAll, zero AddTurn, one StopGame", func(t *testing.T) {
+	t.Run("empty word list: game over, zero AddTurn, one StopGame", func(t *testing.T) {
 		// Exercises the ErrNoWords guard inside onTurnEnd: pickWordOptions
-		// would panic on Intn(0) without the guard, so this stays a clean
-		// error path that retries once then stops the game.
+		// would panic on Intn(0) without the guard. A drained list ends the
+		// match instead of retrying.
 		gl, clock, m := newUsecase(t,
 			func(call int) (model.GameTurn, error) {
 				t.Error("AddTurn must not be called when there are no words")
@@ -2238,11 +2532,11 @@ func TestOnTurnEnd(t *testing.T) {
 		)
 		runHandler(t, gl, clock)
 
-		if m.pubAllCount != 1 {
-			t.Errorf("PubAll count: got %d, want 1", m.pubAllCount)
+		if m.pubAllCount != 2 {
+			t.Errorf("PubAll count: got %d, want 2 (turnended+gameover)", m.pubAllCount)
 		}
-		if m.unusedCount != 2 {
-			t.Errorf("GetUnusedByList count: got %d, want 2", m.unusedCount)
+		if m.unusedCount != 1 {
+			t.Errorf("GetUnusedByList count: got %d, want 1", m.unusedCount)
 		}
 		if m.addTurnCount != 0 {
 			t.Errorf("AddTurn count: got %d, want 0", m.addTurnCount)
@@ -2290,6 +2584,53 @@ func TestOnTurnEnd(t *testing.T) {
 		}
 	})
 
+	t.Run("every player told their rounds: finished, gameover, no new turn", func(t *testing.T) {
+		m := &onTurnEndMocks{
+			pubAllCh: make(chan struct{}, 4),
+			stopCh:   make(chan string, 4),
+		}
+		types := []string{}
+		mgr := &repotest.MockGameRepository{
+			FindByIDMock: func(ctx context.Context, id string) (model.Game, error) {
+				return model.Game{ID: id, ListID: "list-1", Settings: model.GameSettings{Rounds: 2}}, nil
+			},
+			GetPlayersMock: func(ctx context.Context, id string) ([]model.Player, error) {
+				return []model.Player{
+					{ID: "p1", State: model.ActivePlayerState},
+					{ID: "p2", State: model.ActivePlayerState},
+				}, nil
+			},
+			GetTurnsMock: func(ctx context.Context, id string) ([]model.GameTurn, error) {
+				return []model.GameTurn{{TellerID: "p1"}, {TellerID: "p2"}, {TellerID: "p1"}, {TellerID: "p2"}}, nil
+			},
+			AddTurnMock: func(ctx context.Context, params repository.AddTurnParams) (model.GameTurn, error) {
+				t.Error("AddTurn must not run once the rounds are done")
+				return model.GameTurn{}, nil
+			},
+		}
+		mgn := &servicetest.MockGameNotifier{
+			PubAllMock: func(g string, n service.GameNotification) {
+				types = append(types, n.GetType())
+				m.pubAllCh <- struct{}{}
+			},
+		}
+		gl := &servicetest.MockGameLoop{
+			StopGameMock: func(g string) {
+				m.stopCount++
+				m.stopCh <- g
+			},
+		}
+		clock := servicetest.NewFakeClock()
+		_ = usecase.NewEmojixUsecase(nil, mgr, nil, nil, mgn, gl, clock)
+		runHandler(t, gl, clock)
+
+		assertValue(t, "PubAll types", []string{"turnended", "gameover"}, types)
+		assertValue(t, "SetStatus", model.FinishedGameStatus, mgr.SetStatusLastStatus)
+		if m.stopCount != 1 {
+			t.Errorf("StopGame count: got %d, want 1", m.stopCount)
+		}
+	})
+
 }
 
 func TestPickWord(t *testing.T) {
@@ -2304,14 +2645,16 @@ func TestPickWord(t *testing.T) {
 	}
 
 	t.Run("seeds emoji_hint from word.Hint and begins turn", func(t *testing.T) {
+		fc := servicetest.NewFakeClock()
 		mgr := &repotest.MockGameRepository{
 			GetLatestTurnMock: func(ctx context.Context, id string) (model.GameTurn, error) {
 				return baseTurn, nil
 			},
-			SetTurnWordMock: func(ctx context.Context, tid, wid, hint string) error {
+			SetTurnWordMock: func(ctx context.Context, tid, wid, hint string, endDeadline time.Time) error {
 				assertCalledWith(t, "TurnID", turnID, tid)
 				assertCalledWith(t, "WordID", wordID, wid)
 				assertCalledWith(t, "EmojiHint", "🍎🍌", hint)
+				assertCalledWith(t, "EndDeadline", fc.Now().Add(usecase.DefaultGameSettings().TurnDuration), endDeadline)
 				return nil
 			},
 		}
@@ -2332,7 +2675,7 @@ func TestPickWord(t *testing.T) {
 				pubAllCh <- n
 			},
 		}
-		uc := usecase.NewEmojixUsecase(nil, mgr, mwr, nil, mgn, gl, service.NewRealClock())
+		uc := usecase.NewEmojixUsecase(nil, mgr, mwr, nil, mgn, gl, fc)
 
 		if err := uc.PickWord(context.Background(), gameID, tellerID, wordID); err != nil {
 			t.Fatalf("PickWord: %v", err)
@@ -0,0 +1,78 @@
+package usecase
+
+import (
+	"database/sql"
+	"errors"
+)
+
+// ErrorKind says what sort of refusal an error is, so transports can pick a
+// status without knowing every sentinel.
+type ErrorKind int
+
+const (
+	KindInternal    ErrorKind = iota // unclassified: a bug or a storage failure
+	KindValidation                   // the input itself is wrong
+	KindForbidden                    // the caller may not do this
+	KindNotFound                     // the thing asked for doesn't exist
+	KindConflict                     // not possible in the current state
+	KindGone                         // the game is over for good
+	KindUnavailable                  // try again later, e.g. while restarting
+	KindRateLimited                  // too many requests; slow down
+)
+
+func (k ErrorKind) String() string {
+	switch k {
+	case KindValidation:
+		return "validation"
+	case KindForbidden:
+		return "forbidden"
+	case KindNotFound:
+		return "not_found"
+	case KindConflict:
+		return "conflict"
+	case KindGone:
+		return "gone"
+	case KindUnavailable:
+		return "unavailable"
+	case KindRateLimited:
+		return "rate_limited"
+	default:
+		return "internal"
+	}
+}
+
+// Error is a classified usecase error. The sentinels below are all *Error,
+// so errors.Is keeps working and KindOf sees through fmt.Errorf wrapping.
+type Error struct {
+	Kind ErrorKind
+	msg  string
+	err  error
+}
+
+func (e *Error) Error() string {
+	if e.msg == "" && e.err != nil {
+		return e.err.Error()
+	}
+	return e.msg
+}
+
+func (e *Error) Unwrap() error { return e.err }
+
+// NewError returns a classified error; transports use it for their own input
+// checks so those are reported like usecase refusals.
+func NewError(kind ErrorKind, msg string) error {
+	return &Error{Kind: kind, msg: msg}
+}
+
+// KindOf classifies err. A bare sql.ErrNoRows from a lookup is NotFound;
+// anything unclassified is Internal.
+func KindOf(err error) ErrorKind {
+	var ue *Error
+	if errors.As(err, &ue) {
+		return ue.Kind
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return KindNotFound
+	}
+	return KindInternal
+}
@@ -0,0 +1,41 @@
+package usecase_test
+
+import (
+	"database/sql"
+	"emojix/repository"
+	"emojix/usecase"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestKindOf(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want usecase.ErrorKind
+	}{
+		{"sentinel", usecase.ErrTellerEmojiOnly, usecase.KindValidation},
+		{"wrapped sentinel", fmt.Errorf("%w: unknown scoring", usecase.ErrInvalidGameSettings), usecase.KindValidation},
+		{"forbidden", usecase.ErrTellerCannotGuess, usecase.KindForbidden},
+		{"missing row", fmt.Errorf("find game: %w", sql.ErrNoRows), usecase.KindNotFound},
+		{"conflict", usecase.ErrJoinGameRoomFull, usecase.KindConflict},
+		{"gone", usecase.ErrGameFinished, usecase.KindGone},
+		{"unavailable", usecase.ErrShuttingDown, usecase.KindUnavailable},
+		{"rate limited", &usecase.SlowDownError{RetryAfter: time.Second}, usecase.KindRateLimited},
+		{"unclassified", errors.New("disk on fire"), usecase.KindInternal},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assertValue(t, "Kind", tc.want, usecase.KindOf(tc.err))
+		})
+	}
+
+	t.Run("ErrWordInUse still matches the repository error", func(t *testing.T) {
+		if !errors.Is(usecase.ErrWordInUse, repository.ErrWordInUse) {
+			t.Error("expected ErrWordInUse to wrap repository.ErrWordInUse")
+		}
+		assertValue(t, "Kind", usecase.KindConflict, usecase.KindOf(usecase.ErrWordInUse))
+	})
+}
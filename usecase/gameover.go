@@ -0,0 +1,185 @@
+package usecase
+
+import (
+	"cmp"
+	"context"
+	"emojix/model"
+	"errors"
+	"log"
+	"slices"
+)
+
+var ErrGameFinished = NewError(KindGone, "game is already over")
+var ErrGameNotFinished = NewError(KindConflict, "game is not over yet")
+
+// podiumSize is how many leaderboard entries the results page highlights.
+const podiumSize = 3
+
+type GameOverNotification struct{}
+
+func (n *GameOverNotification) GetType() string { return "gameover" }
+func (n *GameOverNotification) GetData() string { return "" }
+
+// RematchNotification tells everyone still on the results page where the
+// rematch lives so their browser can follow. InviteCode is only set for a
+// private rematch, which followers must enter by code.
+type RematchNotification struct {
+	GameID     string
+	InviteCode string
+}
+
+func (n *RematchNotification) GetType() string { return "rematch" }
+func (n *RematchNotification) GetData() string {
+	if n.InviteCode == "" {
+		return n.GameID
+	}
+	return n.GameID + "," + n.InviteCode
+}
+
+// roundsComplete reports whether every active player has told at least
+// rounds turns.
+func roundsComplete(turns []model.GameTurn, activePlayers []model.Player, rounds int) bool {
+	if len(activePlayers) == 0 {
+		return false
+	}
+	told := map[string]int{}
+	for _, t := range turns {
+		told[t.TellerID]++
+	}
+	for _, p := range activePlayers {
+		if told[p.ID] < rounds {
+			return false
+		}
+	}
+	return true
+}
+
+// finishGame marks the game finished, stops its loop and tells clients to
+// render the results.
+func (e *emojixUsecase) finishGame(ctx context.Context, gameID string) {
+	if err := e.gameRepo.SetStatus(ctx, gameID, model.FinishedGameStatus); err != nil {
+		log.Printf("failed to mark game %s finished: %v", gameID, err)
+	}
+	e.gameLoop.StopGame(gameID)
+	e.gameNotifier.PubAll(gameID, &GameOverNotification{})
+}
+
+// buildPodium returns the top entries by score; ties keep leaderboard order.
+func buildPodium(leaderboard []model.LeaderboardEntry) []model.LeaderboardEntry {
+	podium := slices.Clone(leaderboard)
+	slices.SortStableFunc(podium, func(a, b model.LeaderboardEntry) int {
+		return cmp.Compare(b.Score, a.Score)
+	})
+	if len(podium) > podiumSize {
+		podium = podium[:podiumSize]
+	}
+	return podium
+}
+
+// buildTurnResults lists every turn with its word and the points each player
+// earned in it. players includes inactive ones so early leavers still show up.
+func (e *emojixUsecase) buildTurnResults(ctx context.Context, turns []model.GameTurn, players []model.Player, scores []model.Score) ([]model.TurnResult, error) {
+	nicknames := map[string]string{}
+	for _, p := range players {
+		nicknames[p.ID] = p.Nickname
+	}
+
+	results := []model.TurnResult{}
+	for _, turn := range turns {
+		result := model.TurnResult{
+			TurnID:         turn.ID,
+			TellerNickname: nicknames[turn.TellerID],
+		}
+		if turn.WordID != "" {
+			word, err := e.wordRepo.FindByID(ctx, turn.WordID)
+			if err != nil {
+				return nil, err
+			}
+			result.Word = word.Word
+		}
+
+		// Teller penalties and guess points share the turn.
+		result.Scores = turnScores(scores, turn.ID, nicknames)
+
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// turnScores sums each player's points over one turn, in the order they
+// first scored.
+func turnScores(scores []model.Score, turnID string, nicknames map[string]string) []model.TurnScore {
+	byPlayer := map[string]int{}
+	order := []string{}
+	for _, score := range scores {
+		if score.TurnID != turnID {
+			continue
+		}
+		if _, ok := byPlayer[score.PlayerID]; !ok {
+			order = append(order, score.PlayerID)
+		}
+		byPlayer[score.PlayerID] += score.Score
+	}
+	var out []model.TurnScore
+	for _, playerID := range order {
+		out = append(out, model.TurnScore{Nickname: nicknames[playerID], Score: byPlayer[playerID]})
+	}
+	return out
+}
+
+func (e *emojixUsecase) Rematch(ctx context.Context, gameID string, userID string) (model.Game, error) {
+	game, err := e.gameRepo.FindByID(ctx, gameID)
+	if err != nil {
+		return model.Game{}, err
+	}
+	if game.Status != model.FinishedGameStatus {
+		return model.Game{}, ErrGameNotFinished
+	}
+
+	players, err := e.gameRepo.GetPlayers(ctx, gameID)
+	if err != nil {
+		return model.Game{}, err
+	}
+	if err = e.isPlayerInGame(userID, e.filterPresentPlayers(players)); err != nil {
+		return model.Game{}, err
+	}
+
+	if game.NextGameID != "" {
+		return e.followRematch(ctx, game.NextGameID, userID)
+	}
+
+	next, err := e.InitGame(ctx, userID, game.ListID, game.Settings)
+	if err != nil {
+		return model.Game{}, err
+	}
+
+	// Two players may click at once; everyone follows whichever rematch won.
+	winnerID, err := e.gameRepo.ClaimNextGame(ctx, gameID, next.ID)
+	if err != nil {
+		return model.Game{}, err
+	}
+	if winnerID != next.ID {
+		return e.followRematch(ctx, winnerID, userID)
+	}
+
+	notif := &RematchNotification{GameID: next.ID}
+	if next.Settings.Private {
+		notif.InviteCode = next.InviteCode
+	}
+	go e.gameNotifier.PubAll(gameID, notif)
+
+	return next, nil
+}
+
+// followRematch loads an existing rematch and, when it is private, seats
+// userID so the redirect to it does not hit ErrInviteRequired.
+func (e *emojixUsecase) followRematch(ctx context.Context, nextGameID, userID string) (model.Game, error) {
+	next, err := e.gameRepo.FindByID(ctx, nextGameID)
+	if err != nil || !next.Settings.Private {
+		return next, err
+	}
+	if err = e.joinGame(ctx, next, userID, true, false); err != nil && !errors.Is(err, ErrJoinGameUserAlreadyJoined) {
+		return model.Game{}, err
+	}
+	return next, nil
+}
@@ -0,0 +1,176 @@
+package usecase_test
+
+import (
+	"context"
+	"emojix/model"
+	"emojix/repository"
+	"emojix/repository/repotest"
+	"emojix/service"
+	"emojix/service/servicetest"
+	"emojix/usecase"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRematch(t *testing.T) {
+	players := []model.Player{
+		{ID: "p1", Nickname: "One", State: model.ActivePlayerState},
+		{ID: "p2", Nickname: "Two", State: model.ActivePlayerState},
+	}
+	finished := model.Game{
+		ID:       "old-game",
+		ListID:   "list-1",
+		Status:   model.FinishedGameStatus,
+		Settings: model.GameSettings{TurnDuration: 90 * time.Second, Rounds: 2},
+	}
+
+	t.Run("rejects a game that is still running", func(t *testing.T) {
+		mgr := &repotest.MockGameRepository{
+			FindByIDMock: func(ctx context.Context, id string) (model.Game, error) {
+				return model.Game{ID: id, Status: model.PlayingGameStatus}, nil
+			},
+		}
+		uc := usecase.NewEmojixUsecase(nil, mgr, nil, nil, nil, &servicetest.MockGameLoop{}, service.NewRealClock())
+
+		_, err := uc.Rematch(context.Background(), "old-game", "p1")
+		if !errors.Is(err, usecase.ErrGameNotFinished) {
+			t.Errorf("expected ErrGameNotFinished but got %v", err)
+		}
+	})
+
+	t.Run("follows an existing rematch without creating another", func(t *testing.T) {
+		mgr := &repotest.MockGameRepository{
+			FindByIDMock: func(ctx context.Context, id string) (model.Game, error) {
+				if id == "old-game" {
+					g := finished
+					g.NextGameID = "next-game"
+					return g, nil
+				}
+				return model.Game{ID: id}, nil
+			},
+			GetPlayersMock: func(ctx context.Context, id string) ([]model.Player, error) {
+				return players, nil
+			},
+		}
+		uc := usecase.NewEmojixUsecase(nil, mgr, nil, nil, nil, &servicetest.MockGameLoop{}, service.NewRealClock())
+
+		game, err := uc.Rematch(context.Background(), "old-game", "p2")
+		if err != nil {
+			t.Fatalf("expected no error but got %v", err)
+		}
+		assertValue(t, "GameID", "next-game", game.ID)
+		if mgr.CreateCalled {
+			t.Error("expected GameRepository.Create not to be called")
+		}
+	})
+
+	t.Run("creates the rematch with the same list and settings", func(t *testing.T) {
+		pubCh := make(chan service.GameNotification, 1)
+		mgr := &repotest.MockGameRepository{
+			FindByIDMock: func(ctx context.Context, id string) (model.Game, error) {
+				if id == "old-game" {
+					return finished, nil
+				}
+				return model.Game{ID: id}, nil
+			},
+			GetPlayersMock: func(ctx context.Context, id string) ([]model.Player, error) {
+				if id == "old-game" {
+					return players, nil
+				}
+				return players[:1], nil
+			},
+			CreateMock: func(ctx context.Context, listID string, settings model.GameSettings) (model.Game, error) {
+				assertCalledWith(t, "ListID", "list-1", listID)
+				assertCalledWith(t, "TurnDuration", 90*time.Second, settings.TurnDuration)
+				assertCalledWith(t, "Rounds", 2, settings.Rounds)
+				return model.Game{ID: "new-game"}, nil
+			},
+			AddPlayerMock: func(ctx context.Context, gameID, playerID string) error {
+				assertCalledWith(t, "PlayerID", "p1", playerID)
+				return nil
+			},
+			ClaimNextGameMock: func(ctx context.Context, gameID, nextGameID string) (string, error) {
+				assertCalledWith(t, "GameID", "old-game", gameID)
+				return nextGameID, nil
+			},
+		}
+		uow := &repotest.MockUnitOfWork{
+			GameRepositoryMock: mgr,
+			CommitMock:         func() error { return nil },
+			RollbackMock:       func() error { return nil },
+		}
+		factory := &repotest.MockUnitOfWorkFactory{
+			NewMock: func(ctx context.Context) (repository.UnitOfWork, error) { return uow, nil },
+		}
+		mgn := &servicetest.MockGameNotifier{
+			PubAllMock: func(gameID string, notif service.GameNotification) {
+				assertCalledWith(t, "GameID", "old-game", gameID)
+				pubCh <- notif
+			},
+		}
+		uc := usecase.NewEmojixUsecase(nil, mgr, nil, factory, mgn, &servicetest.MockGameLoop{}, service.NewRealClock())
+
+		game, err := uc.Rematch(context.Background(), "old-game", "p1")
+		if err != nil {
+			t.Fatalf("expected no error but got %v", err)
+		}
+		assertValue(t, "GameID", "new-game", game.ID)
+
+		notif := <-pubCh
+		assertValue(t, "NotifType", "rematch", notif.GetType())
+		assertValue(t, "NotifData", "new-game", notif.GetData())
+	})
+}
+
+func TestGameStateFinished(t *testing.T) {
+	mgr := &repotest.MockGameRepository{
+		FindByIDMock: func(ctx context.Context, id string) (model.Game, error) {
+			return model.Game{ID: id, Status: model.FinishedGameStatus}, nil
+		},
+		GetPlayersMock: func(ctx context.Context, id string) ([]model.Player, error) {
+			return []model.Player{
+				{ID: "p1", Nickname: "One", State: model.ActivePlayerState},
+				{ID: "p2", Nickname: "Two", State: model.ActivePlayerState},
+				{ID: "p3", Nickname: "Gone", State: model.InactivePlayerState},
+			}, nil
+		},
+		GetMessagesMock: func(ctx context.Context, id string) ([]model.Message, error) { return nil, nil },
+		GetScoresMock: func(ctx context.Context, id string) ([]model.Score, error) {
+			return []model.Score{
+				{PlayerID: "p2", TurnID: "t1", Score: 8},
+				{PlayerID: "p3", TurnID: "t1", Score: 5},
+				{PlayerID: "p1", TurnID: "t2", Score: 3},
+			}, nil
+		},
+		GetLatestTurnMock: func(ctx context.Context, id string) (model.GameTurn, error) {
+			return model.GameTurn{ID: "t2", TellerID: "p2", WordID: "w2"}, nil
+		},
+		GetTurnsMock: func(ctx context.Context, id string) ([]model.GameTurn, error) {
+			return []model.GameTurn{
+				{ID: "t1", TellerID: "p1", WordID: "w1"},
+				{ID: "t2", TellerID: "p2"},
+			}, nil
+		},
+	}
+	mwr := &repotest.MockWordRepository{
+		FindByIDMock: func(ctx context.Context, id string) (model.Word, error) {
+			return model.Word{ID: id, Word: "Alpha"}, nil
+		},
+	}
+	uc := usecase.NewEmojixUsecase(nil, mgr, mwr, nil, nil, &servicetest.MockGameLoop{}, service.NewRealClock())
+
+	state, err := uc.GameState(context.Background(), "game-1", "p1")
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+
+	assertValue(t, "Status", model.FinishedGameStatus, state.Status)
+	if len(state.Podium) != 2 || state.Podium[0].PlayerID != "p2" || state.Podium[1].PlayerID != "p1" {
+		t.Errorf("expected podium p2, p1 but got %+v", state.Podium)
+	}
+	assertValue(t, "TurnResults", []model.TurnResult{
+		{TurnID: "t1", TellerNickname: "One", Word: "Alpha", Scores: []model.TurnScore{{Nickname: "Two", Score: 8}, {Nickname: "Gone", Score: 5}}},
+		{TurnID: "t2", TellerNickname: "Two", Scores: []model.TurnScore{{Nickname: "One", Score: 3}}},
+	}, state.TurnResults)
+}
@@ -0,0 +1,97 @@
+package usecase
+
+import (
+	"emojix/model"
+	"fmt"
+	"time"
+)
+
+// ErrInvalidGameSettings is wrapped by every settings validation failure.
+var ErrInvalidGameSettings = NewError(KindValidation, "invalid game settings")
+
+const (
+	defaultTurnDuration = time.Second * 60
+	defaultPickDuration = time.Second * 10
+	defaultMinPlayers   = 2 // host alone waits for a second player
+	defaultMaxPlayers   = 10
+	defaultRounds       = 3
+	defaultStealDelay   = time.Second * 20
+
+	minTurnDuration = time.Second * 15
+	maxTurnDuration = time.Minute * 5
+	minPickDuration = time.Second * 5
+	maxPickDuration = time.Minute
+	maxRoomCapacity = 20
+	maxRounds       = 10
+)
+
+// DefaultGameSettings are the rules used when the creator leaves a field unset.
+func DefaultGameSettings() model.GameSettings {
+	return model.GameSettings{
+		TurnDuration: defaultTurnDuration,
+		PickDuration: defaultPickDuration,
+		MinPlayers:   defaultMinPlayers,
+		MaxPlayers:   defaultMaxPlayers,
+		Rounds:       defaultRounds,
+		Scoring:      ClassicScoring,
+		StealDelay:   defaultStealDelay,
+	}
+}
+
+// withDefaults fills zero fields from the configured defaults
+// (DefaultGameSettings unless WithGameDefaults says otherwise) so rows and
+// callers that predate a setting keep working.
+func (e *emojixUsecase) withDefaults(s model.GameSettings) model.GameSettings {
+	d := e.gameDefaults
+	if s.TurnDuration == 0 {
+		s.TurnDuration = d.TurnDuration
+	}
+	if s.PickDuration == 0 {
+		s.PickDuration = d.PickDuration
+	}
+	if s.MinPlayers == 0 {
+		s.MinPlayers = d.MinPlayers
+	}
+	if s.MaxPlayers == 0 {
+		s.MaxPlayers = d.MaxPlayers
+	}
+	if s.Rounds == 0 {
+		s.Rounds = d.Rounds
+	}
+	if s.Scoring == "" {
+		s.Scoring = d.Scoring
+	}
+	if s.StealDelay == 0 {
+		s.StealDelay = d.StealDelay
+	}
+	if s.Reveals == "" {
+		s.Reveals = d.Reveals
+	}
+	return s
+}
+
+// ValidateGameSettings checks s (with defaults filled) against the allowed ranges.
+func ValidateGameSettings(s model.GameSettings) error {
+	if s.TurnDuration < minTurnDuration || s.TurnDuration > maxTurnDuration {
+		return fmt.Errorf("%w: turn length must be between %s and %s", ErrInvalidGameSettings, minTurnDuration, maxTurnDuration)
+	}
+	if s.PickDuration < minPickDuration || s.PickDuration > maxPickDuration {
+		return fmt.Errorf("%w: pick time must be between %s and %s", ErrInvalidGameSettings, minPickDuration, maxPickDuration)
+	}
+	if s.MaxPlayers < 2 || s.MaxPlayers > maxRoomCapacity {
+		return fmt.Errorf("%w: capacity must be between 2 and %d", ErrInvalidGameSettings, maxRoomCapacity)
+	}
+	if s.MinPlayers < 2 || s.MinPlayers > s.MaxPlayers {
+		return fmt.Errorf("%w: players to start must be between 2 and the room capacity", ErrInvalidGameSettings)
+	}
+	if s.Rounds < 1 || s.Rounds > maxRounds {
+		return fmt.Errorf("%w: rounds must be between 1 and %d", ErrInvalidGameSettings, maxRounds)
+	}
+	if s.StealDelay < 0 || s.StealDelay >= s.TurnDuration {
+		return fmt.Errorf("%w: steal delay must be shorter than the turn", ErrInvalidGameSettings)
+	}
+	if _, err := parseReveals(s.Reveals); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidGameSettings, err)
+	}
+	return nil
+}
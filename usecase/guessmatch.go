@@ -0,0 +1,173 @@
+package usecase
+
+import (
+	"strings"
+	"unicode"
+)
+
+// GuessVerdict is the outcome of comparing a guess against the secret word.
+type GuessVerdict int
+
+const (
+	GuessWrong GuessVerdict = iota
+	// GuessClose is a near miss; only the guesser is told.
+	GuessClose
+	GuessCorrect
+)
+
+// GuessMatcher decides whether a guess matches the secret word. Guess and
+// chat masking share one matcher so scoring and masking never disagree.
+type GuessMatcher interface {
+	Match(guess, word string) GuessVerdict
+}
+
+const defaultGuessMaxDistance = 2
+
+// DefaultGuessMatcher is used when no matcher is configured on the usecase.
+var DefaultGuessMatcher GuessMatcher = NewGuessMatcher(defaultGuessMaxDistance)
+
+// NewGuessMatcher returns a matcher that compares normalized forms (case,
+// punctuation, whitespace, diacritics, leading articles and number words are
+// ignored) and reports GuessClose when the normalized guess is within
+// maxDistance edits of the word. maxDistance <= 0 disables close verdicts.
+func NewGuessMatcher(maxDistance int) GuessMatcher {
+	return &fuzzyGuessMatcher{maxDistance: maxDistance}
+}
+
+type fuzzyGuessMatcher struct {
+	maxDistance int
+}
+
+func (m *fuzzyGuessMatcher) Match(guess, word string) GuessVerdict {
+	g, w := normalizeGuess(guess), normalizeGuess(word)
+	if w == "" {
+		// Punctuation-only secret: nothing survives normalization, compare raw.
+		if strings.EqualFold(strings.TrimSpace(guess), strings.TrimSpace(word)) {
+			return GuessCorrect
+		}
+		return GuessWrong
+	}
+	if g == w {
+		return GuessCorrect
+	}
+	if g == "" || m.maxDistance <= 0 {
+		return GuessWrong
+	}
+
+	d := editDistance(g, w)
+	// Never call a guess close when half the word is wrong; short words would
+	// otherwise be "close" to almost anything.
+	if d <= m.maxDistance && 2*d < len([]rune(w)) {
+		return GuessClose
+	}
+	return GuessWrong
+}
+
+var leadingArticles = map[string]bool{
+	"the": true,
+	"a":   true,
+	"an":  true,
+}
+
+var numberWords = map[string]string{
+	"zero":     "0",
+	"one":      "1",
+	"two":      "2",
+	"three":    "3",
+	"four":     "4",
+	"five":     "5",
+	"six":      "6",
+	"seven":    "7",
+	"eight":    "8",
+	"nine":     "9",
+	"ten":      "10",
+	"eleven":   "11",
+	"twelve":   "12",
+	"thirteen": "13",
+	"fourteen": "14",
+	"fifteen":  "15",
+	"sixteen":  "16",
+	"twenty":   "20",
+	"hundred":  "100",
+}
+
+// diacritics folds common accented Latin letters to their base letter. The
+// stdlib has no NFD decomposition, and this covers the lists we ship.
+var diacritics = map[rune]rune{
+	'à': 'a', 'á': 'a', 'â': 'a', 'ã': 'a', 'ä': 'a', 'å': 'a',
+	'ç': 'c', 'ć': 'c', 'č': 'c',
+	'è': 'e', 'é': 'e', 'ê': 'e', 'ë': 'e',
+	'ğ': 'g',
+	'ì': 'i', 'í': 'i', 'î': 'i', 'ï': 'i', 'ı': 'i',
+	'ñ': 'n', 'ń': 'n',
+	'ò': 'o', 'ó': 'o', 'ô': 'o', 'õ': 'o', 'ö': 'o', 'ø': 'o',
+	'ş': 's', 'ś': 's', 'š': 's',
+	'ù': 'u', 'ú': 'u', 'û': 'u', 'ü': 'u',
+	'ý': 'y', 'ÿ': 'y',
+	'ž': 'z', 'ź': 'z', 'ż': 'z',
+}
+
+// normalizeGuess reduces s to a comparable key: "The Dark Knight " and
+// "dark-knight" both become "darkknight", "Terminator2" and "terminator two"
+// both become "terminator2".
+func normalizeGuess(s string) string {
+	var b strings.Builder
+	var prev rune
+	for _, r := range strings.ToLower(s) {
+		if base, ok := diacritics[r]; ok {
+			r = base
+		}
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			// Split letter/digit runs so "terminator2" tokenizes like "terminator 2".
+			if prev != 0 && unicode.IsDigit(r) != unicode.IsDigit(prev) {
+				b.WriteByte(' ')
+			}
+			b.WriteRune(r)
+			prev = r
+		case unicode.Is(unicode.Mn, r):
+			// Combining accent (decomposed input); drop it.
+		case r == '\'' || r == '’':
+			// "don't" and "dont" are the same guess.
+		case r == '&':
+			b.WriteString(" and ")
+			prev = 0
+		default:
+			b.WriteByte(' ')
+			prev = 0
+		}
+	}
+
+	fields := strings.Fields(b.String())
+	if len(fields) > 1 && leadingArticles[fields[0]] {
+		fields = fields[1:]
+	}
+	for i, f := range fields {
+		if d, ok := numberWords[f]; ok {
+			fields[i] = d
+		}
+	}
+	return strings.Join(fields, "")
+}
+
+// editDistance is the Levenshtein distance between a and b in runes.
+func editDistance(a, b string) int {
+	ar, br := []rune(a), []rune(b)
+	prev := make([]int, len(br)+1)
+	curr := make([]int, len(br)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ar); i++ {
+		curr[0] = i
+		for j := 1; j <= len(br); j++ {
+			cost := 1
+			if ar[i-1] == br[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(br)]
+}
@@ -0,0 +1,44 @@
+package usecase_test
+
+import (
+	"emojix/usecase"
+	"testing"
+)
+
+func TestGuessMatcher(t *testing.T) {
+	m := usecase.NewGuessMatcher(2)
+
+	cases := []struct {
+		guess string
+		word  string
+		want  usecase.GuessVerdict
+	}{
+		{"secret", "Secret", usecase.GuessCorrect},
+		{"Terminator2", "Terminator 2", usecase.GuessCorrect},
+		{"terminator two", "Terminator 2", usecase.GuessCorrect},
+		{"the dark knight ", "The Dark Knight", usecase.GuessCorrect},
+		{"dark knight", "The Dark Knight", usecase.GuessCorrect},
+		{"mad max fury road", "Mad Max: Fury Road", usecase.GuessCorrect},
+		{"Amelie", "Amélie", usecase.GuessCorrect},
+		{"fast and furious", "Fast & Furious", usecase.GuessCorrect},
+		{"terminatr 2", "Terminator 2", usecase.GuessClose},
+		{"dark knigt", "The Dark Knight", usecase.GuessClose},
+		{"car", "cat", usecase.GuessClose},
+		{"ax", "ox", usecase.GuessWrong}, // half the word wrong is never "close"
+		{"nope", "Secret", usecase.GuessWrong},
+		{"", "Secret", usecase.GuessWrong},
+		{"The The", "the the", usecase.GuessCorrect},
+	}
+	for _, tc := range cases {
+		if got := m.Match(tc.guess, tc.word); got != tc.want {
+			t.Errorf("Match(%q, %q) = %v, want %v", tc.guess, tc.word, got, tc.want)
+		}
+	}
+
+	t.Run("zero distance disables close", func(t *testing.T) {
+		exact := usecase.NewGuessMatcher(0)
+		if got := exact.Match("terminatr 2", "Terminator 2"); got != usecase.GuessWrong {
+			t.Errorf("got %v, want GuessWrong", got)
+		}
+	})
+}
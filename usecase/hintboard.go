@@ -0,0 +1,104 @@
+package usecase
+
+import (
+	"context"
+	"emojix/repository"
+	"strings"
+	"unicode/utf8"
+)
+
+var ErrHintNotTeller = NewError(KindForbidden, "only the teller can edit the hint")
+var ErrHintTooLong = NewError(KindValidation, "hint board is full")
+
+// maxHintRunes caps the board so one turn cannot grow an unbounded row.
+const maxHintRunes = 64
+
+type HintUpdatedNotification struct {
+	Hint string
+}
+
+func (n *HintUpdatedNotification) GetType() string { return "hintupdated" }
+func (n *HintUpdatedNotification) GetData() string { return n.Hint }
+
+// AppendHint adds emoji to the end of the current turn's board.
+func (e *emojixUsecase) AppendHint(ctx context.Context, gameID, userID, emoji string) (string, error) {
+	emoji = strings.TrimSpace(emoji)
+	if !IsEmojiOnly(emoji) {
+		return "", ErrTellerEmojiOnly
+	}
+	return e.editHint(ctx, gameID, userID, func(gr repository.GameRepository, turnID, current string) (string, error) {
+		if utf8.RuneCountInString(current)+utf8.RuneCountInString(emoji) > maxHintRunes {
+			return "", ErrHintTooLong
+		}
+		return gr.AppendTurnHint(ctx, turnID, emoji)
+	})
+}
+
+// ReplaceHint swaps the whole board, e.g. to rearrange it.
+func (e *emojixUsecase) ReplaceHint(ctx context.Context, gameID, userID, hint string) (string, error) {
+	hint = strings.TrimSpace(hint)
+	if !IsEmojiOnly(hint) {
+		return "", ErrTellerEmojiOnly
+	}
+	if utf8.RuneCountInString(hint) > maxHintRunes {
+		return "", ErrHintTooLong
+	}
+	return e.editHint(ctx, gameID, userID, func(gr repository.GameRepository, turnID, current string) (string, error) {
+		return gr.ReplaceTurnHint(ctx, turnID, hint)
+	})
+}
+
+// UndoHint reverts the latest Append or Replace of the current turn.
+func (e *emojixUsecase) UndoHint(ctx context.Context, gameID, userID string) (string, error) {
+	return e.editHint(ctx, gameID, userID, func(gr repository.GameRepository, turnID, current string) (string, error) {
+		return gr.UndoTurnHint(ctx, turnID)
+	})
+}
+
+// editHint checks the caller is the teller of a running turn, applies edit in
+// a unit of work and broadcasts the resulting board. The turn is read again
+// in the unit of work, so edit sees the board as it is, not as it was before
+// a concurrent edit or the turn's end.
+func (e *emojixUsecase) editHint(ctx context.Context, gameID, userID string, edit func(gr repository.GameRepository, turnID, current string) (string, error)) (string, error) {
+	turn, err := e.gameRepo.GetLatestTurn(ctx, gameID)
+	if err != nil {
+		return "", err
+	}
+	if turn.TellerID != userID {
+		return "", ErrHintNotTeller
+	}
+	if turn.WordID == "" {
+		return "", ErrPickFirst
+	}
+	if !turn.EndedAt.IsZero() {
+		return "", ErrTurnOver
+	}
+
+	uow, err := e.unitOfWorkFactory.New(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer uow.Rollback()
+
+	gameRepo := uow.GameRepository()
+	current, err := gameRepo.GetLatestTurn(ctx, gameID)
+	if err != nil {
+		return "", err
+	}
+	if current.ID != turn.ID || !current.EndedAt.IsZero() {
+		return "", ErrTurnOver
+	}
+
+	hint, err := edit(gameRepo, current.ID, current.EmojiHint)
+	if err != nil {
+		return "", err
+	}
+
+	if err = uow.Commit(); err != nil {
+		return "", err
+	}
+
+	go e.gameNotifier.PubAll(gameID, &HintUpdatedNotification{Hint: hint})
+
+	return hint, nil
+}
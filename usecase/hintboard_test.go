@@ -0,0 +1,174 @@
+package usecase_test
+
+import (
+	"context"
+	"emojix/model"
+	"emojix/repository/repotest"
+	"emojix/service"
+	"emojix/service/servicetest"
+	"emojix/usecase"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHintBoard(t *testing.T) {
+	const (
+		gameID   = "game-1"
+		tellerID = "teller-1"
+		turnID   = "turn-1"
+	)
+	turnRepo := func(turn model.GameTurn) *repotest.MockGameRepository {
+		return &repotest.MockGameRepository{
+			GetLatestTurnMock: func(ctx context.Context, id string) (model.GameTurn, error) {
+				assertCalledWith(t, "GameID", gameID, id)
+				return turn, nil
+			},
+		}
+	}
+	started := model.GameTurn{ID: turnID, TellerID: tellerID, WordID: "w-1", EmojiHint: "🍎"}
+
+	t.Run("append commits and broadcasts the new board", func(t *testing.T) {
+		mgr := turnRepo(started)
+		mgr.AppendTurnHintMock = func(ctx context.Context, tid, emoji string) (string, error) {
+			assertCalledWith(t, "TurnID", turnID, tid)
+			assertCalledWith(t, "Emoji", "🍌", emoji)
+			return "🍎🍌", nil
+		}
+		pubCh := make(chan service.GameNotification, 1)
+		mgn := &servicetest.MockGameNotifier{
+			PubAllMock: func(g string, n service.GameNotification) {
+				assertCalledWith(t, "GameID", gameID, g)
+				pubCh <- n
+			},
+		}
+		uc, uow := newGuessUsecase(nil, mgr, nil, mgn, &servicetest.MockGameLoop{}, nil)
+
+		hint, err := uc.AppendHint(context.Background(), gameID, tellerID, " 🍌 ")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		assertValue(t, "Hint", "🍎🍌", hint)
+		if !uow.CommitCalled {
+			t.Error("expected Commit to be called")
+		}
+		n := drainPub(t, pubCh, 1)[0]
+		assertValue(t, "NotifType", "hintupdated", n.GetType())
+		assertValue(t, "NotifData", "🍎🍌", n.GetData())
+	})
+
+	t.Run("undo returns the previous board", func(t *testing.T) {
+		mgr := turnRepo(started)
+		mgr.UndoTurnHintMock = func(ctx context.Context, tid string) (string, error) {
+			return "", nil
+		}
+		uc, _ := newGuessUsecase(nil, mgr, nil, &servicetest.MockGameNotifier{}, &servicetest.MockGameLoop{}, nil)
+
+		hint, err := uc.UndoHint(context.Background(), gameID, tellerID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		assertValue(t, "Hint", "", hint)
+	})
+
+	// The second read happens in the unit of work, after another request
+	// changed the turn.
+	raced := []struct {
+		name  string
+		after model.GameTurn
+		want  error
+	}{
+		{"board filled meanwhile", model.GameTurn{ID: turnID, TellerID: tellerID, WordID: "w-1", EmojiHint: strings.Repeat("🍎", 64)}, usecase.ErrHintTooLong},
+		{"turn ended meanwhile", model.GameTurn{ID: turnID, TellerID: tellerID, WordID: "w-1", EndedAt: time.Now()}, usecase.ErrTurnOver},
+		{"next turn started meanwhile", model.GameTurn{ID: "turn-2", TellerID: tellerID, WordID: "w-2"}, usecase.ErrTurnOver},
+	}
+	for _, tc := range raced {
+		t.Run(tc.name, func(t *testing.T) {
+			reads := 0
+			mgr := &repotest.MockGameRepository{
+				GetLatestTurnMock: func(ctx context.Context, id string) (model.GameTurn, error) {
+					reads++
+					if reads == 1 {
+						return started, nil
+					}
+					return tc.after, nil
+				},
+			}
+			uc, uow := newGuessUsecase(nil, mgr, nil, &servicetest.MockGameNotifier{}, &servicetest.MockGameLoop{}, nil)
+
+			if _, err := uc.AppendHint(context.Background(), gameID, tellerID, "🍌"); !errors.Is(err, tc.want) {
+				t.Errorf("expected %v but got %v", tc.want, err)
+			}
+			if uow.CommitCalled {
+				t.Error("expected no Commit")
+			}
+		})
+	}
+
+	cases := []struct {
+		name string
+		turn model.GameTurn
+		call func(uc usecase.EmojixUsecase) error
+		want error
+	}{
+		{
+			name: "guesser cannot edit",
+			turn: started,
+			call: func(uc usecase.EmojixUsecase) error {
+				_, err := uc.AppendHint(context.Background(), gameID, "guesser", "🍌")
+				return err
+			},
+			want: usecase.ErrHintNotTeller,
+		},
+		{
+			name: "letters rejected",
+			turn: started,
+			call: func(uc usecase.EmojixUsecase) error {
+				_, err := uc.ReplaceHint(context.Background(), gameID, tellerID, "apple")
+				return err
+			},
+			want: usecase.ErrTellerEmojiOnly,
+		},
+		{
+			name: "board before the pick",
+			turn: model.GameTurn{ID: turnID, TellerID: tellerID},
+			call: func(uc usecase.EmojixUsecase) error {
+				_, err := uc.UndoHint(context.Background(), gameID, tellerID)
+				return err
+			},
+			want: usecase.ErrPickFirst,
+		},
+		{
+			name: "full board",
+			turn: model.GameTurn{ID: turnID, TellerID: tellerID, WordID: "w-1", EmojiHint: strings.Repeat("🍎", 64)},
+			call: func(uc usecase.EmojixUsecase) error {
+				_, err := uc.AppendHint(context.Background(), gameID, tellerID, "🍌")
+				return err
+			},
+			want: usecase.ErrHintTooLong,
+		},
+		{
+			name: "ended turn",
+			turn: model.GameTurn{ID: turnID, TellerID: tellerID, WordID: "w-1", EndedAt: time.Now()},
+			call: func(uc usecase.EmojixUsecase) error {
+				_, err := uc.AppendHint(context.Background(), gameID, tellerID, "🍌")
+				return err
+			},
+			want: usecase.ErrTurnOver,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			// No *TurnHintMock wired: reaching the repository would panic.
+			uc, uow := newGuessUsecase(nil, turnRepo(tc.turn), nil, &servicetest.MockGameNotifier{}, &servicetest.MockGameLoop{}, nil)
+
+			if err := tc.call(uc); !errors.Is(err, tc.want) {
+				t.Errorf("expected %v but got %v", tc.want, err)
+			}
+			if uow.CommitCalled {
+				t.Error("expected no Commit")
+			}
+		})
+	}
+}
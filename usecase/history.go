@@ -0,0 +1,165 @@
+package usecase
+
+import (
+	"context"
+	"emojix/model"
+	"slices"
+)
+
+// GameHistory reads a game back turn by turn: who told, what they picked and
+// passed on, each edit of the hint board, every message and the points each
+// one moved. It is open once the game is finished, or abandoned with nobody
+// left playing. Anyone who had a seat may read it, including leavers.
+func (e *emojixUsecase) GameHistory(ctx context.Context, gameID, userID string) (model.GameHistory, error) {
+	history := model.GameHistory{GameID: gameID}
+
+	game, err := e.gameRepo.FindByID(ctx, gameID)
+	if err != nil {
+		return history, err
+	}
+	players, err := e.gameRepo.GetPlayers(ctx, gameID)
+	if err != nil {
+		return history, err
+	}
+	if game.Status != model.FinishedGameStatus && len(e.filterActivePlayers(players)) > 0 {
+		return history, ErrGameNotFinished
+	}
+	if !seatedPlayer(players, userID) {
+		return history, ErrUserNotInGame
+	}
+
+	turns, err := e.gameRepo.GetTurns(ctx, gameID)
+	if err != nil {
+		return history, err
+	}
+	messages, err := e.gameRepo.GetMessages(ctx, gameID)
+	if err != nil {
+		return history, err
+	}
+	scores, err := e.gameRepo.GetScores(ctx, gameID)
+	if err != nil {
+		return history, err
+	}
+	edits, err := e.gameRepo.GetHintEdits(ctx, gameID)
+	if err != nil {
+		return history, err
+	}
+
+	nicknames := map[string]string{}
+	for _, p := range players {
+		nicknames[p.ID] = p.Nickname
+	}
+	words := map[string]string{}
+	wordOf := func(id string) (string, error) {
+		if w, ok := words[id]; ok || id == "" {
+			return w, nil
+		}
+		w, err := e.wordRepo.FindByID(ctx, id)
+		if err != nil {
+			return "", err
+		}
+		words[id] = w.Word
+		return w.Word, nil
+	}
+
+	for _, turn := range turns {
+		th := model.TurnHistory{
+			TurnID:         turn.ID,
+			TellerID:       turn.TellerID,
+			TellerNickname: nicknames[turn.TellerID],
+			Hint:           turn.EmojiHint,
+			CreatedAt:      turn.CreatedAt,
+			StartedAt:      turn.StartedAt,
+			EndedAt:        turn.EndedAt,
+		}
+		if th.Word, err = wordOf(turn.WordID); err != nil {
+			return history, err
+		}
+		// Padded options repeat; list each passed word once.
+		for _, id := range []string{turn.OptionA, turn.OptionB, turn.OptionC} {
+			w, err := wordOf(id)
+			if err != nil {
+				return history, err
+			}
+			if id != turn.WordID && w != "" && !slices.Contains(th.Passed, w) {
+				th.Passed = append(th.Passed, w)
+			}
+		}
+
+		th.Events = append(th.Events, model.HistoryEvent{
+			Kind: model.DealtHistoryEvent, At: turn.CreatedAt,
+			PlayerID: turn.TellerID, Nickname: th.TellerNickname,
+		})
+		if !turn.StartedAt.IsZero() {
+			th.Events = append(th.Events, model.HistoryEvent{
+				Kind: model.PickedHistoryEvent, At: turn.StartedAt,
+				PlayerID: turn.TellerID, Nickname: th.TellerNickname, Content: th.Word,
+			})
+		}
+		th.Events = append(th.Events, boardEvents(turn, edits, th.TellerNickname)...)
+		for _, msg := range messages {
+			if msg.TurnID != turn.ID {
+				continue
+			}
+			ev := model.HistoryEvent{
+				Kind: model.GuessHistoryEvent, At: msg.CreatedAt,
+				PlayerID: msg.PlayerID, Nickname: nicknames[msg.PlayerID], Content: msg.Content,
+			}
+			if msg.PlayerID == turn.TellerID {
+				ev.Kind = model.HintHistoryEvent
+			}
+			for _, s := range scores {
+				if s.MessageID != msg.ID {
+					continue
+				}
+				ev.Scores = append(ev.Scores, model.TurnScore{Nickname: nicknames[s.PlayerID], Score: s.Score})
+				if s.PlayerID == msg.PlayerID && s.Score > 0 && ev.Kind == model.GuessHistoryEvent {
+					ev.Correct = true
+				}
+			}
+			th.Events = append(th.Events, ev)
+		}
+		if !turn.EndedAt.IsZero() {
+			th.Events = append(th.Events, model.HistoryEvent{Kind: model.TurnEndHistoryEvent, At: turn.EndedAt})
+		}
+		slices.SortStableFunc(th.Events, func(a, b model.HistoryEvent) int { return a.At.Compare(b.At) })
+
+		th.Scores = turnScores(scores, turn.ID, nicknames)
+		history.Turns = append(history.Turns, th)
+	}
+	if len(turns) > 0 {
+		history.StartAt = turns[0].CreatedAt
+	}
+
+	history.Podium = buildPodium(e.buildLeaderboard(gameID, userID, "", "", scores, historyPlayers(players, turns, scores)))
+	return history, nil
+}
+
+// boardEvents is a hint event per kept edit of turn's board, carrying the
+// board as it stood after the edit: the next edit's previous board, or the
+// final one for the last edit.
+func boardEvents(turn model.GameTurn, edits []model.HintEdit, tellerNickname string) []model.HistoryEvent {
+	turnEdits := slices.DeleteFunc(slices.Clone(edits), func(ed model.HintEdit) bool { return ed.TurnID != turn.ID })
+	events := make([]model.HistoryEvent, 0, len(turnEdits))
+	for i, ed := range turnEdits {
+		board := turn.EmojiHint
+		if i+1 < len(turnEdits) {
+			board = turnEdits[i+1].Previous
+		}
+		events = append(events, model.HistoryEvent{
+			Kind: model.HintHistoryEvent, At: ed.At,
+			PlayerID: turn.TellerID, Nickname: tellerNickname, Content: board,
+		})
+	}
+	return events
+}
+
+// historyPlayers is everyone who told or scored, so spectators who only
+// watched stay off the final standings.
+func historyPlayers(players []model.Player, turns []model.GameTurn, scores []model.Score) []model.Player {
+	return slices.DeleteFunc(slices.Clone(players), func(p model.Player) bool {
+		told := slices.ContainsFunc(turns, func(t model.GameTurn) bool { return t.TellerID == p.ID })
+		scored := slices.ContainsFunc(scores, func(s model.Score) bool { return s.PlayerID == p.ID })
+		return !told && !scored
+	})
+}
@@ -0,0 +1,147 @@
+package usecase_test
+
+import (
+	"context"
+	"emojix/model"
+	"emojix/repository/repotest"
+	"emojix/service"
+	"emojix/service/servicetest"
+	"emojix/usecase"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestGameHistory(t *testing.T) {
+	start := time.Date(2026, 1, 2, 15, 0, 0, 0, time.UTC)
+	at := func(secs int) time.Time { return start.Add(time.Duration(secs) * time.Second) }
+	players := []model.Player{
+		{ID: "p1", Nickname: "One", State: model.ActivePlayerState},
+		{ID: "p2", Nickname: "Two", State: model.ActivePlayerState},
+		{ID: "kicked", Nickname: "Kicked", State: model.KickedPlayerState},
+	}
+	repo := func(status model.GameStatus) *repotest.MockGameRepository {
+		return &repotest.MockGameRepository{
+			FindByIDMock: func(ctx context.Context, id string) (model.Game, error) {
+				return model.Game{ID: id, Status: status}, nil
+			},
+			GetPlayersMock: func(ctx context.Context, id string) ([]model.Player, error) { return players, nil },
+			GetHintEditsMock: func(ctx context.Context, id string) ([]model.HintEdit, error) {
+				return []model.HintEdit{
+					{TurnID: "t1", Previous: "🌳", At: at(6)},
+					{TurnID: "t1", Previous: "🌳🍏", At: at(12)},
+				}, nil
+			},
+			GetTurnsMock: func(ctx context.Context, id string) ([]model.GameTurn, error) {
+				return []model.GameTurn{
+					{ID: "t1", TellerID: "p1", WordID: "w2", OptionA: "w1", OptionB: "w2", OptionC: "w1",
+						EmojiHint: "🍎", CreatedAt: at(0), StartedAt: at(5), EndedAt: at(30)},
+					{ID: "t2", TellerID: "p2", OptionA: "w3", OptionB: "w1", OptionC: "w2",
+						CreatedAt: at(31), EndedAt: at(45)},
+				}, nil
+			},
+			GetMessagesMock: func(ctx context.Context, id string) ([]model.Message, error) {
+				return []model.Message{
+					{ID: "m2", TurnID: "t1", PlayerID: "p2", Content: "apple", CreatedAt: at(20)},
+					{ID: "m1", TurnID: "t1", PlayerID: "p2", Content: "pear", CreatedAt: at(10)},
+					{ID: "m0", TurnID: "t1", PlayerID: "p1", Content: "🍏", CreatedAt: at(8)},
+				}, nil
+			},
+			GetScoresMock: func(ctx context.Context, id string) ([]model.Score, error) {
+				return []model.Score{
+					{PlayerID: "p2", MessageID: "m2", TurnID: "t1", Score: 8},
+					{PlayerID: "p1", MessageID: "m2", TurnID: "t1", Score: 4},
+				}, nil
+			},
+		}
+	}
+	mwr := &repotest.MockWordRepository{
+		FindByIDMock: func(ctx context.Context, id string) (model.Word, error) {
+			return model.Word{ID: id, Word: map[string]string{"w1": "Pear", "w2": "Apple", "w3": "Plum"}[id]}, nil
+		},
+	}
+
+	t.Run("rebuilds each turn in time order", func(t *testing.T) {
+		uc := usecase.NewEmojixUsecase(nil, repo(model.FinishedGameStatus), mwr, nil, nil, &servicetest.MockGameLoop{}, service.NewRealClock())
+
+		history, err := uc.GameHistory(context.Background(), "game-1", "p1")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		assertValue(t, "StartAt", at(0), history.StartAt)
+		if len(history.Podium) != 2 || history.Podium[0].PlayerID != "p2" || !history.Podium[1].Me {
+			t.Errorf("expected podium Two, One but got %+v", history.Podium)
+		}
+		if len(history.Turns) != 2 {
+			t.Fatalf("expected 2 turns but got %d", len(history.Turns))
+		}
+
+		first := history.Turns[0]
+		assertValue(t, "Word", "Apple", first.Word)
+		assertValue(t, "Passed", []string{"Pear"}, first.Passed)
+		assertValue(t, "Hint", "🍎", first.Hint)
+		assertValue(t, "Scores", []model.TurnScore{{Nickname: "Two", Score: 8}, {Nickname: "One", Score: 4}}, first.Scores)
+
+		kinds := []model.HistoryEventKind{}
+		for _, ev := range first.Events {
+			kinds = append(kinds, ev.Kind)
+		}
+		assertValue(t, "Kinds", []model.HistoryEventKind{
+			model.DealtHistoryEvent, model.PickedHistoryEvent, model.HintHistoryEvent, model.HintHistoryEvent,
+			model.GuessHistoryEvent, model.HintHistoryEvent, model.GuessHistoryEvent, model.TurnEndHistoryEvent,
+		}, kinds)
+		// Each board edit shows the board it left: the next edit's starting
+		// point, and the final board for the last one.
+		assertValue(t, "first edit", "🌳🍏", first.Events[2].Content)
+		assertValue(t, "teller hint", "🍏", first.Events[3].Content)
+		assertValue(t, "last edit", "🍎", first.Events[5].Content)
+		assertValue(t, "edit Nickname", "One", first.Events[5].Nickname)
+		wrong, right := first.Events[4], first.Events[6]
+		assertValue(t, "wrong Content", "pear", wrong.Content)
+		assertValue(t, "wrong Correct", false, wrong.Correct)
+		assertValue(t, "right Correct", true, right.Correct)
+		assertValue(t, "right Scores", []model.TurnScore{{Nickname: "Two", Score: 8}, {Nickname: "One", Score: 4}}, right.Scores)
+
+		second := history.Turns[1]
+		assertValue(t, "unpicked Word", "", second.Word)
+		assertValue(t, "unpicked Passed", []string{"Plum", "Pear", "Apple"}, second.Passed)
+		assertValue(t, "unpicked events", 2, len(second.Events))
+	})
+
+	t.Run("game still running", func(t *testing.T) {
+		uc := usecase.NewEmojixUsecase(nil, repo(model.PlayingGameStatus), mwr, nil, nil, &servicetest.MockGameLoop{}, service.NewRealClock())
+
+		_, err := uc.GameHistory(context.Background(), "game-1", "p1")
+		if !errors.Is(err, usecase.ErrGameNotFinished) {
+			t.Errorf("expected ErrGameNotFinished but got %v", err)
+		}
+	})
+
+	t.Run("game abandoned by every player", func(t *testing.T) {
+		mgr := repo(model.PlayingGameStatus)
+		mgr.GetPlayersMock = func(ctx context.Context, id string) ([]model.Player, error) {
+			return []model.Player{
+				{ID: "p1", Nickname: "One", State: model.InactivePlayerState},
+				{ID: "p2", Nickname: "Two", State: model.InactivePlayerState},
+			}, nil
+		}
+		uc := usecase.NewEmojixUsecase(nil, mgr, mwr, nil, nil, &servicetest.MockGameLoop{}, service.NewRealClock())
+
+		history, err := uc.GameHistory(context.Background(), "game-1", "p1")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		assertValue(t, "Turns", 2, len(history.Turns))
+	})
+
+	t.Run("kicked players and strangers cannot read it", func(t *testing.T) {
+		uc := usecase.NewEmojixUsecase(nil, repo(model.FinishedGameStatus), mwr, nil, nil, &servicetest.MockGameLoop{}, service.NewRealClock())
+
+		for _, userID := range []string{"kicked", "stranger"} {
+			_, err := uc.GameHistory(context.Background(), "game-1", userID)
+			if !errors.Is(err, usecase.ErrUserNotInGame) {
+				t.Errorf("%s: expected ErrUserNotInGame but got %v", userID, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,210 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"emojix/model"
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+)
+
+var ErrNotHost = NewError(KindForbidden, "only the host can do that")
+var ErrInviteRequired = NewError(KindForbidden, "this room is private, join with its invite code")
+var ErrInvalidInviteCode = NewError(KindNotFound, "no game with that invite code")
+var ErrRoomLocked = NewError(KindForbidden, "room is locked")
+var ErrPlayerKicked = NewError(KindForbidden, "removed from this game by the host")
+var ErrKickHost = NewError(KindValidation, "the host cannot kick themselves")
+
+// ErrPlayerNotInGame is returned when a host action targets someone without a
+// seat; unlike ErrUserNotInGame it is about the target, not the caller.
+var ErrPlayerNotInGame = NewError(KindValidation, "player not in the game")
+
+type PlayerKickedNotification struct {
+	PlayerID string
+}
+
+func (n *PlayerKickedNotification) GetType() string { return "kicked" }
+func (n *PlayerKickedNotification) GetData() string { return n.PlayerID }
+
+type RoomLockedNotification struct {
+	Locked bool
+}
+
+func (n *RoomLockedNotification) GetType() string { return "roomlocked" }
+func (n *RoomLockedNotification) GetData() string { return strconv.FormatBool(n.Locked) }
+
+type HostChangedNotification struct {
+	HostID string
+}
+
+func (n *HostChangedNotification) GetType() string { return "hostchanged" }
+func (n *HostChangedNotification) GetData() string { return n.HostID }
+
+// IsInviteCode reports whether s has the shape of an invite code, so a single
+// "game code" field can take either a game id or an invite code.
+func IsInviteCode(s string) bool {
+	s = normalizeInviteCode(s)
+	if len(s) != model.InviteCodeLength {
+		return false
+	}
+	for _, r := range s {
+		if !strings.ContainsRune(model.InviteCodeAlphabet, r) {
+			return false
+		}
+	}
+	return true
+}
+
+func normalizeInviteCode(s string) string {
+	return strings.ToUpper(strings.TrimSpace(s))
+}
+
+// JoinGameByCode seats userID in the game with that invite code. Unlike
+// JoinGame it admits new players to private rooms.
+func (e *emojixUsecase) JoinGameByCode(ctx context.Context, code string, userID string) (model.Game, error) {
+	game, err := e.gameRepo.FindByInviteCode(ctx, normalizeInviteCode(code))
+	if errors.Is(err, sql.ErrNoRows) {
+		return game, ErrInvalidInviteCode
+	}
+	if err != nil {
+		return game, err
+	}
+	err = e.joinGame(ctx, game, userID, true, false)
+	if errors.Is(err, ErrJoinGameUserAlreadyJoined) {
+		err = nil
+	}
+	return game, err
+}
+
+// KickPlayer removes playerID for good; a kicked teller's turn is skipped.
+func (e *emojixUsecase) KickPlayer(ctx context.Context, gameID, hostID, playerID string) error {
+	if _, err := e.hostedGame(ctx, gameID, hostID); err != nil {
+		return err
+	}
+	if playerID == hostID {
+		return ErrKickHost
+	}
+	players, err := e.gameRepo.GetPlayers(ctx, gameID)
+	if err != nil {
+		return err
+	}
+	if !seatedPlayer(players, playerID) {
+		return ErrPlayerNotInGame
+	}
+
+	if err = e.gameRepo.SetPlayerState(ctx, gameID, playerID, model.KickedPlayerState); err != nil {
+		return err
+	}
+
+	turn, err := e.gameRepo.GetLatestTurn(ctx, gameID)
+	if err == nil && turn.TellerID == playerID {
+		e.gameLoop.SkipTurn(gameID)
+	} else if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		log.Printf("KickPlayer GetLatestTurn: %v", err)
+	}
+
+	// PubAll reaches the kicked player too, whose page leaves on this event.
+	go e.gameNotifier.PubAll(gameID, &PlayerKickedNotification{PlayerID: playerID})
+
+	e.promoteSpectators(ctx, gameID)
+
+	return nil
+}
+
+// LockRoom closes (or reopens) the room to new players. Seated players,
+// including inactive ones, can still come back.
+func (e *emojixUsecase) LockRoom(ctx context.Context, gameID, hostID string, locked bool) error {
+	if _, err := e.hostedGame(ctx, gameID, hostID); err != nil {
+		return err
+	}
+	if err := e.gameRepo.SetLocked(ctx, gameID, locked); err != nil {
+		return err
+	}
+
+	go e.gameNotifier.PubAll(gameID, &RoomLockedNotification{Locked: locked})
+
+	return nil
+}
+
+// TransferHost hands host controls to another active player.
+func (e *emojixUsecase) TransferHost(ctx context.Context, gameID, hostID, newHostID string) error {
+	if _, err := e.hostedGame(ctx, gameID, hostID); err != nil {
+		return err
+	}
+	players, err := e.gameRepo.GetPlayers(ctx, gameID)
+	if err != nil {
+		return err
+	}
+	if e.isPlayerInGame(newHostID, e.filterActivePlayers(players)) != nil {
+		return ErrPlayerNotInGame
+	}
+
+	return e.setHost(ctx, gameID, newHostID)
+}
+
+// SkipTurn ends the current pick phase or turn now, as if its timer ran out.
+func (e *emojixUsecase) SkipTurn(ctx context.Context, gameID, hostID string) error {
+	if _, err := e.hostedGame(ctx, gameID, hostID); err != nil {
+		return err
+	}
+	e.gameLoop.SkipTurn(gameID)
+	return nil
+}
+
+func (e *emojixUsecase) hostedGame(ctx context.Context, gameID, hostID string) (model.Game, error) {
+	game, err := e.gameRepo.FindByID(ctx, gameID)
+	if err != nil {
+		return game, err
+	}
+	if game.HostID == "" || game.HostID != hostID {
+		return game, ErrNotHost
+	}
+	if game.Status == model.FinishedGameStatus {
+		return game, ErrGameFinished
+	}
+	return game, nil
+}
+
+func (e *emojixUsecase) setHost(ctx context.Context, gameID, hostID string) error {
+	if err := e.gameRepo.SetHost(ctx, gameID, hostID); err != nil {
+		return err
+	}
+
+	go e.gameNotifier.PubAll(gameID, &HostChangedNotification{HostID: hostID})
+
+	return nil
+}
+
+// handOffHost passes host controls to the longest-seated active player when
+// the host leaves, so a room is never stuck without one.
+func (e *emojixUsecase) handOffHost(ctx context.Context, gameID, leftID string) {
+	game, err := e.gameRepo.FindByID(ctx, gameID)
+	if err != nil || game.HostID != leftID {
+		return
+	}
+	players, err := e.gameRepo.GetPlayers(ctx, gameID)
+	if err != nil {
+		log.Printf("handOffHost GetPlayers: %v", err)
+		return
+	}
+	for _, p := range e.filterActivePlayers(players) {
+		if p.ID != leftID {
+			if err := e.setHost(ctx, gameID, p.ID); err != nil {
+				log.Printf("handOffHost SetHost: %v", err)
+			}
+			return
+		}
+	}
+}
+
+// seatedPlayer reports whether playerID has a seat, active or not.
+func seatedPlayer(players []model.Player, playerID string) bool {
+	for _, p := range players {
+		if p.ID == playerID && p.State != model.KickedPlayerState {
+			return true
+		}
+	}
+	return false
+}
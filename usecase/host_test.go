@@ -0,0 +1,283 @@
+package usecase_test
+
+import (
+	"context"
+	"database/sql"
+	"emojix/model"
+	"emojix/repository/repotest"
+	"emojix/service"
+	"emojix/service/servicetest"
+	"emojix/usecase"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestHostControls(t *testing.T) {
+	hostedRepo := func() *repotest.MockGameRepository {
+		return &repotest.MockGameRepository{
+			FindByIDMock: func(ctx context.Context, id string) (model.Game, error) {
+				return model.Game{ID: id, HostID: "host-id", Status: model.PlayingGameStatus}, nil
+			},
+			GetPlayersMock: func(ctx context.Context, id string) ([]model.Player, error) {
+				return []model.Player{
+					{ID: "host-id", State: model.ActivePlayerState},
+					{ID: "teller-id", State: model.ActivePlayerState},
+					{ID: "gone-id", State: model.InactivePlayerState},
+				}, nil
+			},
+		}
+	}
+	awaitPubAll := func(mgn *servicetest.MockGameNotifier) <-chan service.GameNotification {
+		ch := make(chan service.GameNotification, 1)
+		mgn.PubAllMock = func(gameID string, notif service.GameNotification) {
+			ch <- notif
+		}
+		return ch
+	}
+	assertPubAll := func(t *testing.T, ch <-chan service.GameNotification, typ, data string) {
+		t.Helper()
+		select {
+		case notif := <-ch:
+			assertValue(t, "NotifType", typ, notif.GetType())
+			assertValue(t, "NotifData", data, notif.GetData())
+		case <-time.After(time.Second):
+			t.Fatalf("expected a %s notification", typ)
+		}
+	}
+
+	t.Run("KickPlayer removes the teller and skips the turn", func(t *testing.T) {
+		mgr := hostedRepo()
+		mgr.SetPlayerStateMock = func(ctx context.Context, gameID, userID string, state model.PlayerState) error {
+			assertCalledWith(t, "UserID", "teller-id", userID)
+			assertCalledWith(t, "State", model.KickedPlayerState, state)
+			return nil
+		}
+		mgr.GetLatestTurnMock = func(ctx context.Context, id string) (model.GameTurn, error) {
+			return model.GameTurn{TellerID: "teller-id"}, nil
+		}
+		mgn := &servicetest.MockGameNotifier{}
+		pubs := awaitPubAll(mgn)
+		mgl := &servicetest.MockGameLoop{}
+		uc := usecase.NewEmojixUsecase(nil, mgr, nil, nil, mgn, mgl, service.NewRealClock())
+
+		if err := uc.KickPlayer(context.Background(), "game-id", "host-id", "teller-id"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !mgl.SkipTurnCalled {
+			t.Error("expected GameLoop.SkipTurn to be called")
+		}
+		assertPubAll(t, pubs, "kicked", "teller-id")
+	})
+
+	t.Run("LockRoom stores and announces the lock", func(t *testing.T) {
+		mgr := hostedRepo()
+		mgr.SetLockedMock = func(ctx context.Context, gameID string, locked bool) error {
+			assertCalledWith(t, "Locked", true, locked)
+			return nil
+		}
+		mgn := &servicetest.MockGameNotifier{}
+		pubs := awaitPubAll(mgn)
+		uc := usecase.NewEmojixUsecase(nil, mgr, nil, nil, mgn, &servicetest.MockGameLoop{}, service.NewRealClock())
+
+		if err := uc.LockRoom(context.Background(), "game-id", "host-id", true); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		assertPubAll(t, pubs, "roomlocked", "true")
+	})
+
+	t.Run("TransferHost hands over to an active player", func(t *testing.T) {
+		mgr := hostedRepo()
+		mgn := &servicetest.MockGameNotifier{}
+		pubs := awaitPubAll(mgn)
+		uc := usecase.NewEmojixUsecase(nil, mgr, nil, nil, mgn, &servicetest.MockGameLoop{}, service.NewRealClock())
+
+		if err := uc.TransferHost(context.Background(), "game-id", "host-id", "teller-id"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		assertValue(t, "HostID", "teller-id", mgr.SetHostLastUserID)
+		assertPubAll(t, pubs, "hostchanged", "teller-id")
+	})
+
+	cases := []struct {
+		name string
+		call func(uc usecase.EmojixUsecase) error
+		want error
+	}{
+		{
+			name: "kick by a non-host",
+			call: func(uc usecase.EmojixUsecase) error {
+				return uc.KickPlayer(context.Background(), "game-id", "teller-id", "host-id")
+			},
+			want: usecase.ErrNotHost,
+		},
+		{
+			name: "host kicking themselves",
+			call: func(uc usecase.EmojixUsecase) error {
+				return uc.KickPlayer(context.Background(), "game-id", "host-id", "host-id")
+			},
+			want: usecase.ErrKickHost,
+		},
+		{
+			name: "kick someone not seated",
+			call: func(uc usecase.EmojixUsecase) error {
+				return uc.KickPlayer(context.Background(), "game-id", "host-id", "stranger-id")
+			},
+			want: usecase.ErrPlayerNotInGame,
+		},
+		{
+			name: "transfer to an inactive player",
+			call: func(uc usecase.EmojixUsecase) error {
+				return uc.TransferHost(context.Background(), "game-id", "host-id", "gone-id")
+			},
+			want: usecase.ErrPlayerNotInGame,
+		},
+		{
+			name: "skip by a non-host",
+			call: func(uc usecase.EmojixUsecase) error {
+				return uc.SkipTurn(context.Background(), "game-id", "teller-id")
+			},
+			want: usecase.ErrNotHost,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			// No write mocks wired: reaching them would panic.
+			mgl := &servicetest.MockGameLoop{}
+			uc := usecase.NewEmojixUsecase(nil, hostedRepo(), nil, nil, &servicetest.MockGameNotifier{}, mgl, service.NewRealClock())
+
+			if err := tc.call(uc); !errors.Is(err, tc.want) {
+				t.Errorf("expected %v but got %v", tc.want, err)
+			}
+			if mgl.SkipTurnCalled {
+				t.Error("expected GameLoop.SkipTurn not to be called")
+			}
+		})
+	}
+}
+
+func TestOnlySeatedPlayersGuess(t *testing.T) {
+	mgr := &repotest.MockGameRepository{
+		GetPlayersMock: func(ctx context.Context, id string) ([]model.Player, error) {
+			return []model.Player{
+				{ID: "p-1", State: model.ActivePlayerState},
+				{ID: "kicked", State: model.KickedPlayerState},
+				{ID: "gone", State: model.InactivePlayerState},
+			}, nil
+		},
+	}
+	uc := usecase.NewEmojixUsecase(nil, mgr, nil, nil, &servicetest.MockGameNotifier{}, &servicetest.MockGameLoop{}, service.NewRealClock())
+
+	for _, tc := range []struct {
+		userID string
+		want   error
+	}{
+		{"kicked", usecase.ErrPlayerKicked},
+		{"gone", usecase.ErrUserNotInGame},
+		{"outsider", usecase.ErrUserNotInGame},
+	} {
+		if _, err := uc.Guess(context.Background(), "game-1", tc.userID, "apple"); !errors.Is(err, tc.want) {
+			t.Errorf("Guess %s: expected %v but got %v", tc.userID, tc.want, err)
+		}
+		if err := uc.Message(context.Background(), "game-1", tc.userID, "hi"); !errors.Is(err, tc.want) {
+			t.Errorf("Message %s: expected %v but got %v", tc.userID, tc.want, err)
+		}
+	}
+	if mgr.SendMessageCalled {
+		t.Error("expected nothing to be stored")
+	}
+}
+
+func TestPrivateRooms(t *testing.T) {
+	mur := &repotest.MockUserRepository{
+		FindByIDMock: func(ctx context.Context, id string) (model.User, error) {
+			return model.User{ID: id, Nickname: "NewPlayer"}, nil
+		},
+	}
+	privateRepo := func(players []model.Player) *repotest.MockGameRepository {
+		game := model.Game{ID: "game-id", InviteCode: "ABC234", Settings: model.GameSettings{Private: true}}
+		return &repotest.MockGameRepository{
+			FindByIDMock: func(ctx context.Context, id string) (model.Game, error) {
+				return game, nil
+			},
+			FindByInviteCodeMock: func(ctx context.Context, code string) (model.Game, error) {
+				if code != game.InviteCode {
+					return model.Game{}, sql.ErrNoRows
+				}
+				return game, nil
+			},
+			GetPlayersMock: func(ctx context.Context, id string) ([]model.Player, error) {
+				return players, nil
+			},
+			SeatPlayerMock: func(ctx context.Context, gameID, userID string, maxPlayers int) (bool, error) {
+				return true, nil
+			},
+		}
+	}
+
+	t.Run("JoinGame needs the invite code", func(t *testing.T) {
+		mgr := privateRepo(nil)
+		uc := usecase.NewEmojixUsecase(mur, mgr, nil, nil, &servicetest.MockGameNotifier{}, &servicetest.MockGameLoop{}, service.NewRealClock())
+
+		err := uc.JoinGame(context.Background(), "game-id", "new-player-id", false)
+		if !errors.Is(err, usecase.ErrInviteRequired) {
+			t.Errorf("expected ErrInviteRequired but got %v", err)
+		}
+		if mgr.SeatPlayerCalled {
+			t.Error("expected GameRepository.SeatPlayer not to be called")
+		}
+	})
+
+	t.Run("JoinGameByCode seats a newcomer", func(t *testing.T) {
+		mgr := privateRepo(nil)
+		uc := usecase.NewEmojixUsecase(mur, mgr, nil, nil, &servicetest.MockGameNotifier{}, &servicetest.MockGameLoop{}, service.NewRealClock())
+
+		game, err := uc.JoinGameByCode(context.Background(), " abc234 ", "new-player-id")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		assertValue(t, "GameID", "game-id", game.ID)
+		if !mgr.SeatPlayerCalled {
+			t.Error("expected GameRepository.SeatPlayer to be called")
+		}
+	})
+
+	t.Run("JoinGameByCode rejects an unknown code", func(t *testing.T) {
+		uc := usecase.NewEmojixUsecase(mur, privateRepo(nil), nil, nil, &servicetest.MockGameNotifier{}, &servicetest.MockGameLoop{}, service.NewRealClock())
+
+		if _, err := uc.JoinGameByCode(context.Background(), "ZZZ999", "new-player-id"); !errors.Is(err, usecase.ErrInvalidInviteCode) {
+			t.Errorf("expected ErrInvalidInviteCode but got %v", err)
+		}
+	})
+
+	t.Run("kicked players stay out", func(t *testing.T) {
+		mgr := privateRepo([]model.Player{{ID: "new-player-id", State: model.KickedPlayerState}})
+		uc := usecase.NewEmojixUsecase(mur, mgr, nil, nil, &servicetest.MockGameNotifier{}, &servicetest.MockGameLoop{}, service.NewRealClock())
+
+		if _, err := uc.JoinGameByCode(context.Background(), "ABC234", "new-player-id"); !errors.Is(err, usecase.ErrPlayerKicked) {
+			t.Errorf("expected ErrPlayerKicked but got %v", err)
+		}
+	})
+
+	t.Run("a locked room still lets old players back", func(t *testing.T) {
+		mgr := &repotest.MockGameRepository{
+			FindByIDMock: func(ctx context.Context, id string) (model.Game, error) {
+				return model.Game{ID: id, Locked: true}, nil
+			},
+			GetPlayersMock: func(ctx context.Context, id string) ([]model.Player, error) {
+				return []model.Player{{ID: "old-player-id", State: model.InactivePlayerState}}, nil
+			},
+			SeatPlayerMock: func(ctx context.Context, gameID, userID string, maxPlayers int) (bool, error) {
+				return true, nil
+			},
+		}
+		uc := usecase.NewEmojixUsecase(mur, mgr, nil, nil, &servicetest.MockGameNotifier{}, &servicetest.MockGameLoop{}, service.NewRealClock())
+
+		if err := uc.JoinGame(context.Background(), "game-id", "old-player-id", false); err != nil {
+			t.Errorf("expected no error but got %v", err)
+		}
+		if err := uc.JoinGame(context.Background(), "game-id", "new-player-id", false); !errors.Is(err, usecase.ErrRoomLocked) {
+			t.Errorf("expected ErrRoomLocked but got %v", err)
+		}
+	})
+}
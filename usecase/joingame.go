@@ -3,14 +3,12 @@ package usecase
 import (
 	"context"
 	"emojix/model"
-	"errors"
 	"fmt"
+	"strings"
 )
 
-var maxRoomCapacity = 10
-
-var ErrJoinGameUserAlreadyJoined = errors.New("already joined")
-var ErrJoinGameRoomFull = errors.New("room is full")
+var ErrJoinGameUserAlreadyJoined = NewError(KindConflict, "already joined")
+var ErrJoinGameRoomFull = NewError(KindConflict, "room is full")
 
 type GameJoinNotification struct {
 	Nickname string
@@ -25,15 +23,51 @@ func (gmn *GameJoinNotification) GetData() string {
 	return fmt.Sprintf("%s,%s", gmn.PlayerID, gmn.Nickname)
 }
 
-func (e *emojixUsecase) JoinGame(ctx context.Context, gameID string, userID string) error {
-	// TODO: in the future there can be multiple users joined the game but only 10 of them can be active at the same time
-	// this repository call only get full list of players who joined the game, after addign activity logic with realtime features
-	// update this call as well
+// JoinGame seats userID in gameID, or with spectate puts them in the stands
+// without queueing for a seat.
+func (e *emojixUsecase) JoinGame(ctx context.Context, gameID string, userID string, spectate bool) error {
+	game, err := e.gameRepo.FindByID(ctx, gameID)
+	if err != nil {
+		return err
+	}
+	return e.joinGame(ctx, game, userID, false, spectate)
+}
+
+func (e *emojixUsecase) CheckMember(ctx context.Context, gameID, userID string) error {
+	players, err := e.gameRepo.GetPlayers(ctx, gameID)
+	if err != nil {
+		return err
+	}
+	for _, p := range players {
+		if p.ID != userID {
+			continue
+		}
+		if p.State == model.KickedPlayerState {
+			return ErrPlayerKicked
+		}
+		return nil
+	}
+	return ErrUserNotInGame
+}
+
+// joinGame seats userID in game. invited is true when they came in with the
+// invite code, which is what lets a new player into a private room. When
+// every seat is taken they join the stands instead, queued for the next one;
+// with spectate they go to the stands anyway, unqueued.
+func (e *emojixUsecase) joinGame(ctx context.Context, game model.Game, userID string, invited, spectate bool) error {
+	if e.draining() {
+		return ErrShuttingDown
+	}
+	gameID := game.ID
 	player, err := e.userRepo.FindByID(ctx, userID)
 	if err != nil {
 		return err
 	}
 
+	if game.Status == model.FinishedGameStatus {
+		return ErrGameFinished
+	}
+
 	players, err := e.gameRepo.GetPlayers(ctx, gameID)
 	if err != nil {
 		return err
@@ -46,27 +80,51 @@ func (e *emojixUsecase) JoinGame(ctx context.Context, gameID string, userID stri
 			activePlayers = append(activePlayers, p)
 		}
 
+		if p.ID == player.ID && p.State == model.KickedPlayerState {
+			return ErrPlayerKicked
+		}
 		if p.ID == player.ID && p.State == model.InactivePlayerState {
 			prevInactiveUser = true
 		}
-		if p.ID == player.ID && p.State == model.ActivePlayerState {
+		if p.ID == player.ID && (p.State == model.ActivePlayerState || p.State == model.SpectatorPlayerState) {
 			return ErrJoinGameUserAlreadyJoined
 		}
 	}
-
-	if len(activePlayers) >= maxRoomCapacity {
-		return ErrJoinGameRoomFull
+	// UpdateProfile keeps nicknames unique within a game; joining must too,
+	// or two players could not tell each other apart.
+	for _, p := range players {
+		if p.ID != player.ID && p.State != model.KickedPlayerState && strings.EqualFold(p.Nickname, player.Nickname) {
+			return ErrNicknameTaken
+		}
 	}
 
-	if prevInactiveUser {
-		err = e.gameRepo.SetPlayerState(ctx, gameID, player.ID, model.ActivePlayerState)
-	} else {
-		err = e.gameRepo.AddPlayer(ctx, gameID, player.ID)
+	// Lock and privacy only keep out newcomers; seated players may come back.
+	if !prevInactiveUser {
+		if game.Locked {
+			return ErrRoomLocked
+		}
+		if game.Settings.Private && !invited {
+			return ErrInviteRequired
+		}
 	}
 
+	if spectate {
+		return e.joinStands(ctx, gameID, player.ID, prevInactiveUser, players, false)
+	}
+	// The count and the seat are one guarded update, so concurrent joins
+	// and promotions can't overfill the room; whoever misses out queues.
+	seated, err := e.gameRepo.SeatPlayer(ctx, gameID, player.ID, e.withDefaults(game.Settings).MaxPlayers)
 	if err != nil {
 		return err
 	}
+	if !seated {
+		return e.joinStands(ctx, gameID, player.ID, prevInactiveUser, players, true)
+	}
+
+	// Rejoining players keep their side; newcomers even the teams out.
+	if err = e.assignTeam(ctx, game, activePlayers, model.Player{ID: player.ID, Team: teamOf(players, player.ID)}); err != nil {
+		return err
+	}
 
 	// First/second seat may unstick a waiting room or resume a paused game.
 	e.tryStartGame(ctx, gameID)
@@ -78,3 +136,24 @@ func (e *emojixUsecase) JoinGame(ctx context.Context, gameID string, userID stri
 
 	return nil
 }
+
+// joinStands puts userID in the stands, queued for a seat or just watching,
+// up to maxSpectators.
+func (e *emojixUsecase) joinStands(ctx context.Context, gameID, userID string, returning bool, players []model.Player, queued bool) error {
+	if countSpectators(players) >= maxSpectators {
+		return ErrJoinGameRoomFull
+	}
+	var err error
+	if returning {
+		err = e.gameRepo.SetSpectator(ctx, gameID, userID, queued)
+	} else {
+		err = e.gameRepo.AddSpectator(ctx, gameID, userID, queued)
+	}
+	if err != nil {
+		return err
+	}
+
+	go e.gameNotifier.Pub(gameID, userID, &SeatsNotification{})
+
+	return nil
+}
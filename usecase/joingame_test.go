@@ -45,11 +45,11 @@ func TestJoinGame(t *testing.T) {
 
 				return []model.Player{{ID: "other-player", Nickname: "OtherPlayer"}}, nil
 			},
-			AddPlayerMock: func(ctx context.Context, id, playerID string) error {
+			SeatPlayerMock: func(ctx context.Context, id, playerID string, maxPlayers int) (bool, error) {
 				assertCalledWith(t, "GameID", "some-game-id", id)
 				assertCalledWith(t, "PlayerID", "new-player-id", playerID)
 
-				return nil
+				return true, nil
 			},
 		}
 		pubCh := make(chan int)
@@ -70,13 +70,13 @@ func TestJoinGame(t *testing.T) {
 		emojiUsecase := usecase.NewEmojixUsecase(mur, mgr, nil, nil, mgns, &servicetest.MockGameLoop{}, service.NewRealClock())
 
 		ctx := context.Background()
-		err := emojiUsecase.JoinGame(ctx, "some-game-id", "new-player-id")
+		err := emojiUsecase.JoinGame(ctx, "some-game-id", "new-player-id", false)
 		if err != nil {
 			t.Errorf("expected no error but got %v", err)
 		}
 
-		if mgr.AddPlayerCalled == false {
-			t.Error("expected GameRepository.AddPlayer to be called")
+		if mgr.SeatPlayerCalled == false {
+			t.Error("expected GameRepository.SeatPlayer to be called")
 		}
 
 		<-pubCh
@@ -101,9 +101,6 @@ func TestJoinGame(t *testing.T) {
 			GetPlayersMock: func(ctx context.Context, id string) ([]model.Player, error) {
 				return []model.Player{{ID: "other-player-id", Nickname: "OtherPlayer", State: model.ActivePlayerState}}, nil
 			},
-			AddPlayerMock: func(ctx context.Context, id, playerID string) error {
-				return nil
-			},
 		}
 		pubCh := make(chan struct{}, 1)
 		mgns := &servicetest.MockGameNotifier{
@@ -115,13 +112,13 @@ func TestJoinGame(t *testing.T) {
 		emojiUsecase := usecase.NewEmojixUsecase(mur, mgr, nil, nil, mgns, &servicetest.MockGameLoop{}, service.NewRealClock())
 
 		ctx := context.Background()
-		err := emojiUsecase.JoinGame(ctx, "some-game-id", "other-player-id")
+		err := emojiUsecase.JoinGame(ctx, "some-game-id", "other-player-id", false)
 		if !errors.Is(usecase.ErrJoinGameUserAlreadyJoined, err) {
 			t.Errorf("expected already joined error but got %v", err)
 		}
 
-		if mgr.AddPlayerCalled == true {
-			t.Error("expected GameRepository.AddPlayer not to be called")
+		if mgr.SeatPlayerCalled == true {
+			t.Error("expected GameRepository.SeatPlayer not to be called")
 		}
 
 		assertPubNotCalled(t, pubCh)
@@ -145,11 +142,9 @@ func TestJoinGame(t *testing.T) {
 					{ID: "other-player-id", Nickname: "OtherPlayer", State: model.ActivePlayerState},
 				}, nil
 			},
-			AddPlayerMock: func(ctx context.Context, id, playerID string) error {
-				return nil
-			},
-			SetPlayerStateMock: func(ctx context.Context, gameID, userID, state model.PlayerState) error {
-				return nil
+			SeatPlayerMock: func(ctx context.Context, gameID, userID string, maxPlayers int) (bool, error) {
+				assertCalledWith(t, "PlayerID", "kicked-player-id", userID)
+				return true, nil
 			},
 		}
 
@@ -162,15 +157,11 @@ func TestJoinGame(t *testing.T) {
 		emojiUsecase := usecase.NewEmojixUsecase(mur, mgr, nil, nil, mgns, &servicetest.MockGameLoop{}, service.NewRealClock())
 
 		ctx := context.Background()
-		err := emojiUsecase.JoinGame(ctx, "some-game-id", "kicked-player-id")
+		err := emojiUsecase.JoinGame(ctx, "some-game-id", "kicked-player-id", false)
 		if err != nil {
 			t.Errorf("expected no error but got %v", err)
 		}
 
-		if mgr.AddPlayerCalled == true {
-			t.Error("expected GameRepository.AddPlayer not to be called")
-		}
-
 		// Wait for the go Pub(...) goroutine to finish before reading PubCalled.
 		select {
 		case <-pubCh:
@@ -182,12 +173,12 @@ func TestJoinGame(t *testing.T) {
 			t.Error("expected GameNotifier.Pub to be called")
 		}
 
-		if mgr.SetPlayerStateCalled == false {
-			t.Error("expected GameRepository.SetPlayerState to be called")
+		if mgr.SeatPlayerCalled == false {
+			t.Error("expected GameRepository.SeatPlayer to be called")
 		}
 	})
 
-	t.Run("fails to add if room is full", func(t *testing.T) {
+	t.Run("queues a spectator if room is full", func(t *testing.T) {
 		mur := &repotest.MockUserRepository{
 			FindByIDMock: func(ctx context.Context, id string) (model.User, error) {
 				return model.User{
@@ -198,7 +189,6 @@ func TestJoinGame(t *testing.T) {
 			},
 		}
 
-		addPlayerCalled := false
 		mgr := &repotest.MockGameRepository{
 			GetPlayersMock: func(ctx context.Context, id string) ([]model.Player, error) {
 				players := []model.Player{}
@@ -211,8 +201,10 @@ func TestJoinGame(t *testing.T) {
 				}
 				return players, nil
 			},
-			AddPlayerMock: func(ctx context.Context, id, playerID string) error {
-				addPlayerCalled = true
+			SeatPlayerMock: func(ctx context.Context, gameID, userID string, maxPlayers int) (bool, error) {
+				return false, nil
+			},
+			AddSpectatorMock: func(ctx context.Context, gameID, userID string, queued bool) error {
 				return nil
 			},
 		}
@@ -226,16 +218,100 @@ func TestJoinGame(t *testing.T) {
 		emojiUsecase := usecase.NewEmojixUsecase(mur, mgr, nil, nil, mgns, &servicetest.MockGameLoop{}, service.NewRealClock())
 
 		ctx := context.Background()
-		err := emojiUsecase.JoinGame(ctx, "some-game-id", "new-player-id")
-		if !errors.Is(usecase.ErrJoinGameRoomFull, err) {
-			t.Errorf("expected room full error but got %v", err)
+		err := emojiUsecase.JoinGame(ctx, "some-game-id", "new-player-id", false)
+		if err != nil {
+			t.Fatalf("expected no error but got %v", err)
 		}
 
-		if addPlayerCalled == true {
-			t.Error("expected GameRepository.AddPlayer not to be called")
+		if !mgr.AddSpectatorCalled {
+			t.Error("expected GameRepository.AddSpectator to be called")
 		}
 
-		assertPubNotCalled(t, pubCh)
+		select {
+		case <-pubCh:
+		case <-time.After(time.Second):
+			t.Error("expected the room to hear about the new spectator")
+		}
+	})
+
+	t.Run("rejects a finished game", func(t *testing.T) {
+		mur := &repotest.MockUserRepository{
+			FindByIDMock: func(ctx context.Context, id string) (model.User, error) {
+				return model.User{ID: id}, nil
+			},
+		}
+		mgr := &repotest.MockGameRepository{
+			FindByIDMock: func(ctx context.Context, id string) (model.Game, error) {
+				return model.Game{ID: id, Status: model.FinishedGameStatus}, nil
+			},
+		}
+		emojiUsecase := usecase.NewEmojixUsecase(mur, mgr, nil, nil, nil, &servicetest.MockGameLoop{}, service.NewRealClock())
+
+		err := emojiUsecase.JoinGame(context.Background(), "some-game-id", "new-player-id", false)
+		if !errors.Is(err, usecase.ErrGameFinished) {
+			t.Errorf("expected ErrGameFinished but got %v", err)
+		}
+		if mgr.SeatPlayerCalled {
+			t.Error("expected GameRepository.SeatPlayer not to be called")
+		}
+	})
+
+	t.Run("rejects a nickname already used in the game, ignoring case", func(t *testing.T) {
+		mur := &repotest.MockUserRepository{
+			FindByIDMock: func(ctx context.Context, id string) (model.User, error) {
+				return model.User{ID: "new-player-id", Nickname: "SillyCat"}, nil
+			},
+		}
+		mgr := &repotest.MockGameRepository{
+			GetPlayersMock: func(ctx context.Context, id string) ([]model.Player, error) {
+				return []model.Player{{ID: "p-1", Nickname: "sillycat", State: model.InactivePlayerState}}, nil
+			},
+		}
+		emojiUsecase := usecase.NewEmojixUsecase(mur, mgr, nil, nil, &servicetest.MockGameNotifier{}, &servicetest.MockGameLoop{}, service.NewRealClock())
+
+		err := emojiUsecase.JoinGame(context.Background(), "some-game-id", "new-player-id", false)
+		if !errors.Is(err, usecase.ErrNicknameTaken) {
+			t.Errorf("expected ErrNicknameTaken but got %v", err)
+		}
+		if mgr.SeatPlayerCalled || mgr.AddSpectatorCalled {
+			t.Error("expected the player not to be seated")
+		}
+	})
+
+	t.Run("uses the room capacity from game settings", func(t *testing.T) {
+		mur := &repotest.MockUserRepository{
+			FindByIDMock: func(ctx context.Context, id string) (model.User, error) {
+				return model.User{ID: "new-player-id", Nickname: "NewPlayer"}, nil
+			},
+		}
+		mgr := &repotest.MockGameRepository{
+			FindByIDMock: func(ctx context.Context, id string) (model.Game, error) {
+				return model.Game{ID: id, Settings: model.GameSettings{MaxPlayers: 2}}, nil
+			},
+			GetPlayersMock: func(ctx context.Context, id string) ([]model.Player, error) {
+				return []model.Player{
+					{ID: "p-1", State: model.ActivePlayerState},
+					{ID: "p-2", State: model.ActivePlayerState},
+				}, nil
+			},
+			SeatPlayerMock: func(ctx context.Context, gameID, userID string, maxPlayers int) (bool, error) {
+				assertCalledWith(t, "maxPlayers", 2, maxPlayers)
+				return false, nil
+			},
+			AddSpectatorMock: func(ctx context.Context, gameID, userID string, queued bool) error {
+				return nil
+			},
+		}
+
+		emojiUsecase := usecase.NewEmojixUsecase(mur, mgr, nil, nil, &servicetest.MockGameNotifier{}, &servicetest.MockGameLoop{}, service.NewRealClock())
+
+		err := emojiUsecase.JoinGame(context.Background(), "some-game-id", "new-player-id", false)
+		if err != nil {
+			t.Fatalf("expected no error but got %v", err)
+		}
+		if !mgr.AddSpectatorCalled {
+			t.Error("expected the third player to join the stands")
+		}
 	})
 
 	t.Run("second player starts the game loop", func(t *testing.T) {
@@ -260,9 +336,9 @@ func TestJoinGame(t *testing.T) {
 					{ID: "new-player-id", Nickname: "NewPlayer", State: model.ActivePlayerState},
 				}, nil
 			},
-			AddPlayerMock: func(ctx context.Context, id, playerID string) error { return nil },
+			SeatPlayerMock: func(ctx context.Context, gameID, userID string, maxPlayers int) (bool, error) { return true, nil },
 			FindByIDMock: func(ctx context.Context, id string) (model.Game, error) {
-				return model.Game{ID: id, ListID: "list-1"}, nil
+				return model.Game{ID: id, ListID: "list-1", Settings: model.GameSettings{TurnDuration: 90 * time.Second}}, nil
 			},
 			CountTurnsMock: func(ctx context.Context, gameID string) (int, error) { return 0, nil },
 			AddTurnMock: func(ctx context.Context, params repository.AddTurnParams) (model.GameTurn, error) {
@@ -277,6 +353,8 @@ func TestJoinGame(t *testing.T) {
 		startCh := make(chan struct{}, 1)
 		gl := &servicetest.MockGameLoop{
 			StartMock: func(ctx context.Context, gameID string, turnDuration, pickDuration time.Duration) {
+				assertCalledWith(t, "turnDuration", 90*time.Second, turnDuration)
+				assertCalledWith(t, "pickDuration", usecase.DefaultGameSettings().PickDuration, pickDuration)
 				startCh <- struct{}{}
 			},
 		}
@@ -294,7 +372,7 @@ func TestJoinGame(t *testing.T) {
 			},
 		}
 		emojiUsecase := usecase.NewEmojixUsecase(mur, mgr, mwr, nil, mgns, gl, service.NewRealClock())
-		err := emojiUsecase.JoinGame(context.Background(), "some-game-id", "new-player-id")
+		err := emojiUsecase.JoinGame(context.Background(), "some-game-id", "new-player-id", false)
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -319,3 +397,40 @@ func TestJoinGame(t *testing.T) {
 	})
 
 }
+
+func TestCheckMember(t *testing.T) {
+	players := []model.Player{
+		{ID: "active", State: model.ActivePlayerState},
+		{ID: "inactive", State: model.InactivePlayerState},
+		{ID: "kicked", State: model.KickedPlayerState},
+	}
+	dbErr := errors.New("db down")
+	cases := []struct {
+		name    string
+		userID  string
+		repoErr error
+		wantErr error
+	}{
+		{name: "active player", userID: "active"},
+		{name: "inactive player may reconnect", userID: "inactive"},
+		{name: "kicked player", userID: "kicked", wantErr: usecase.ErrPlayerKicked},
+		{name: "stranger", userID: "stranger", wantErr: usecase.ErrUserNotInGame},
+		{name: "repository error", userID: "active", repoErr: dbErr, wantErr: dbErr},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mgr := &repotest.MockGameRepository{
+				GetPlayersMock: func(ctx context.Context, id string) ([]model.Player, error) {
+					assertCalledWith(t, "GameID", "some-game-id", id)
+					return players, tc.repoErr
+				},
+			}
+			uc := usecase.NewEmojixUsecase(nil, mgr, nil, nil, nil, &servicetest.MockGameLoop{}, service.NewRealClock())
+
+			err := uc.CheckMember(context.Background(), "some-game-id", tc.userID)
+			if !errors.Is(err, tc.wantErr) {
+				t.Errorf("err = %v, want %v", err, tc.wantErr)
+			}
+		})
+	}
+}
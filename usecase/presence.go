@@ -0,0 +1,29 @@
+package usecase
+
+import (
+	"context"
+)
+
+// PresenceNotification tells the room a player went away or came back.
+type PresenceNotification struct {
+	UserID string
+	Away   bool
+}
+
+func (n *PresenceNotification) GetType() string {
+	if n.Away {
+		return "away"
+	}
+	return "back"
+}
+
+func (n *PresenceNotification) GetData() string {
+	return n.UserID
+}
+
+func (e *emojixUsecase) SetAway(ctx context.Context, gameID, userID string, away bool) error {
+	if e.presence.SetAway(gameID, userID, away) {
+		go e.gameNotifier.PubAll(gameID, &PresenceNotification{UserID: userID, Away: away})
+	}
+	return nil
+}
@@ -0,0 +1,237 @@
+package usecase_test
+
+import (
+	"context"
+	"emojix/model"
+	"emojix/repository/repotest"
+	"emojix/service"
+	"emojix/service/servicetest"
+	"emojix/usecase"
+	"testing"
+	"time"
+)
+
+func TestPresenceOnLeave(t *testing.T) {
+
+	t.Run("marks a departed player inactive", func(t *testing.T) {
+		pubCh := make(chan int)
+		mgn := &servicetest.MockGameNotifier{
+			PubMock: func(gameID, userID string, notif service.GameNotification) {
+				assertCalledWith(t, "GameID", "game-id", gameID)
+				assertCalledWith(t, "UserID", "user-4", userID)
+				assertCalledWith(t, "NotifType", "left", notif.GetType())
+				assertCalledWith(t, "NotifData", "user-4", notif.GetData())
+
+				pubCh <- 1
+				close(pubCh)
+			},
+		}
+
+		mgr := &repotest.MockGameRepository{
+			SetPlayerStateMock: func(ctx context.Context, gameID, userID, state model.PlayerState) error {
+				assertCalledWith(t, "GameID", "game-id", gameID)
+				assertCalledWith(t, "UserID", "user-4", userID)
+				assertCalledWith(t, "State", "inactive", state)
+				return nil
+			},
+		}
+		mp := &servicetest.MockPresence{}
+		_ = usecase.NewEmojixUsecase(
+			nil,
+			mgr,
+			nil,
+			nil,
+			mgn,
+			&servicetest.MockGameLoop{},
+			service.NewRealClock(),
+			usecase.WithPresence(mp),
+		)
+
+		mp.OnLeaveHandler(context.Background(), "game-id", "user-4", time.Now())
+
+		if mgr.SetPlayerStateCalled != true {
+			t.Error("expected GameRepository.SetPlayerState to be called")
+		}
+
+		select {
+		case <-pubCh:
+		case <-time.After(time.Second * 1):
+		}
+
+		if mgn.PubCalled != true {
+			t.Error("expected NotifierService.Pub to be called")
+		}
+	})
+
+	t.Run("keeps a player who reconnected", func(t *testing.T) {
+		pubCh := make(chan struct{}, 1)
+		mgn := &servicetest.MockGameNotifier{
+			PubMock: func(gameID, userID string, notif service.GameNotification) {
+				pubCh <- struct{}{}
+			},
+		}
+
+		mgr := &repotest.MockGameRepository{
+			SetPlayerStateMock: func(ctx context.Context, gameID, userID, state model.PlayerState) error {
+				return nil
+			},
+		}
+		mp := &servicetest.MockPresence{
+			StateMock: func(gameID, userID string) service.PresenceState {
+				return service.Online
+			},
+		}
+		_ = usecase.NewEmojixUsecase(
+			nil,
+			mgr,
+			nil,
+			nil,
+			mgn,
+			&servicetest.MockGameLoop{},
+			service.NewRealClock(),
+			usecase.WithPresence(mp),
+		)
+
+		mp.OnLeaveHandler(context.Background(), "game-id", "user-1", time.Now())
+
+		if mgr.SetPlayerStateCalled != false {
+			t.Error("expected GameRepository.SetPlayerState not to be called")
+		}
+
+		assertPubNotCalled(t, pubCh)
+	})
+
+	t.Run("keeps a player connected to another instance", func(t *testing.T) {
+		goneAt := time.Now()
+		pubCh := make(chan struct{}, 1)
+		mgn := &servicetest.MockGameNotifier{
+			PubMock: func(gameID, userID string, notif service.GameNotification) {
+				pubCh <- struct{}{}
+			},
+		}
+
+		mgr := &repotest.MockGameRepository{
+			GetPlayersMock: func(ctx context.Context, id string) ([]model.Player, error) {
+				return []model.Player{
+					{ID: "user-1", State: model.ActivePlayerState, LastSeenAt: goneAt.Add(service.HeartbeatInterval)},
+				}, nil
+			},
+			SetPlayerStateMock: func(ctx context.Context, gameID, userID, state model.PlayerState) error {
+				return nil
+			},
+		}
+		mp := &servicetest.MockPresence{}
+		_ = usecase.NewEmojixUsecase(
+			nil,
+			mgr,
+			nil,
+			nil,
+			mgn,
+			&servicetest.MockGameLoop{},
+			service.NewRealClock(),
+			usecase.WithPresence(mp),
+		)
+
+		mp.OnLeaveHandler(context.Background(), "game-id", "user-1", goneAt)
+
+		if mgr.SetPlayerStateCalled != false {
+			t.Error("expected GameRepository.SetPlayerState not to be called")
+		}
+
+		assertPubNotCalled(t, pubCh)
+	})
+
+}
+
+func TestSetAway(t *testing.T) {
+	pubCh := make(chan service.GameNotification, 2)
+	mgn := &servicetest.MockGameNotifier{
+		PubAllMock: func(gameID string, notif service.GameNotification) {
+			pubCh <- notif
+		},
+	}
+	changed := true
+	mp := &servicetest.MockPresence{
+		SetAwayMock: func(gameID, userID string, away bool) bool {
+			return changed
+		},
+	}
+	uc := usecase.NewEmojixUsecase(nil, nil, nil, nil, mgn, &servicetest.MockGameLoop{}, service.NewRealClock(), usecase.WithPresence(mp))
+
+	if err := uc.SetAway(context.Background(), "game-id", "user-1", true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	select {
+	case notif := <-pubCh:
+		assertValue(t, "NotifType", "away", notif.GetType())
+		assertValue(t, "NotifData", "user-1", notif.GetData())
+	case <-time.After(time.Second):
+		t.Fatal("expected an away notification")
+	}
+
+	changed = false
+	if err := uc.SetAway(context.Background(), "game-id", "user-1", true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	select {
+	case notif := <-pubCh:
+		t.Errorf("expected no notification for an unchanged state but got %s", notif.GetType())
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestGameUpdates_HeartbeatKeepsPresence(t *testing.T) {
+	fc := servicetest.NewFakeClock()
+	beats := make(chan struct{}, 4)
+	released := false
+	mp := &servicetest.MockPresence{
+		ConnectMock: func(gameID, userID string) (func(), func()) {
+			return func() { beats <- struct{}{} }, func() { released = true }
+		},
+	}
+	mgn := &servicetest.MockGameNotifier{
+		SubMock: func(gameID, userID string) (chan service.GameNotification, func()) {
+			return make(chan service.GameNotification), func() {}
+		},
+	}
+	var seen []time.Time
+	mgr := &repotest.MockGameRepository{
+		SeenPlayerMock: func(ctx context.Context, gameID, userID string, at time.Time) error {
+			seen = append(seen, at)
+			return nil
+		},
+	}
+	uc := usecase.NewEmojixUsecase(nil, mgr, nil, nil, mgn, &servicetest.MockGameLoop{}, fc, usecase.WithPresence(mp))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error)
+	var gotType string
+	go func() {
+		done <- uc.GameUpdates(ctx, "game-id", "user-1", 0, func(eventID uint64, notifType, data string) error {
+			gotType = notifType
+			return nil
+		})
+	}()
+
+	for fc.PendingTimers() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	fc.Advance(service.HeartbeatInterval)
+
+	select {
+	case <-beats:
+	case <-time.After(time.Second):
+		t.Fatal("expected a heartbeat to beat presence")
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertValue(t, "NotifType", usecase.HeartbeatEvent, gotType)
+	if !released {
+		t.Error("expected the presence connection to be released")
+	}
+	// Once on connect and once per heartbeat, for the other instances.
+	assertValue(t, "SeenPlayer calls", 2, len(seen))
+}
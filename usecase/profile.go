@@ -0,0 +1,199 @@
+package usecase
+
+import (
+	"context"
+	"emojix/model"
+	"emojix/repository"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var ErrNicknameLength = NewError(KindValidation, fmt.Sprintf("nickname must be %d to %d characters", minNicknameRunes, maxNicknameRunes))
+var ErrNicknameChars = NewError(KindValidation, "nickname may only use letters, digits, spaces, '-', '_' and '.'")
+var ErrNicknameProfane = NewError(KindValidation, "pick a friendlier nickname")
+var ErrNicknameTaken = NewError(KindConflict, "another player in your game already uses that nickname")
+var ErrInvalidAvatar = NewError(KindValidation, "avatar must be a single emoji")
+
+const (
+	minNicknameRunes = 2
+	maxNicknameRunes = 20
+)
+
+// blockedNicknameWords are matched against the words of the nickname with
+// case and leetspeak folded away, so "Sh1t Lord" is caught but "Scunthorpe"
+// is not. Words split at separators and camel case, and letters spelled out
+// one by one count as one word, so "F.u_c-k" and "BigShit" are caught too.
+var blockedNicknameWords = []string{
+	"fuck", "shit", "cunt", "bitch", "nigger", "nigga", "faggot", "whore",
+	"slut", "asshole", "pussy", "bastard", "nazi", "hitler",
+}
+
+var leetReplacer = strings.NewReplacer("0", "o", "1", "i", "3", "e", "4", "a", "5", "s", "7", "t")
+
+type NickChangedNotification struct {
+	UserID   string
+	Nickname string
+	Avatar   string
+}
+
+func (n *NickChangedNotification) GetType() string { return "nickchanged" }
+
+// GetData is "<user id>,<nickname>,<avatar>"; neither a valid nickname nor an
+// avatar can contain a comma.
+func (n *NickChangedNotification) GetData() string {
+	return fmt.Sprintf("%s,%s,%s", n.UserID, n.Nickname, n.Avatar)
+}
+
+// UpdateProfile sets userID's nickname and avatar and tells every game they
+// are still seated in. Nicknames are unique, ignoring case, within each of
+// those games; the check and the write share a unit of work so two players
+// can't rename to the same nickname at once.
+func (e *emojixUsecase) UpdateProfile(ctx context.Context, userID, nickname, avatar string) (model.User, error) {
+	user, err := e.GetUser(ctx, userID)
+	if err != nil {
+		return model.User{}, err
+	}
+
+	nickname = normalizeNickname(nickname)
+	if err := ValidateNickname(nickname); err != nil {
+		return model.User{}, err
+	}
+	avatar = strings.TrimSpace(avatar)
+	if avatar != "" && !IsSingleEmoji(avatar) {
+		return model.User{}, ErrInvalidAvatar
+	}
+
+	uow, err := e.unitOfWorkFactory.New(ctx)
+	if err != nil {
+		return model.User{}, err
+	}
+	defer uow.Rollback()
+	gameRepo := uow.GameRepository()
+
+	gameIDs, err := gameRepo.ListOpenGameIDs(ctx, userID)
+	if err != nil {
+		return model.User{}, err
+	}
+	for _, gameID := range gameIDs {
+		players, err := gameRepo.GetPlayers(ctx, gameID)
+		if err != nil {
+			return model.User{}, err
+		}
+		for _, p := range players {
+			if p.ID != userID && p.State != model.KickedPlayerState && strings.EqualFold(p.Nickname, nickname) {
+				return model.User{}, ErrNicknameTaken
+			}
+		}
+	}
+
+	err = uow.UserRepository().CreateOrUpdate(ctx, userID, repository.UserCreateOrUpdateParams{Nickname: nickname, Avatar: avatar})
+	if err != nil {
+		return model.User{}, err
+	}
+	if err = uow.Commit(); err != nil {
+		return model.User{}, err
+	}
+	user.Nickname = nickname
+	user.Avatar = avatar
+
+	for _, gameID := range gameIDs {
+		go e.gameNotifier.PubAll(gameID, &NickChangedNotification{UserID: userID, Nickname: nickname, Avatar: avatar})
+	}
+
+	return user, nil
+}
+
+// normalizeNickname trims the nickname and collapses inner runs of spaces.
+func normalizeNickname(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// ValidateNickname checks an already normalized nickname. Commas are refused
+// with the other punctuation because notifications are comma separated.
+func ValidateNickname(nickname string) error {
+	n := utf8.RuneCountInString(nickname)
+	if n < minNicknameRunes || n > maxNicknameRunes {
+		return ErrNicknameLength
+	}
+	for _, r := range nickname {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune(" -_.", r) {
+			continue
+		}
+		return ErrNicknameChars
+	}
+
+	for _, word := range nicknameWords(nickname) {
+		if slices.Contains(blockedNicknameWords, leetReplacer.Replace(strings.ToLower(word))) {
+			return ErrNicknameProfane
+		}
+	}
+	return nil
+}
+
+// nicknameWords splits nickname at separators and lower-to-upper case
+// changes, then joins runs of single letters back into one word.
+func nicknameWords(nickname string) []string {
+	parts := []string{}
+	var cur []rune
+	flush := func() {
+		if len(cur) > 0 {
+			parts = append(parts, string(cur))
+			cur = nil
+		}
+	}
+	for _, r := range nickname {
+		if strings.ContainsRune(" -_.", r) {
+			flush()
+			continue
+		}
+		if len(cur) > 0 && unicode.IsUpper(r) && !unicode.IsUpper(cur[len(cur)-1]) {
+			flush()
+		}
+		cur = append(cur, r)
+	}
+	flush()
+
+	words := []string{}
+	spelled := false // the last word was built from single letters
+	for _, p := range parts {
+		single := utf8.RuneCountInString(p) == 1
+		if single && spelled {
+			words[len(words)-1] += p
+			continue
+		}
+		words = append(words, p)
+		spelled = single
+	}
+	return words
+}
+
+// IsSingleEmoji reports whether s is one emoji: a symbol, optionally with
+// skin tone and variation selectors, a flag, or a zero-width-joiner sequence
+// of those.
+func IsSingleEmoji(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, part := range strings.Split(s, "\u200d") {
+		bases, flags := 0, 0
+		for _, r := range part {
+			switch {
+			case r == '\ufe0f' || r == '\ufe0e': // variation selectors
+			case r >= 0x1f3fb && r <= 0x1f3ff: // skin tone modifiers
+			case r >= 0x1f1e6 && r <= 0x1f1ff: // regional indicators pair into flags
+				flags++
+			case unicode.Is(unicode.So, r):
+				bases++
+			default:
+				return false
+			}
+		}
+		if !(bases == 1 && flags == 0 || bases == 0 && flags == 2) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,203 @@
+package usecase_test
+
+import (
+	"context"
+	"emojix/model"
+	"emojix/repository"
+	"emojix/repository/repotest"
+	"emojix/service"
+	"emojix/service/servicetest"
+	"emojix/usecase"
+	"errors"
+	"testing"
+)
+
+func TestValidateNickname(t *testing.T) {
+	cases := []struct {
+		nickname string
+		want     error
+	}{
+		{"SillyCat", nil},
+		{"Ana-Maria_2", nil},
+		{"Zoë", nil},
+		{"x", usecase.ErrNicknameLength},
+		{"ThisNicknameIsWayTooLong", usecase.ErrNicknameLength},
+		{"Cat,Dog", usecase.ErrNicknameChars},
+		{"<b>hi</b>", usecase.ErrNicknameChars},
+		{"sh1t lord", usecase.ErrNicknameProfane},
+		{"F.u_c-k", usecase.ErrNicknameProfane},
+		{"BigSh1t", usecase.ErrNicknameProfane},
+		{"NAZI", usecase.ErrNicknameProfane},
+		// Only whole words count, so innocent names that contain one pass.
+		{"Scunthorpe", nil},
+		{"Shitake Mushroom", nil},
+	}
+	for _, tc := range cases {
+		t.Run(tc.nickname, func(t *testing.T) {
+			if err := usecase.ValidateNickname(tc.nickname); !errors.Is(err, tc.want) {
+				t.Errorf("got %v, want %v", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestIsSingleEmoji(t *testing.T) {
+	cases := map[string]bool{
+		"🦊":             true,
+		"❤\ufe0f":       true,
+		"👍🏽":            true,
+		"🇹🇷":            true,
+		"👩\u200d🚀":      true,
+		"🦊🐼":            false,
+		"a":             false,
+		"<":             false,
+		"🦊 ":            false,
+		"":              false,
+		"🇹":             false,
+		"👩\u200d\u200d": false,
+	}
+	for in, want := range cases {
+		if got := usecase.IsSingleEmoji(in); got != want {
+			t.Errorf("IsSingleEmoji(%q) = %v, want %v", in, got, want)
+		}
+	}
+}
+
+func TestUpdateProfile(t *testing.T) {
+	const userID = "user-1"
+	userRepo := func(saved *repository.UserCreateOrUpdateParams) *repotest.MockUserRepository {
+		return &repotest.MockUserRepository{
+			FindByIDMock: func(ctx context.Context, id string) (model.User, error) {
+				assertCalledWith(t, "ID", userID, id)
+				return model.User{ID: userID, Nickname: "SillyCat"}, nil
+			},
+			CreateOrUpdateMock: func(ctx context.Context, id string, params repository.UserCreateOrUpdateParams) error {
+				assertCalledWith(t, "ID", userID, id)
+				*saved = params
+				return nil
+			},
+		}
+	}
+	gameRepo := func(players map[string][]model.Player) *repotest.MockGameRepository {
+		return &repotest.MockGameRepository{
+			ListOpenGameIDsMock: func(ctx context.Context, id string) ([]string, error) {
+				assertCalledWith(t, "UserID", userID, id)
+				ids := []string{}
+				for gameID := range players {
+					ids = append(ids, gameID)
+				}
+				return ids, nil
+			},
+			GetPlayersMock: func(ctx context.Context, gameID string) ([]model.Player, error) {
+				return players[gameID], nil
+			},
+		}
+	}
+
+	// inUnitOfWork serves both repositories from one unit of work, as the
+	// uniqueness check and the write must share it.
+	inUnitOfWork := func(mur *repotest.MockUserRepository, mgr *repotest.MockGameRepository) (*repotest.MockUnitOfWork, repository.UnitOfWorkFactory) {
+		uow := &repotest.MockUnitOfWork{
+			UserRepositoryMock: mur,
+			GameRepositoryMock: mgr,
+			CommitMock:         func() error { return nil },
+			RollbackMock:       func() error { return nil },
+		}
+		return uow, &repotest.MockUnitOfWorkFactory{
+			NewMock: func(ctx context.Context) (repository.UnitOfWork, error) { return uow, nil },
+		}
+	}
+
+	t.Run("saves the profile and tells every open game", func(t *testing.T) {
+		var saved repository.UserCreateOrUpdateParams
+		mgr := gameRepo(map[string][]model.Player{
+			"game-1": {{ID: userID, Nickname: "SillyCat"}, {ID: "user-2", Nickname: "AngryDog"}},
+			"game-2": {{ID: userID, Nickname: "SillyCat"}, {ID: "user-3", Nickname: "Otter", State: model.KickedPlayerState}},
+		})
+		pubCh := make(chan service.GameNotification, 2)
+		games := make(chan string, 2)
+		mgn := &servicetest.MockGameNotifier{
+			PubAllMock: func(gameID string, n service.GameNotification) {
+				games <- gameID
+				pubCh <- n
+			},
+		}
+		mur := userRepo(&saved)
+		uow, factory := inUnitOfWork(mur, mgr)
+		uc := usecase.NewEmojixUsecase(mur, &repotest.MockGameRepository{}, nil, factory, mgn, &servicetest.MockGameLoop{}, service.NewRealClock())
+
+		user, err := uc.UpdateProfile(context.Background(), userID, "  Sly   otter ", " 🦦 ")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !uow.CommitCalled {
+			t.Error("expected the unit of work to be committed")
+		}
+		assertValue(t, "Nickname", "Sly otter", user.Nickname)
+		assertValue(t, "Avatar", "🦦", user.Avatar)
+		assertValue(t, "SavedNickname", "Sly otter", saved.Nickname)
+		assertValue(t, "SavedAvatar", "🦦", saved.Avatar)
+
+		for _, n := range drainPub(t, pubCh, 2) {
+			assertValue(t, "NotifType", "nickchanged", n.GetType())
+			assertValue(t, "NotifData", userID+",Sly otter,🦦", n.GetData())
+		}
+		got := map[string]bool{<-games: true, <-games: true}
+		if !got["game-1"] || !got["game-2"] {
+			t.Errorf("expected pubs to game-1 and game-2, got %v", got)
+		}
+	})
+
+	t.Run("refuses a nickname taken in one of the games", func(t *testing.T) {
+		var saved repository.UserCreateOrUpdateParams
+		mur := userRepo(&saved)
+		mgr := gameRepo(map[string][]model.Player{
+			"game-1": {{ID: userID, Nickname: "SillyCat"}, {ID: "user-2", Nickname: "AngryDog"}},
+		})
+		uow, factory := inUnitOfWork(mur, mgr)
+		uc := usecase.NewEmojixUsecase(mur, &repotest.MockGameRepository{}, nil, factory, &servicetest.MockGameNotifier{}, &servicetest.MockGameLoop{}, service.NewRealClock())
+
+		_, err := uc.UpdateProfile(context.Background(), userID, "angrydog", "")
+		if !errors.Is(err, usecase.ErrNicknameTaken) {
+			t.Fatalf("got %v, want ErrNicknameTaken", err)
+		}
+		if mur.CreateOrUpdateCalled {
+			t.Error("expected CreateOrUpdate not to be called")
+		}
+		if uow.CommitCalled || !uow.RollbackCalled {
+			t.Error("expected the unit of work to be rolled back")
+		}
+	})
+
+	t.Run("refuses an invalid avatar before touching games", func(t *testing.T) {
+		var saved repository.UserCreateOrUpdateParams
+		mur := userRepo(&saved)
+		uc := usecase.NewEmojixUsecase(mur, &repotest.MockGameRepository{}, nil, nil, nil, &servicetest.MockGameLoop{}, service.NewRealClock())
+
+		_, err := uc.UpdateProfile(context.Background(), userID, "SillyCat", "🦊🐼")
+		if !errors.Is(err, usecase.ErrInvalidAvatar) {
+			t.Fatalf("got %v, want ErrInvalidAvatar", err)
+		}
+		if mur.CreateOrUpdateCalled {
+			t.Error("expected CreateOrUpdate not to be called")
+		}
+	})
+}
+
+func TestGeneratedNicknamesAreValid(t *testing.T) {
+	mur := &repotest.MockUserRepository{
+		CreateOrUpdateMock: func(ctx context.Context, id string, params repository.UserCreateOrUpdateParams) error {
+			return nil
+		},
+	}
+	uc := usecase.NewEmojixUsecase(mur, nil, nil, nil, nil, &servicetest.MockGameLoop{}, service.NewRealClock())
+	for i := 0; i < 200; i++ {
+		user, err := uc.InitUser(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := usecase.ValidateNickname(user.Nickname); err != nil {
+			t.Fatalf("generated nickname %q is invalid: %v", user.Nickname, err)
+		}
+	}
+}
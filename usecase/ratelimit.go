@@ -0,0 +1,174 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrSlowDown is what every rate-limit refusal wraps; errors.As with
+// *SlowDownError gives the wait.
+var ErrSlowDown = NewError(KindRateLimited, "slow down")
+
+// SlowDownError refuses an action taken too fast. RetryAfter is how long
+// until it would be allowed.
+type SlowDownError struct {
+	RetryAfter time.Duration
+}
+
+func (e *SlowDownError) Error() string {
+	secs := int(math.Ceil(e.RetryAfter.Seconds()))
+	return fmt.Sprintf("slow down, try again in %ds", max(secs, 1))
+}
+
+func (e *SlowDownError) Unwrap() error { return ErrSlowDown }
+
+// RateLimit is a token bucket: Burst actions at once, then one more every
+// Every. A zero Burst means no limit.
+type RateLimit struct {
+	Burst int
+	Every time.Duration
+}
+
+// String is the "burst/every" form ParseRateLimit reads, e.g. "5/1s".
+func (l RateLimit) String() string {
+	if l.Burst == 0 {
+		return "off"
+	}
+	return fmt.Sprintf("%d/%s", l.Burst, l.Every)
+}
+
+// ParseRateLimit reads "burst/every", e.g. "5/2s"; "off" or "0" disables the
+// limit.
+func ParseRateLimit(s string) (RateLimit, error) {
+	s = strings.TrimSpace(s)
+	if s == "off" || s == "0" {
+		return RateLimit{}, nil
+	}
+	burst, every, ok := strings.Cut(s, "/")
+	if !ok {
+		return RateLimit{}, errors.New(`want "burst/every", e.g. "5/2s", or "off"`)
+	}
+	n, err := strconv.Atoi(burst)
+	if err != nil || n < 1 {
+		return RateLimit{}, fmt.Errorf("burst %q is not a positive number", burst)
+	}
+	d, err := time.ParseDuration(every)
+	if err != nil || d <= 0 {
+		return RateLimit{}, fmt.Errorf("interval %q is not a positive duration", every)
+	}
+	return RateLimit{Burst: n, Every: d}, nil
+}
+
+// RateLimits caps how fast one player may act in one game.
+type RateLimits struct {
+	Guess   RateLimit
+	Message RateLimit
+	// WrongGuessCooldown, when set, blocks a player's next guess for that
+	// long after each wrong one, so the word list can't be brute-forced.
+	WrongGuessCooldown time.Duration
+}
+
+func DefaultRateLimits() RateLimits {
+	return RateLimits{
+		Guess:   RateLimit{Burst: 5, Every: time.Second},
+		Message: RateLimit{Burst: 5, Every: 2 * time.Second},
+	}
+}
+
+// WithRateLimits replaces DefaultRateLimits.
+func WithRateLimits(l RateLimits) Option {
+	return func(e *emojixUsecase) {
+		e.rateLimits = l
+	}
+}
+
+type rateAction string
+
+const (
+	guessAction   rateAction = "guess"
+	messageAction rateAction = "message"
+)
+
+type rateKey struct {
+	action         rateAction
+	gameID, userID string
+}
+
+type rateBucket struct {
+	tokens        float64
+	last          time.Time
+	refill        time.Duration // from empty to full
+	cooldownUntil time.Time
+}
+
+// maxIdleBuckets is how many buckets may pile up before full, idle ones are
+// dropped; a dropped bucket is the same as a new one.
+const maxIdleBuckets = 4096
+
+// rateLimiter holds one bucket per action, game and player.
+type rateLimiter struct {
+	mu      sync.Mutex
+	buckets map[rateKey]*rateBucket
+}
+
+// take spends one token from key's bucket, refilled up to now, or reports
+// how long until one is available.
+func (l *rateLimiter) take(key rateKey, limit RateLimit, now time.Time) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.buckets == nil {
+		l.buckets = map[rateKey]*rateBucket{}
+	}
+	b, ok := l.buckets[key]
+	if !ok {
+		if len(l.buckets) >= maxIdleBuckets {
+			l.prune(now)
+		}
+		b = &rateBucket{tokens: float64(limit.Burst), last: now, refill: time.Duration(limit.Burst) * limit.Every}
+		l.buckets[key] = b
+	}
+
+	if now.Before(b.cooldownUntil) {
+		return &SlowDownError{RetryAfter: b.cooldownUntil.Sub(now)}
+	}
+	if limit.Burst == 0 {
+		return nil
+	}
+	b.tokens = min(float64(limit.Burst), b.tokens+float64(now.Sub(b.last))/float64(limit.Every))
+	b.last = now
+	if b.tokens < 1 {
+		return &SlowDownError{RetryAfter: time.Duration((1 - b.tokens) * float64(limit.Every))}
+	}
+	b.tokens--
+	return nil
+}
+
+// cooldown blocks key until now+d.
+func (l *rateLimiter) cooldown(key rateKey, d time.Duration, now time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if b, ok := l.buckets[key]; ok {
+		b.cooldownUntil = now.Add(d)
+	}
+}
+
+// prune drops buckets that have refilled and have no cooldown left. The
+// caller holds mu.
+func (l *rateLimiter) prune(now time.Time) {
+	for key, b := range l.buckets {
+		if now.Sub(b.last) >= b.refill && !now.Before(b.cooldownUntil) {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+func (e *emojixUsecase) allow(action rateAction, limit RateLimit, gameID, userID string) error {
+	return e.limiter.take(rateKey{action, gameID, userID}, limit, e.clock.Now())
+}
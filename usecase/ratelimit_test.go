@@ -0,0 +1,151 @@
+package usecase_test
+
+import (
+	"context"
+	"emojix/model"
+	"emojix/repository"
+	"emojix/repository/repotest"
+	"emojix/service/servicetest"
+	"emojix/usecase"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseRateLimit(t *testing.T) {
+	for in, want := range map[string]usecase.RateLimit{
+		"5/1s":   {Burst: 5, Every: time.Second},
+		" 3/2s ": {Burst: 3, Every: 2 * time.Second},
+		"off":    {},
+		"0":      {},
+	} {
+		got, err := usecase.ParseRateLimit(in)
+		if err != nil || got != want {
+			t.Errorf("ParseRateLimit(%q) = %+v, %v; want %+v", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"5", "0/1s", "x/1s", "5/soon", "5/-1s"} {
+		if _, err := usecase.ParseRateLimit(in); err == nil {
+			t.Errorf("ParseRateLimit(%q) accepted", in)
+		}
+	}
+}
+
+// newRateLimitedUsecase wires a usecase whose guesses are always wrong and
+// whose messages always go through, on a fake clock.
+func newRateLimitedUsecase(limits usecase.RateLimits) (usecase.EmojixUsecase, *servicetest.FakeClock) {
+	mgr := &repotest.MockGameRepository{
+		GetPlayersMock: func(ctx context.Context, id string) ([]model.Player, error) {
+			return []model.Player{{ID: "p-1", State: model.ActivePlayerState}, {ID: "p-2", State: model.ActivePlayerState}}, nil
+		},
+		GetLatestTurnMock: func(ctx context.Context, id string) (model.GameTurn, error) {
+			return model.GameTurn{ID: "turn-1", WordID: "w-1", TellerID: "teller-1"}, nil
+		},
+		SendMessageMock: func(ctx context.Context, g, turn, u, content string) (model.Message, error) {
+			return model.Message{ID: "msg-1", PlayerID: u, Content: content, TurnID: turn}, nil
+		},
+	}
+	mwr := &repotest.MockWordRepository{
+		FindByIDMock: func(ctx context.Context, id string) (model.Word, error) {
+			return model.Word{ID: id, Word: "Secret"}, nil
+		},
+	}
+	mur := &repotest.MockUserRepository{
+		FindByIDMock: func(ctx context.Context, id string) (model.User, error) {
+			return model.User{ID: id, Nickname: "Nick"}, nil
+		},
+	}
+	factory := &repotest.MockUnitOfWorkFactory{
+		NewMock: func(ctx context.Context) (repository.UnitOfWork, error) {
+			return &repotest.MockUnitOfWork{
+				GameRepositoryMock: mgr,
+				CommitMock:         func() error { return nil },
+				RollbackMock:       func() error { return nil },
+			}, nil
+		},
+	}
+	clock := servicetest.NewFakeClock()
+	uc := usecase.NewEmojixUsecase(mur, mgr, mwr, factory, &servicetest.MockGameNotifier{}, &servicetest.MockGameLoop{}, clock,
+		usecase.WithRateLimits(limits))
+	return uc, clock
+}
+
+func assertSlowDown(t *testing.T, err error, retryAfter time.Duration) {
+	t.Helper()
+	var slow *usecase.SlowDownError
+	if !errors.As(err, &slow) || !errors.Is(err, usecase.ErrSlowDown) {
+		t.Fatalf("got %v, want a SlowDownError", err)
+	}
+	assertValue(t, "RetryAfter", retryAfter, slow.RetryAfter)
+	assertValue(t, "Kind", usecase.KindRateLimited, usecase.KindOf(err))
+}
+
+func TestRateLimits(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("guesses refill one per interval", func(t *testing.T) {
+		uc, clock := newRateLimitedUsecase(usecase.RateLimits{Guess: usecase.RateLimit{Burst: 2, Every: time.Second}})
+
+		for i := 0; i < 2; i++ {
+			if _, err := uc.Guess(ctx, "game-1", "p-1", "nope"); err != nil {
+				t.Fatalf("guess %d: %v", i+1, err)
+			}
+		}
+		_, err := uc.Guess(ctx, "game-1", "p-1", "nope")
+		assertSlowDown(t, err, time.Second)
+
+		// Buckets are per player and per game.
+		if _, err := uc.Guess(ctx, "game-1", "p-2", "nope"); err != nil {
+			t.Fatalf("other player: %v", err)
+		}
+		if _, err := uc.Guess(ctx, "game-2", "p-1", "nope"); err != nil {
+			t.Fatalf("other game: %v", err)
+		}
+
+		clock.Advance(time.Second)
+		if _, err := uc.Guess(ctx, "game-1", "p-1", "nope"); err != nil {
+			t.Fatalf("after refill: %v", err)
+		}
+	})
+
+	t.Run("a wrong guess starts the cooldown", func(t *testing.T) {
+		uc, clock := newRateLimitedUsecase(usecase.RateLimits{WrongGuessCooldown: 3 * time.Second})
+
+		if _, err := uc.Guess(ctx, "game-1", "p-1", "nope"); err != nil {
+			t.Fatalf("first guess: %v", err)
+		}
+		clock.Advance(time.Second)
+		_, err := uc.Guess(ctx, "game-1", "p-1", "nope")
+		assertSlowDown(t, err, 2*time.Second)
+
+		clock.Advance(2 * time.Second)
+		if _, err := uc.Guess(ctx, "game-1", "p-1", "nope"); err != nil {
+			t.Fatalf("after cooldown: %v", err)
+		}
+	})
+
+	t.Run("messages have their own bucket", func(t *testing.T) {
+		uc, _ := newRateLimitedUsecase(usecase.RateLimits{
+			Guess:   usecase.RateLimit{Burst: 1, Every: time.Second},
+			Message: usecase.RateLimit{Burst: 1, Every: 4 * time.Second},
+		})
+
+		if _, err := uc.Guess(ctx, "game-1", "p-1", "nope"); err != nil {
+			t.Fatalf("guess: %v", err)
+		}
+		if err := uc.Message(ctx, "game-1", "p-1", "hi"); err != nil {
+			t.Fatalf("first message: %v", err)
+		}
+		assertSlowDown(t, uc.Message(ctx, "game-1", "p-1", "hi again"), 4*time.Second)
+	})
+
+	t.Run("off means unlimited", func(t *testing.T) {
+		uc, _ := newRateLimitedUsecase(usecase.RateLimits{})
+
+		for i := 0; i < 50; i++ {
+			if err := uc.Message(ctx, "game-1", "p-1", "spam"); err != nil {
+				t.Fatalf("message %d: %v", i+1, err)
+			}
+		}
+	})
+}
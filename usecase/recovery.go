@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+)
+
+// RecoverGames restarts the loops of games a previous process left playing.
+// Each resumes its latest turn with whatever time its deadline leaves; a turn
+// whose deadline passed meanwhile ends at once through the normal turn-end
+// path, as does one that had ended but not yet been followed by the next.
+// Games whose loop already runs (here or, with leases, elsewhere) are skipped.
+func (e *emojixUsecase) RecoverGames(ctx context.Context) error {
+	ids, err := e.gameRepo.ListPlayingIDs(ctx)
+	if err != nil {
+		return err
+	}
+	for _, gameID := range ids {
+		if e.gameLoop.Running(gameID) {
+			continue
+		}
+		if err := e.recoverGame(ctx, gameID); err != nil {
+			log.Printf("failed to recover game %s: %v", gameID, err)
+		}
+	}
+	return nil
+}
+
+// WatchGames reruns the recovery pass every interval. With leases, a game
+// whose owner crashed counts as not running once its lease lapses, and the
+// next pass on any instance resumes it there.
+func (e *emojixUsecase) WatchGames(ctx context.Context, every time.Duration) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-e.clock.After(every):
+		}
+		if err := e.RecoverGames(ctx); err != nil {
+			log.Printf("failed to recover running games: %v", err)
+		}
+	}
+}
+
+func (e *emojixUsecase) recoverGame(ctx context.Context, gameID string) error {
+	game, err := e.gameRepo.FindByID(ctx, gameID)
+	if err != nil {
+		return err
+	}
+	turn, err := e.gameRepo.GetLatestTurn(ctx, gameID)
+	if errors.Is(err, sql.ErrNoRows) {
+		e.tryStartGame(ctx, gameID)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	settings := e.withDefaults(game.Settings)
+	now := e.clock.Now()
+	switch {
+	case !turn.EndedAt.IsZero():
+		e.gameLoop.Resume(context.Background(), gameID, settings.TurnDuration, settings.PickDuration, true, 0)
+	case turn.WordID != "":
+		e.gameLoop.Resume(context.Background(), gameID, settings.TurnDuration, settings.PickDuration, true, turn.EndDeadline.Sub(now))
+	default:
+		e.gameLoop.Resume(context.Background(), gameID, settings.TurnDuration, settings.PickDuration, false, turn.PickDeadline.Sub(now))
+	}
+	return nil
+}
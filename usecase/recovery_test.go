@@ -0,0 +1,160 @@
+package usecase_test
+
+import (
+	"context"
+	"emojix/model"
+	"emojix/repository/repotest"
+	"emojix/service"
+	"emojix/service/servicetest"
+	"emojix/usecase"
+	"testing"
+	"time"
+)
+
+func TestRecoverGames(t *testing.T) {
+	fc := servicetest.NewFakeClock()
+	now := fc.Now()
+
+	tests := []struct {
+		name          string
+		turn          model.GameTurn
+		running       bool
+		wantResume    bool
+		wantPicked    bool
+		wantRemaining time.Duration
+	}{
+		{
+			name:          "resumes the pick phase with the time left",
+			turn:          model.GameTurn{ID: "t1", PickDeadline: now.Add(12 * time.Second)},
+			wantResume:    true,
+			wantRemaining: 12 * time.Second,
+		},
+		{
+			name:          "resumes play with the time left",
+			turn:          model.GameTurn{ID: "t1", WordID: "w1", PickDeadline: now.Add(-time.Minute), EndDeadline: now.Add(40 * time.Second)},
+			wantResume:    true,
+			wantPicked:    true,
+			wantRemaining: 40 * time.Second,
+		},
+		{
+			name:          "ends a turn whose deadline passed while down",
+			turn:          model.GameTurn{ID: "t1", WordID: "w1", EndDeadline: now.Add(-time.Hour)},
+			wantResume:    true,
+			wantPicked:    true,
+			wantRemaining: -time.Hour,
+		},
+		{
+			name:          "finishes the turn end of an already ended turn",
+			turn:          model.GameTurn{ID: "t1", WordID: "w1", EndDeadline: now.Add(-time.Hour), EndedAt: now.Add(-time.Hour)},
+			wantResume:    true,
+			wantPicked:    true,
+			wantRemaining: 0,
+		},
+		{
+			name:    "skips games whose loop already runs",
+			turn:    model.GameTurn{ID: "t1", PickDeadline: now.Add(12 * time.Second)},
+			running: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mgr := &repotest.MockGameRepository{
+				ListPlayingIDsMock: func(ctx context.Context) ([]string, error) {
+					return []string{"game-1"}, nil
+				},
+				GetLatestTurnMock: func(ctx context.Context, id string) (model.GameTurn, error) {
+					assertCalledWith(t, "GameID", "game-1", id)
+					return tt.turn, nil
+				},
+			}
+			gl := &servicetest.MockGameLoop{
+				RunningMock: func(gameID string) bool { return tt.running },
+				ResumeMock: func(ctx context.Context, gameID string, turnDuration, pickDuration time.Duration, picked bool, remaining time.Duration) {
+					assertCalledWith(t, "GameID", "game-1", gameID)
+					assertCalledWith(t, "TurnDuration", usecase.DefaultGameSettings().TurnDuration, turnDuration)
+					assertCalledWith(t, "Picked", tt.wantPicked, picked)
+					assertCalledWith(t, "Remaining", tt.wantRemaining, remaining)
+				},
+			}
+			uc := usecase.NewEmojixUsecase(nil, mgr, nil, nil, &servicetest.MockGameNotifier{}, gl, fc)
+
+			if err := uc.RecoverGames(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			assertValue(t, "ResumeCalled", tt.wantResume, gl.ResumeCalled)
+		})
+	}
+}
+
+func TestOnTurnEnd_RecordsEndedAt(t *testing.T) {
+	fc := servicetest.NewFakeClock()
+	endedCh := make(chan time.Time, 1)
+	mgr := &repotest.MockGameRepository{
+		EndTurnMock: func(ctx context.Context, gameID string, endedAt time.Time) error {
+			assertCalledWith(t, "GameID", "game-1", gameID)
+			endedCh <- endedAt
+			return nil
+		},
+		GetPlayersMock: func(ctx context.Context, id string) ([]model.Player, error) {
+			return nil, nil // too few players: the game pauses
+		},
+	}
+	gl := &servicetest.MockGameLoop{}
+	mgn := &servicetest.MockGameNotifier{PubAllMock: func(string, service.GameNotification) {}}
+	_ = usecase.NewEmojixUsecase(nil, mgr, nil, nil, mgn, gl, fc)
+
+	done := make(chan struct{})
+	go func() {
+		gl.FireOnTurnEnd(context.Background(), "game-1")
+		close(done)
+	}()
+	select {
+	case endedAt := <-endedCh:
+		assertValue(t, "EndedAt", fc.Now(), endedAt)
+	case <-time.After(time.Second):
+		t.Fatal("expected the turn to be stamped ended")
+	}
+	for fc.PendingTimers() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	fc.Advance(5 * time.Second)
+	<-done
+}
+
+func TestWatchGames(t *testing.T) {
+	fc := servicetest.NewFakeClock()
+	mgr := &repotest.MockGameRepository{
+		ListPlayingIDsMock: func(ctx context.Context) ([]string, error) { return []string{"game-1"}, nil },
+		GetLatestTurnMock: func(ctx context.Context, id string) (model.GameTurn, error) {
+			return model.GameTurn{ID: "t1", WordID: "w1", EndDeadline: fc.Now().Add(time.Second)}, nil
+		},
+	}
+	resumed := make(chan string, 1)
+	gl := &servicetest.MockGameLoop{
+		ResumeMock: func(ctx context.Context, gameID string, turnDuration, pickDuration time.Duration, picked bool, remaining time.Duration) {
+			resumed <- gameID
+		},
+	}
+	uc := usecase.NewEmojixUsecase(nil, mgr, nil, nil, &servicetest.MockGameNotifier{}, gl, fc)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go uc.WatchGames(ctx, time.Minute)
+
+	for fc.PendingTimers() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	select {
+	case <-resumed:
+		t.Fatal("expected nothing before the first interval")
+	default:
+	}
+	fc.Advance(time.Minute)
+	select {
+	case id := <-resumed:
+		assertValue(t, "GameID", "game-1", id)
+	case <-time.After(time.Second):
+		t.Fatal("expected the sweep to resume the orphaned game")
+	}
+}
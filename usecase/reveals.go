@@ -0,0 +1,158 @@
+package usecase
+
+import (
+	"context"
+	"emojix/model"
+	"fmt"
+	"log"
+	mathRand "math/rand"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// RevealsOff in GameSettings.Reveals turns reveals off even when the
+	// server default has some.
+	RevealsOff = "off"
+
+	maxReveals = 5
+	// revealPenaltyPercent is taken off a solver's points for each letter
+	// revealed before their guess. A solver always keeps at least one point.
+	revealPenaltyPercent = 25
+)
+
+// RevealNotification tells the room another letter of the word is showing.
+type RevealNotification struct {
+	Count int // letters revealed so far this turn
+}
+
+func (n *RevealNotification) GetType() string { return "reveal" }
+func (n *RevealNotification) GetData() string { return strconv.Itoa(n.Count) }
+
+// parseReveals reads a reveal schedule: comma-separated percents of the turn,
+// each between 1 and 99 and rising. Empty and RevealsOff mean none.
+func parseReveals(s string) ([]int, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || s == RevealsOff {
+		return nil, nil
+	}
+	parts := strings.Split(s, ",")
+	if len(parts) > maxReveals {
+		return nil, fmt.Errorf("at most %d reveals", maxReveals)
+	}
+	percents := make([]int, 0, len(parts))
+	for _, part := range parts {
+		pct, err := strconv.Atoi(strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(part), "%")))
+		if err != nil || pct < 1 || pct > 99 {
+			return nil, fmt.Errorf("reveal %q is not a percent between 1 and 99", part)
+		}
+		if len(percents) > 0 && pct <= percents[len(percents)-1] {
+			return nil, fmt.Errorf("reveals must rise")
+		}
+		percents = append(percents, pct)
+	}
+	return percents, nil
+}
+
+// revealOffsets is when, into the play phase, each reveal of settings is due.
+func revealOffsets(settings model.GameSettings) []time.Duration {
+	percents, _ := parseReveals(settings.Reveals) // validated on create
+	offsets := make([]time.Duration, len(percents))
+	for i, pct := range percents {
+		offsets[i] = settings.TurnDuration * time.Duration(pct) / 100
+	}
+	return offsets
+}
+
+// onReveal is the game loop's reveal handler. It unmasks a letter for every
+// reveal that has come due (catching up after a restart) and returns the wait
+// until the next one.
+func (e *emojixUsecase) onReveal(ctx context.Context, gameID string) time.Duration {
+	turn, err := e.gameRepo.GetLatestTurn(ctx, gameID)
+	if err != nil {
+		log.Printf("onReveal GetLatestTurn: %v", err)
+		return 0
+	}
+	if turn.WordID == "" || !turn.EndedAt.IsZero() {
+		return 0
+	}
+	game, err := e.gameRepo.FindByID(ctx, gameID)
+	if err != nil {
+		log.Printf("onReveal FindByID: %v", err)
+		return 0
+	}
+	offsets := revealOffsets(e.withDefaults(game.Settings))
+	elapsed := e.clock.Now().Sub(turn.StartedAt)
+	due := 0
+	for due < len(offsets) && offsets[due] <= elapsed {
+		due++
+	}
+
+	if due > len(turn.Revealed) {
+		word, err := e.wordRepo.FindByID(ctx, turn.WordID)
+		if err != nil {
+			log.Printf("onReveal word: %v", err)
+			return 0
+		}
+		revealed := revealLetters(word.Word, turn.Revealed, due-len(turn.Revealed))
+		if len(revealed) > len(turn.Revealed) {
+			if err := e.gameRepo.SetTurnRevealed(ctx, turn.ID, revealed); err != nil {
+				log.Printf("onReveal SetTurnRevealed: %v", err)
+				return 0
+			}
+			go e.gameNotifier.PubAll(gameID, &RevealNotification{Count: len(revealed)})
+		}
+	}
+
+	if due < len(offsets) {
+		return offsets[due] - elapsed
+	}
+	return 0
+}
+
+// revealLetters adds n random hidden letters of word to revealed. One letter
+// always stays hidden so a reveal never gives the word away.
+func revealLetters(word string, revealed []int, n int) []int {
+	hidden := []int{}
+	for i, r := range []rune(word) {
+		if maskable(r) && !slices.Contains(revealed, i) {
+			hidden = append(hidden, i)
+		}
+	}
+	out := slices.Clone(revealed)
+	for ; n > 0 && len(hidden) > 1; n-- {
+		j := mathRand.Intn(len(hidden))
+		out = append(out, hidden[j])
+		hidden = slices.Delete(hidden, j, j+1)
+	}
+	return out
+}
+
+// maskWord replaces each letter of word with '*', except the revealed
+// positions (rune indexes).
+func maskWord(word string, revealed []int) string {
+	runes := []rune(word)
+	for i, r := range runes {
+		if maskable(r) && !slices.Contains(revealed, i) {
+			runes[i] = '*'
+		}
+	}
+	return string(runes)
+}
+
+// maskable matches what the mask has always hidden: ASCII letters, digits
+// and '_' (regexp's \w).
+func maskable(r rune) bool {
+	return r < utf8.RuneSelf && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_')
+}
+
+// afterReveals cuts a solver's points by revealPenaltyPercent for each of
+// the reveals made before the guess.
+func afterReveals(points, reveals int) int {
+	cut := min(reveals*revealPenaltyPercent, 100)
+	return max(points*(100-cut)/100, 1)
+}
@@ -0,0 +1,108 @@
+package usecase_test
+
+import (
+	"context"
+	"emojix/model"
+	"emojix/repository/repotest"
+	"emojix/service"
+	"emojix/service/servicetest"
+	"emojix/usecase"
+	"testing"
+	"time"
+)
+
+func TestOnReveal(t *testing.T) {
+	const gameID = "game-1"
+	game := model.Game{ID: gameID, Settings: model.GameSettings{TurnDuration: 60 * time.Second, Reveals: "50,80"}}
+
+	setup := func(turn model.GameTurn) (*servicetest.MockGameLoop, *servicetest.FakeClock, *[][]int, chan service.GameNotification) {
+		clock := servicetest.NewFakeClock()
+		var saved [][]int
+		mgr := &repotest.MockGameRepository{
+			FindByIDMock: func(ctx context.Context, id string) (model.Game, error) { return game, nil },
+			GetLatestTurnMock: func(ctx context.Context, id string) (model.GameTurn, error) {
+				return turn, nil
+			},
+			SetTurnRevealedMock: func(ctx context.Context, turnID string, revealed []int) error {
+				assertCalledWith(t, "TurnID", "turn-1", turnID)
+				saved = append(saved, revealed)
+				return nil
+			},
+		}
+		mwr := &repotest.MockWordRepository{
+			FindByIDMock: func(ctx context.Context, id string) (model.Word, error) {
+				return model.Word{ID: id, Word: "ice cream"}, nil
+			},
+		}
+		pubCh := make(chan service.GameNotification, 4)
+		mgn := &servicetest.MockGameNotifier{
+			PubAllMock: func(gameID string, n service.GameNotification) { pubCh <- n },
+		}
+		gl := &servicetest.MockGameLoop{}
+		usecase.NewEmojixUsecase(nil, mgr, mwr, nil, mgn, gl, clock)
+		turn.StartedAt = clock.Now()
+		return gl, clock, &saved, pubCh
+	}
+
+	t.Run("waits for the first reveal", func(t *testing.T) {
+		gl, clock, saved, _ := setup(model.GameTurn{ID: "turn-1", WordID: "w-1"})
+		clock.Advance(10 * time.Second)
+
+		next := gl.FireOnReveal(context.Background(), gameID)
+		assertValue(t, "next", 20*time.Second, next)
+		assertValue(t, "saved", 0, len(*saved))
+	})
+
+	t.Run("catches up on every reveal due", func(t *testing.T) {
+		gl, clock, saved, pubCh := setup(model.GameTurn{ID: "turn-1", WordID: "w-1"})
+		clock.Advance(50 * time.Second)
+
+		next := gl.FireOnReveal(context.Background(), gameID)
+		assertValue(t, "next", time.Duration(0), next)
+		if len(*saved) != 1 || len((*saved)[0]) != 2 {
+			t.Fatalf("expected two letters saved at once, got %v", *saved)
+		}
+		n := drainPub(t, pubCh, 1)[0]
+		assertValue(t, "type", "reveal", n.GetType())
+		assertValue(t, "data", "2", n.GetData())
+	})
+
+	t.Run("keeps what was already revealed", func(t *testing.T) {
+		gl, clock, saved, _ := setup(model.GameTurn{ID: "turn-1", WordID: "w-1", Revealed: []int{4}})
+		clock.Advance(50 * time.Second)
+
+		gl.FireOnReveal(context.Background(), gameID)
+		if len(*saved) != 1 || len((*saved)[0]) != 2 || (*saved)[0][0] != 4 {
+			t.Fatalf("expected one more letter after position 4, got %v", *saved)
+		}
+	})
+
+	t.Run("ended turn reveals nothing", func(t *testing.T) {
+		gl, clock, saved, _ := setup(model.GameTurn{ID: "turn-1", WordID: "w-1", EndedAt: time.Now()})
+		clock.Advance(50 * time.Second)
+
+		assertValue(t, "next", time.Duration(0), gl.FireOnReveal(context.Background(), gameID))
+		assertValue(t, "saved", 0, len(*saved))
+	})
+}
+
+func TestGameWordReveals(t *testing.T) {
+	mgr := &repotest.MockGameRepository{
+		GetLatestTurnMock: func(ctx context.Context, id string) (model.GameTurn, error) {
+			return model.GameTurn{ID: "t-1", WordID: "w-1", TellerID: "teller", Revealed: []int{0, 4}}, nil
+		},
+		GetScoresMock: func(ctx context.Context, id string) ([]model.Score, error) { return nil, nil },
+	}
+	mwr := &repotest.MockWordRepository{
+		FindByIDMock: func(ctx context.Context, id string) (model.Word, error) {
+			return model.Word{ID: id, Word: "ice cream"}, nil
+		},
+	}
+	uc := usecase.NewEmojixUsecase(nil, mgr, mwr, nil, nil, &servicetest.MockGameLoop{}, service.NewRealClock())
+
+	got, err := uc.GameWord(context.Background(), "game-1", "p-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertValue(t, "word", "i** c****", got)
+}
@@ -0,0 +1,117 @@
+package usecase
+
+import "time"
+
+// Scoring policy names stored in GameSettings.Scoring.
+const (
+	ClassicScoring = "classic"
+	TimedScoring   = "timed"
+)
+
+// ScoreAward is one score row to write with AddScore. Negative points are
+// penalties.
+type ScoreAward struct {
+	PlayerID string
+	Points   int
+}
+
+// ScoringEvent describes the turn at the moment something scoreable happened.
+type ScoringEvent struct {
+	PlayerID       string // guesser, or the teller for TellerMessage
+	TellerID       string
+	ActiveGuessers int // active non-teller players
+	PriorCorrect   int // guessers who already got it this turn
+	TurnPoints     int // PlayerID's points so far this turn
+	Reveals        int // letters revealed before the guess
+	Elapsed        time.Duration
+	TurnDuration   time.Duration
+}
+
+// ScoringPolicy decides the points for each scoreable event. Policies only
+// compute awards; the usecase writes them with AddScore inside the same unit
+// of work as the message that caused them.
+//
+// A correct guess must award the guesser a positive score: a positive row on
+// a turn is what marks a player as having guessed it.
+type ScoringPolicy interface {
+	CorrectGuess(ev ScoringEvent) []ScoreAward
+	WrongGuess(ev ScoringEvent) []ScoreAward
+	TellerMessage(ev ScoringEvent) []ScoreAward
+}
+
+const (
+	classicGuessPoints          = 10
+	tellerPointsPerCorrectGuess = 5
+	// tellerMessagePenalty is taken from the teller's current-turn points only
+	// (not banked totals). Floor is 0 — messages are free once turn points are gone.
+	tellerMessagePenalty = 2
+
+	timedBasePoints        = 5
+	timedWrongGuessPenalty = 1
+)
+
+// NewClassicScoring is the original formula: early solvers get
+// 10 * (guessers / solvers so far), the teller gets 5 per solver and pays 2
+// per hint message out of their turn points. Solvers lose a quarter of their
+// points for each letter revealed before they guessed.
+func NewClassicScoring() ScoringPolicy {
+	return classicScoring{}
+}
+
+type classicScoring struct{}
+
+func (classicScoring) CorrectGuess(ev ScoringEvent) []ScoreAward {
+	guessers := max(ev.ActiveGuessers, 1) // solo edge case, avoid div by zero
+	coeff := max(guessers/(ev.PriorCorrect+1), 1)
+	return withTellerBonus(ev, afterReveals(classicGuessPoints*coeff, ev.Reveals))
+}
+
+func (classicScoring) WrongGuess(ev ScoringEvent) []ScoreAward {
+	return nil
+}
+
+func (classicScoring) TellerMessage(ev ScoringEvent) []ScoreAward {
+	return tellerPenalty(ev)
+}
+
+// NewTimedScoring rewards speed: a solver gets 5 plus one point per second
+// left on the turn clock, and each wrong guess costs 1. Reveal cuts and
+// teller rules match classic.
+func NewTimedScoring() ScoringPolicy {
+	return timedScoring{}
+}
+
+type timedScoring struct{}
+
+func (timedScoring) CorrectGuess(ev ScoringEvent) []ScoreAward {
+	left := max(ev.TurnDuration-ev.Elapsed, 0)
+	return withTellerBonus(ev, afterReveals(timedBasePoints+int(left/time.Second), ev.Reveals))
+}
+
+func (timedScoring) WrongGuess(ev ScoringEvent) []ScoreAward {
+	return []ScoreAward{{PlayerID: ev.PlayerID, Points: -timedWrongGuessPenalty}}
+}
+
+func (timedScoring) TellerMessage(ev ScoringEvent) []ScoreAward {
+	return tellerPenalty(ev)
+}
+
+func withTellerBonus(ev ScoringEvent, guesserPoints int) []ScoreAward {
+	awards := []ScoreAward{{PlayerID: ev.PlayerID, Points: guesserPoints}}
+	if ev.TellerID != "" {
+		awards = append(awards, ScoreAward{PlayerID: ev.TellerID, Points: tellerPointsPerCorrectGuess})
+	}
+	return awards
+}
+
+func tellerPenalty(ev ScoringEvent) []ScoreAward {
+	if ev.TurnPoints <= 0 {
+		return nil
+	}
+	return []ScoreAward{{PlayerID: ev.PlayerID, Points: -min(tellerMessagePenalty, ev.TurnPoints)}}
+}
+
+var defaultScoringPolicies = map[string]ScoringPolicy{
+	ClassicScoring: NewClassicScoring(),
+	TimedScoring:   NewTimedScoring(),
+}
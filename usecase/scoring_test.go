@@ -0,0 +1,53 @@
+package usecase_test
+
+import (
+	"emojix/usecase"
+	"testing"
+	"time"
+)
+
+func TestScoringPolicies(t *testing.T) {
+	classic := usecase.NewClassicScoring()
+	timed := usecase.NewTimedScoring()
+
+	guess := usecase.ScoringEvent{
+		PlayerID:       "g",
+		TellerID:       "t",
+		ActiveGuessers: 4,
+		Elapsed:        20 * time.Second,
+		TurnDuration:   60 * time.Second,
+	}
+	later := guess
+	later.PriorCorrect = 3
+	overtime := guess
+	overtime.Elapsed = 2 * time.Minute
+	revealed := guess
+	revealed.Reveals = 2
+	allRevealed := overtime
+	allRevealed.Reveals = 5
+
+	teller := usecase.ScoringEvent{PlayerID: "t", TellerID: "t", TurnPoints: 1}
+	broke := teller
+	broke.TurnPoints = 0
+
+	cases := []struct {
+		name string
+		got  []usecase.ScoreAward
+		want []usecase.ScoreAward
+	}{
+		{"classic first solver", classic.CorrectGuess(guess), []usecase.ScoreAward{{PlayerID: "g", Points: 40}, {PlayerID: "t", Points: 5}}},
+		{"classic last solver", classic.CorrectGuess(later), []usecase.ScoreAward{{PlayerID: "g", Points: 10}, {PlayerID: "t", Points: 5}}},
+		{"classic wrong guess is free", classic.WrongGuess(guess), nil},
+		{"classic teller penalty clamps", classic.TellerMessage(teller), []usecase.ScoreAward{{PlayerID: "t", Points: -1}}},
+		{"classic teller with no turn points", classic.TellerMessage(broke), nil},
+		{"timed base plus seconds left", timed.CorrectGuess(guess), []usecase.ScoreAward{{PlayerID: "g", Points: 45}, {PlayerID: "t", Points: 5}}},
+		{"timed past the clock keeps base", timed.CorrectGuess(overtime), []usecase.ScoreAward{{PlayerID: "g", Points: 5}, {PlayerID: "t", Points: 5}}},
+		{"classic after two reveals", classic.CorrectGuess(revealed), []usecase.ScoreAward{{PlayerID: "g", Points: 20}, {PlayerID: "t", Points: 5}}},
+		{"timed after two reveals", timed.CorrectGuess(revealed), []usecase.ScoreAward{{PlayerID: "g", Points: 22}, {PlayerID: "t", Points: 5}}},
+		{"reveal cuts keep one point", timed.CorrectGuess(allRevealed), []usecase.ScoreAward{{PlayerID: "g", Points: 1}, {PlayerID: "t", Points: 5}}},
+		{"timed wrong guess costs one", timed.WrongGuess(guess), []usecase.ScoreAward{{PlayerID: "g", Points: -1}}},
+	}
+	for _, tc := range cases {
+		assertValue(t, tc.name, tc.want, tc.got)
+	}
+}
@@ -0,0 +1,55 @@
+package usecase
+
+import (
+	"context"
+)
+
+var ErrShuttingDown = NewError(KindUnavailable, "the server is restarting, try again in a moment")
+
+// ServerRestartNotification is the last event of every stream when the server
+// shuts down; browsers reconnect to whichever instance comes up next.
+type ServerRestartNotification struct{}
+
+func (n *ServerRestartNotification) GetType() string { return "serverrestart" }
+func (n *ServerRestartNotification) GetData() string { return "" }
+
+func (e *emojixUsecase) Shutdown(ctx context.Context) error {
+	e.shutdownMu.Lock()
+	e.shuttingDown = true
+	e.shutdownMu.Unlock()
+
+	e.gameLoop.Stop()
+
+	done := make(chan struct{})
+	go func() {
+		e.turnEnds.Wait()
+		close(done)
+	}()
+	var err error
+	select {
+	case <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	e.gameNotifier.Close(&ServerRestartNotification{})
+	return err
+}
+
+func (e *emojixUsecase) draining() bool {
+	e.shutdownMu.Lock()
+	defer e.shutdownMu.Unlock()
+	return e.shuttingDown
+}
+
+// beginTurnEnd registers a turn end with Shutdown, unless it already began.
+// Callers that get true call turnEnds.Done when finished.
+func (e *emojixUsecase) beginTurnEnd() bool {
+	e.shutdownMu.Lock()
+	defer e.shutdownMu.Unlock()
+	if e.shuttingDown {
+		return false
+	}
+	e.turnEnds.Add(1)
+	return true
+}
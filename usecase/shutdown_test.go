@@ -0,0 +1,106 @@
+package usecase_test
+
+import (
+	"context"
+	"emojix/model"
+	"emojix/repository/repotest"
+	"emojix/service"
+	"emojix/service/servicetest"
+	"emojix/usecase"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestShutdown(t *testing.T) {
+	t.Run("waits for a turn end under way, then closes streams", func(t *testing.T) {
+		release := make(chan struct{})
+		inTurnEnd := make(chan struct{})
+		mgr := &repotest.MockGameRepository{
+			EndTurnMock: func(ctx context.Context, gameID string, endedAt time.Time) error {
+				close(inTurnEnd)
+				<-release
+				return nil
+			},
+			GetPlayersMock: func(ctx context.Context, id string) ([]model.Player, error) {
+				return nil, nil
+			},
+		}
+		gl := &servicetest.MockGameLoop{}
+		var final service.GameNotification
+		mgn := &servicetest.MockGameNotifier{
+			CloseMock: func(n service.GameNotification) { final = n },
+		}
+		fc := servicetest.NewFakeClock()
+		uc := usecase.NewEmojixUsecase(nil, mgr, nil, nil, mgn, gl, fc)
+
+		go gl.FireOnTurnEnd(context.Background(), "game-1")
+		<-inTurnEnd
+
+		done := make(chan error, 1)
+		go func() { done <- uc.Shutdown(context.Background()) }()
+		select {
+		case <-done:
+			t.Fatal("Shutdown returned while a turn end was still running")
+		case <-time.After(20 * time.Millisecond):
+		}
+
+		close(release)
+		for fc.PendingTimers() == 0 {
+			time.Sleep(time.Millisecond)
+		}
+		fc.Advance(5 * time.Second)
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Fatal(err)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("Shutdown did not return after the turn end finished")
+		}
+		assertValue(t, "StopCalled", true, gl.StopCalled)
+		assertValue(t, "final event", "serverrestart", final.GetType())
+	})
+
+	t.Run("gives up waiting when ctx is done", func(t *testing.T) {
+		stuck := make(chan struct{})
+		t.Cleanup(func() { close(stuck) })
+		mgr := &repotest.MockGameRepository{
+			EndTurnMock: func(ctx context.Context, gameID string, endedAt time.Time) error {
+				<-stuck // a turn end that outlasts the deadline
+				return nil
+			},
+		}
+		gl := &servicetest.MockGameLoop{}
+		mgn := &servicetest.MockGameNotifier{}
+		// The fake clock never advances, so the turn end stops at its pause.
+		uc := usecase.NewEmojixUsecase(nil, mgr, nil, nil, mgn, gl, servicetest.NewFakeClock())
+		go gl.FireOnTurnEnd(context.Background(), "game-1")
+		time.Sleep(10 * time.Millisecond)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		if err := uc.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected DeadlineExceeded, got %v", err)
+		}
+		assertValue(t, "CloseCalled", true, mgn.CloseCalled)
+	})
+
+	t.Run("refuses joins and skips new turn ends", func(t *testing.T) {
+		mgr := &repotest.MockGameRepository{}
+		gl := &servicetest.MockGameLoop{}
+		uc := usecase.NewEmojixUsecase(nil, mgr, nil, nil, &servicetest.MockGameNotifier{}, gl, service.NewRealClock())
+		if err := uc.Shutdown(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := uc.JoinGame(context.Background(), "game-1", "user-1", false); !errors.Is(err, usecase.ErrShuttingDown) {
+			t.Errorf("JoinGame: expected ErrShuttingDown, got %v", err)
+		}
+		if _, err := uc.InitGame(context.Background(), "user-1", "list-1", model.GameSettings{}); !errors.Is(err, usecase.ErrShuttingDown) {
+			t.Errorf("InitGame: expected ErrShuttingDown, got %v", err)
+		}
+		gl.FireOnTurnEnd(context.Background(), "game-1")
+		assertValue(t, "EndTurnCalled", false, mgr.EndTurnCalled)
+	})
+}
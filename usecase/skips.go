@@ -0,0 +1,171 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"emojix/model"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+var ErrOnlyTellerGivesUp = NewError(KindForbidden, "only the teller can give up the turn")
+var ErrTurnOver = NewError(KindConflict, "the turn is already over")
+var ErrAlreadyRerolled = NewError(KindConflict, "the word options were already rerolled this turn")
+var ErrNoRerollWords = NewError(KindConflict, "no other words left to offer")
+var ErrTellerCannotVote = NewError(KindForbidden, "the teller can give up the turn instead")
+
+// TurnGivenUpNotification tells the room the teller gave up; the turn end
+// follows as usual.
+type TurnGivenUpNotification struct {
+	TellerID string
+}
+
+func (n *TurnGivenUpNotification) GetType() string { return "gaveup" }
+func (n *TurnGivenUpNotification) GetData() string { return n.TellerID }
+
+// OptionsRerolledNotification tells the room the teller swapped their word
+// options.
+type OptionsRerolledNotification struct{}
+
+func (n *OptionsRerolledNotification) GetType() string { return "rerolled" }
+func (n *OptionsRerolledNotification) GetData() string { return "" }
+
+// SkipVoteNotification carries the vote count after a guesser voted to skip.
+type SkipVoteNotification struct {
+	Votes  int
+	Needed int
+}
+
+func (n *SkipVoteNotification) GetType() string { return "skipvote" }
+func (n *SkipVoteNotification) GetData() string { return fmt.Sprintf("%d/%d", n.Votes, n.Needed) }
+
+// GiveUpTurn lets the teller end a turn they can't tell. Nobody scores for
+// the rest of it.
+func (e *emojixUsecase) GiveUpTurn(ctx context.Context, gameID, userID string) error {
+	turn, err := e.gameRepo.GetLatestTurn(ctx, gameID)
+	if err != nil {
+		return err
+	}
+	if turn.TellerID != userID {
+		return ErrOnlyTellerGivesUp
+	}
+	if turn.WordID == "" {
+		return ErrTurnNotStarted
+	}
+	if !turn.EndedAt.IsZero() {
+		return ErrTurnOver
+	}
+
+	go e.gameNotifier.PubAll(gameID, &TurnGivenUpNotification{TellerID: userID})
+	e.gameLoop.EndGameTurn(gameID)
+	return nil
+}
+
+// RerollWords deals the teller three new options in place of the current
+// ones, once per turn and only before they pick.
+func (e *emojixUsecase) RerollWords(ctx context.Context, gameID, userID string) error {
+	turn, err := e.gameRepo.GetLatestTurn(ctx, gameID)
+	if err != nil {
+		return err
+	}
+	if turn.WordID != "" {
+		return ErrAlreadyPicked
+	}
+	if turn.TellerID != userID {
+		return ErrNotTeller
+	}
+	if turn.Rerolled {
+		return ErrAlreadyRerolled
+	}
+
+	game, err := e.gameRepo.FindByID(ctx, gameID)
+	if err != nil {
+		return err
+	}
+	unused, err := e.wordRepo.GetUnusedByList(ctx, game.ListID, gameID)
+	if err != nil {
+		return err
+	}
+	current := []string{turn.OptionA, turn.OptionB, turn.OptionC}
+	unused = slices.DeleteFunc(unused, func(w model.Word) bool { return slices.Contains(current, w.ID) })
+	if len(unused) == 0 {
+		return ErrNoRerollWords
+	}
+
+	options := dealWordOptions(unused)
+	err = e.gameRepo.RerollTurnOptions(ctx, turn.ID, options[0].ID, options[1].ID, options[2].ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrAlreadyRerolled // picked or rerolled since we looked
+	}
+	if err != nil {
+		return err
+	}
+
+	go e.gameNotifier.PubAll(gameID, &OptionsRerolledNotification{})
+	return nil
+}
+
+// VoteSkip records a guesser's vote to skip the current turn. Once a
+// majority of active guessers agree the turn ends as if time ran out.
+func (e *emojixUsecase) VoteSkip(ctx context.Context, gameID, userID string) error {
+	players, err := e.gameRepo.GetPlayers(ctx, gameID)
+	if err != nil {
+		return err
+	}
+	if isSpectator(players, userID) {
+		return ErrSpectatorsWatchOnly
+	}
+	active := e.filterActivePlayers(players)
+	if err := e.isPlayerInGame(userID, active); err != nil {
+		return err
+	}
+
+	turn, err := e.gameRepo.GetLatestTurn(ctx, gameID)
+	if err != nil {
+		return err
+	}
+	if turn.WordID == "" {
+		return ErrTurnNotStarted
+	}
+	if !turn.EndedAt.IsZero() {
+		return ErrTurnOver
+	}
+	if turn.TellerID == userID {
+		return ErrTellerCannotVote
+	}
+
+	if err := e.gameRepo.AddSkipVote(ctx, turn.ID, userID); err != nil {
+		return err
+	}
+	votes, err := e.gameRepo.GetSkipVotes(ctx, turn.ID)
+	if err != nil {
+		return err
+	}
+
+	tally := countSkipVotes(votes, active, turn.TellerID)
+	needed := skipVotesNeeded(e.countGuessers(active, turn.TellerID))
+	go e.gameNotifier.PubAll(gameID, &SkipVoteNotification{Votes: tally, Needed: needed})
+
+	if tally >= needed {
+		e.gameLoop.EndGameTurn(gameID)
+	}
+	return nil
+}
+
+// countSkipVotes counts the votes of active guessers only, so a voter who
+// left no longer counts towards the majority.
+func countSkipVotes(votes []string, active []model.Player, tellerID string) int {
+	n := 0
+	for _, p := range active {
+		if p.ID != tellerID && slices.Contains(votes, p.ID) {
+			n++
+		}
+	}
+	return n
+}
+
+// skipVotesNeeded is a strict majority of guessers.
+func skipVotesNeeded(guessers int) int {
+	return guessers/2 + 1
+}
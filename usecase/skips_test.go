@@ -0,0 +1,223 @@
+package usecase_test
+
+import (
+	"context"
+	"emojix/model"
+	"emojix/repository/repotest"
+	"emojix/service"
+	"emojix/service/servicetest"
+	"emojix/usecase"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestGiveUpTurn(t *testing.T) {
+	playing := model.GameTurn{ID: "turn-1", TellerID: "teller", WordID: "w-1"}
+
+	t.Run("teller ends the turn", func(t *testing.T) {
+		mgr := &repotest.MockGameRepository{
+			GetLatestTurnMock: func(ctx context.Context, id string) (model.GameTurn, error) { return playing, nil },
+		}
+		pubCh := make(chan service.GameNotification, 1)
+		mgn := &servicetest.MockGameNotifier{
+			PubAllMock: func(gameID string, n service.GameNotification) { pubCh <- n },
+		}
+		gl := &servicetest.MockGameLoop{}
+		uc := usecase.NewEmojixUsecase(nil, mgr, nil, nil, mgn, gl, service.NewRealClock())
+
+		if err := uc.GiveUpTurn(context.Background(), "game-1", "teller"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !gl.EndGameTurnCalled {
+			t.Error("expected the turn to end")
+		}
+		assertValue(t, "event", "gaveup", drainPub(t, pubCh, 1)[0].GetType())
+	})
+
+	for _, tc := range []struct {
+		name   string
+		turn   model.GameTurn
+		userID string
+		want   error
+	}{
+		{"guesser cannot give up", playing, "guesser", usecase.ErrOnlyTellerGivesUp},
+		{"pick phase", model.GameTurn{ID: "turn-1", TellerID: "teller"}, "teller", usecase.ErrTurnNotStarted},
+		{"turn already over", model.GameTurn{ID: "turn-1", TellerID: "teller", WordID: "w-1", EndedAt: time.Now()}, "teller", usecase.ErrTurnOver},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mgr := &repotest.MockGameRepository{
+				GetLatestTurnMock: func(ctx context.Context, id string) (model.GameTurn, error) { return tc.turn, nil },
+			}
+			gl := &servicetest.MockGameLoop{}
+			uc := usecase.NewEmojixUsecase(nil, mgr, nil, nil, &servicetest.MockGameNotifier{}, gl, service.NewRealClock())
+
+			if err := uc.GiveUpTurn(context.Background(), "game-1", tc.userID); !errors.Is(err, tc.want) {
+				t.Errorf("expected %v but got %v", tc.want, err)
+			}
+			if gl.EndGameTurnCalled {
+				t.Error("expected the turn to keep running")
+			}
+		})
+	}
+}
+
+func TestRerollWords(t *testing.T) {
+	picking := model.GameTurn{ID: "turn-1", TellerID: "teller", OptionA: "w-1", OptionB: "w-2", OptionC: "w-3"}
+	words := func(ids ...string) []model.Word {
+		out := []model.Word{}
+		for _, id := range ids {
+			out = append(out, model.Word{ID: id, Word: id})
+		}
+		return out
+	}
+	setup := func(turn model.GameTurn, unused []model.Word) (*repotest.MockGameRepository, *[]string, usecase.EmojixUsecase) {
+		var options []string
+		mgr := &repotest.MockGameRepository{
+			GetLatestTurnMock: func(ctx context.Context, id string) (model.GameTurn, error) { return turn, nil },
+			RerollOptionsMock: func(ctx context.Context, turnID, optionA, optionB, optionC string) error {
+				options = []string{optionA, optionB, optionC}
+				return nil
+			},
+		}
+		mwr := &repotest.MockWordRepository{
+			GetUnusedByListMock: func(ctx context.Context, listID, gameID string) ([]model.Word, error) { return unused, nil },
+		}
+		uc := usecase.NewEmojixUsecase(nil, mgr, mwr, nil, &servicetest.MockGameNotifier{}, &servicetest.MockGameLoop{}, service.NewRealClock())
+		return mgr, &options, uc
+	}
+
+	t.Run("deals words other than the current options", func(t *testing.T) {
+		_, options, uc := setup(picking, words("w-1", "w-2", "w-3", "w-4", "w-5"))
+
+		if err := uc.RerollWords(context.Background(), "game-1", "teller"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(*options) != 3 {
+			t.Fatalf("expected three options but got %v", *options)
+		}
+		for _, id := range *options {
+			if !slices.Contains([]string{"w-4", "w-5"}, id) {
+				t.Errorf("expected only fresh words but got %v", *options)
+			}
+		}
+	})
+
+	rerolled := picking
+	rerolled.Rerolled = true
+	picked := picking
+	picked.WordID = "w-1"
+	for _, tc := range []struct {
+		name   string
+		turn   model.GameTurn
+		unused []model.Word
+		userID string
+		want   error
+	}{
+		{"only once per turn", rerolled, words("w-4"), "teller", usecase.ErrAlreadyRerolled},
+		{"not after the pick", picked, words("w-4"), "teller", usecase.ErrAlreadyPicked},
+		{"teller only", picking, words("w-4"), "guesser", usecase.ErrNotTeller},
+		{"list has nothing new", picking, words("w-1", "w-2", "w-3"), "teller", usecase.ErrNoRerollWords},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, options, uc := setup(tc.turn, tc.unused)
+
+			if err := uc.RerollWords(context.Background(), "game-1", tc.userID); !errors.Is(err, tc.want) {
+				t.Errorf("expected %v but got %v", tc.want, err)
+			}
+			if len(*options) != 0 {
+				t.Errorf("expected no reroll but got %v", *options)
+			}
+		})
+	}
+}
+
+func TestVoteSkip(t *testing.T) {
+	playing := model.GameTurn{ID: "turn-1", TellerID: "teller", WordID: "w-1"}
+	players := []model.Player{
+		{ID: "teller", State: model.ActivePlayerState},
+		{ID: "g-1", State: model.ActivePlayerState},
+		{ID: "g-2", State: model.ActivePlayerState},
+		{ID: "g-3", State: model.ActivePlayerState},
+		{ID: "gone", State: model.InactivePlayerState},
+	}
+	setup := func(votes []string) (*repotest.MockGameRepository, *servicetest.MockGameLoop, chan service.GameNotification, usecase.EmojixUsecase) {
+		mgr := &repotest.MockGameRepository{
+			GetPlayersMock:    func(ctx context.Context, id string) ([]model.Player, error) { return players, nil },
+			GetLatestTurnMock: func(ctx context.Context, id string) (model.GameTurn, error) { return playing, nil },
+			AddSkipVoteMock: func(ctx context.Context, turnID, playerID string) error {
+				if !slices.Contains(votes, playerID) {
+					votes = append(votes, playerID)
+				}
+				return nil
+			},
+			GetSkipVotesMock: func(ctx context.Context, turnID string) ([]string, error) { return votes, nil },
+		}
+		pubCh := make(chan service.GameNotification, 1)
+		mgn := &servicetest.MockGameNotifier{
+			PubAllMock: func(gameID string, n service.GameNotification) { pubCh <- n },
+		}
+		gl := &servicetest.MockGameLoop{}
+		uc := usecase.NewEmojixUsecase(nil, mgr, nil, nil, mgn, gl, service.NewRealClock())
+		return mgr, gl, pubCh, uc
+	}
+
+	t.Run("first vote is short of a majority", func(t *testing.T) {
+		// A vote from a player who left does not count.
+		_, gl, pubCh, uc := setup([]string{"gone"})
+
+		if err := uc.VoteSkip(context.Background(), "game-1", "g-1"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		n := drainPub(t, pubCh, 1)[0]
+		assertValue(t, "event", "skipvote", n.GetType())
+		assertValue(t, "tally", "1/2", n.GetData())
+		if gl.EndGameTurnCalled {
+			t.Error("expected the turn to keep running")
+		}
+	})
+
+	t.Run("majority ends the turn", func(t *testing.T) {
+		_, gl, pubCh, uc := setup([]string{"g-1"})
+
+		if err := uc.VoteSkip(context.Background(), "game-1", "g-2"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		assertValue(t, "tally", "2/2", drainPub(t, pubCh, 1)[0].GetData())
+		if !gl.EndGameTurnCalled {
+			t.Error("expected the turn to end")
+		}
+	})
+
+	t.Run("teller gives up instead", func(t *testing.T) {
+		mgr, gl, _, uc := setup(nil)
+
+		if err := uc.VoteSkip(context.Background(), "game-1", "teller"); !errors.Is(err, usecase.ErrTellerCannotVote) {
+			t.Errorf("expected ErrTellerCannotVote but got %v", err)
+		}
+		if mgr.AddSkipVoteCalled || gl.EndGameTurnCalled {
+			t.Error("expected no vote")
+		}
+	})
+
+	t.Run("game state shows the tally", func(t *testing.T) {
+		mgr, gl, _, _ := setup([]string{"g-1", "gone"})
+		mgr.GetMessagesMock = func(ctx context.Context, gameID string) ([]model.Message, error) { return nil, nil }
+		mgr.GetScoresMock = func(ctx context.Context, gameID string) ([]model.Score, error) { return nil, nil }
+		mwr := &repotest.MockWordRepository{
+			FindByIDMock: func(ctx context.Context, id string) (model.Word, error) {
+				return model.Word{ID: id, Word: "apple"}, nil
+			},
+		}
+		uc := usecase.NewEmojixUsecase(nil, mgr, mwr, nil, &servicetest.MockGameNotifier{}, gl, service.NewRealClock())
+
+		state, err := uc.GameState(context.Background(), "game-1", "g-1")
+		if err != nil {
+			t.Fatalf("GameState: %v", err)
+		}
+		assertValue(t, "SkipVotes", 1, state.SkipVotes)
+		assertValue(t, "SkipVotesNeeded", 2, state.SkipVotesNeeded)
+		assertValue(t, "VotedSkip", true, state.VotedSkip)
+	})
+}
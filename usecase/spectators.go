@@ -0,0 +1,204 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"emojix/model"
+	"errors"
+	"log"
+	"slices"
+)
+
+// maxSpectators caps the stands; past it JoinGame answers ErrJoinGameRoomFull.
+const maxSpectators = 50
+
+var ErrSpectatorsWatchOnly = NewError(KindForbidden, "spectators can watch but not play")
+var ErrTellerCannotSpectate = NewError(KindConflict, "finish telling before you step out")
+
+// SeatsNotification tells the room someone moved between the seats and the
+// stands, or joined the queue for a seat.
+type SeatsNotification struct{}
+
+func (n *SeatsNotification) GetType() string { return "seats" }
+func (n *SeatsNotification) GetData() string { return "" }
+
+// Spectate moves a seated player to the stands. The teller of a running turn
+// has to finish it first. A queued spectator calling it leaves the queue.
+func (e *emojixUsecase) Spectate(ctx context.Context, gameID, userID string) error {
+	game, err := e.gameRepo.FindByID(ctx, gameID)
+	if err != nil {
+		return err
+	}
+	if game.Status == model.FinishedGameStatus {
+		return ErrGameFinished
+	}
+	players, err := e.gameRepo.GetPlayers(ctx, gameID)
+	if err != nil {
+		return err
+	}
+	if err := e.isPlayerInGame(userID, e.filterPresentPlayers(players)); err != nil {
+		return err
+	}
+
+	if !isSpectator(players, userID) {
+		turn, err := e.gameRepo.GetLatestTurn(ctx, gameID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+		if err == nil && turn.TellerID == userID && turn.EndedAt.IsZero() {
+			return ErrTellerCannotSpectate
+		}
+	}
+
+	if err := e.gameRepo.SetSpectator(ctx, gameID, userID, false); err != nil {
+		return err
+	}
+	go e.gameNotifier.PubAll(gameID, &SeatsNotification{})
+
+	e.promoteSpectators(ctx, gameID)
+	return nil
+}
+
+// Play puts a spectator in the queue for a seat, taking one straight away if
+// the room has room.
+func (e *emojixUsecase) Play(ctx context.Context, gameID, userID string) error {
+	game, err := e.gameRepo.FindByID(ctx, gameID)
+	if err != nil {
+		return err
+	}
+	if game.Status == model.FinishedGameStatus {
+		return ErrGameFinished
+	}
+	players, err := e.gameRepo.GetPlayers(ctx, gameID)
+	if err != nil {
+		return err
+	}
+	if !isSpectator(players, userID) {
+		if e.isPlayerInGame(userID, e.filterActivePlayers(players)) == nil {
+			return ErrJoinGameUserAlreadyJoined
+		}
+		return ErrUserNotInGame
+	}
+
+	if err := e.gameRepo.SetSpectator(ctx, gameID, userID, true); err != nil {
+		return err
+	}
+	go e.gameNotifier.PubAll(gameID, &SeatsNotification{})
+
+	e.promoteSpectators(ctx, gameID)
+	return nil
+}
+
+// promoteSpectators fills free seats from the queue, first come first
+// served, and starts the game if that brought enough players. Call it after
+// anything that may have freed a seat. Each seat is taken with a guarded
+// update, so two callers racing for the last seat cannot both get it.
+func (e *emojixUsecase) promoteSpectators(ctx context.Context, gameID string) {
+	game, err := e.gameRepo.FindByID(ctx, gameID)
+	if err != nil {
+		log.Printf("promoteSpectators FindByID: %v", err)
+		return
+	}
+	if game.Status == model.FinishedGameStatus {
+		return
+	}
+	players, err := e.gameRepo.GetPlayers(ctx, gameID)
+	if err != nil {
+		log.Printf("promoteSpectators GetPlayers: %v", err)
+		return
+	}
+
+	active := e.filterActivePlayers(players)
+	maxPlayers := e.withDefaults(game.Settings).MaxPlayers
+	if len(active) >= maxPlayers {
+		return
+	}
+	promoted := 0
+	for _, p := range spectatorQueue(players) {
+		seated, err := e.gameRepo.PromoteSpectator(ctx, gameID, p.ID, maxPlayers)
+		if err != nil {
+			log.Printf("promoteSpectators PromoteSpectator: %v", err)
+			break
+		}
+		if !seated {
+			// full again, or they left the queue meanwhile
+			continue
+		}
+		if err := e.assignTeam(ctx, game, active, p); err != nil {
+			log.Printf("promoteSpectators SetPlayerTeam: %v", err)
+		}
+		p.State = model.ActivePlayerState
+		active = append(active, p)
+		promoted++
+
+		go e.gameNotifier.Pub(gameID, p.ID, &GameJoinNotification{
+			Nickname: p.Nickname,
+			PlayerID: p.ID,
+		})
+	}
+	if promoted == 0 {
+		return
+	}
+	// Promoted players' pages swap from the stands to the board on this.
+	go e.gameNotifier.PubAll(gameID, &SeatsNotification{})
+	e.tryStartGame(ctx, gameID)
+}
+
+// assignTeam puts a newly seated player without a side on the smaller team
+// of a team game; players coming back keep theirs.
+func (e *emojixUsecase) assignTeam(ctx context.Context, game model.Game, active []model.Player, p model.Player) error {
+	if !game.Settings.Teams || p.Team != "" {
+		return nil
+	}
+	return e.gameRepo.SetPlayerTeam(ctx, game.ID, p.ID, smallerTeam(active))
+}
+
+// filterPresentPlayers is everyone watching the game: active players and
+// spectators. They may read its state; only active players take turns.
+func (e *emojixUsecase) filterPresentPlayers(players []model.Player) []model.Player {
+	present := []model.Player{}
+	for _, p := range players {
+		if p.State == model.InactivePlayerState || p.State == model.KickedPlayerState {
+			continue
+		}
+		present = append(present, p)
+	}
+	return present
+}
+
+func isSpectator(players []model.Player, userID string) bool {
+	return slices.ContainsFunc(players, func(p model.Player) bool {
+		return p.ID == userID && p.State == model.SpectatorPlayerState
+	})
+}
+
+// spectatorQueue is the spectators waiting for a seat, longest waiting first.
+func spectatorQueue(players []model.Player) []model.Player {
+	queue := []model.Player{}
+	for _, p := range players {
+		if p.State == model.SpectatorPlayerState && !p.QueuedAt.IsZero() {
+			queue = append(queue, p)
+		}
+	}
+	slices.SortStableFunc(queue, func(a, b model.Player) int {
+		return a.QueuedAt.Compare(b.QueuedAt)
+	})
+	return queue
+}
+
+func countSpectators(players []model.Player) int {
+	n := 0
+	for _, p := range players {
+		if p.State == model.SpectatorPlayerState {
+			n++
+		}
+	}
+	return n
+}
+
+// queuePosition is userID's 1-based place in the queue for a seat, or 0.
+func queuePosition(players []model.Player, userID string) int {
+	return slices.IndexFunc(spectatorQueue(players), func(p model.Player) bool {
+		return p.ID == userID
+	}) + 1
+}
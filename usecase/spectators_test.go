@@ -0,0 +1,341 @@
+package usecase_test
+
+import (
+	"context"
+	"database/sql"
+	"emojix/model"
+	"emojix/repository/repotest"
+	"emojix/service"
+	"emojix/service/servicetest"
+	"emojix/usecase"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// standsRepo keeps players in memory so a test can watch spectators move
+// between the stands and the seats.
+func standsRepo(game model.Game, players []model.Player) *repotest.MockGameRepository {
+	set := func(userID string, change func(p *model.Player)) {
+		for i := range players {
+			if players[i].ID == userID {
+				change(&players[i])
+			}
+		}
+	}
+	return &repotest.MockGameRepository{
+		FindByIDMock: func(ctx context.Context, id string) (model.Game, error) { return game, nil },
+		GetPlayersMock: func(ctx context.Context, id string) ([]model.Player, error) {
+			return append([]model.Player(nil), players...), nil
+		},
+		GetLatestTurnMock: func(ctx context.Context, gameID string) (model.GameTurn, error) {
+			return model.GameTurn{}, sql.ErrNoRows
+		},
+		GetMessagesMock: func(ctx context.Context, gameID string) ([]model.Message, error) { return nil, nil },
+		GetScoresMock:   func(ctx context.Context, gameID string) ([]model.Score, error) { return nil, nil },
+		SetPlayerStateMock: func(ctx context.Context, gameID, userID string, state model.PlayerState) error {
+			set(userID, func(p *model.Player) { p.State, p.QueuedAt = state, time.Time{} })
+			return nil
+		},
+		SeatPlayerMock: func(ctx context.Context, gameID, userID string, maxPlayers int) (bool, error) {
+			if countActive(players) >= maxPlayers {
+				return false, nil
+			}
+			for i := range players {
+				if players[i].ID == userID {
+					players[i].State = model.ActivePlayerState
+					return true, nil
+				}
+			}
+			players = append(players, model.Player{ID: userID, State: model.ActivePlayerState})
+			return true, nil
+		},
+		PromoteSpectatorMock: func(ctx context.Context, gameID, userID string, maxPlayers int) (bool, error) {
+			active := countActive(players)
+			seated := false
+			set(userID, func(p *model.Player) {
+				if p.State == model.SpectatorPlayerState && !p.QueuedAt.IsZero() && active < maxPlayers {
+					p.State, p.QueuedAt = model.ActivePlayerState, time.Time{}
+					seated = true
+				}
+			})
+			return seated, nil
+		},
+		SetSpectatorMock: func(ctx context.Context, gameID, userID string, queued bool) error {
+			set(userID, func(p *model.Player) {
+				p.State = model.SpectatorPlayerState
+				if !queued {
+					p.QueuedAt = time.Time{}
+				} else if p.QueuedAt.IsZero() {
+					p.QueuedAt = time.Now()
+				}
+			})
+			return nil
+		},
+		AddSpectatorMock: func(ctx context.Context, gameID, userID string, queued bool) error {
+			p := model.Player{ID: userID, State: model.SpectatorPlayerState}
+			if queued {
+				p.QueuedAt = time.Now()
+			}
+			players = append(players, p)
+			return nil
+		},
+	}
+}
+
+func countActive(players []model.Player) int {
+	active := 0
+	for _, p := range players {
+		if p.State == model.ActivePlayerState {
+			active++
+		}
+	}
+	return active
+}
+
+func stateOf(t *testing.T, mgr *repotest.MockGameRepository, userID string) model.PlayerState {
+	t.Helper()
+	return playerOf(t, mgr, userID).State
+}
+
+func queuedOf(t *testing.T, mgr *repotest.MockGameRepository, userID string) bool {
+	t.Helper()
+	return !playerOf(t, mgr, userID).QueuedAt.IsZero()
+}
+
+func playerOf(t *testing.T, mgr *repotest.MockGameRepository, userID string) model.Player {
+	t.Helper()
+	players, _ := mgr.GetPlayers(context.Background(), "")
+	for _, p := range players {
+		if p.ID == userID {
+			return p
+		}
+	}
+	t.Fatalf("no player %s", userID)
+	return model.Player{}
+}
+
+func TestJoinGameStands(t *testing.T) {
+	mur := &repotest.MockUserRepository{
+		FindByIDMock: func(ctx context.Context, id string) (model.User, error) {
+			return model.User{ID: id, Nickname: id}, nil
+		},
+	}
+	full := model.Game{ID: "game-1", Settings: model.GameSettings{MaxPlayers: 2}}
+
+	t.Run("returning player waits in the queue when the room filled up", func(t *testing.T) {
+		mgr := standsRepo(full, []model.Player{
+			{ID: "p-1", State: model.ActivePlayerState},
+			{ID: "p-2", State: model.ActivePlayerState},
+			{ID: "back", State: model.InactivePlayerState},
+		})
+		uc := usecase.NewEmojixUsecase(mur, mgr, nil, nil, &servicetest.MockGameNotifier{}, &servicetest.MockGameLoop{}, service.NewRealClock())
+
+		if err := uc.JoinGame(context.Background(), "game-1", "back", false); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		assertValue(t, "State", model.SpectatorPlayerState, stateOf(t, mgr, "back"))
+		if mgr.AddSpectatorCalled {
+			t.Error("expected a returning player to keep their row")
+		}
+	})
+
+	t.Run("queues when a concurrent join takes the last seat", func(t *testing.T) {
+		mgr := standsRepo(full, []model.Player{{ID: "p-1", State: model.ActivePlayerState}})
+		mgr.SeatPlayerMock = func(ctx context.Context, gameID, userID string, maxPlayers int) (bool, error) {
+			return false, nil // someone else was seated since GetPlayers
+		}
+		uc := usecase.NewEmojixUsecase(mur, mgr, nil, nil, &servicetest.MockGameNotifier{}, &servicetest.MockGameLoop{}, service.NewRealClock())
+
+		if err := uc.JoinGame(context.Background(), "game-1", "new", false); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		assertValue(t, "State", model.SpectatorPlayerState, stateOf(t, mgr, "new"))
+		assertValue(t, "queued", true, queuedOf(t, mgr, "new"))
+	})
+
+	t.Run("spectate skips a free seat and the queue", func(t *testing.T) {
+		mgr := standsRepo(full, []model.Player{{ID: "p-1", State: model.ActivePlayerState}})
+		uc := usecase.NewEmojixUsecase(mur, mgr, nil, nil, &servicetest.MockGameNotifier{}, &servicetest.MockGameLoop{}, service.NewRealClock())
+
+		if err := uc.JoinGame(context.Background(), "game-1", "new", true); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		assertValue(t, "State", model.SpectatorPlayerState, stateOf(t, mgr, "new"))
+		assertValue(t, "queued", false, queuedOf(t, mgr, "new"))
+	})
+
+	t.Run("returning player can spectate", func(t *testing.T) {
+		mgr := standsRepo(full, []model.Player{
+			{ID: "p-1", State: model.ActivePlayerState},
+			{ID: "back", State: model.InactivePlayerState},
+		})
+		uc := usecase.NewEmojixUsecase(mur, mgr, nil, nil, &servicetest.MockGameNotifier{}, &servicetest.MockGameLoop{}, service.NewRealClock())
+
+		if err := uc.JoinGame(context.Background(), "game-1", "back", true); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		assertValue(t, "State", model.SpectatorPlayerState, stateOf(t, mgr, "back"))
+		assertValue(t, "queued", false, queuedOf(t, mgr, "back"))
+		if mgr.AddSpectatorCalled {
+			t.Error("expected a returning player to keep their row")
+		}
+	})
+
+	t.Run("refuses when the stands are full too", func(t *testing.T) {
+		players := []model.Player{
+			{ID: "p-1", State: model.ActivePlayerState},
+			{ID: "p-2", State: model.ActivePlayerState},
+		}
+		for i := range 50 {
+			players = append(players, model.Player{ID: fmt.Sprintf("s-%d", i), State: model.SpectatorPlayerState})
+		}
+		mgr := standsRepo(full, players)
+		uc := usecase.NewEmojixUsecase(mur, mgr, nil, nil, &servicetest.MockGameNotifier{}, &servicetest.MockGameLoop{}, service.NewRealClock())
+
+		err := uc.JoinGame(context.Background(), "game-1", "new", false)
+		if !errors.Is(err, usecase.ErrJoinGameRoomFull) {
+			t.Errorf("expected ErrJoinGameRoomFull but got %v", err)
+		}
+	})
+
+	t.Run("spectator is already in", func(t *testing.T) {
+		mgr := standsRepo(full, []model.Player{{ID: "s-1", State: model.SpectatorPlayerState}})
+		uc := usecase.NewEmojixUsecase(mur, mgr, nil, nil, &servicetest.MockGameNotifier{}, &servicetest.MockGameLoop{}, service.NewRealClock())
+
+		err := uc.JoinGame(context.Background(), "game-1", "s-1", false)
+		if !errors.Is(err, usecase.ErrJoinGameUserAlreadyJoined) {
+			t.Errorf("expected ErrJoinGameUserAlreadyJoined but got %v", err)
+		}
+	})
+}
+
+func TestSpectate(t *testing.T) {
+	game := model.Game{ID: "game-1", Status: model.PlayingGameStatus, Settings: model.GameSettings{MaxPlayers: 2}}
+	running := &servicetest.MockGameLoop{RunningMock: func(gameID string) bool { return true }}
+
+	t.Run("frees the seat for the longest waiting spectator", func(t *testing.T) {
+		now := time.Now()
+		mgr := standsRepo(game, []model.Player{
+			{ID: "p-1", State: model.ActivePlayerState},
+			{ID: "p-2", State: model.ActivePlayerState},
+			{ID: "late", State: model.SpectatorPlayerState, QueuedAt: now},
+			{ID: "early", State: model.SpectatorPlayerState, QueuedAt: now.Add(-time.Minute)},
+			{ID: "watcher", State: model.SpectatorPlayerState},
+		})
+		pubCh := make(chan service.GameNotification, 8)
+		mgn := &servicetest.MockGameNotifier{
+			PubMock:    func(gameID, userID string, n service.GameNotification) { pubCh <- n },
+			PubAllMock: func(gameID string, n service.GameNotification) { pubCh <- n },
+		}
+		uc := usecase.NewEmojixUsecase(nil, mgr, nil, nil, mgn, running, service.NewRealClock())
+
+		if err := uc.Spectate(context.Background(), "game-1", "p-2"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		assertValue(t, "p-2", model.SpectatorPlayerState, stateOf(t, mgr, "p-2"))
+		assertValue(t, "early", model.ActivePlayerState, stateOf(t, mgr, "early"))
+		assertValue(t, "late", model.SpectatorPlayerState, stateOf(t, mgr, "late"))
+		assertValue(t, "watcher", model.SpectatorPlayerState, stateOf(t, mgr, "watcher"))
+
+		joined := false
+		for _, n := range drainPub(t, pubCh, 3) {
+			if j, ok := n.(*usecase.GameJoinNotification); ok {
+				joined = j.PlayerID == "early"
+			}
+		}
+		if !joined {
+			t.Error("expected a join notification for the promoted spectator")
+		}
+	})
+
+	t.Run("teller finishes the turn first", func(t *testing.T) {
+		mgr := standsRepo(game, []model.Player{
+			{ID: "p-1", State: model.ActivePlayerState},
+			{ID: "p-2", State: model.ActivePlayerState},
+		})
+		mgr.GetLatestTurnMock = func(ctx context.Context, gameID string) (model.GameTurn, error) {
+			return model.GameTurn{ID: "turn-1", TellerID: "p-1"}, nil
+		}
+		uc := usecase.NewEmojixUsecase(nil, mgr, nil, nil, &servicetest.MockGameNotifier{}, running, service.NewRealClock())
+
+		err := uc.Spectate(context.Background(), "game-1", "p-1")
+		if !errors.Is(err, usecase.ErrTellerCannotSpectate) {
+			t.Errorf("expected ErrTellerCannotSpectate but got %v", err)
+		}
+		if mgr.SetSpectatorCalled {
+			t.Error("expected SetSpectator not to be called")
+		}
+	})
+}
+
+func TestPlay(t *testing.T) {
+	game := model.Game{ID: "game-1", Settings: model.GameSettings{MaxPlayers: 2}}
+	running := &servicetest.MockGameLoop{RunningMock: func(gameID string) bool { return true }}
+
+	t.Run("takes a free seat straight away", func(t *testing.T) {
+		mgr := standsRepo(game, []model.Player{
+			{ID: "p-1", State: model.ActivePlayerState},
+			{ID: "s-1", State: model.SpectatorPlayerState},
+		})
+		uc := usecase.NewEmojixUsecase(nil, mgr, nil, nil, &servicetest.MockGameNotifier{}, running, service.NewRealClock())
+
+		if err := uc.Play(context.Background(), "game-1", "s-1"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		assertValue(t, "State", model.ActivePlayerState, stateOf(t, mgr, "s-1"))
+	})
+
+	t.Run("waits in line in a full room", func(t *testing.T) {
+		mgr := standsRepo(game, []model.Player{
+			{ID: "p-1", State: model.ActivePlayerState},
+			{ID: "p-2", State: model.ActivePlayerState},
+			{ID: "s-1", State: model.SpectatorPlayerState},
+		})
+		uc := usecase.NewEmojixUsecase(nil, mgr, nil, nil, &servicetest.MockGameNotifier{}, running, service.NewRealClock())
+
+		if err := uc.Play(context.Background(), "game-1", "s-1"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		state, err := uc.GameState(context.Background(), "game-1", "s-1")
+		if err != nil {
+			t.Fatalf("GameState: %v", err)
+		}
+		assertValue(t, "IsSpectator", true, state.IsSpectator)
+		assertValue(t, "QueuePosition", 1, state.QueuePosition)
+	})
+
+	t.Run("seated player is already playing", func(t *testing.T) {
+		mgr := standsRepo(game, []model.Player{{ID: "p-1", State: model.ActivePlayerState}})
+		uc := usecase.NewEmojixUsecase(nil, mgr, nil, nil, &servicetest.MockGameNotifier{}, running, service.NewRealClock())
+
+		err := uc.Play(context.Background(), "game-1", "p-1")
+		if !errors.Is(err, usecase.ErrJoinGameUserAlreadyJoined) {
+			t.Errorf("expected ErrJoinGameUserAlreadyJoined but got %v", err)
+		}
+	})
+}
+
+func TestSpectatorsWatchOnly(t *testing.T) {
+	players := []model.Player{
+		{ID: "p-1", State: model.ActivePlayerState},
+		{ID: "s-1", State: model.SpectatorPlayerState},
+	}
+	mgr := standsRepo(model.Game{ID: "game-1"}, players)
+	uc := usecase.NewEmojixUsecase(nil, mgr, nil, nil, &servicetest.MockGameNotifier{}, &servicetest.MockGameLoop{}, service.NewRealClock())
+
+	if _, err := uc.Guess(context.Background(), "game-1", "s-1", "apple"); !errors.Is(err, usecase.ErrSpectatorsWatchOnly) {
+		t.Errorf("Guess: expected ErrSpectatorsWatchOnly but got %v", err)
+	}
+	if err := uc.Message(context.Background(), "game-1", "s-1", "hi"); !errors.Is(err, usecase.ErrSpectatorsWatchOnly) {
+		t.Errorf("Message: expected ErrSpectatorsWatchOnly but got %v", err)
+	}
+
+	state, err := uc.GameState(context.Background(), "game-1", "p-1")
+	if err != nil {
+		t.Fatalf("GameState: %v", err)
+	}
+	assertValue(t, "SpectatorCount", 1, state.SpectatorCount)
+	assertValue(t, "Leaderboard size", 1, len(state.Leaderboard))
+}
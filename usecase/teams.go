@@ -0,0 +1,160 @@
+package usecase
+
+import (
+	"cmp"
+	"context"
+	"emojix/model"
+	"fmt"
+	"slices"
+	"time"
+)
+
+var ErrNotTeamGame = NewError(KindValidation, "this game is not played in teams")
+var ErrInvalidTeam = NewError(KindValidation, "team must be red or blue")
+var ErrTeamsLocked = NewError(KindConflict, "teams are fixed once the game starts")
+var ErrStealNotOpen = NewError(KindConflict, "it is the other team's turn, wait for the steal")
+
+// TeamNotification tells the room a player changed sides.
+type TeamNotification struct {
+	UserID string
+	Team   model.Team
+}
+
+func (n *TeamNotification) GetType() string { return "team" }
+func (n *TeamNotification) GetData() string { return fmt.Sprintf("%s,%s", n.UserID, n.Team) }
+
+// PickTeam moves userID to team. Sides can only be picked while the room
+// waits for its first turn; after that they are fixed.
+func (e *emojixUsecase) PickTeam(ctx context.Context, gameID, userID string, team model.Team) error {
+	if !slices.Contains(model.Teams, team) {
+		return ErrInvalidTeam
+	}
+	game, err := e.gameRepo.FindByID(ctx, gameID)
+	if err != nil {
+		return err
+	}
+	if !game.Settings.Teams {
+		return ErrNotTeamGame
+	}
+	if game.Status != model.LobbyGameStatus {
+		return ErrTeamsLocked
+	}
+
+	players, err := e.gameRepo.GetPlayers(ctx, gameID)
+	if err != nil {
+		return err
+	}
+	if err := e.isPlayerInGame(userID, e.filterActivePlayers(players)); err != nil {
+		return err
+	}
+	if teamOf(players, userID) == team {
+		return nil
+	}
+
+	if err := e.gameRepo.SetPlayerTeam(ctx, gameID, userID, team); err != nil {
+		return err
+	}
+	go e.gameNotifier.PubAll(gameID, &TeamNotification{UserID: userID, Team: team})
+	return nil
+}
+
+// TeamLeaderboard is GameState's TeamLeaderboard on its own, for refreshing
+// the side panel; it is empty outside team games.
+func (e *emojixUsecase) TeamLeaderboard(ctx context.Context, gameID, currentUserID string) ([]model.TeamScore, error) {
+	players, err := e.gameRepo.GetPlayers(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+	if err := e.isPlayerInGame(currentUserID, e.filterPresentPlayers(players)); err != nil {
+		return nil, err
+	}
+	game, err := e.gameRepo.FindByID(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+	if !game.Settings.Teams {
+		return nil, nil
+	}
+	scores, err := e.gameRepo.GetScores(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+	return buildTeamLeaderboard(players, scores, currentUserID), nil
+}
+
+// smallerTeam is the side a newcomer joins: the one with fewer active
+// players, red on a tie.
+func smallerTeam(activePlayers []model.Player) model.Team {
+	counts := map[model.Team]int{}
+	for _, p := range activePlayers {
+		counts[p.Team]++
+	}
+	if counts[model.BlueTeam] < counts[model.RedTeam] {
+		return model.BlueTeam
+	}
+	return model.RedTeam
+}
+
+func teamOf(players []model.Player, userID string) model.Team {
+	for _, p := range players {
+		if p.ID == userID {
+			return p.Team
+		}
+	}
+	return ""
+}
+
+// pickTeller chooses the teller of turn number turnCount from the active
+// players, who are sorted by join order. Team games alternate sides and
+// rotate within each side; if one side is empty the other keeps telling.
+func pickTeller(active []model.Player, turnCount int, teams bool) model.Player {
+	if !teams {
+		return active[turnCount%len(active)]
+	}
+	bySide := map[model.Team][]model.Player{}
+	for _, p := range active {
+		bySide[p.Team] = append(bySide[p.Team], p)
+	}
+	side := bySide[model.Teams[turnCount%len(model.Teams)]]
+	if len(side) == 0 {
+		side = bySide[model.Teams[(turnCount+1)%len(model.Teams)]]
+	}
+	if len(side) == 0 {
+		return active[turnCount%len(active)]
+	}
+	return side[(turnCount/len(model.Teams))%len(side)]
+}
+
+// stealOpensAt is when the team that is not telling may start guessing.
+func stealOpensAt(turn model.GameTurn, settings model.GameSettings) time.Time {
+	return turn.StartedAt.Add(settings.StealDelay)
+}
+
+// buildTeamLeaderboard sums every non-kicked player's points per side, so a
+// team keeps what a player earned after they leave. Totals floor at zero like
+// player scores; the leading team comes first.
+func buildTeamLeaderboard(players []model.Player, scores []model.Score, currentUserID string) []model.TeamScore {
+	teams := map[string]model.Team{}
+	for _, p := range players {
+		if p.State != model.KickedPlayerState {
+			teams[p.ID] = p.Team
+		}
+	}
+	totals := map[model.Team]int{}
+	for _, s := range scores {
+		totals[teams[s.PlayerID]] += s.Score
+	}
+
+	board := make([]model.TeamScore, 0, len(model.Teams))
+	for _, team := range model.Teams {
+		board = append(board, model.TeamScore{
+			Team:  team,
+			Score: max(totals[team], 0),
+			Me:    teams[currentUserID] == team,
+		})
+	}
+	slices.SortStableFunc(board, func(a, b model.TeamScore) int {
+		return cmp.Compare(b.Score, a.Score)
+	})
+	return board
+}
@@ -0,0 +1,336 @@
+package usecase_test
+
+import (
+	"context"
+	"database/sql"
+	"emojix/model"
+	"emojix/repository"
+	"emojix/repository/repotest"
+	"emojix/service"
+	"emojix/service/servicetest"
+	"emojix/usecase"
+	"errors"
+	"testing"
+	"time"
+)
+
+func teamGame(id string) model.Game {
+	return model.Game{ID: id, ListID: "list-1", Status: model.LobbyGameStatus, Settings: model.GameSettings{Teams: true}}
+}
+
+func TestJoinGameAssignsTeams(t *testing.T) {
+	mur := &repotest.MockUserRepository{
+		FindByIDMock: func(ctx context.Context, id string) (model.User, error) {
+			return model.User{ID: id, Nickname: id}, nil
+		},
+	}
+	running := &servicetest.MockGameLoop{RunningMock: func(gameID string) bool { return true }}
+
+	t.Run("newcomer joins the smaller team", func(t *testing.T) {
+		var gotTeam model.Team
+		mgr := &repotest.MockGameRepository{
+			FindByIDMock: func(ctx context.Context, id string) (model.Game, error) { return teamGame(id), nil },
+			GetPlayersMock: func(ctx context.Context, id string) ([]model.Player, error) {
+				return []model.Player{
+					{ID: "p-1", Team: model.RedTeam, State: model.ActivePlayerState},
+					{ID: "p-2", Team: model.BlueTeam, State: model.ActivePlayerState},
+					{ID: "p-3", Team: model.RedTeam, State: model.ActivePlayerState},
+					{ID: "p-4", Team: model.BlueTeam, State: model.InactivePlayerState},
+				}, nil
+			},
+			SeatPlayerMock: func(ctx context.Context, gameID, userID string, maxPlayers int) (bool, error) { return true, nil },
+			SetPlayerTeamMock: func(ctx context.Context, gameID, userID string, team model.Team) error {
+				assertCalledWith(t, "UserID", "new", userID)
+				gotTeam = team
+				return nil
+			},
+		}
+		uc := usecase.NewEmojixUsecase(mur, mgr, nil, nil, &servicetest.MockGameNotifier{}, running, service.NewRealClock())
+
+		if err := uc.JoinGame(context.Background(), "game-1", "new", false); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		assertValue(t, "Team", model.BlueTeam, gotTeam)
+	})
+
+	t.Run("returning player keeps their side", func(t *testing.T) {
+		mgr := &repotest.MockGameRepository{
+			FindByIDMock: func(ctx context.Context, id string) (model.Game, error) { return teamGame(id), nil },
+			GetPlayersMock: func(ctx context.Context, id string) ([]model.Player, error) {
+				return []model.Player{
+					{ID: "p-1", Team: model.RedTeam, State: model.ActivePlayerState},
+					{ID: "back", Team: model.RedTeam, State: model.InactivePlayerState},
+				}, nil
+			},
+			SeatPlayerMock: func(ctx context.Context, gameID, userID string, maxPlayers int) (bool, error) { return true, nil },
+		}
+		uc := usecase.NewEmojixUsecase(mur, mgr, nil, nil, &servicetest.MockGameNotifier{}, running, service.NewRealClock())
+
+		if err := uc.JoinGame(context.Background(), "game-1", "back", false); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if mgr.SetPlayerTeamCalled {
+			t.Error("expected SetPlayerTeam not to be called for a returning player")
+		}
+	})
+}
+
+func TestPickTeam(t *testing.T) {
+	players := []model.Player{
+		{ID: "p-1", Team: model.RedTeam, State: model.ActivePlayerState},
+		{ID: "p-2", Team: model.RedTeam, State: model.ActivePlayerState},
+	}
+	newRepo := func(game model.Game) *repotest.MockGameRepository {
+		return &repotest.MockGameRepository{
+			FindByIDMock:   func(ctx context.Context, id string) (model.Game, error) { return game, nil },
+			GetPlayersMock: func(ctx context.Context, id string) ([]model.Player, error) { return players, nil },
+			SetPlayerTeamMock: func(ctx context.Context, gameID, userID string, team model.Team) error {
+				assertCalledWith(t, "UserID", "p-2", userID)
+				assertCalledWith(t, "Team", model.BlueTeam, team)
+				return nil
+			},
+		}
+	}
+
+	t.Run("switches side in the lobby and tells the room", func(t *testing.T) {
+		pubCh := make(chan service.GameNotification, 1)
+		mgn := &servicetest.MockGameNotifier{
+			PubAllMock: func(gameID string, n service.GameNotification) { pubCh <- n },
+		}
+		mgr := newRepo(teamGame("game-1"))
+		uc := usecase.NewEmojixUsecase(nil, mgr, nil, nil, mgn, &servicetest.MockGameLoop{}, service.NewRealClock())
+
+		if err := uc.PickTeam(context.Background(), "game-1", "p-2", model.BlueTeam); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		n := drainPub(t, pubCh, 1)[0]
+		assertValue(t, "NotifType", "team", n.GetType())
+		assertValue(t, "NotifData", "p-2,blue", n.GetData())
+	})
+
+	cases := []struct {
+		name string
+		game model.Game
+		user string
+		team model.Team
+		want error
+	}{
+		{"unknown team", teamGame("game-1"), "p-2", "green", usecase.ErrInvalidTeam},
+		{"game without teams", model.Game{ID: "game-1", Status: model.LobbyGameStatus}, "p-2", model.BlueTeam, usecase.ErrNotTeamGame},
+		{"game already started", model.Game{ID: "game-1", Status: model.PlayingGameStatus, Settings: model.GameSettings{Teams: true}}, "p-2", model.BlueTeam, usecase.ErrTeamsLocked},
+		{"not seated", teamGame("game-1"), "stranger", model.BlueTeam, usecase.ErrUserNotInGame},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mgr := newRepo(tc.game)
+			uc := usecase.NewEmojixUsecase(nil, mgr, nil, nil, &servicetest.MockGameNotifier{}, &servicetest.MockGameLoop{}, service.NewRealClock())
+
+			if err := uc.PickTeam(context.Background(), "game-1", tc.user, tc.team); !errors.Is(err, tc.want) {
+				t.Fatalf("got %v, want %v", err, tc.want)
+			}
+			if mgr.SetPlayerTeamCalled {
+				t.Error("expected SetPlayerTeam not to be called")
+			}
+		})
+	}
+}
+
+func TestTeamTellerRotation(t *testing.T) {
+	start := time.Now()
+	roster := []model.Player{
+		{ID: "red-1", Team: model.RedTeam, State: model.ActivePlayerState, JoinedAt: start},
+		{ID: "blue-1", Team: model.BlueTeam, State: model.ActivePlayerState, JoinedAt: start.Add(time.Second)},
+		{ID: "red-2", Team: model.RedTeam, State: model.ActivePlayerState, JoinedAt: start.Add(2 * time.Second)},
+		{ID: "blue-2", Team: model.BlueTeam, State: model.ActivePlayerState, JoinedAt: start.Add(3 * time.Second)},
+		{ID: "red-3", Team: model.RedTeam, State: model.ActivePlayerState, JoinedAt: start.Add(4 * time.Second)},
+	}
+	cases := []struct {
+		turns  int
+		teller string
+	}{
+		{0, "red-1"},
+		{1, "blue-1"},
+		{2, "red-2"},
+		{3, "blue-2"},
+		{4, "red-3"},
+		{5, "blue-1"},
+		{6, "red-1"},
+	}
+	for _, tc := range cases {
+		var gotTeller string
+		calls := 0
+		mgr := &repotest.MockGameRepository{
+			FindByIDMock: func(ctx context.Context, id string) (model.Game, error) {
+				g := teamGame(id)
+				g.Status = model.PlayingGameStatus
+				return g, nil
+			},
+			GetPlayersMock: func(ctx context.Context, id string) ([]model.Player, error) {
+				// The rejoin of red-1 is what restarts the paused loop.
+				calls++
+				if calls == 1 {
+					players := append([]model.Player{}, roster...)
+					players[0].State = model.InactivePlayerState
+					return players, nil
+				}
+				return roster, nil
+			},
+			SeatPlayerMock: func(ctx context.Context, gameID, userID string, maxPlayers int) (bool, error) { return true, nil },
+			CountTurnsMock: func(ctx context.Context, gameID string) (int, error) { return tc.turns, nil },
+			AddTurnMock: func(ctx context.Context, params repository.AddTurnParams) (model.GameTurn, error) {
+				gotTeller = params.TellerID
+				return model.GameTurn{ID: "turn", TellerID: params.TellerID}, nil
+			},
+		}
+		mur := &repotest.MockUserRepository{
+			FindByIDMock: func(ctx context.Context, id string) (model.User, error) { return model.User{ID: id, Nickname: id}, nil },
+		}
+		mwr := &repotest.MockWordRepository{
+			GetUnusedByListMock: func(ctx context.Context, listID, gameID string) ([]model.Word, error) {
+				return []model.Word{{ID: "w1"}, {ID: "w2"}, {ID: "w3"}}, nil
+			},
+		}
+		mgn := &servicetest.MockGameNotifier{
+			PubMock:    func(gameID, userID string, n service.GameNotification) {},
+			PubAllMock: func(gameID string, n service.GameNotification) {},
+		}
+		uc := usecase.NewEmojixUsecase(mur, mgr, mwr, nil, mgn, &servicetest.MockGameLoop{}, service.NewRealClock())
+
+		if err := uc.JoinGame(context.Background(), "game-1", "red-1", false); err != nil {
+			t.Fatalf("turn %d: unexpected error: %v", tc.turns, err)
+		}
+		if gotTeller != tc.teller {
+			t.Errorf("turn %d: teller %q, want %q", tc.turns, gotTeller, tc.teller)
+		}
+	}
+}
+
+func TestTeamGuess(t *testing.T) {
+	const (
+		gameID = "game-1"
+		turnID = "turn-1"
+		teller = "red-teller"
+	)
+	players := []model.Player{
+		{ID: teller, Nickname: "Teller", Team: model.RedTeam, State: model.ActivePlayerState},
+		{ID: "red-guesser", Nickname: "RedGuesser", Team: model.RedTeam, State: model.ActivePlayerState},
+		{ID: "blue-guesser", Nickname: "BlueGuesser", Team: model.BlueTeam, State: model.ActivePlayerState},
+	}
+	newRepo := func(startedAgo time.Duration, awarded map[string]int) *repotest.MockGameRepository {
+		return &repotest.MockGameRepository{
+			FindByIDMock: func(ctx context.Context, id string) (model.Game, error) {
+				g := teamGame(id)
+				g.Status = model.PlayingGameStatus
+				return g, nil
+			},
+			GetLatestTurnMock: func(ctx context.Context, id string) (model.GameTurn, error) {
+				return model.GameTurn{ID: turnID, WordID: "w-1", TellerID: teller, StartedAt: time.Now().Add(-startedAgo)}, nil
+			},
+			GetPlayersMock: func(ctx context.Context, id string) ([]model.Player, error) { return players, nil },
+			GetScoresMock:  func(ctx context.Context, id string) ([]model.Score, error) { return nil, nil },
+			SendMessageMock: func(ctx context.Context, g, turn, u, content string) (model.Message, error) {
+				return model.Message{ID: "msg-1", PlayerID: u, Content: content, TurnID: turn}, nil
+			},
+			AddScoreMock: func(ctx context.Context, g, u, msg, turn string, points int) error {
+				awarded[u] += points
+				return nil
+			},
+		}
+	}
+	mur := &repotest.MockUserRepository{
+		FindByIDMock: func(ctx context.Context, id string) (model.User, error) { return model.User{ID: id, Nickname: id}, nil },
+	}
+	mwr := &repotest.MockWordRepository{
+		FindByIDMock: func(ctx context.Context, id string) (model.Word, error) {
+			return model.Word{ID: id, Word: "Secret"}, nil
+		},
+	}
+	mgn := &servicetest.MockGameNotifier{PubMock: func(g, u string, n service.GameNotification) {}}
+
+	t.Run("other team waits for the steal window", func(t *testing.T) {
+		mgr := newRepo(time.Second, map[string]int{})
+		uc, _ := newGuessUsecase(mur, mgr, mwr, mgn, &servicetest.MockGameLoop{}, nil)
+
+		_, err := uc.Guess(context.Background(), gameID, "blue-guesser", "secret")
+		if !errors.Is(err, usecase.ErrStealNotOpen) {
+			t.Fatalf("got %v, want ErrStealNotOpen", err)
+		}
+		if mgr.SendMessageCalled {
+			t.Error("expected a blocked steal not to be recorded")
+		}
+	})
+
+	t.Run("teammates score at once and the teller gets the bonus", func(t *testing.T) {
+		awarded := map[string]int{}
+		uc, _ := newGuessUsecase(mur, newRepo(time.Second, awarded), mwr, mgn, &servicetest.MockGameLoop{}, nil)
+
+		correct, err := uc.Guess(context.Background(), gameID, "red-guesser", "secret")
+		if err != nil || !correct {
+			t.Fatalf("got correct=%v err=%v, want a correct guess", correct, err)
+		}
+		if awarded["red-guesser"] <= 0 || awarded[teller] <= 0 {
+			t.Errorf("expected guesser and teller points, got %v", awarded)
+		}
+	})
+
+	t.Run("a steal scores for the other team only", func(t *testing.T) {
+		awarded := map[string]int{}
+		uc, _ := newGuessUsecase(mur, newRepo(30*time.Second, awarded), mwr, mgn, &servicetest.MockGameLoop{}, nil)
+
+		correct, err := uc.Guess(context.Background(), gameID, "blue-guesser", "secret")
+		if err != nil || !correct {
+			t.Fatalf("got correct=%v err=%v, want a correct steal", correct, err)
+		}
+		if awarded["blue-guesser"] <= 0 {
+			t.Errorf("expected the stealer to score, got %v", awarded)
+		}
+		if _, ok := awarded[teller]; ok {
+			t.Errorf("expected no teller bonus on a steal, got %v", awarded)
+		}
+	})
+}
+
+func TestGameStateTeamLeaderboard(t *testing.T) {
+	players := []model.Player{
+		{ID: "red-1", Team: model.RedTeam, State: model.ActivePlayerState},
+		{ID: "red-2", Team: model.RedTeam, State: model.InactivePlayerState},
+		{ID: "blue-1", Team: model.BlueTeam, State: model.ActivePlayerState},
+		{ID: "blue-2", Team: model.BlueTeam, State: model.KickedPlayerState},
+	}
+	mgr := &repotest.MockGameRepository{
+		FindByIDMock:    func(ctx context.Context, id string) (model.Game, error) { return teamGame(id), nil },
+		GetPlayersMock:  func(ctx context.Context, id string) ([]model.Player, error) { return players, nil },
+		GetMessagesMock: func(ctx context.Context, id string) ([]model.Message, error) { return nil, nil },
+		GetScoresMock: func(ctx context.Context, id string) ([]model.Score, error) {
+			return []model.Score{
+				{PlayerID: "red-1", Score: 5},
+				{PlayerID: "red-2", Score: 10}, // left, still counts
+				{PlayerID: "blue-1", Score: 20},
+				{PlayerID: "blue-2", Score: 30}, // kicked, does not
+			}, nil
+		},
+		GetLatestTurnMock: func(ctx context.Context, id string) (model.GameTurn, error) {
+			return model.GameTurn{}, sql.ErrNoRows
+		},
+	}
+	uc := usecase.NewEmojixUsecase(nil, mgr, nil, nil, nil, &servicetest.MockGameLoop{}, service.NewRealClock())
+
+	gs, err := uc.GameState(context.Background(), "game-1", "red-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertValue(t, "MyTeam", model.RedTeam, gs.MyTeam)
+	want := []model.TeamScore{
+		{Team: model.BlueTeam, Score: 20},
+		{Team: model.RedTeam, Score: 15, Me: true},
+	}
+	if len(gs.TeamLeaderboard) != len(want) {
+		t.Fatalf("TeamLeaderboard = %+v, want %+v", gs.TeamLeaderboard, want)
+	}
+	for i := range want {
+		assertValue(t, "TeamLeaderboard", want[i], gs.TeamLeaderboard[i])
+	}
+	if gs.Leaderboard[0].Team != model.RedTeam {
+		t.Errorf("expected leaderboard entries to carry the team, got %+v", gs.Leaderboard[0])
+	}
+}
@@ -0,0 +1,306 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"emojix/model"
+	"emojix/repository"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ErrInvalidWord is wrapped by every list/word validation failure.
+var ErrInvalidWord = NewError(KindValidation, "invalid word")
+
+// ErrDuplicateWord is returned when a list already has the same word, compared
+// the way guesses are (case, accents and articles ignored).
+var ErrDuplicateWord = NewError(KindConflict, "word already in the list")
+
+var ErrWordListNotFound = NewError(KindNotFound, "word list not found")
+var ErrWordNotFound = NewError(KindNotFound, "word not found")
+
+// ErrWordInUse is returned when changing or deleting a word a game has
+// already dealt.
+var ErrWordInUse error = &Error{Kind: KindConflict, err: repository.ErrWordInUse}
+
+// ErrNotListOwner is returned when someone other than a list's creator tries
+// to change it. Seeded and imported lists have no creator.
+var ErrNotListOwner = NewError(KindForbidden, "only the list's creator may change it")
+
+const (
+	maxListTitleRunes = 60
+	maxWordRunes      = 40
+)
+
+// ImportParams describes a bulk import. An empty ListID creates a new list
+// titled Title in the same transaction as the words.
+type ImportParams struct {
+	ListID string
+	Title  string
+	Words  []model.Word
+}
+
+// ImportProblem is a rejected import row; Row is 1-based.
+type ImportProblem struct {
+	Row  int
+	Word string
+	Err  error
+}
+
+// ImportReport lists what ImportWords did. Duplicates are skipped rather than
+// failing the import; any Invalid row fails it with nothing written.
+type ImportReport struct {
+	List       model.WordList
+	Added      []model.Word
+	Duplicates []ImportProblem
+	Invalid    []ImportProblem
+}
+
+func (e *emojixUsecase) GetWordList(ctx context.Context, listID string) (model.WordList, []model.Word, error) {
+	list, err := e.findWordList(ctx, listID)
+	if err != nil {
+		return list, nil, err
+	}
+	words, err := e.wordRepo.GetByList(ctx, listID)
+	return list, words, err
+}
+
+func (e *emojixUsecase) CreateWordList(ctx context.Context, userID, title string) (model.WordList, error) {
+	title, err := validateListTitle(title)
+	if err != nil {
+		return model.WordList{}, err
+	}
+	return e.wordRepo.CreateList(ctx, title, userID)
+}
+
+func (e *emojixUsecase) AddWord(ctx context.Context, userID, listID, word, hint string) (model.Word, error) {
+	word, hint, err := validateWord(word, hint)
+	if err != nil {
+		return model.Word{}, err
+	}
+	if _, err = e.ownedList(ctx, userID, listID); err != nil {
+		return model.Word{}, err
+	}
+
+	// The duplicate check and the write share a unit of work, so two
+	// concurrent adds of one word can't both pass it.
+	uow, err := e.unitOfWorkFactory.New(ctx)
+	if err != nil {
+		return model.Word{}, err
+	}
+	defer uow.Rollback()
+	wr := uow.WordRepository()
+
+	existing, err := wr.GetByList(ctx, listID)
+	if err != nil {
+		return model.Word{}, err
+	}
+	if duplicateWord(existing, "", word) {
+		return model.Word{}, fmt.Errorf("%w: %q", ErrDuplicateWord, word)
+	}
+	added, err := wr.AddWord(ctx, listID, word, hint)
+	if err != nil {
+		return model.Word{}, err
+	}
+	return added, uow.Commit()
+}
+
+func (e *emojixUsecase) UpdateWord(ctx context.Context, userID, wordID, word, hint string) error {
+	word, hint, err := validateWord(word, hint)
+	if err != nil {
+		return err
+	}
+	current, err := e.ownedWord(ctx, userID, wordID)
+	if err != nil {
+		return err
+	}
+
+	uow, err := e.unitOfWorkFactory.New(ctx)
+	if err != nil {
+		return err
+	}
+	defer uow.Rollback()
+	wr := uow.WordRepository()
+
+	existing, err := wr.GetByList(ctx, current.ListID)
+	if err != nil {
+		return err
+	}
+	if duplicateWord(existing, wordID, word) {
+		return fmt.Errorf("%w: %q", ErrDuplicateWord, word)
+	}
+	err = wr.UpdateWord(ctx, wordID, word, hint)
+	if errors.Is(err, repository.ErrWordInUse) {
+		return ErrWordInUse
+	}
+	if err != nil {
+		return err
+	}
+	return uow.Commit()
+}
+
+func (e *emojixUsecase) DeleteWord(ctx context.Context, userID, wordID string) error {
+	if _, err := e.ownedWord(ctx, userID, wordID); err != nil {
+		return err
+	}
+	err := e.wordRepo.DeleteWord(ctx, wordID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrWordNotFound
+	}
+	if errors.Is(err, repository.ErrWordInUse) {
+		return ErrWordInUse
+	}
+	return err
+}
+
+// ImportWords validates every row up front, then adds the new ones in one
+// unit of work.
+func (e *emojixUsecase) ImportWords(ctx context.Context, params ImportParams) (ImportReport, error) {
+	report := ImportReport{}
+
+	var existing []model.Word
+	if params.ListID != "" {
+		list, err := e.findWordList(ctx, params.ListID)
+		if err != nil {
+			return report, err
+		}
+		report.List = list
+		if existing, err = e.wordRepo.GetByList(ctx, params.ListID); err != nil {
+			return report, err
+		}
+	} else {
+		title, err := validateListTitle(params.Title)
+		if err != nil {
+			return report, err
+		}
+		report.List.Title = title
+	}
+
+	toAdd := []model.Word{}
+	for i, w := range params.Words {
+		word, hint, err := validateWord(w.Word, w.Hint)
+		if err != nil {
+			report.Invalid = append(report.Invalid, ImportProblem{Row: i + 1, Word: w.Word, Err: err})
+			continue
+		}
+		if duplicateWord(existing, "", word) || duplicateWord(toAdd, "", word) {
+			report.Duplicates = append(report.Duplicates, ImportProblem{Row: i + 1, Word: word, Err: ErrDuplicateWord})
+			continue
+		}
+		toAdd = append(toAdd, model.Word{Word: word, Hint: hint})
+	}
+	if len(report.Invalid) > 0 {
+		return report, fmt.Errorf("%w: %d invalid rows", ErrInvalidWord, len(report.Invalid))
+	}
+
+	uow, err := e.unitOfWorkFactory.New(ctx)
+	if err != nil {
+		return report, err
+	}
+	defer uow.Rollback()
+	wr := uow.WordRepository()
+
+	if params.ListID == "" {
+		if report.List, err = wr.CreateList(ctx, report.List.Title, ""); err != nil {
+			return report, err
+		}
+	}
+	added := make([]model.Word, 0, len(toAdd))
+	for _, w := range toAdd {
+		word, err := wr.AddWord(ctx, report.List.ID, w.Word, w.Hint)
+		if err != nil {
+			return report, err
+		}
+		added = append(added, word)
+	}
+
+	if err = uow.Commit(); err != nil {
+		return report, err
+	}
+	report.Added = added
+
+	return report, nil
+}
+
+func (e *emojixUsecase) findWordList(ctx context.Context, listID string) (model.WordList, error) {
+	list, err := e.wordRepo.FindListByID(ctx, listID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return list, ErrWordListNotFound
+	}
+	return list, err
+}
+
+// ownedList finds listID and checks userID created it.
+func (e *emojixUsecase) ownedList(ctx context.Context, userID, listID string) (model.WordList, error) {
+	list, err := e.findWordList(ctx, listID)
+	if err != nil {
+		return list, err
+	}
+	if list.OwnerID == "" || list.OwnerID != userID {
+		return list, ErrNotListOwner
+	}
+	return list, nil
+}
+
+// ownedWord finds wordID and checks userID created its list.
+func (e *emojixUsecase) ownedWord(ctx context.Context, userID, wordID string) (model.Word, error) {
+	word, err := e.wordRepo.FindByID(ctx, wordID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return word, ErrWordNotFound
+	}
+	if err != nil {
+		return word, err
+	}
+	_, err = e.ownedList(ctx, userID, word.ListID)
+	return word, err
+}
+
+func validateListTitle(title string) (string, error) {
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return "", fmt.Errorf("%w: title required", ErrInvalidWord)
+	}
+	if utf8.RuneCountInString(title) > maxListTitleRunes {
+		return "", fmt.Errorf("%w: title longer than %d characters", ErrInvalidWord, maxListTitleRunes)
+	}
+	return title, nil
+}
+
+// validateWord trims both fields and checks the word is guessable text and
+// the hint is emoji-only, matching what the teller board accepts.
+func validateWord(word, hint string) (string, string, error) {
+	word = strings.Join(strings.Fields(word), " ")
+	hint = strings.TrimSpace(hint)
+
+	if !strings.ContainsFunc(word, unicode.IsLetter) {
+		return "", "", fmt.Errorf("%w: word needs letters", ErrInvalidWord)
+	}
+	if utf8.RuneCountInString(word) > maxWordRunes {
+		return "", "", fmt.Errorf("%w: word longer than %d characters", ErrInvalidWord, maxWordRunes)
+	}
+	if !IsEmojiOnly(hint) {
+		return "", "", fmt.Errorf("%w: hint must be emoji only", ErrInvalidWord)
+	}
+	if utf8.RuneCountInString(hint) > maxHintRunes {
+		return "", "", fmt.Errorf("%w: hint longer than %d characters", ErrInvalidWord, maxHintRunes)
+	}
+	return word, hint, nil
+}
+
+// duplicateWord reports whether words has another entry (not skipID) that a
+// guess for word would also match exactly.
+func duplicateWord(words []model.Word, skipID, word string) bool {
+	norm := normalizeGuess(word)
+	for _, w := range words {
+		if skipID != "" && w.ID == skipID {
+			continue
+		}
+		if normalizeGuess(w.Word) == norm {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,316 @@
+package usecase_test
+
+import (
+	"context"
+	"database/sql"
+	"emojix/model"
+	"emojix/repository"
+	"emojix/repository/repotest"
+	"emojix/service"
+	"emojix/service/servicetest"
+	"emojix/usecase"
+	"errors"
+	"testing"
+)
+
+// newWordListUsecase runs units of work on uow, or on mwr itself when uow is
+// nil.
+func newWordListUsecase(mwr *repotest.MockWordRepository, uow *repotest.MockUnitOfWork) usecase.EmojixUsecase {
+	if uow == nil {
+		uow = &repotest.MockUnitOfWork{
+			WordRepositoryMock: mwr,
+			CommitMock:         func() error { return nil },
+			RollbackMock:       func() error { return nil },
+		}
+	}
+	factory := &repotest.MockUnitOfWorkFactory{
+		NewMock: func(ctx context.Context) (repository.UnitOfWork, error) { return uow, nil },
+	}
+	return usecase.NewEmojixUsecase(nil, nil, mwr, factory, nil, &servicetest.MockGameLoop{}, service.NewRealClock())
+}
+
+func TestWordListAuthoring(t *testing.T) {
+	list := model.WordList{ID: "list-1", Title: "Fruit", OwnerID: "owner-1"}
+	existing := []model.Word{
+		{ID: "w-1", ListID: "list-1", Word: "Apple", Hint: "🍎"},
+		{ID: "w-2", ListID: "list-1", Word: "Pear", Hint: "🍐"},
+	}
+	wordRepo := func() *repotest.MockWordRepository {
+		return &repotest.MockWordRepository{
+			FindListByIDMock: func(ctx context.Context, id string) (model.WordList, error) {
+				switch id {
+				case list.ID:
+					return list, nil
+				case "seeded":
+					return model.WordList{ID: id, Title: "Seeded"}, nil
+				}
+				return model.WordList{}, sql.ErrNoRows
+			},
+			FindByIDMock: func(ctx context.Context, id string) (model.Word, error) {
+				for _, w := range existing {
+					if w.ID == id {
+						return w, nil
+					}
+				}
+				return model.Word{}, sql.ErrNoRows
+			},
+			GetByListMock: func(ctx context.Context, listID string) ([]model.Word, error) {
+				return existing, nil
+			},
+		}
+	}
+
+	t.Run("AddWord trims and stores a valid word", func(t *testing.T) {
+		mwr := wordRepo()
+		mwr.AddWordMock = func(ctx context.Context, listID, word, hint string) (model.Word, error) {
+			assertCalledWith(t, "ListID", "list-1", listID)
+			assertCalledWith(t, "Word", "Ice Cream", word)
+			assertCalledWith(t, "Hint", "🍦", hint)
+			return model.Word{ID: "w-3", ListID: listID, Word: word, Hint: hint}, nil
+		}
+		uc := newWordListUsecase(mwr, nil)
+
+		word, err := uc.AddWord(context.Background(), "owner-1", "list-1", "  Ice   Cream ", " 🍦 ")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		assertValue(t, "WordID", "w-3", word.ID)
+	})
+
+	t.Run("AddWord checks for duplicates in the unit of work it writes in", func(t *testing.T) {
+		txRepo := &repotest.MockWordRepository{
+			GetByListMock: func(ctx context.Context, listID string) ([]model.Word, error) {
+				// a concurrent add got Kiwi in first
+				return append(existing, model.Word{ID: "w-3", ListID: listID, Word: "Kiwi", Hint: "🥝"}), nil
+			},
+		}
+		uow := &repotest.MockUnitOfWork{
+			WordRepositoryMock: txRepo,
+			CommitMock:         func() error { return nil },
+			RollbackMock:       func() error { return nil },
+		}
+		uc := newWordListUsecase(wordRepo(), uow)
+
+		_, err := uc.AddWord(context.Background(), "owner-1", "list-1", "kiwi", "🥝")
+		if !errors.Is(err, usecase.ErrDuplicateWord) {
+			t.Fatalf("expected ErrDuplicateWord but got %v", err)
+		}
+		if uow.CommitCalled || !uow.RollbackCalled || txRepo.AddWordCount != 0 {
+			t.Error("expected nothing written and the unit of work rolled back")
+		}
+	})
+
+	t.Run("CreateWordList records the creator", func(t *testing.T) {
+		mwr := wordRepo()
+		mwr.CreateListMock = func(ctx context.Context, title, ownerID string) (model.WordList, error) {
+			assertCalledWith(t, "OwnerID", "owner-1", ownerID)
+			return model.WordList{ID: "list-2", Title: title, OwnerID: ownerID}, nil
+		}
+		uc := newWordListUsecase(mwr, nil)
+
+		if _, err := uc.CreateWordList(context.Background(), "owner-1", "Veg"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("UpdateWord refuses a dealt word", func(t *testing.T) {
+		mwr := wordRepo()
+		mwr.UpdateWordMock = func(ctx context.Context, id, word, hint string) error {
+			return repository.ErrWordInUse
+		}
+		uc := newWordListUsecase(mwr, nil)
+
+		err := uc.UpdateWord(context.Background(), "owner-1", "w-1", "Apples", "🍎")
+		if !errors.Is(err, usecase.ErrWordInUse) {
+			t.Errorf("expected ErrWordInUse but got %v", err)
+		}
+	})
+
+	t.Run("UpdateWord may keep its own word", func(t *testing.T) {
+		mwr := wordRepo()
+		mwr.UpdateWordMock = func(ctx context.Context, id, word, hint string) error {
+			assertCalledWith(t, "WordID", "w-1", id)
+			return nil
+		}
+		uc := newWordListUsecase(mwr, nil)
+
+		if err := uc.UpdateWord(context.Background(), "owner-1", "w-1", "apple", "🍏"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	cases := []struct {
+		name string
+		call func(uc usecase.EmojixUsecase) error
+		want error
+	}{
+		{
+			name: "blank title",
+			call: func(uc usecase.EmojixUsecase) error {
+				_, err := uc.CreateWordList(context.Background(), "owner-1", "  ")
+				return err
+			},
+			want: usecase.ErrInvalidWord,
+		},
+		{
+			name: "hint with letters",
+			call: func(uc usecase.EmojixUsecase) error {
+				_, err := uc.AddWord(context.Background(), "owner-1", "list-1", "Kiwi", "kiwi 🥝")
+				return err
+			},
+			want: usecase.ErrInvalidWord,
+		},
+		{
+			name: "word without letters",
+			call: func(uc usecase.EmojixUsecase) error {
+				_, err := uc.AddWord(context.Background(), "owner-1", "list-1", "123", "🥝")
+				return err
+			},
+			want: usecase.ErrInvalidWord,
+		},
+		{
+			name: "duplicate ignoring case and articles",
+			call: func(uc usecase.EmojixUsecase) error {
+				_, err := uc.AddWord(context.Background(), "owner-1", "list-1", "the APPLE", "🍎")
+				return err
+			},
+			want: usecase.ErrDuplicateWord,
+		},
+		{
+			name: "rename onto another word",
+			call: func(uc usecase.EmojixUsecase) error {
+				return uc.UpdateWord(context.Background(), "owner-1", "w-1", "pear", "🍐")
+			},
+			want: usecase.ErrDuplicateWord,
+		},
+		{
+			name: "someone else's list",
+			call: func(uc usecase.EmojixUsecase) error {
+				_, err := uc.AddWord(context.Background(), "someone", "list-1", "Kiwi", "🥝")
+				return err
+			},
+			want: usecase.ErrNotListOwner,
+		},
+		{
+			name: "someone else's word",
+			call: func(uc usecase.EmojixUsecase) error {
+				return uc.UpdateWord(context.Background(), "someone", "w-1", "Kiwi", "🥝")
+			},
+			want: usecase.ErrNotListOwner,
+		},
+		{
+			name: "delete from someone else's list",
+			call: func(uc usecase.EmojixUsecase) error {
+				return uc.DeleteWord(context.Background(), "someone", "w-1")
+			},
+			want: usecase.ErrNotListOwner,
+		},
+		{
+			name: "seeded list",
+			call: func(uc usecase.EmojixUsecase) error {
+				_, err := uc.AddWord(context.Background(), "owner-1", "seeded", "Kiwi", "🥝")
+				return err
+			},
+			want: usecase.ErrNotListOwner,
+		},
+		{
+			name: "unknown list",
+			call: func(uc usecase.EmojixUsecase) error {
+				_, _, err := uc.GetWordList(context.Background(), "nope")
+				return err
+			},
+			want: usecase.ErrWordListNotFound,
+		},
+		{
+			name: "unknown word",
+			call: func(uc usecase.EmojixUsecase) error {
+				return uc.UpdateWord(context.Background(), "owner-1", "nope", "Kiwi", "🥝")
+			},
+			want: usecase.ErrWordNotFound,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			// No write mocks wired: reaching them would panic.
+			uc := newWordListUsecase(wordRepo(), nil)
+
+			if err := tc.call(uc); !errors.Is(err, tc.want) {
+				t.Errorf("expected %v but got %v", tc.want, err)
+			}
+		})
+	}
+}
+
+func TestImportWords(t *testing.T) {
+	newUoW := func(wr *repotest.MockWordRepository) *repotest.MockUnitOfWork {
+		return &repotest.MockUnitOfWork{
+			WordRepositoryMock: wr,
+			CommitMock:         func() error { return nil },
+			RollbackMock:       func() error { return nil },
+		}
+	}
+
+	t.Run("creates the list and skips duplicates", func(t *testing.T) {
+		txRepo := &repotest.MockWordRepository{
+			CreateListMock: func(ctx context.Context, title, ownerID string) (model.WordList, error) {
+				assertCalledWith(t, "Title", "Fruit", title)
+				assertCalledWith(t, "OwnerID", "", ownerID)
+				return model.WordList{ID: "list-new", Title: title}, nil
+			},
+			AddWordMock: func(ctx context.Context, listID, word, hint string) (model.Word, error) {
+				assertCalledWith(t, "ListID", "list-new", listID)
+				return model.Word{ID: word, ListID: listID, Word: word, Hint: hint}, nil
+			},
+		}
+		uow := newUoW(txRepo)
+		uc := newWordListUsecase(&repotest.MockWordRepository{}, uow)
+
+		report, err := uc.ImportWords(context.Background(), usecase.ImportParams{
+			Title: "Fruit",
+			Words: []model.Word{
+				{Word: "Apple", Hint: "🍎"},
+				{Word: "Pear", Hint: "🍐"},
+				{Word: "apple", Hint: "🍏"},
+			},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !uow.CommitCalled {
+			t.Error("expected Commit to be called")
+		}
+		assertValue(t, "ListID", "list-new", report.List.ID)
+		assertValue(t, "Added", 2, len(report.Added))
+		if len(report.Duplicates) != 1 || report.Duplicates[0].Row != 3 {
+			t.Errorf("expected row 3 reported as duplicate but got %+v", report.Duplicates)
+		}
+	})
+
+	t.Run("invalid rows abort the import", func(t *testing.T) {
+		txRepo := &repotest.MockWordRepository{}
+		uow := newUoW(txRepo)
+		mwr := &repotest.MockWordRepository{
+			FindListByIDMock: func(ctx context.Context, id string) (model.WordList, error) {
+				return model.WordList{ID: id, Title: "Fruit"}, nil
+			},
+		}
+		uc := newWordListUsecase(mwr, uow)
+
+		report, err := uc.ImportWords(context.Background(), usecase.ImportParams{
+			ListID: "list-1",
+			Words: []model.Word{
+				{Word: "Apple", Hint: "🍎"},
+				{Word: "Kiwi", Hint: "green"},
+			},
+		})
+		if !errors.Is(err, usecase.ErrInvalidWord) {
+			t.Fatalf("expected ErrInvalidWord but got %v", err)
+		}
+		if len(report.Invalid) != 1 || report.Invalid[0].Row != 2 {
+			t.Errorf("expected row 2 reported invalid but got %+v", report.Invalid)
+		}
+		if uow.CommitCalled || txRepo.AddWordCount != 0 {
+			t.Error("expected nothing written")
+		}
+	})
+}
@@ -3,6 +3,7 @@ package emojix
 import (
 	"embed"
 	"emojix/model"
+	"fmt"
 	"html/template"
 	"io"
 	"time"
@@ -14,10 +15,48 @@ import (
 //go:embed template/*.gohtml
 var templateFS embed.FS
 
+// PageViewParam is embedded in the params of every full page; base.gohtml
+// hands CSRFToken to HTMX and templates put it in their plain forms.
+type PageViewParam struct {
+	CSRFToken string
+}
+
 type IndexPageViewParam struct {
+	PageViewParam
 	Title    string
 	Nickname string
+	Avatar   string
 	Lists    []model.WordList
+	Settings model.GameSettings // defaults prefilled in the room settings form
+}
+
+type ListsPageViewParam struct {
+	PageViewParam
+	Lists []model.WordList
+}
+
+type ListPageViewParam struct {
+	PageViewParam
+	List  model.WordList
+	Words []model.Word
+	// CanEdit is set for the list's creator; everyone else only reads it.
+	CanEdit bool
+}
+
+type HistoryPageViewParam struct {
+	PageViewParam
+	History model.GameHistory
+}
+
+// Offset is how far into the game t was, in milliseconds; the replay waits
+// out the gaps between events.
+func (p HistoryPageViewParam) Offset(t time.Time) int64 {
+	return t.Sub(p.History.StartAt).Milliseconds()
+}
+
+// TurnNumber counts turns from one for display.
+func (p HistoryPageViewParam) TurnNumber(i int) int {
+	return i + 1
 }
 
 // TellerEmojiKeyboard is the fixed palette shown to the active teller for chat.
@@ -29,12 +68,16 @@ var TellerEmojiKeyboard = []string{
 }
 
 type GamePageViewParam struct {
+	PageViewParam
 	GameID            string
+	ShareBase         string // origin for share links; empty uses the page's own
 	Leaderboard       []model.LeaderboardEntry
 	Messages          []model.GameStateMessage
 	MaskedWord        []string
 	EmojiHint         string
 	TurnStartedAt     time.Time
+	TurnDuration      time.Duration
+	PickDuration      time.Duration
 	AwaitingPick      bool
 	WaitingForPlayers bool
 	IsTeller          bool
@@ -44,6 +87,37 @@ type GamePageViewParam struct {
 	WordOptions       []model.Word
 	TurnEnded         bool
 	EmojiKeyboard     []string
+	GameOver          bool
+	Podium            []model.LeaderboardEntry
+	TurnResults       []model.TurnResult
+	IsHost            bool
+	HostID            string
+	InviteCode        string
+	Private           bool
+	Locked            bool
+	Teams             bool
+	TeamLeaderboard   []model.TeamScore
+	MyTeam            model.Team
+	TellerTeam        model.Team
+	StealOpensAt      time.Time
+	IsSpectator       bool
+	QueuePosition     int
+	SpectatorCount    int
+	CanReroll         bool
+	SkipVotes         int
+	SkipVotesNeeded   int
+	VotedSkip         bool
+}
+
+// TimerLabel formats d as the m:ss shown next to a timer bar before JS takes over.
+func (p GamePageViewParam) TimerLabel(d time.Duration) string {
+	secs := int(d.Round(time.Second) / time.Second)
+	return fmt.Sprintf("%d:%02d", secs/60, secs%60)
+}
+
+// StealDelay is how long into the turn the other team may start guessing.
+func (p GamePageViewParam) StealDelay() time.Duration {
+	return p.StealOpensAt.Sub(p.TurnStartedAt)
 }
 
 type GameWordViewParam struct {
@@ -51,29 +125,67 @@ type GameWordViewParam struct {
 }
 
 type GameLeaderboardViewParam struct {
-	Leaderboard []model.LeaderboardEntry
+	Leaderboard     []model.LeaderboardEntry
+	TeamLeaderboard []model.TeamScore
 }
 
 type GameMsgViewParam = model.GameStateMessage
 
+// ErrorViewParam describes a failed request. Message is empty for internal
+// errors, whose details are only logged.
+type ErrorViewParam struct {
+	PageViewParam
+	Status  int
+	Message string
+}
+
+// Title is the error page heading for Status.
+func (p ErrorViewParam) Title() string {
+	switch p.Status {
+	case 400:
+		return "That didn't work"
+	case 403:
+		return "Not allowed"
+	case 404:
+		return "Not found"
+	case 409:
+		return "Not right now"
+	case 410:
+		return "Game over"
+	case 429:
+		return "Slow down"
+	default:
+		return "Something broke"
+	}
+}
+
 type View interface {
-	renderErrorPage(wr io.Writer) error
+	renderErrorPage(wr io.Writer, params ErrorViewParam) error
+	renderErrorFragment(wr io.Writer, params ErrorViewParam) error
 
 	renderIndexPage(wr io.Writer, params IndexPageViewParam) error
 
+	renderListsPage(wr io.Writer, params ListsPageViewParam) error
+	renderListPage(wr io.Writer, params ListPageViewParam) error
+
 	renderGamePage(wr io.Writer, params GamePageViewParam) error
 	renderGameWord(wr io.Writer, params GameWordViewParam) error
 	renderGameMsg(wr io.Writer, params GameMsgViewParam) error
 	renderGameLeaderboard(wr io.Writer, params GameLeaderboardViewParam) error
+	renderHistoryPage(wr io.Writer, params HistoryPageViewParam) error
 }
 
 type htmlView struct {
 	indexPageTemplate       template.Template
+	listsPageTemplate       template.Template
+	listPageTemplate        template.Template
 	gamePageTemplate        template.Template
 	gameWordTemplate        template.Template
 	gameMsgTemplate         template.Template
 	gameLeaderboardTemplate template.Template
+	historyPageTemplate     template.Template
 	errorPageTemplate       template.Template
+	errorFragmentTemplate   template.Template
 }
 
 func NewHTMLView() View {
@@ -82,12 +194,22 @@ func NewHTMLView() View {
 		"template/index.gohtml",
 	))
 
+	listsPageTemplate := *template.Must(template.ParseFS(templateFS,
+		"template/base.gohtml",
+		"template/lists.gohtml",
+	))
+	listPageTemplate := *template.Must(template.ParseFS(templateFS,
+		"template/base.gohtml",
+		"template/list.gohtml",
+	))
+
 	gamePageTemplate := *template.Must(template.ParseFS(templateFS,
 		"template/base.gohtml",
 		"template/game.gohtml",
 		"template/game-msg-def.gohtml",
 		"template/game-leaderboard-def.gohtml",
 		"template/game-word-def.gohtml",
+		"template/game-results-def.gohtml",
 	))
 	gameWordTemplate := *template.Must(template.ParseFS(templateFS,
 		"template/game-word.gohtml",
@@ -102,18 +224,30 @@ func NewHTMLView() View {
 		"template/game-leaderboard-def.gohtml",
 	))
 
+	historyPageTemplate := *template.Must(template.ParseFS(templateFS,
+		"template/base.gohtml",
+		"template/history.gohtml",
+	))
+
 	errorPageTemplate := *template.Must(template.ParseFS(templateFS,
 		"template/base.gohtml",
 		"template/error.gohtml",
 	))
+	errorFragmentTemplate := *template.Must(template.ParseFS(templateFS,
+		"template/error-fragment.gohtml",
+	))
 
 	return &htmlView{
 		indexPageTemplate:       indexPageTemplate,
+		listsPageTemplate:       listsPageTemplate,
+		listPageTemplate:        listPageTemplate,
 		gamePageTemplate:        gamePageTemplate,
 		gameWordTemplate:        gameWordTemplate,
 		gameMsgTemplate:         gameMsgTemplate,
 		gameLeaderboardTemplate: gameLeaderboardTemplate,
+		historyPageTemplate:     historyPageTemplate,
 		errorPageTemplate:       errorPageTemplate,
+		errorFragmentTemplate:   errorFragmentTemplate,
 	}
 }
 
@@ -121,6 +255,14 @@ func (v *htmlView) renderIndexPage(wr io.Writer, params IndexPageViewParam) erro
 	return v.indexPageTemplate.Execute(wr, params)
 }
 
+func (v *htmlView) renderListsPage(wr io.Writer, params ListsPageViewParam) error {
+	return v.listsPageTemplate.Execute(wr, params)
+}
+
+func (v *htmlView) renderListPage(wr io.Writer, params ListPageViewParam) error {
+	return v.listPageTemplate.Execute(wr, params)
+}
+
 func (v *htmlView) renderGamePage(wr io.Writer, params GamePageViewParam) error {
 	return v.gamePageTemplate.Execute(wr, params)
 }
@@ -137,6 +279,14 @@ func (v *htmlView) renderGameWord(wr io.Writer, params GameWordViewParam) error
 	return v.gameWordTemplate.Execute(wr, params)
 }
 
-func (v *htmlView) renderErrorPage(wr io.Writer) error {
-	return v.errorPageTemplate.Execute(wr, nil)
+func (v *htmlView) renderHistoryPage(wr io.Writer, params HistoryPageViewParam) error {
+	return v.historyPageTemplate.Execute(wr, params)
+}
+
+func (v *htmlView) renderErrorPage(wr io.Writer, params ErrorViewParam) error {
+	return v.errorPageTemplate.Execute(wr, params)
+}
+
+func (v *htmlView) renderErrorFragment(wr io.Writer, params ErrorViewParam) error {
+	return v.errorFragmentTemplate.Execute(wr, params)
 }
@@ -33,7 +33,21 @@ func TestRenderEveryTemplate(t *testing.T) {
 			name:     "renderErrorPage",
 			contains: "Something broke",
 			render: func(buf *bytes.Buffer) error {
-				return view.renderErrorPage(buf)
+				return view.renderErrorPage(buf, ErrorViewParam{Status: 500})
+			},
+		},
+		{
+			name:     "renderErrorPage with message",
+			contains: "room is full",
+			render: func(buf *bytes.Buffer) error {
+				return view.renderErrorPage(buf, ErrorViewParam{Status: 409, Message: "room is full"})
+			},
+		},
+		{
+			name:     "renderErrorFragment",
+			contains: `class="error-flash-msg"`,
+			render: func(buf *bytes.Buffer) error {
+				return view.renderErrorFragment(buf, ErrorViewParam{Status: 403, Message: "only the teller can pick the word"})
 			},
 		},
 		{
@@ -43,6 +57,59 @@ func TestRenderEveryTemplate(t *testing.T) {
 				return view.renderIndexPage(buf, IndexPageViewParam{Title: "x", Nickname: "y"})
 			},
 		},
+		{
+			name:     "renderIndexPage csrf token",
+			contains: `<input type="hidden" name="csrf-token" value="tok-1" />`,
+			render: func(buf *bytes.Buffer) error {
+				return view.renderIndexPage(buf, IndexPageViewParam{PageViewParam: PageViewParam{CSRFToken: "tok-1"}})
+			},
+		},
+		{
+			name:     "base hx-headers csrf token",
+			contains: `hx-headers='{"X-CSRF-Token": "tok-1"}'`,
+			render: func(buf *bytes.Buffer) error {
+				return view.renderListsPage(buf, ListsPageViewParam{PageViewParam: PageViewParam{CSRFToken: "tok-1"}})
+			},
+		},
+		{
+			name:     "renderListsPage",
+			contains: `href="/lists/l1"`,
+			render: func(buf *bytes.Buffer) error {
+				return view.renderListsPage(buf, ListsPageViewParam{Lists: []model.WordList{{ID: "l1", Title: "Fruit"}}})
+			},
+		},
+		{
+			name:     "renderListPage",
+			contains: `action="/lists/l1/words/w1/delete"`,
+			render: func(buf *bytes.Buffer) error {
+				return view.renderListPage(buf, ListPageViewParam{
+					List:    model.WordList{ID: "l1", Title: "Fruit", OwnerID: "u1"},
+					Words:   []model.Word{{ID: "w1", ListID: "l1", Word: "Apple", Hint: "🍎"}},
+					CanEdit: true,
+				})
+			},
+		},
+		{
+			name:     "renderHistoryPage",
+			contains: `data-at="12000"`,
+			render: func(buf *bytes.Buffer) error {
+				start := time.Date(2026, 1, 2, 15, 0, 0, 0, time.UTC)
+				return view.renderHistoryPage(buf, HistoryPageViewParam{History: model.GameHistory{
+					GameID:  "game-1",
+					StartAt: start,
+					Podium:  []model.LeaderboardEntry{{PlayerID: "p1", Nickname: "One", Score: 8}},
+					Turns: []model.TurnHistory{{
+						TellerNickname: "One", Word: "Apple", Passed: []string{"Pear"}, Hint: "🍎",
+						Events: []model.HistoryEvent{
+							{Kind: model.DealtHistoryEvent, At: start, Nickname: "One"},
+							{Kind: model.GuessHistoryEvent, At: start.Add(12 * time.Second), Nickname: "Two", Content: "apple",
+								Correct: true, Scores: []model.TurnScore{{Nickname: "Two", Score: 8}}},
+						},
+						Scores: []model.TurnScore{{Nickname: "Two", Score: 8}},
+					}},
+				}})
+			},
+		},
 		{
 			name:     "renderGamePage",
 			contains: "Me-nickname",
@@ -100,11 +167,34 @@ func TestRenderEveryTemplate(t *testing.T) {
 		},
 		{
 			name:     "renderGamePageInPlaceTurnRefresh",
-			contains: `hx-trigger="sse:turnended,sse:wordpicked,sse:newturn"`,
+			contains: `hx-trigger="sse:turnended,sse:wordpicked,sse:newturn,sse:gameover,sse:hostchanged,sse:roomlocked,sse:kicked,sse:seats,sse:rerolled"`,
 			render: func(buf *bytes.Buffer) error {
 				return view.renderGamePage(buf, GamePageViewParam{GameID: "game-1"})
 			},
 		},
+		{
+			name:     "renderGamePage host panel",
+			contains: `hx-post="/game/game-1/kick"`,
+			render: func(buf *bytes.Buffer) error {
+				return view.renderGamePage(buf, GamePageViewParam{
+					GameID:     "game-1",
+					IsHost:     true,
+					InviteCode: "ABC234",
+					Private:    true,
+					Leaderboard: []model.LeaderboardEntry{
+						{PlayerID: "p1", Nickname: "Me-nickname", Me: true},
+						{PlayerID: "p2", Nickname: "Other-nickname"},
+					},
+				})
+			},
+		},
+		{
+			name:     "renderGamePage invite link",
+			contains: `data-share-path="/join/ABC234"`,
+			render: func(buf *bytes.Buffer) error {
+				return view.renderGamePage(buf, GamePageViewParam{GameID: "game-1", InviteCode: "ABC234"})
+			},
+		},
 		{
 			name:     "renderGamePageTurnEnded",
 			contains: "Next turn",
@@ -115,6 +205,32 @@ func TestRenderEveryTemplate(t *testing.T) {
 				})
 			},
 		},
+		{
+			name:     "renderGamePageGameOverResults",
+			contains: `action="/game/game-1/rematch"`,
+			render: func(buf *bytes.Buffer) error {
+				return view.renderGamePage(buf, GamePageViewParam{
+					GameID:   "game-1",
+					GameOver: true,
+					Podium:   []model.LeaderboardEntry{{Nickname: "Winner", Score: 12}},
+					TurnResults: []model.TurnResult{
+						{TellerNickname: "Winner", Word: "Alpha", Scores: []model.TurnScore{{Nickname: "Other", Score: 8}}},
+					},
+				})
+			},
+		},
+		{
+			name:     "renderGamePageTellerHintBoard",
+			contains: `hx-post="/game/game-1/hint"`,
+			render: func(buf *bytes.Buffer) error {
+				return view.renderGamePage(buf, GamePageViewParam{
+					GameID:        "game-1",
+					IsTeller:      true,
+					EmojiHint:     "🍎",
+					EmojiKeyboard: TellerEmojiKeyboard,
+				})
+			},
+		},
 		{
 			name:     "renderGamePageTellerEmojiKeyboard",
 			contains: `name="content"`,
@@ -161,6 +277,69 @@ func TestRenderEveryTemplate(t *testing.T) {
 				})
 			},
 		},
+		{
+			name:     "renderGameLeaderboard away badge",
+			contains: "away-badge",
+			render: func(buf *bytes.Buffer) error {
+				return view.renderGameLeaderboard(buf, GameLeaderboardViewParam{
+					Leaderboard: []model.LeaderboardEntry{
+						{PlayerID: "p1", Nickname: "n1", Away: true},
+					},
+				})
+			},
+		},
+		{
+			name:     "renderGameLeaderboard team totals",
+			contains: `class="team team-blue is-me"`,
+			render: func(buf *bytes.Buffer) error {
+				return view.renderGameLeaderboard(buf, GameLeaderboardViewParam{
+					Leaderboard: []model.LeaderboardEntry{
+						{PlayerID: "p1", Nickname: "n1", Team: model.BlueTeam, Me: true, Score: 4},
+					},
+					TeamLeaderboard: []model.TeamScore{
+						{Team: model.BlueTeam, Score: 4, Me: true},
+						{Team: model.RedTeam, Score: 0},
+					},
+				})
+			},
+		},
+		{
+			name:     "renderGamePage team picker",
+			contains: `hx-post="/game/game-1/team"`,
+			render: func(buf *bytes.Buffer) error {
+				return view.renderGamePage(buf, GamePageViewParam{GameID: "game-1", WaitingForPlayers: true, Teams: true, MyTeam: model.RedTeam})
+			},
+		},
+		{
+			name:     "renderGamePage spectator queue",
+			contains: "#2 in line for a seat",
+			render: func(buf *bytes.Buffer) error {
+				return view.renderGamePage(buf, GamePageViewParam{GameID: "game-1", MaskedWord: []string{"*"}, IsSpectator: true, QueuePosition: 2, SpectatorCount: 3})
+			},
+		},
+		{
+			name:     "renderGamePage watch instead",
+			contains: `hx-post="/game/game-1/spectate"`,
+			render: func(buf *bytes.Buffer) error {
+				return view.renderGamePage(buf, GamePageViewParam{GameID: "game-1", WaitingForPlayers: true})
+			},
+		},
+		{
+			name:     "renderGamePage steal note",
+			contains: "You can steal after 0:20",
+			render: func(buf *bytes.Buffer) error {
+				started := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+				return view.renderGamePage(buf, GamePageViewParam{
+					GameID:        "game-1",
+					MaskedWord:    []string{"*"},
+					TurnStartedAt: started,
+					Teams:         true,
+					MyTeam:        model.BlueTeam,
+					TellerTeam:    model.RedTeam,
+					StealOpensAt:  started.Add(20 * time.Second),
+				})
+			},
+		},
 		{
 			name:     "renderGameLeaderboard teller badge",
 			contains: "teller-badge",
@@ -200,6 +379,7 @@ func TestRenderGamePageStatusChromeDetails(t *testing.T) {
 		},
 		MaskedWord:    []string{"*", "*", "*"},
 		TurnStartedAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		TurnDuration:  90 * time.Second,
 		LetterCount:   8,
 		WordCount:     2,
 	})
@@ -212,6 +392,8 @@ func TestRenderGamePageStatusChromeDetails(t *testing.T) {
 		"8 letters · 2 words",
 		"turn-timer-text",
 		"word-meta",
+		`data-duration="90000"`,
+		">1:30<",
 	} {
 		if !strings.Contains(out, want) {
 			t.Errorf("missing %q", want)